@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/LISSConsulting/RalphSpec/internal/agenttest"
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// agentScript is the hidden --agent-script flag: a TOML agent script (see
+// agenttest.Load) that loops replay instead of running the Claude CLI, for
+// black-box tests of prompts and gate configs.
+var agentScript string
+
+// newAgent returns the agent loops run: the scripted agent when
+// --agent-script is set, the Claude CLI otherwise.
+func newAgent(cfg *config.Config) (claude.Agent, error) {
+	if agentScript != "" {
+		return agenttest.Load(agentScript)
+	}
+	return loop.NewClaudeAgentFor(cfg), nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAgentScript_BlackBoxRun(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	t.Chdir(dir)
+	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
+	writeExecTestFile(t, dir, "PLAN.md", "plan the work")
+	for _, args := range [][]string{{"add", "."}, {"commit", "-qm", "setup"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	script := filepath.Join(t.TempDir(), "agent.toml")
+	writeExecTestFile(t, filepath.Dir(script), "agent.toml", `[[run]]
+output = '''
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Write","input":{"file_path":"notes.md"}}]}}
+{"type":"result","subtype":"success","cost_usd":0.07,"duration_ms":1000}
+'''
+
+[[run.step]]
+after_line = 1
+files = { "notes.md" = "planned\n" }
+commit = "docs: add notes"
+`)
+	t.Cleanup(func() { agentScript = "" })
+
+	root := rootCmd()
+	root.SetArgs([]string{"--agent-script", script, "--no-tui", "--no-color", "loop", "plan"})
+	var err error
+	out := captureStdout(func() { err = root.Execute() })
+	if err != nil {
+		t.Fatalf("ralph loop plan: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "tool: Write  notes.md") || !strings.Contains(out, "$0.07") {
+		t.Errorf("output should show the scripted run:\n%s", out)
+	}
+	log, err := exec.Command("git", "-C", dir, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(log), "docs: add notes\n") {
+		t.Errorf("git log =\n%s", log)
+	}
+}
+
+func TestAgentScript_FlagHidden(t *testing.T) {
+	f := rootCmd().PersistentFlags().Lookup("agent-script")
+	if f == nil || !f.Hidden {
+		t.Fatalf("--agent-script flag = %+v, want a hidden flag", f)
+	}
+}
+
+func TestNewAgent_BadScript(t *testing.T) {
+	agentScript = filepath.Join(t.TempDir(), "missing.toml")
+	t.Cleanup(func() { agentScript = "" })
+	if _, err := newAgent(nil); err == nil {
+		t.Error("newAgent with a missing script should fail")
+	}
+}
@@ -27,15 +27,13 @@ func loopPlanCmd() *cobra.Command {
 		Use:   "plan",
 		Short: "Run Claude in plan mode",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			max, _ := cmd.Flags().GetInt("max")
-			noTUI, _ := cmd.Root().PersistentFlags().GetBool("no-tui")
-			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
-			worktreeFlag, _ := cmd.Flags().GetBool("worktree")
-			return executeLoop(loop.ModePlan, max, noTUI, false, "", noColor, worktreeFlag)
+			return executeLoop(loop.ModePlan, loopFlags(cmd))
 		},
 	}
 	cmd.Flags().Int("max", 0, "override max iterations (0 = use config)")
-	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree via worktrunk")
+	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree ([worktree] backend)")
+	cmd.Flags().Bool("dry-run-push", false, "log what would be pushed instead of pushing")
+	cmd.Flags().Bool("resume", false, "continue the loop from its last checkpoint (.ralph/checkpoint.json)")
 	return cmd
 }
 
@@ -44,19 +42,15 @@ func loopBuildCmd() *cobra.Command {
 		Use:   "build",
 		Short: "Run Claude in build mode",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			max, _ := cmd.Flags().GetInt("max")
-			noTUI, _ := cmd.Root().PersistentFlags().GetBool("no-tui")
-			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
-			roam, _ := cmd.Flags().GetBool("roam")
-			focus, _ := cmd.Flags().GetString("focus")
-			worktreeFlag, _ := cmd.Flags().GetBool("worktree")
-			return executeLoop(loop.ModeBuild, max, noTUI, roam, focus, noColor, worktreeFlag)
+			return executeLoop(loop.ModeBuild, loopFlags(cmd))
 		},
 	}
 	cmd.Flags().Int("max", 0, "override max iterations (0 = use config)")
 	cmd.Flags().Bool("roam", false, "roam freely across the codebase instead of targeting the active spec")
 	cmd.Flags().String("focus", "", "constrain roam to a specific topic (e.g. \"UI/UX\")")
-	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree via worktrunk")
+	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree ([worktree] backend)")
+	cmd.Flags().Bool("dry-run-push", false, "log what would be pushed instead of pushing")
+	cmd.Flags().Bool("resume", false, "continue the loop from its last checkpoint (.ralph/checkpoint.json)")
 	return cmd
 }
 
@@ -65,41 +59,47 @@ func loopRunCmd() *cobra.Command {
 		Use:   "run",
 		Short: "Smart mode: plan if needed, then build",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			max, _ := cmd.Flags().GetInt("max")
-			noTUI, _ := cmd.Root().PersistentFlags().GetBool("no-tui")
-			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
-			roam, _ := cmd.Flags().GetBool("roam")
-			focus, _ := cmd.Flags().GetString("focus")
-			worktreeFlag, _ := cmd.Flags().GetBool("worktree")
-			return executeSmartRun(max, noTUI, roam, focus, noColor, worktreeFlag)
+			return executeSmartRun(loopFlags(cmd))
 		},
 	}
 	cmd.Flags().Int("max", 0, "override max iterations (0 = use config)")
 	cmd.Flags().Bool("roam", false, "roam freely across the codebase instead of targeting the active spec")
 	cmd.Flags().String("focus", "", "constrain roam to a specific topic (e.g. \"UI/UX\")")
-	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree via worktrunk")
+	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree ([worktree] backend)")
+	cmd.Flags().Bool("dry-run-push", false, "log what would be pushed instead of pushing")
 	return cmd
 }
 
+// loopFlags reads the loop run options from cmd's flags. Flags the command
+// does not define (--roam on plan, --resume on run) stay at their zero value.
+func loopFlags(cmd *cobra.Command) loopOptions {
+	var opts loopOptions
+	opts.maxOverride, _ = cmd.Flags().GetInt("max")
+	opts.noTUI, _ = cmd.Root().PersistentFlags().GetBool("no-tui")
+	opts.noColor, _ = cmd.Root().PersistentFlags().GetBool("no-color")
+	opts.roam, _ = cmd.Flags().GetBool("roam")
+	opts.focus, _ = cmd.Flags().GetString("focus")
+	opts.worktree, _ = cmd.Flags().GetBool("worktree")
+	opts.dryRunPush, _ = cmd.Flags().GetBool("dry-run-push")
+	opts.resume, _ = cmd.Flags().GetBool("resume")
+	return opts
+}
+
 // buildCmd is preserved as a top-level alias for the common build workflow.
 func buildCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "build",
 		Short: "Run Claude in build mode",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			max, _ := cmd.Flags().GetInt("max")
-			noTUI, _ := cmd.Root().PersistentFlags().GetBool("no-tui")
-			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
-			roam, _ := cmd.Flags().GetBool("roam")
-			focus, _ := cmd.Flags().GetString("focus")
-			worktreeFlag, _ := cmd.Flags().GetBool("worktree")
-			return executeLoop(loop.ModeBuild, max, noTUI, roam, focus, noColor, worktreeFlag)
+			return executeLoop(loop.ModeBuild, loopFlags(cmd))
 		},
 	}
 	cmd.Flags().Int("max", 0, "override max iterations (0 = use config)")
 	cmd.Flags().Bool("roam", false, "roam freely across the codebase instead of targeting the active spec")
 	cmd.Flags().String("focus", "", "constrain roam to a specific topic (e.g. \"UI/UX\")")
-	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree via worktrunk")
+	cmd.Flags().BoolP("worktree", "w", false, "run loop in an isolated git worktree ([worktree] backend)")
+	cmd.Flags().Bool("dry-run-push", false, "log what would be pushed instead of pushing")
+	cmd.Flags().Bool("resume", false, "continue the loop from its last checkpoint (.ralph/checkpoint.json)")
 	return cmd
 }
 
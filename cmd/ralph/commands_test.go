@@ -150,6 +150,19 @@ func captureStderr(fn func()) string {
 	return buf.String()
 }
 
+// captureStdout captures output written to os.Stdout during fn.
+func captureStdout(fn func()) string {
+	r, w, _ := os.Pipe()
+	old := os.Stdout
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	return buf.String()
+}
+
 // TestAPIKeyWarning_KeySet verifies the warning appears on stderr when
 // ANTHROPIC_API_KEY is set.
 func TestAPIKeyWarning_KeySet(t *testing.T) {
@@ -241,7 +254,7 @@ func TestRootCmdStructure(t *testing.T) {
 	}
 
 	// Loop and project management commands
-	for _, want := range []string{"build", "loop", "status", "init", "spec"} {
+	for _, want := range []string{"build", "loop", "status", "init", "spec", "pr", "steer", "cost", "report", "history"} {
 		if !subs[want] {
 			t.Errorf("missing top-level command %q", want)
 		}
@@ -596,6 +609,35 @@ func TestBuildCmd_FocusFlag(t *testing.T) {
 	}
 }
 
+// TestLoopCmds_DryRunPushFlag verifies --dry-run-push is registered on every
+// command that starts a loop.
+func TestLoopCmds_DryRunPushFlag(t *testing.T) {
+	for name, cmd := range map[string]*cobra.Command{
+		"build":      buildCmd(),
+		"loop plan":  loopPlanCmd(),
+		"loop build": loopBuildCmd(),
+		"loop run":   loopRunCmd(),
+	} {
+		if cmd.Flags().Lookup("dry-run-push") == nil {
+			t.Errorf("%s: --dry-run-push flag not registered", name)
+		}
+	}
+}
+
+// TestLoopCmds_ResumeFlag verifies --resume is registered on the commands
+// that run a single mode, whose checkpoint it continues.
+func TestLoopCmds_ResumeFlag(t *testing.T) {
+	for name, cmd := range map[string]*cobra.Command{
+		"build":      buildCmd(),
+		"loop plan":  loopPlanCmd(),
+		"loop build": loopBuildCmd(),
+	} {
+		if cmd.Flags().Lookup("resume") == nil {
+			t.Errorf("%s: --resume flag not registered", name)
+		}
+	}
+}
+
 // TestRootCmd_NoSubcommand_CallsDashboard exercises the rootCmd RunE body
 // (return executeDashboard()) by executing the root command with no subcommand.
 // Without ralph.toml present, executeDashboard fails early at config.Load,
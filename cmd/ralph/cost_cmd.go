@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/spf13/cobra"
+
+	"github.com/LISSConsulting/RalphSpec/internal/cost"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/tui"
+)
+
+// costCmd implements `ralph cost`: spend across every stored session, per
+// spec, per day and per model, with a projection for the active spec.
+func costCmd() *cobra.Command {
+	var since, specName string
+	var asJSON, asCSV bool
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Report spend per spec, day and model across all sessions",
+		Long: `Report what the loop has cost across every session log in .ralph/logs:
+totals, spend per day, per spec and per model, average cost per completed
+task, and a projection of what the active spec's unchecked tasks will cost.
+The active spec is --spec or the one the current branch resolves to.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if asJSON && asCSV {
+				return fmt.Errorf("cost: --json and --csv cannot be combined")
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+			var filter cost.Filter
+			if since != "" {
+				if filter.Since, err = cost.ParseSince(since, time.Now()); err != nil {
+					return err
+				}
+			}
+			filter.Spec = specName
+			records, err := cost.Load(dir)
+			if err != nil {
+				return err
+			}
+			records = filter.Apply(records)
+
+			if asCSV {
+				return cost.WriteCSV(os.Stdout, records)
+			}
+			rep := cost.Summarize(dir, records, activeCostSpec(dir, specName))
+			if asJSON {
+				data, err := json.MarshalIndent(rep, "", "  ")
+				if err != nil {
+					return fmt.Errorf("cost: encode json: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
+			fmt.Print(formatCostReport(rep, !noColor))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "only count iterations since a date (2006-01-02) or duration (7d, 36h)")
+	cmd.Flags().StringVar(&specName, "spec", "", "only count iterations of this spec, and project it")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the report as JSON")
+	cmd.Flags().BoolVar(&asCSV, "csv", false, "print one CSV row per iteration")
+	return cmd
+}
+
+// activeCostSpec returns the spec to project: specName when given, else the
+// spec the current branch resolves to, else "".
+func activeCostSpec(dir, specName string) string {
+	if specName != "" {
+		return specName
+	}
+	branch, _ := git.NewRunner(dir).CurrentBranch()
+	if as, err := spec.Resolve(dir, "", branch); err == nil {
+		return as.Name
+	}
+	return ""
+}
+
+// formatCostReport renders the report as the TUI's Spend tab does, plain
+// when color is false.
+func formatCostReport(rep cost.Report, color bool) string {
+	var b strings.Builder
+	for _, line := range tui.RenderSpend(rep, 100) {
+		if !color {
+			line = ansi.Strip(line)
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/cost"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// writeCostFixture writes a session log with one iteration of 001-auth
+// ten days ago and one of 002-billing today, plus their tasks.md files.
+func writeCostFixture(t *testing.T, dir string) {
+	t.Helper()
+	logs := filepath.Join(dir, ".ralph", "logs")
+	for _, d := range []string{logs, filepath.Join(dir, "specs", "001-auth"), filepath.Join(dir, "specs", "002-billing")} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().AddDate(0, 0, -10)
+	var b strings.Builder
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Iteration: 1, Mode: "build", Spec: "001-auth", Model: "opus", Timestamp: old},
+		{Kind: loop.LogIterComplete, Iteration: 1, CostUSD: 1.2, Subtype: "success", Timestamp: old},
+		{Kind: loop.LogIterStart, Iteration: 2, Mode: "build", Spec: "002-billing", Timestamp: time.Now()},
+		{Kind: loop.LogIterComplete, Iteration: 2, CostUSD: 0.3, Subtype: "success", Timestamp: time.Now()},
+	} {
+		data, _ := json.Marshal(e)
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	files := map[string]string{
+		filepath.Join(logs, "100-1.jsonl"):                     b.String(),
+		filepath.Join(dir, "specs", "001-auth", "tasks.md"):    "- [x] a\n- [x] b\n- [ ] c\n",
+		filepath.Join(dir, "specs", "002-billing", "tasks.md"): "- [ ] a\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func runCostCmd(t *testing.T, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := costCmd()
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var err error
+	out := captureStdout(func() { err = cmd.RunE(cmd, nil) })
+	return out, err
+}
+
+func TestCostCmd_Text(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeCostFixture(t, dir)
+
+	out, err := runCostCmd(t, map[string]string{"spec": "001-auth"})
+	if err != nil {
+		t.Fatalf("cost: %v", err)
+	}
+	for _, want := range []string{"Total $1.20", "001-auth", "opus", "Projected $0.60 to finish 001-auth"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "002-billing") {
+		t.Errorf("--spec should exclude other specs:\n%s", out)
+	}
+}
+
+func TestCostCmd_JSONSince(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeCostFixture(t, dir)
+
+	out, err := runCostCmd(t, map[string]string{"json": "true", "since": "2d"})
+	if err != nil {
+		t.Fatalf("cost --json: %v", err)
+	}
+	var rep cost.Report
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("output is not a JSON report: %v\n%s", err, out)
+	}
+	if rep.TotalUSD != 0.3 || len(rep.Specs) != 1 || rep.Specs[0].Key != "002-billing" {
+		t.Errorf("--since 2d report = %+v, want only today's billing iteration", rep)
+	}
+}
+
+func TestCostCmd_CSV(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeCostFixture(t, dir)
+
+	out, err := runCostCmd(t, map[string]string{"csv": "true"})
+	if err != nil {
+		t.Fatalf("cost --csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "date,session,spec") {
+		t.Fatalf("csv = %q", out)
+	}
+	if !strings.Contains(lines[1], "001-auth,opus,build,1,1.2000") || !strings.Contains(lines[2], "002-billing,default") {
+		t.Errorf("csv rows = %q", lines[1:])
+	}
+}
+
+func TestCostCmd_Errors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if _, err := runCostCmd(t, map[string]string{"since": "soon"}); err == nil {
+		t.Error("an invalid --since should fail")
+	}
+	if _, err := runCostCmd(t, map[string]string{"json": "true", "csv": "true"}); err == nil {
+		t.Error("--json with --csv should fail")
+	}
+	out, err := runCostCmd(t, nil)
+	if err != nil || !strings.Contains(out, "No completed iterations") {
+		t.Errorf("cost without logs = %q, %v", out, err)
+	}
+}
@@ -36,10 +36,22 @@ type loopSetup struct {
 	cleanup       func() // closes the JSONL store if one was opened
 }
 
+// loopOptions are the command-line options of a loop run.
+type loopOptions struct {
+	maxOverride int    // --max; 0 = use config
+	noTUI       bool   // --no-tui
+	noColor     bool   // --no-color
+	roam        bool   // --roam
+	focus       string // --focus; empty = [build] focus
+	worktree    bool   // --worktree
+	dryRunPush  bool   // --dry-run-push
+	resume      bool   // --resume; ignored by executeSmartRun
+}
+
 // setupLoop performs the common initialisation shared by executeLoop and
 // executeSmartRun: config load, validation, working dir, signal context, git
 // runner, loop struct init, spec resolution, and store init.
-func setupLoop(noTUI, roam, noColor bool) (*loopSetup, error) {
+func setupLoop(opts loopOptions) (*loopSetup, error) {
 	cfg, err := config.Load("")
 	if err != nil {
 		return nil, err
@@ -47,29 +59,37 @@ func setupLoop(noTUI, roam, noColor bool) (*loopSetup, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation: %w", err)
 	}
+	if err := checkSandbox(cfg); err != nil {
+		return nil, err
+	}
 
 	dir, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("get working directory: %w", err)
 	}
+	agent, err := newAgent(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	var ctx context.Context
 	var cancel context.CancelFunc
 	var stopCh <-chan struct{}
-	if noTUI {
+	if opts.noTUI {
 		ctx, cancel, stopCh = signalContextGraceful()
 	} else {
 		ctx, cancel = signalContext()
 	}
 
 	gitRunner := git.NewRunner(dir)
-	effectiveRoam := roam || cfg.Build.Roam
+	effectiveRoam := opts.roam || cfg.Build.Roam
 
 	lp := &loop.Loop{
-		Agent:  loop.NewClaudeAgent(),
-		Git:    gitRunner,
-		Config: cfg,
-		Dir:    dir,
+		Agent:    agent,
+		Git:      gitRunner,
+		Config:   cfg,
+		Dir:      dir,
+		Steering: loop.NewSteerQueue(dir),
 	}
 	if stopCh != nil {
 		lp.StopAfter = stopCh
@@ -102,6 +122,9 @@ func setupLoop(noTUI, roam, noColor bool) (*loopSetup, error) {
 		sw = s
 		sr = s
 		cleanup = func() { _ = s.Close() }
+		if sum, sumErr := s.SessionSummary(); sumErr == nil {
+			lp.SessionID = sum.SessionID
+		}
 	}
 
 	return &loopSetup{
@@ -114,22 +137,24 @@ func setupLoop(noTUI, roam, noColor bool) (*loopSetup, error) {
 		effectiveRoam: effectiveRoam,
 		sw:            sw,
 		sr:            sr,
-		formatter:     lineFormatter{color: !noColor},
+		formatter:     lineFormatter{color: !opts.noColor},
 		cleanup:       cleanup,
 	}, nil
 }
 
 // executeLoop loads config, builds the loop, and runs it in the given mode.
-func executeLoop(mode loop.Mode, maxOverride int, noTUI bool, roam bool, focus string, noColor bool, useWorktree bool) error {
-	setup, err := setupLoop(noTUI, roam, noColor)
+// With opts.resume, the loop continues from the checkpoint its last run saved.
+func executeLoop(mode loop.Mode, opts loopOptions) error {
+	setup, err := setupLoop(opts)
 	if err != nil {
 		return err
 	}
 	defer setup.cancel()
 	defer setup.cleanup()
+	setup.lp.DryRunPush = opts.dryRunPush
 
 	// Worktree mode: create an isolated worktree and run the loop inside it.
-	if useWorktree {
+	if opts.worktree {
 		if wtErr := setupWorktree(setup); wtErr != nil {
 			return wtErr
 		}
@@ -149,37 +174,68 @@ func executeLoop(mode loop.Mode, maxOverride int, noTUI bool, roam bool, focus s
 		return fmt.Errorf("prompt file %s: %w", promptFile, statErr)
 	}
 
+	if opts.resume {
+		if err := applyResume(setup, mode); err != nil {
+			return err
+		}
+	}
+
 	setup.lp.Roam = setup.effectiveRoam
-	if focus != "" {
-		setup.lp.Focus = focus
+	if opts.focus != "" {
+		setup.lp.Focus = opts.focus
 	} else {
 		setup.lp.Focus = setup.cfg.Build.Focus
 	}
 
+	applySpecCompleteAction(setup)
+
 	runFn := func(ctx context.Context) error {
-		return setup.lp.Run(ctx, mode, maxOverride)
+		return setup.lp.Run(ctx, mode, opts.maxOverride)
 	}
 
 	if !setup.cfg.Regent.Enabled {
-		if noTUI {
+		if opts.noTUI {
 			return runWithStateTracking(setup.ctx, setup.lp, setup.lp.Dir, setup.gitRunner, string(mode), setup.sw, setup.formatter, runFn)
 		}
 		return runWithTUIAndState(setup.ctx, setup.lp, setup.lp.Dir, setup.gitRunner, string(mode), setup.cfg.TUI.AccentColor, setup.cfg.Project.Name, setup.sw, setup.sr, runFn)
 	}
 
-	if noTUI {
+	if opts.noTUI {
 		return runWithRegent(setup.ctx, setup.lp, setup.cfg, setup.gitRunner, setup.lp.Dir, setup.sw, setup.formatter, runFn)
 	}
 	return runWithRegentTUI(setup.ctx, setup.lp, setup.cfg, setup.gitRunner, setup.lp.Dir, setup.sw, setup.sr, runFn)
 }
 
-// setupWorktree detects worktrunk, creates/switches to the worktree for the
-// current branch, and updates setup.lp.Dir and setup.gitRunner to point at the
-// worktree directory. Must be called before any prompt pre-flight checks.
+// applyResume loads the checkpoint from the loop's directory, which is the
+// worktree in worktree mode, so the loop continues its numbering and cost.
+func applyResume(setup *loopSetup, mode loop.Mode) error {
+	cp, err := loop.LoadCheckpoint(setup.lp.Dir)
+	if err != nil {
+		return err
+	}
+	if cp.Mode != mode {
+		return fmt.Errorf("checkpoint is for a %s loop, not %s", cp.Mode, mode)
+	}
+	setup.lp.Resume = &cp
+	return nil
+}
+
+// applySpecCompleteAction wires build.on_spec_complete into the loop. It must
+// run after setupWorktree so the hook operates in the worktree directory.
+func applySpecCompleteAction(setup *loopSetup) {
+	if setup.cfg.Build.OnSpecComplete != "open_pr" || setup.effectiveRoam {
+		return
+	}
+	setup.lp.OnSpecComplete = specCompletePRHook(setup.cfg, setup.lp.Dir, setup.lp.Spec, setup.lp.DryRunPush)
+}
+
+// setupWorktree detects the configured worktree backend, creates/switches to
+// the worktree for the current branch, and updates setup.lp.Dir and
+// setup.gitRunner to point at the worktree directory. Must be called before any prompt pre-flight checks.
 func setupWorktree(setup *loopSetup) error {
-	wtr := worktree.NewRunner(setup.dir)
+	var wtr worktree.WorktreeOps = worktree.NewRunner(setup.dir)
 	if setup.cfg != nil {
-		wtr.WorktreeDir = setup.cfg.Worktree.ResolvedWorktreeDir()
+		wtr = worktree.New(setup.cfg.Worktree, setup.dir)
 	}
 	if err := wtr.Detect(); err != nil {
 		return err
@@ -204,7 +260,7 @@ func setupWorktree(setup *loopSetup) error {
 	}
 
 	// Guard: if the worktree path resolves to the same directory we
-	// started in, worktrunk didn't actually create a separate worktree
+	// started in, the backend didn't actually create a separate worktree
 	// (e.g. the branch is already checked out in the main working tree).
 	absWT, _ := filepath.Abs(wtPath)
 	absDir, _ := filepath.Abs(setup.dir)
@@ -227,30 +283,35 @@ func setupWorktree(setup *loopSetup) error {
 		setup.sw = s
 		setup.sr = s
 		setup.cleanup = func() { _ = s.Close() }
+		if sum, sumErr := s.SessionSummary(); sumErr == nil {
+			setup.lp.SessionID = sum.SessionID
+		}
 	}
 
 	return nil
 }
 
 // executeSmartRun runs plan if CHRONICLE.md doesn't exist, then build.
-func executeSmartRun(maxOverride int, noTUI bool, roam bool, focus string, noColor bool, useWorktree bool) error {
-	setup, err := setupLoop(noTUI, roam, noColor)
+func executeSmartRun(opts loopOptions) error {
+	setup, err := setupLoop(opts)
 	if err != nil {
 		return err
 	}
 	defer setup.cancel()
 	defer setup.cleanup()
+	setup.lp.DryRunPush = opts.dryRunPush
 
-	if useWorktree {
+	if opts.worktree {
 		if wtErr := setupWorktree(setup); wtErr != nil {
 			return wtErr
 		}
 	}
 
-	effectiveFocus := focus
+	effectiveFocus := opts.focus
 	if effectiveFocus == "" {
 		effectiveFocus = setup.cfg.Build.Focus
 	}
+	applySpecCompleteAction(setup)
 
 	smartRunFn := func(ctx context.Context) error {
 		// Check inside the closure so Regent retries re-evaluate whether
@@ -264,17 +325,17 @@ func executeSmartRun(maxOverride int, noTUI bool, roam bool, focus string, noCol
 		}
 		setup.lp.Roam = setup.effectiveRoam
 		setup.lp.Focus = effectiveFocus
-		return setup.lp.Run(ctx, loop.ModeBuild, maxOverride)
+		return setup.lp.Run(ctx, loop.ModeBuild, opts.maxOverride)
 	}
 
 	if !setup.cfg.Regent.Enabled {
-		if noTUI {
+		if opts.noTUI {
 			return runWithStateTracking(setup.ctx, setup.lp, setup.lp.Dir, setup.gitRunner, "run", setup.sw, setup.formatter, smartRunFn)
 		}
 		return runWithTUIAndState(setup.ctx, setup.lp, setup.lp.Dir, setup.gitRunner, "run", setup.cfg.TUI.AccentColor, setup.cfg.Project.Name, setup.sw, setup.sr, smartRunFn)
 	}
 
-	if noTUI {
+	if opts.noTUI {
 		return runWithRegent(setup.ctx, setup.lp, setup.cfg, setup.gitRunner, setup.lp.Dir, setup.sw, setup.formatter, smartRunFn)
 	}
 	return runWithRegentTUI(setup.ctx, setup.lp, setup.cfg, setup.gitRunner, setup.lp.Dir, setup.sw, setup.sr, smartRunFn)
@@ -333,6 +394,10 @@ func formatStatus(state regent.State, now time.Time) string {
 		ago := now.Sub(state.LastOutputAt).Round(time.Second)
 		fmt.Fprintf(&b, "  %-20s %s ago\n", "Last output:", ago)
 	}
+	if result == statusRunning && state.RateLimitedUntil.After(now) {
+		fmt.Fprintf(&b, "  %-20s %s (usage limit, %s left)\n", "Paused until:",
+			state.RateLimitedUntil.Local().Format("15:04"), state.RateLimitedUntil.Sub(now).Round(time.Second))
+	}
 
 	switch result {
 	case statusRunning:
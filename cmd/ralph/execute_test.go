@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"io/fs"
 	"os"
 	"os/exec"
@@ -222,6 +223,17 @@ func TestFormatStatus(t *testing.T) {
 				"running",
 			},
 		},
+		{
+			name: "running and rate-limited — shows when the loop resumes",
+			state: regent.State{
+				RalphPID:         123,
+				Iteration:        4,
+				StartedAt:        started,
+				LastOutputAt:     lastOutput,
+				RateLimitedUntil: now.Add(time.Hour),
+			},
+			contains: []string{"Paused until:", "(usage limit, 1h0m0s left)", "running"},
+		},
 		{
 			name: "pass — shows duration and pass result",
 			state: regent.State{
@@ -392,7 +404,7 @@ func TestExecuteLoop_ConfigNotFound(t *testing.T) {
 	// Isolated temp dir with no ralph.toml anywhere in its ancestor tree.
 	t.Chdir(t.TempDir())
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when ralph.toml not found")
 	}
@@ -407,7 +419,7 @@ func TestExecuteLoop_ConfigInvalid(t *testing.T) {
 	// Empty plan.prompt_file fails Validate()
 	writeExecTestFile(t, dir, "ralph.toml", "[plan]\nprompt_file = \"\"\n[build]\nprompt_file = \"b.md\"\n")
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected validation error")
 	}
@@ -423,7 +435,7 @@ func TestExecuteLoop_RegentDisabled_PromptMissing(t *testing.T) {
 	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
 	// PLAN.md intentionally absent — loop.Run fails reading it.
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when prompt file missing")
 	}
@@ -440,7 +452,7 @@ func TestExecuteLoop_RegentEnabled_PromptMissing(t *testing.T) {
 	// PLAN.md intentionally absent.
 	// Pre-flight check returns an error before Regent is initialised.
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when prompt file missing")
 	}
@@ -456,7 +468,7 @@ func TestExecuteLoop_BuildMode_PromptMissing(t *testing.T) {
 	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
 	// BUILD.md intentionally absent — covers default case in mode switch.
 
-	err := executeLoop(loop.ModeBuild, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModeBuild, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when build prompt file missing")
 	}
@@ -474,7 +486,7 @@ func TestExecuteLoop_RegentDisabled_PromptExists_GitFails(t *testing.T) {
 	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
 	writeExecTestFile(t, dir, "PLAN.md", "# Plan\n")
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	// Loop fails at git CurrentBranch — must be an error but not a prompt-file error.
 	if err == nil {
 		t.Fatal("expected error from git operations")
@@ -494,7 +506,7 @@ func TestExecuteLoop_RegentEnabled_PromptExists_GitFails(t *testing.T) {
 	writeExecTestFile(t, dir, "ralph.toml", testConfigWithRegent())
 	writeExecTestFile(t, dir, "PLAN.md", "# Plan\n")
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	// Regent gives up after 0 retries — must be an error.
 	if err == nil {
 		t.Fatal("expected error — Regent should give up after 0 retries")
@@ -509,7 +521,7 @@ func TestExecuteLoop_RegentEnabled_PromptExists_GitFails(t *testing.T) {
 func TestExecuteSmartRun_ConfigNotFound(t *testing.T) {
 	t.Chdir(t.TempDir())
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when ralph.toml not found")
 	}
@@ -526,7 +538,7 @@ func TestExecuteSmartRun_NeedsPlan_PromptMissing(t *testing.T) {
 	// No CHRONICLE.md → needsPlanPhase returns true.
 	// No PLAN.md → plan phase fails reading it.
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when plan prompt file missing")
 	}
@@ -544,7 +556,7 @@ func TestExecuteSmartRun_SkipPlan_BuildPromptMissing(t *testing.T) {
 	writeExecTestFile(t, dir, "CHRONICLE.md", "# Plan\n\nSome content.\n")
 	// BUILD.md absent → build loop fails reading it.
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error when build prompt file missing")
 	}
@@ -559,7 +571,7 @@ func TestExecuteSmartRun_ConfigInvalid(t *testing.T) {
 	// Empty plan.prompt_file triggers Validate() error.
 	writeExecTestFile(t, dir, "ralph.toml", "[plan]\nprompt_file = \"\"\n[build]\nprompt_file = \"b.md\"\n")
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected validation error")
 	}
@@ -577,7 +589,7 @@ func TestExecuteSmartRun_RegentEnabled_PromptMissing(t *testing.T) {
 	// No PLAN.md → plan phase fails reading it.
 	// Regent gives up after 0 retries and returns max-retries error.
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error — Regent should give up (max_retries=0)")
 	}
@@ -596,7 +608,7 @@ func TestExecuteLoop_StoreUnavailable(t *testing.T) {
 		t.Fatalf("WriteFile .ralph: %v", err)
 	}
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error from git operations")
 	}
@@ -614,7 +626,7 @@ func TestExecuteSmartRun_StoreUnavailable(t *testing.T) {
 		t.Fatalf("WriteFile .ralph: %v", err)
 	}
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error from git operations")
 	}
@@ -629,7 +641,7 @@ func TestExecuteLoop_NotificationsURLSet(t *testing.T) {
 	writeExecTestFile(t, dir, "ralph.toml", cfg)
 	writeExecTestFile(t, dir, "PLAN.md", "# Plan\n")
 
-	err := executeLoop(loop.ModePlan, 1, true, false, "", false, false)
+	err := executeLoop(loop.ModePlan, loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error from git operations")
 	}
@@ -648,7 +660,7 @@ func TestExecuteSmartRun_NotificationsURLSet(t *testing.T) {
 	writeExecTestFile(t, dir, "CHRONICLE.md", "# Done\n\nSome content.\n")
 	writeExecTestFile(t, dir, "BUILD.md", "# Build\n")
 
-	err := executeSmartRun(1, true, false, "", false, false)
+	err := executeSmartRun(loopOptions{maxOverride: 1, noTUI: true})
 	if err == nil {
 		t.Fatal("expected error from git operations")
 	}
@@ -849,7 +861,7 @@ func TestExecuteLoop_Roam_StaysOnCurrentBranch(t *testing.T) {
 	branchBefore := strings.TrimSpace(string(outBefore))
 
 	// roam=true: should stay on the current branch (no sweep branch creation).
-	_ = executeLoop(loop.ModeBuild, 1, true, true, "", false, false)
+	_ = executeLoop(loop.ModeBuild, loopOptions{maxOverride: 1, noTUI: true, roam: true})
 
 	after := exec.Command("git", "branch", "--show-current")
 	after.Dir = dir
@@ -883,3 +895,32 @@ func TestSetupWorktree_DetectFails(t *testing.T) {
 		t.Errorf("error should mention worktrunk, got: %v", err)
 	}
 }
+
+func TestExecuteLoop_ResumeWithoutCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
+	writeExecTestFile(t, dir, "BUILD.md", "# Build\n")
+
+	err := executeLoop(loop.ModeBuild, loopOptions{maxOverride: 1, noTUI: true, resume: true})
+	if !errors.Is(err, loop.ErrNoCheckpoint) {
+		t.Errorf("--resume with no checkpoint: err = %v, want ErrNoCheckpoint", err)
+	}
+}
+
+func TestApplyResume(t *testing.T) {
+	dir := t.TempDir()
+	setup := &loopSetup{lp: &loop.Loop{Dir: dir}}
+	if err := loop.SaveCheckpoint(dir, loop.Checkpoint{Mode: loop.ModePlan, Iteration: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyResume(setup, loop.ModeBuild); err == nil || !strings.Contains(err.Error(), "plan loop") {
+		t.Errorf("mode mismatch: err = %v", err)
+	}
+	if err := applyResume(setup, loop.ModePlan); err != nil {
+		t.Fatalf("applyResume: %v", err)
+	}
+	if setup.lp.Resume == nil || setup.lp.Resume.Iteration != 4 {
+		t.Errorf("Resume = %+v, want the iteration 4 checkpoint", setup.lp.Resume)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+)
+
+// fleetCmd implements `ralph fleet [spec...]`.
+func fleetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet [spec...]",
+		Short: "Build several specs in parallel worktrees without the TUI",
+		Long: `Launch a build agent in its own worktree for each selected spec and stream
+their output, prefixed by branch, until every agent has finished.
+
+Specs are the ones named on the command line, or all specs when none are
+named, narrowed by --glob and --status. Agents beyond max_parallel are queued.
+Completed agents are merged when auto-merge is enabled and tests pass.
+
+Ctrl+C stops every agent after its current iteration; press it again to
+kill them. Exits non-zero if any agent failed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			glob, _ := cmd.Flags().GetString("glob")
+			statuses, _ := cmd.Flags().GetStringSlice("status")
+			max, _ := cmd.Flags().GetInt("max")
+			parallel, _ := cmd.Flags().GetInt("parallel")
+			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
+
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+
+			wtr, cfg := worktreeOps(dir)
+			if err := wtr.Detect(); err != nil {
+				return err
+			}
+			if err := checkSandbox(cfg); err != nil {
+				return err
+			}
+
+			all, err := spec.List(dir)
+			if err != nil {
+				return fmt.Errorf("list specs: %w", err)
+			}
+			specs, err := selectFleetSpecs(all, args, glob, statuses)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel, stopCh := signalContextGraceful()
+			defer cancel()
+
+			orch := orchestrator.New(cfg, wtr)
+			orch.StatePath = orchestrator.StatePath(dir)
+			if parallel > 0 {
+				orch.MaxParallel = parallel
+			}
+			if cmd.Flags().Changed("auto-merge") {
+				orch.AutoMerge, _ = cmd.Flags().GetBool("auto-merge")
+			}
+			return runFleet(ctx, stopCh, orch, specs, fleetOptions{
+				maxIterations: max,
+				formatter:     lineFormatter{color: !noColor},
+			}, os.Stdout)
+		},
+	}
+	cmd.Flags().String("glob", "", "only specs whose name matches this pattern (e.g. \"00*\")")
+	cmd.Flags().StringSlice("status", nil, "only specs with these statuses (e.g. planned,tasked)")
+	cmd.Flags().Int("max", 0, "override max iterations per agent (0 = use config)")
+	cmd.Flags().Int("parallel", 0, "override [worktree] max_parallel (0 = use config)")
+	cmd.Flags().Bool("auto-merge", false, "merge completed agents whose tests pass (default: [worktree] auto_merge)")
+	return cmd
+}
+
+// fleetStatuses are the spec statuses accepted by --status.
+var fleetStatuses = []spec.Status{
+	spec.StatusNotStarted, spec.StatusInProgress, spec.StatusDone,
+	spec.StatusSpecified, spec.StatusPlanned, spec.StatusTasked,
+}
+
+// selectFleetSpecs picks the specs to run: the named ones (in argument order)
+// or all of them, filtered by a glob on the spec name and by status.
+func selectFleetSpecs(all []spec.SpecFile, names []string, glob string, statuses []string) ([]spec.SpecFile, error) {
+	specs := all
+	if len(names) > 0 {
+		var err error
+		if specs, err = resolveLaunchSpecs(all, names); err != nil {
+			return nil, err
+		}
+	}
+
+	if glob != "" {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid --glob %q: %w", glob, err)
+		}
+	}
+	want := make(map[spec.Status]bool, len(statuses))
+	for _, s := range statuses {
+		st := spec.Status(strings.TrimSpace(s))
+		valid := false
+		for _, known := range fleetStatuses {
+			if st == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			names := make([]string, len(fleetStatuses))
+			for i, known := range fleetStatuses {
+				names[i] = string(known)
+			}
+			return nil, fmt.Errorf("invalid --status %q (want one of %s)", s, strings.Join(names, ", "))
+		}
+		want[st] = true
+	}
+
+	var selected []spec.SpecFile
+	for _, sf := range specs {
+		if glob != "" {
+			if ok, _ := path.Match(glob, sf.Name); !ok {
+				continue
+			}
+		}
+		if len(want) > 0 && !want[sf.Status] {
+			continue
+		}
+		selected = append(selected, sf)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no specs selected")
+	}
+	return selected, nil
+}
+
+// fleetOptions configures runFleet.
+type fleetOptions struct {
+	maxIterations int // per agent; 0 = use config
+	formatter     lineFormatter
+}
+
+// runFleet enqueues a build agent per spec (branch = spec name) and streams
+// the orchestrator's tagged events to w, each line prefixed by its branch,
+// until every agent has finished. Closing stopCh stops all agents after their
+// current iteration; cancelling ctx kills them. Either one empties the queue.
+//
+// Prints a summary table at the end and returns an error if any agent failed.
+func runFleet(ctx context.Context, stopCh <-chan struct{}, orch *orchestrator.Orchestrator, specs []spec.SpecFile, opts fleetOptions, w io.Writer) error {
+	branches := make([]string, 0, len(specs))
+	for _, sf := range specs {
+		branches = append(branches, sf.Name)
+	}
+	prefix := newBranchPrefixer(branches, opts.formatter.color)
+
+	var launched, failed []string
+	for _, sf := range specs {
+		queued, err := orch.Enqueue(ctx, sf.Name, sf.Name, sf.Dir, loop.ModeBuild, opts.maxIterations)
+		switch {
+		case err != nil:
+			_, _ = fmt.Fprintf(w, "%s%v\n", prefix.prefix(sf.Name), err)
+			failed = append(failed, sf.Name)
+			continue
+		case queued:
+			_, _ = fmt.Fprintf(w, "%sQueued %s\n", prefix.prefix(sf.Name), sf.Name)
+		default:
+			_, _ = fmt.Fprintf(w, "%sLaunched %s\n", prefix.prefix(sf.Name), sf.Name)
+		}
+		launched = append(launched, sf.Name)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		orch.Wait()
+		close(done)
+	}()
+
+	ctxDone := ctx.Done()
+	for waiting := true; waiting; {
+		select {
+		case ev := <-orch.MergedEvents:
+			_, _ = fmt.Fprintln(w, prefix.prefix(ev.Branch)+opts.formatter.format(ev.Entry))
+		case <-stopCh:
+			stopCh = nil
+			orch.StopAll()
+		case <-ctxDone:
+			ctxDone = nil
+			orch.StopAll()
+		case <-done:
+			waiting = false
+		}
+	}
+	// Flush events forwarded just before the last agent finished.
+	for drained := false; !drained; {
+		select {
+		case ev := <-orch.MergedEvents:
+			_, _ = fmt.Fprintln(w, prefix.prefix(ev.Branch)+opts.formatter.format(ev.Entry))
+		default:
+			drained = true
+		}
+	}
+
+	agents := make([]orchestrator.WorktreeAgent, 0, len(launched))
+	for _, b := range launched {
+		a, ok := orch.AgentSnapshot(b)
+		if !ok {
+			continue
+		}
+		agents = append(agents, a)
+		if a.State == orchestrator.StateFailed || a.State == orchestrator.StateMergeFailed {
+			failed = append(failed, b)
+		}
+	}
+	_, _ = fmt.Fprint(w, "\n"+formatFleetSummary(agents))
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d agent(s) failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// fleetPalette holds the branch prefix colours, assigned in spec order.
+var fleetPalette = []lipgloss.Color{
+	"#7D56F4", "#4ECDC4", "#FFD93D", "#FF6B6B",
+	"#6BCB77", "#4D96FF", "#F38BA8", "#FAB387",
+}
+
+// branchPrefixer renders the "[branch] " prefix of fleet output lines, padded
+// to the longest branch so messages line up.
+type branchPrefixer struct {
+	color  bool
+	width  int
+	styles map[string]lipgloss.Style
+}
+
+func newBranchPrefixer(branches []string, color bool) branchPrefixer {
+	p := branchPrefixer{color: color, styles: make(map[string]lipgloss.Style, len(branches))}
+	for i, b := range branches {
+		p.width = max(p.width, len(b))
+		p.styles[b] = lipgloss.NewStyle().Bold(true).Foreground(fleetPalette[i%len(fleetPalette)])
+	}
+	return p
+}
+
+func (p branchPrefixer) prefix(branch string) string {
+	tag := fmt.Sprintf("%-*s", p.width+2, "["+branch+"]")
+	if p.color {
+		if style, ok := p.styles[branch]; ok {
+			tag = style.Render(tag)
+		}
+	}
+	return tag + " "
+}
+
+// formatFleetSummary renders the end-of-run table of agent outcomes.
+func formatFleetSummary(agents []orchestrator.WorktreeAgent) string {
+	if len(agents) == 0 {
+		return "No agents ran.\n"
+	}
+	var b []byte
+	b = append(b, "Fleet summary\n─────────────\n"...)
+	b = fmt.Appendf(b, "  %-30s  %-12s  %5s  %9s  %8s\n", "BRANCH", "STATE", "ITER", "COST", "TIME")
+	var total float64
+	for _, a := range agents {
+		elapsed := "-"
+		if !a.StartedAt.IsZero() && !a.FinishedAt.IsZero() {
+			elapsed = a.FinishedAt.Sub(a.StartedAt).Round(time.Second).String()
+		}
+		b = fmt.Appendf(b, "  %-30s  %-12s  %5d  %9s  %8s", a.Branch, a.State, a.Iterations, fmt.Sprintf("$%.4f", a.TotalCost), elapsed)
+		if a.Error != nil {
+			b = fmt.Appendf(b, "  %v", a.Error)
+		}
+		b = append(b, '\n')
+		total += a.TotalCost
+	}
+	b = fmt.Appendf(b, "  %-30s  %-12s  %5s  %9s\n", "", "", "", fmt.Sprintf("$%.4f", total))
+	return string(b)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+)
+
+// TestFleetCmd_DetectFails covers fleetCmd.RunE up to the Detect() error,
+// which is always hit where worktrunk is not installed.
+func TestFleetCmd_DetectFails(t *testing.T) {
+	t.Setenv("PATH", "")
+	t.Chdir(t.TempDir())
+	cmd := fleetCmd()
+	if err := cmd.RunE(cmd, nil); err == nil {
+		t.Fatal("expected error when worktrunk not on PATH")
+	}
+}
+
+func TestSelectFleetSpecs(t *testing.T) {
+	all := []spec.SpecFile{
+		{Name: "001-core", Status: spec.StatusTasked},
+		{Name: "002-api", Status: spec.StatusPlanned},
+		{Name: "010-ui", Status: spec.StatusTasked},
+	}
+	names := func(specs []spec.SpecFile) string {
+		var n []string
+		for _, sf := range specs {
+			n = append(n, sf.Name)
+		}
+		return strings.Join(n, ",")
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		glob     string
+		statuses []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "all", want: "001-core,002-api,010-ui"},
+		{name: "explicit list keeps order", args: []string{"010-ui", "001-core"}, want: "010-ui,001-core"},
+		{name: "glob", glob: "00*", want: "001-core,002-api"},
+		{name: "status", statuses: []string{"tasked"}, want: "001-core,010-ui"},
+		{name: "glob and status", glob: "00*", statuses: []string{"tasked", "planned"}, want: "001-core,002-api"},
+		{name: "list and status", args: []string{"002-api", "010-ui"}, statuses: []string{"tasked"}, want: "010-ui"},
+		{name: "unknown spec", args: []string{"999-x"}, wantErr: true},
+		{name: "bad glob", glob: "[", wantErr: true},
+		{name: "bad status", statuses: []string{"finished"}, wantErr: true},
+		{name: "nothing selected", glob: "9*", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectFleetSpecs(all, tt.args, tt.glob, tt.statuses)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && names(got) != tt.want {
+				t.Errorf("selected %s, want %s", names(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchPrefixer(t *testing.T) {
+	p := newBranchPrefixer([]string{"001-a", "002-longer"}, false)
+	if got, want := p.prefix("001-a"), "[001-a]      "; got != want {
+		t.Errorf("prefix = %q, want %q", got, want)
+	}
+	if got := p.prefix("002-longer"); got != "[002-longer] " {
+		t.Errorf("prefix = %q", got)
+	}
+	colored := newBranchPrefixer([]string{"001-a"}, true)
+	if got := colored.prefix("001-a"); !strings.Contains(got, "[001-a]") {
+		t.Errorf("colored prefix %q lost the branch name", got)
+	}
+}
+
+func TestFormatFleetSummary(t *testing.T) {
+	if got := formatFleetSummary(nil); got != "No agents ran.\n" {
+		t.Errorf("empty summary = %q", got)
+	}
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	got := formatFleetSummary([]orchestrator.WorktreeAgent{
+		{Branch: "001-a", State: orchestrator.StateMerged, Iterations: 4, TotalCost: 0.5, StartedAt: start, FinishedAt: start.Add(90 * time.Second)},
+		{Branch: "002-b", State: orchestrator.StateFailed, Iterations: 1, TotalCost: 0.25, Error: errors.New("claude crashed")},
+	})
+	for _, want := range []string{"BRANCH", "001-a", "merged", "$0.5000", "1m30s", "002-b", "failed", "claude crashed", "$0.7500"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunFleet_GracefulStop(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Regent.Enabled = false
+	cfg.Worktree.MaxParallel = 1
+	orch := orchestrator.New(&cfg, launchTestOps{dir: t.TempDir()})
+
+	stopCh := make(chan struct{})
+	close(stopCh)
+	var out strings.Builder
+	specs := []spec.SpecFile{{Name: "001-a"}, {Name: "002-b"}, {Name: "003-c"}}
+	_ = runFleet(context.Background(), stopCh, orch, specs, fleetOptions{}, &out)
+
+	if len(orch.Queued()) != 0 {
+		t.Errorf("graceful stop should empty the queue, got %d entries", len(orch.Queued()))
+	}
+	if !strings.Contains(out.String(), "Fleet summary") {
+		t.Errorf("output missing summary:\n%s", out.String())
+	}
+}
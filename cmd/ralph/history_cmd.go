@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/spf13/cobra"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+	"github.com/LISSConsulting/RalphSpec/internal/tui"
+)
+
+// historyCmd implements `ralph history`: list a session's iterations and,
+// with --tools, how the agent used its tools in each.
+func historyCmd() *cobra.Command {
+	var tools bool
+	cmd := &cobra.Command{
+		Use:   "history [session-id|latest]",
+		Short: "List a session's iterations, optionally with tool usage",
+		Long: `List the iterations of a session from .ralph/logs (the latest by default):
+mode, result, cost and duration. Commits whose Ralph-Session and
+Ralph-Iteration trailers (see [git] commit_policy) name the session are
+listed under their iteration. --tools adds tool calls per iteration,
+flags iterations that edited files without running tests, and ranks the
+most-touched files and most-run Bash commands.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := "latest"
+			if len(args) == 1 {
+				id = args[0]
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+			path, err := findSession(filepath.Join(dir, ".ralph", "logs"), id)
+			if err != nil {
+				return err
+			}
+			s, err := store.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = s.Close() }()
+			sum, _ := s.SessionSummary()
+			iters, _ := s.Iterations()
+			fmt.Print(formatHistory(sum, iters, iterationCommits(git.NewRunner(dir), sum.SessionID)))
+			if tools {
+				iterTools, err := s.IterationTools()
+				if err != nil {
+					return err
+				}
+				noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
+				fmt.Println()
+				for _, line := range tui.RenderTools(iterTools, nil, 100) {
+					if noColor {
+						line = ansi.Strip(line)
+					}
+					fmt.Println(line)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&tools, "tools", false, "show tool usage per iteration, top files and top commands")
+	return cmd
+}
+
+// iterationCommits returns the commits on HEAD that carry the session's
+// Ralph-Session trailer, keyed by their Ralph-Iteration trailer. Returns nil
+// outside a git repository or when the commit policy never tagged a commit.
+func iterationCommits(gitRunner *git.Runner, session string) map[int][]git.Commit {
+	if session == "" {
+		return nil
+	}
+	commits, err := gitRunner.CommitsWithTrailer(loop.TrailerSession, session)
+	if err != nil {
+		return nil
+	}
+	var byIter map[int][]git.Commit
+	for _, c := range commits {
+		n, convErr := strconv.Atoi(git.ParseTrailers(c.Message)[loop.TrailerIteration])
+		if convErr != nil {
+			continue
+		}
+		if byIter == nil {
+			byIter = make(map[int][]git.Commit)
+		}
+		byIter[n] = append(byIter[n], c)
+	}
+	return byIter
+}
+
+// formatHistory renders a session header and one line per iteration, each
+// followed by the commits mapped to it by iterationCommits.
+func formatHistory(sum store.SessionSummary, iters []store.IterationSummary, commits map[int][]git.Commit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session %s", sum.SessionID)
+	if sum.Branch != "" {
+		fmt.Fprintf(&b, " on %s", sum.Branch)
+	}
+	fmt.Fprintf(&b, " — started %s — %d iterations — $%.2f\n",
+		sum.StartedAt.Local().Format("2006-01-02 15:04"), sum.Iterations, sum.TotalCost)
+	if len(iters) == 0 {
+		b.WriteString("No completed iterations\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "  %-4s %-6s %-18s %8s %9s  %s\n", "#", "Mode", "Result", "Cost", "Duration", "Commit")
+	for _, it := range iters {
+		dur := time.Duration(it.Duration * float64(time.Second)).Round(time.Second)
+		line := fmt.Sprintf("  %-4d %-6s %-18s %8s %9s  %s", it.Number, it.Mode, it.Subtype,
+			fmt.Sprintf("$%.2f", it.CostUSD), dur, it.Commit)
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+		for _, c := range commits[it.Number] {
+			fmt.Fprintf(&b, "       %s %s\n", shortCommit(c.SHA), c.Subject())
+		}
+	}
+	return b.String()
+}
+
+// shortCommit abbreviates a full commit SHA to 7 characters.
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+func TestHistoryCmd(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeReportSession(t, dir, "100-1")
+
+	cmd := historyCmd()
+	var err error
+	out := captureStdout(func() { err = cmd.RunE(cmd, nil) })
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if !strings.Contains(out, "Session 100-1 on 001-auth") || !strings.Contains(out, "success") || !strings.Contains(out, "$0.42") {
+		t.Errorf("history output:\n%s", out)
+	}
+	if strings.Contains(out, "Most-touched files") {
+		t.Errorf("tool usage needs --tools:\n%s", out)
+	}
+
+	cmd = historyCmd()
+	_ = cmd.Flags().Set("tools", "true")
+	out = captureStdout(func() { err = cmd.RunE(cmd, []string{"100-1"}) })
+	if err != nil {
+		t.Fatalf("history --tools: %v", err)
+	}
+	for _, want := range []string{"Session  1 calls · 1 edits · 0 test runs", "#1", "Edit 1", "edits, no tests", "Most-touched files"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("history --tools missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHistoryCmd_TrailerCommits(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	initGitRepoOnBranch(t, dir, "001-auth")
+	for _, msg := range []string{
+		"feat(auth): add login\n\nRalph-Iteration: 1\nRalph-Session: 100-1",
+		"fix(auth): other session\n\nRalph-Iteration: 1\nRalph-Session: 99-1",
+		"docs: mentions Ralph-Session: 100-1 outside the trailers",
+	} {
+		c := exec.Command("git", "commit", "--allow-empty", "-m", msg)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, out)
+		}
+	}
+	writeReportSession(t, dir, "100-1")
+
+	cmd := historyCmd()
+	var err error
+	out := captureStdout(func() { err = cmd.RunE(cmd, nil) })
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	lines := strings.Split(out, "\n")
+	var iterLine int
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "1 ") {
+			iterLine = i
+		}
+	}
+	if iterLine == 0 || iterLine+1 >= len(lines) || !strings.HasSuffix(lines[iterLine+1], " feat(auth): add login") {
+		t.Errorf("commit not listed under iteration 1:\n%s", out)
+	}
+	for _, unwanted := range []string{"other session", "outside the trailers"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("history lists a commit it should not (%q):\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestHistoryCmd_NoSessions(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cmd := historyCmd()
+	if err := cmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), "no session logs") {
+		t.Errorf("history without logs: %v", err)
+	}
+}
+
+func TestFormatHistory_Empty(t *testing.T) {
+	out := formatHistory(store.SessionSummary{SessionID: "1-1", StartedAt: time.Now()}, nil, nil)
+	if !strings.Contains(out, "Session 1-1 —") || !strings.Contains(out, "No completed iterations") {
+		t.Errorf("formatHistory = %q", out)
+	}
+}
@@ -44,6 +44,7 @@ func rootCmd() *cobra.Command {
 					fmt.Fprintln(os.Stderr, style.Render(msg))
 				}
 			}
+			agentScript, _ = cmd.Root().PersistentFlags().GetString("agent-script")
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -53,6 +54,8 @@ func rootCmd() *cobra.Command {
 
 	root.PersistentFlags().Bool("no-tui", false, "disable TUI, use plain text output")
 	root.PersistentFlags().Bool("no-color", false, "disable color output (plain text only)")
+	root.PersistentFlags().String("agent-script", "", "run loops against a scripted agent instead of Claude (for testing)")
+	_ = root.PersistentFlags().MarkHidden("agent-script")
 
 	root.AddCommand(
 		// Spec kit workflow commands
@@ -66,10 +69,16 @@ func rootCmd() *cobra.Command {
 		loopCmd(),
 		// Worktree management
 		worktreeCmd(),
+		fleetCmd(),
 		// Project management
 		statusCmd(),
 		initCmd(),
 		specCmd(),
+		prCmd(),
+		steerCmd(),
+		costCmd(),
+		reportCmd(),
+		historyCmd(),
 	)
 
 	return root
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/forge"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// prCmd implements `ralph pr`: open (or refresh) a pull request for the
+// active spec's branch with a body generated from spec.md, task progress,
+// and the session logs.
+func prCmd() *cobra.Command {
+	var specFlag string
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Open or update a pull request for the active spec",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load("")
+			if err != nil {
+				return err
+			}
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("config validation: %w", err)
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+			if base, _ := cmd.Flags().GetString("base"); base != "" {
+				cfg.Forge.Base = base
+			}
+			if draft, _ := cmd.Flags().GetBool("draft"); draft {
+				cfg.Forge.Draft = true
+			}
+
+			gitRunner := git.NewRunner(dir)
+			pr, report, err := preparePullRequest(cfg, gitRunner, dir, specFlag)
+			if err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				fmt.Printf("Title: %s\nHead:  %s\nBase:  %s\n\n%s", pr.Title, pr.Head, pr.Base, pr.Body)
+				return nil
+			}
+
+			ctx, cancel := signalContext()
+			defer cancel()
+			msg, err := publishPullRequest(ctx, cfg, gitRunner, pr, report)
+			if err != nil {
+				return err
+			}
+			fmt.Println(msg)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&specFlag, "spec", "", "spec directory name (e.g. 004-my-feature)")
+	cmd.Flags().String("base", "", "target branch (default: forge.base)")
+	cmd.Flags().Bool("draft", false, "open the pull request as a draft")
+	cmd.Flags().Bool("dry-run", false, "print the generated title and body without contacting the forge")
+	return cmd
+}
+
+// preparePullRequest resolves the active spec for the current branch and
+// builds the pull request content from spec.md, tasks.md, and every session
+// log recorded on that branch.
+func preparePullRequest(cfg *config.Config, gitRunner *git.Runner, dir, specFlag string) (forge.PullRequest, forge.Report, error) {
+	branch, err := gitRunner.CurrentBranch()
+	if err != nil {
+		return forge.PullRequest{}, forge.Report{}, err
+	}
+	if branch == "" {
+		return forge.PullRequest{}, forge.Report{}, fmt.Errorf("pr: not on a branch (detached HEAD?)")
+	}
+	as, err := spec.Resolve(dir, specFlag, branch)
+	if err != nil {
+		return forge.PullRequest{}, forge.Report{}, err
+	}
+
+	report, err := buildPRReport(dir, branch, as)
+	if err != nil {
+		return forge.PullRequest{}, forge.Report{}, err
+	}
+	pr := forge.PullRequest{
+		Title: report.Title(),
+		Body:  report.Body(),
+		Head:  branch,
+		Base:  cfg.Forge.Base,
+		Draft: cfg.Forge.Draft,
+	}
+	if pr.Head == pr.Base {
+		return forge.PullRequest{}, forge.Report{}, fmt.Errorf("pr: branch %s is the base branch", branch)
+	}
+	return pr, report, nil
+}
+
+// buildPRReport collects the inputs for the pull request body. Only session
+// logs whose recorded branch matches branch are included.
+func buildPRReport(dir, branch string, as spec.ActiveSpec) (forge.Report, error) {
+	report := forge.Report{SpecName: as.Name}
+	if rel, err := filepath.Rel(dir, as.Dir); err == nil {
+		report.SpecPath = filepath.ToSlash(rel)
+	}
+	if data, err := os.ReadFile(filepath.Join(as.Dir, "spec.md")); err == nil {
+		report.SpecText = string(data)
+	}
+	tasks, err := spec.ReadTaskProgress(as.Dir)
+	if err != nil {
+		return forge.Report{}, err
+	}
+	report.Tasks = tasks
+
+	paths, err := store.Sessions(filepath.Join(dir, ".ralph", "logs"))
+	if err != nil {
+		return forge.Report{}, err
+	}
+	for _, path := range paths {
+		s, openErr := store.Open(path)
+		if openErr != nil {
+			fmt.Fprintf(os.Stderr, "ralph: skipping session log: %v\n", openErr)
+			continue
+		}
+		sum, _ := s.SessionSummary()
+		iters, _ := s.Iterations()
+		_ = s.Close()
+		if sum.Branch != branch || len(iters) == 0 {
+			continue
+		}
+		report.Sessions = append(report.Sessions, forge.SessionReport{ID: sum.SessionID, Iterations: iters})
+	}
+	return report, nil
+}
+
+// publishPullRequest pushes the head branch (see pushPRHead) and creates or updates its pull
+// request. When an existing pull request is updated, a progress comment is
+// added so reviewers are notified. Returns a one-line status message.
+func publishPullRequest(ctx context.Context, cfg *config.Config, gitRunner *git.Runner, pr forge.PullRequest, report forge.Report) (string, error) {
+	repo := cfg.Forge.Repo
+	if repo == "" {
+		remote, err := gitRunner.RemoteURL()
+		if err != nil {
+			return "", fmt.Errorf("pr: resolve repository: %w", err)
+		}
+		repo = forge.RepoFromRemote(remote)
+	}
+	f, err := forge.New(cfg.Forge, repo)
+	if err != nil {
+		return "", err
+	}
+
+	if err := pushPRHead(cfg, gitRunner, pr.Head); err != nil {
+		return "", err
+	}
+
+	result, created, err := forge.Publish(ctx, f, pr, cfg.Forge.Labels)
+	if err != nil {
+		return "", err
+	}
+	if created {
+		return fmt.Sprintf("Opened pull request #%d: %s", result.Number, result.URL), nil
+	}
+
+	comment := fmt.Sprintf("Description refreshed by ralph: %d/%d tasks complete, $%.2f total cost.",
+		report.Tasks.Done, report.Tasks.Total, report.TotalCost())
+	if err := f.AddComment(ctx, result.Number, comment); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Updated pull request #%d: %s", result.Number, result.URL), nil
+}
+
+// pushPRHead pushes the pull request's head branch with the same guards as
+// the loop's own pushes: protected branches and pushes that would need
+// --force are refused.
+func pushPRHead(cfg *config.Config, gitRunner *git.Runner, head string) error {
+	if cfg.Git.IsProtected(head) {
+		return fmt.Errorf("pr: refusing to push protected branch %s (git.protected_branches)", head)
+	}
+	if gitRunner.NeedsForcePush(head) {
+		return fmt.Errorf("pr: refusing to push %s: origin/%s is not an ancestor of HEAD and ralph never force-pushes", head, head)
+	}
+	if err := gitRunner.Push(head); err != nil {
+		return fmt.Errorf("pr: %w", err)
+	}
+	return nil
+}
+
+// specCompletePRHook returns a loop.OnSpecComplete hook that opens or
+// updates the pull request for the spec the loop just finished. With
+// dryRunPush (--dry-run-push) it only reports what it would do.
+func specCompletePRHook(cfg *config.Config, dir, specName string, dryRunPush bool) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		gitRunner := git.NewRunner(dir)
+		pr, report, err := preparePullRequest(cfg, gitRunner, dir, specName)
+		if err != nil {
+			return "", err
+		}
+		if dryRunPush {
+			return fmt.Sprintf("Dry run: would push %s and open a pull request into %s: %s", pr.Head, pr.Base, pr.Title), nil
+		}
+		return publishPullRequest(ctx, cfg, gitRunner, pr, report)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/forge"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// writeSessionLog appends one completed iteration on branch to a new session
+// log under dir/.ralph/logs.
+func writeSessionLog(t *testing.T, dir, branch string, cost float64) {
+	t.Helper()
+	s, err := store.NewJSONL(filepath.Join(dir, ".ralph", "logs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+	now := time.Now()
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 1, Mode: "build", Branch: branch},
+		{Kind: loop.LogIterComplete, Timestamp: now, Iteration: 1, CostUSD: cost, Subtype: "success", Duration: 12},
+	} {
+		if err := s.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuildPRReport(t *testing.T) {
+	dir := t.TempDir()
+	writeExecTestFile(t, dir, "specs/007-x/spec.md", "# Feature Specification: Thing\n")
+	writeExecTestFile(t, dir, "specs/007-x/tasks.md", "- [x] a\n- [ ] b\n")
+	writeSessionLog(t, dir, "007-x", 0.4)
+	writeExecTestFile(t, dir, ".ralph/logs/0-other.jsonl",
+		`{"kind":1,"iteration":1,"branch":"main"}`+"\n")
+
+	as := spec.ActiveSpec{Name: "007-x", Dir: filepath.Join(dir, "specs", "007-x")}
+	report, err := buildPRReport(dir, "007-x", as)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SpecPath != "specs/007-x" {
+		t.Errorf("SpecPath = %q", report.SpecPath)
+	}
+	if report.Title() != "Thing" {
+		t.Errorf("Title = %q", report.Title())
+	}
+	if report.Tasks.Done != 1 || report.Tasks.Total != 2 {
+		t.Errorf("Tasks = %+v", report.Tasks)
+	}
+	if len(report.Sessions) != 1 {
+		t.Fatalf("want 1 session on branch, got %d", len(report.Sessions))
+	}
+	if report.TotalCost() != 0.4 {
+		t.Errorf("TotalCost = %v", report.TotalCost())
+	}
+}
+
+func TestPreparePullRequest_OnBaseBranch(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "main")
+	writeExecTestFile(t, dir, "specs/main/spec.md", "# X\n")
+
+	defaults := config.Defaults()
+	cfg := &defaults
+	_, _, err := preparePullRequest(cfg, git.NewRunner(dir), dir, "main")
+	if err == nil || !strings.Contains(err.Error(), "base branch") {
+		t.Errorf("expected base-branch error, got %v", err)
+	}
+}
+
+func TestPrCmd_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "007-x")
+	t.Chdir(dir)
+	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
+	writeExecTestFile(t, dir, "specs/007-x/spec.md", "# Feature Specification: Thing\n")
+
+	cmd := prCmd()
+	cmd.SetArgs([]string{"--dry-run", "--base", "develop"})
+	out := captureStdout(func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	for _, want := range []string{"Title: Thing", "Head:  007-x", "Base:  develop", "Implements spec `007-x`"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPublishPullRequest(t *testing.T) {
+	// Working repo on a feature branch with a bare origin to push to.
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "007-x")
+	remote := t.TempDir()
+	for _, args := range [][]string{{"git", "init", "--bare", remote}, {"git", "remote", "add", "origin", remote}} {
+		c := exec.Command(args[0], args[1:]...)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+	}
+
+	var existing bool
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		_, _ = io.Copy(io.Discard, r.Body)
+		switch {
+		case r.Method == http.MethodGet:
+			if existing {
+				_, _ = io.WriteString(w, `[{"number":9,"html_url":"https://example/pr/9"}]`)
+			} else {
+				_, _ = io.WriteString(w, `[]`)
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/pulls":
+			_ = json.NewEncoder(w).Encode(map[string]any{"number": 9, "html_url": "https://example/pr/9"})
+		default:
+			_, _ = io.WriteString(w, `{"number":9,"html_url":"https://example/pr/9"}`)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("GITHUB_TOKEN", "tok")
+	defaults := config.Defaults()
+	cfg := &defaults
+	cfg.Forge = config.ForgeConfig{Provider: "github", URL: srv.URL, Repo: "o/r", Base: "main", Labels: []string{"ralph"}}
+	pr := forge.PullRequest{Title: "T", Body: "B", Head: "007-x", Base: "main"}
+	gitRunner := git.NewRunner(dir)
+
+	msg, err := publishPullRequest(context.Background(), cfg, gitRunner, pr, forge.Report{})
+	if err != nil {
+		t.Fatalf("publish (create): %v", err)
+	}
+	if msg != "Opened pull request #9: https://example/pr/9" {
+		t.Errorf("create message = %q", msg)
+	}
+	if !gitRunner.HasRemoteBranch("007-x") {
+		t.Error("expected branch to be pushed to origin")
+	}
+
+	existing = true
+	calls = nil
+	msg, err = publishPullRequest(context.Background(), cfg, gitRunner, pr, forge.Report{})
+	if err != nil {
+		t.Fatalf("publish (update): %v", err)
+	}
+	if !strings.HasPrefix(msg, "Updated pull request #9") {
+		t.Errorf("update message = %q", msg)
+	}
+	want := "GET /repos/o/r/pulls,PATCH /repos/o/r/pulls/9,PUT /repos/o/r/issues/9/labels,POST /repos/o/r/issues/9/comments"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s\nwant    %s", got, want)
+	}
+}
+
+func TestPublishPullRequest_NoToken(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "007-x")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	defaults := config.Defaults()
+	cfg := &defaults
+	cfg.Forge = config.ForgeConfig{Provider: "github", Repo: "o/r"}
+	_, err := publishPullRequest(context.Background(), cfg, git.NewRunner(dir), forge.PullRequest{Head: "007-x"}, forge.Report{})
+	if err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Errorf("expected missing-token error, got %v", err)
+	}
+}
+
+func TestSpecCompletePRHook_DryRunPush(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "007-x")
+	remote := t.TempDir()
+	for _, args := range [][]string{{"git", "init", "--bare", remote}, {"git", "remote", "add", "origin", remote}} {
+		c := exec.Command(args[0], args[1:]...)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+	}
+	writeExecTestFile(t, dir, "specs/007-x/spec.md", "# Feature Specification: Thing\n")
+
+	// No forge server: a dry run must not contact it.
+	defaults := config.Defaults()
+	cfg := &defaults
+	cfg.Forge = config.ForgeConfig{Provider: "github", URL: "http://127.0.0.1:1", Repo: "o/r", Base: "main"}
+	msg, err := specCompletePRHook(cfg, dir, "007-x", true)(context.Background())
+	if err != nil {
+		t.Fatalf("hook: %v", err)
+	}
+	if !strings.HasPrefix(msg, "Dry run: would push 007-x and open a pull request into main") {
+		t.Errorf("message = %q", msg)
+	}
+	if git.NewRunner(dir).HasRemoteBranch("007-x") {
+		t.Error("dry run pushed the branch")
+	}
+}
+
+func TestPublishPullRequest_ProtectedHead(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "release/1.0")
+	t.Setenv("GITHUB_TOKEN", "tok")
+
+	defaults := config.Defaults()
+	cfg := &defaults
+	cfg.Forge = config.ForgeConfig{Provider: "github", Repo: "o/r", Base: "main"}
+	pr := forge.PullRequest{Head: "release/1.0", Base: "main"}
+	_, err := publishPullRequest(context.Background(), cfg, git.NewRunner(dir), pr, forge.Report{})
+	if err == nil || !strings.Contains(err.Error(), "protected") {
+		t.Errorf("expected protected-branch refusal, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/report"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// reportCmd implements `ralph report`: render one session log as a
+// Markdown or HTML report, optionally saved under the spec's reports/
+// directory and committed.
+func reportCmd() *cobra.Command {
+	var format, output string
+	var save, commit bool
+	cmd := &cobra.Command{
+		Use:   "report <session-id|latest>",
+		Short: "Render a session report as Markdown or HTML",
+		Long: `Render a self-contained report of one session from .ralph/logs: spec and
+branch, the iteration timeline with result, cost and duration, commits
+produced, Regent actions (rollbacks, retries, hangs), test runs, tool usage
+and each iteration's final message.
+
+The report is printed unless --output names a file. --save writes it to
+specs/<spec>/reports/<session-id>.md (or .html) and --commit also commits
+that file, so the spec keeps a history of how it was built.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "md" && format != "html" {
+				return fmt.Errorf("report: --format must be md or html, got %q", format)
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+			gitRunner := git.NewRunner(dir)
+			r, err := buildSessionReport(dir, args[0], gitRunner)
+			if err != nil {
+				return err
+			}
+			doc := r.Markdown()
+			if format == "html" {
+				doc = r.HTML()
+			}
+
+			if !save && !commit {
+				if output == "" {
+					fmt.Print(doc)
+					return nil
+				}
+				if err := os.WriteFile(output, []byte(doc), 0o644); err != nil {
+					return fmt.Errorf("report: write %s: %w", output, err)
+				}
+				fmt.Printf("Wrote %s\n", output)
+				return nil
+			}
+
+			if r.Spec == "" {
+				return fmt.Errorf("report: session %s has no spec to save the report under", r.SessionID)
+			}
+			path := filepath.Join(dir, "specs", r.Spec, "reports", r.SessionID+"."+format)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("report: %w", err)
+			}
+			if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+				return fmt.Errorf("report: write %s: %w", path, err)
+			}
+			fmt.Printf("Wrote %s\n", path)
+			if commit {
+				msg := fmt.Sprintf("docs(%s): add report for session %s", r.Spec, r.SessionID)
+				if err := gitRunner.CommitPaths(msg, path); err != nil {
+					return fmt.Errorf("report: %w", err)
+				}
+				fmt.Printf("Committed: %s\n", msg)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "md", "report format: md or html")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write the report to this file instead of stdout")
+	cmd.Flags().BoolVar(&save, "save", false, "write the report to specs/<spec>/reports/")
+	cmd.Flags().BoolVar(&commit, "commit", false, "save the report and commit it")
+	return cmd
+}
+
+// buildSessionReport opens the session named id ("latest" for the newest)
+// and builds its report, listing commits with gitRunner.
+func buildSessionReport(dir, id string, gitRunner *git.Runner) (report.Report, error) {
+	path, err := findSession(filepath.Join(dir, ".ralph", "logs"), id)
+	if err != nil {
+		return report.Report{}, err
+	}
+	s, err := store.Open(path)
+	if err != nil {
+		return report.Report{}, err
+	}
+	defer func() { _ = s.Close() }()
+	sum, _ := s.SessionSummary()
+	iters, _ := s.Iterations()
+	entries, err := s.Entries()
+	if err != nil {
+		return report.Report{}, err
+	}
+
+	r := report.Build(sum, iters, entries)
+	if r.Spec == "" && r.Branch != "" {
+		if as, err := spec.Resolve(dir, "", r.Branch); err == nil {
+			r.Spec = as.Name
+		}
+	}
+	r.AddCommits(gitRunner.CommitsBetween)
+	return r, nil
+}
+
+// findSession returns the path of the session log named id in logsDir, or
+// of the newest one for "latest". A unique prefix of a session ID matches.
+func findSession(logsDir, id string) (string, error) {
+	paths, err := store.Sessions(logsDir)
+	if err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no session logs in %s", logsDir)
+	}
+	if id == "latest" {
+		return paths[len(paths)-1], nil
+	}
+	var matches []string
+	for _, p := range paths {
+		name := strings.TrimSuffix(filepath.Base(p), ".jsonl")
+		if name == id {
+			return p, nil
+		}
+		if strings.HasPrefix(name, id) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no session %q in %s", id, logsDir)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("session %q is ambiguous (%d matches)", id, len(matches))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// writeReportSession writes a one-iteration session log named id for spec
+// 001-auth into dir/.ralph/logs.
+func writeReportSession(t *testing.T, dir, id string) {
+	t.Helper()
+	logs := filepath.Join(dir, ".ralph", "logs")
+	if err := os.MkdirAll(logs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	var b strings.Builder
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Iteration: 1, Mode: "build", Branch: "001-auth", Spec: "001-auth", Timestamp: now},
+		{Kind: loop.LogToolUse, Iteration: 1, ToolName: "Edit", ToolInput: "auth.go", Timestamp: now},
+		{Kind: loop.LogText, Message: "All tasks done.", Timestamp: now},
+		{Kind: loop.LogIterComplete, Iteration: 1, CostUSD: 0.42, Subtype: "success", Timestamp: now},
+		{Kind: loop.LogRegent, Message: "Tests failed ❌ — reverting last commit", Timestamp: now},
+	} {
+		data, _ := json.Marshal(e)
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(logs, id+".jsonl"), []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runReportCmd(t *testing.T, args []string, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := reportCmd()
+	for k, v := range flags {
+		if err := cmd.Flags().Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var err error
+	out := captureStdout(func() { err = cmd.RunE(cmd, args) })
+	return out, err
+}
+
+func TestReportCmd_Markdown(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeReportSession(t, dir, "100-1")
+	writeReportSession(t, dir, "200-1")
+
+	out, err := runReportCmd(t, []string{"latest"}, nil)
+	if err != nil {
+		t.Fatalf("report latest: %v", err)
+	}
+	for _, want := range []string{"# Ralph session 200-1 — 001-auth", "$0.42", "**failed** Tests failed", "| Edit | 1 |", "> All tasks done."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runReportCmd(t, []string{"10"}, nil)
+	if err != nil || !strings.Contains(out, "Ralph session 100-1") {
+		t.Errorf("a unique prefix should select 100-1: %v\n%s", err, out)
+	}
+}
+
+func TestReportCmd_HTMLOutput(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeReportSession(t, dir, "100-1")
+
+	path := filepath.Join(dir, "out.html")
+	if _, err := runReportCmd(t, []string{"100-1"}, map[string]string{"format": "html", "output": path}); err != nil {
+		t.Fatalf("report --format html: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") || !strings.Contains(string(data), "001-auth") {
+		t.Errorf("html report = %.200s", data)
+	}
+}
+
+func TestReportCmd_SaveAndCommit(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	initGitRepoOnBranch(t, dir, "001-auth")
+	writeReportSession(t, dir, "100-1")
+
+	out, err := runReportCmd(t, []string{"latest"}, map[string]string{"commit": "true"})
+	if err != nil {
+		t.Fatalf("report --commit: %v", err)
+	}
+	path := filepath.Join(dir, "specs", "001-auth", "reports", "100-1.md")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("report not saved: %v", err)
+	}
+	if !strings.Contains(out, "Committed: docs(001-auth): add report for session 100-1") {
+		t.Errorf("output = %q", out)
+	}
+	c := exec.Command("git", "show", "--name-only", "--format=%s", "HEAD")
+	c.Dir = dir
+	show, err := c.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(show), "specs/001-auth/reports/100-1.md") {
+		t.Errorf("HEAD = %s, want the report committed", show)
+	}
+}
+
+func TestReportCmd_Errors(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if _, err := runReportCmd(t, []string{"latest"}, nil); err == nil || !strings.Contains(err.Error(), "no session logs") {
+		t.Errorf("report without logs: %v", err)
+	}
+	writeReportSession(t, dir, "100-1")
+	writeReportSession(t, dir, "100-2")
+	if _, err := runReportCmd(t, []string{"100"}, nil); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("ambiguous prefix: %v", err)
+	}
+	if _, err := runReportCmd(t, []string{"999"}, nil); err == nil {
+		t.Error("unknown session should fail")
+	}
+	if _, err := runReportCmd(t, []string{"latest"}, map[string]string{"format": "pdf"}); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// steerCmd implements `ralph steer`: queue operator guidance for the next
+// iteration of the loop running in this directory, the same queue the TUI's
+// : key writes to.
+func steerCmd() *cobra.Command {
+	var list bool
+	var remove string
+	cmd := &cobra.Command{
+		Use:   "steer [text...]",
+		Short: "Queue guidance for the running loop's next iteration",
+		Long: `Queue a steering note for the loop running in this directory. The note is
+added to the next iteration's prompt under "Operator Guidance" and then
+removed from the queue. Use --list to see queued notes and --remove to
+drop one before it is picked up.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+			q := loop.NewSteerQueue(dir)
+			switch {
+			case remove != "":
+				if err := q.Remove(remove); err != nil {
+					return err
+				}
+				fmt.Printf("Removed steering note %s\n", remove)
+				return nil
+			case list:
+				notes, err := q.Pending()
+				if err != nil {
+					return err
+				}
+				fmt.Print(formatSteerNotes(notes))
+				return nil
+			}
+			if len(args) == 0 {
+				return errors.New("steer: give the note text, or use --list or --remove")
+			}
+			note, err := q.Add(strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Queued steering note %s for the next iteration\n", note.ID)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&list, "list", false, "list queued steering notes")
+	cmd.Flags().StringVar(&remove, "remove", "", "remove the queued note with this ID")
+	return cmd
+}
+
+// formatSteerNotes renders queued notes one per line, oldest first.
+func formatSteerNotes(notes []loop.SteerNote) string {
+	if len(notes) == 0 {
+		return "No steering notes queued\n"
+	}
+	var b strings.Builder
+	for _, n := range notes {
+		fmt.Fprintf(&b, "%s  %s  %s\n", n.ID, n.At.Format("15:04:05"), strings.Join(strings.Fields(n.Text), " "))
+	}
+	return b.String()
+}
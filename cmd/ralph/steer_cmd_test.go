@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+func TestSteerCmd_QueueListRemove(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	cmd := steerCmd()
+	out := captureStdout(func() {
+		if err := cmd.RunE(cmd, []string{"skip", "the", "docs"}); err != nil {
+			t.Fatalf("steer RunE: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Queued steering note") {
+		t.Errorf("output = %q, want queued confirmation", out)
+	}
+	notes, err := loop.NewSteerQueue(dir).Pending()
+	if err != nil || len(notes) != 1 || notes[0].Text != "skip the docs" {
+		t.Fatalf("Pending = %+v, %v; want one note %q", notes, err, "skip the docs")
+	}
+
+	cmd = steerCmd()
+	_ = cmd.Flags().Set("list", "true")
+	out = captureStdout(func() {
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("steer --list: %v", err)
+		}
+	})
+	if !strings.Contains(out, notes[0].ID) || !strings.Contains(out, "skip the docs") {
+		t.Errorf("list output = %q", out)
+	}
+
+	cmd = steerCmd()
+	_ = cmd.Flags().Set("remove", notes[0].ID)
+	captureStdout(func() {
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("steer --remove: %v", err)
+		}
+	})
+	if notes, _ := loop.NewSteerQueue(dir).Pending(); len(notes) != 0 {
+		t.Errorf("note should be removed, still queued: %+v", notes)
+	}
+}
+
+func TestSteerCmd_Errors(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cmd := steerCmd()
+	if err := cmd.RunE(cmd, nil); err == nil {
+		t.Error("steer without text should fail")
+	}
+	cmd = steerCmd()
+	_ = cmd.Flags().Set("remove", "123")
+	if err := cmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), "not queued") {
+		t.Errorf("removing an unknown note: err = %v", err)
+	}
+}
+
+func TestFormatSteerNotes_Empty(t *testing.T) {
+	if got := formatSteerNotes(nil); !strings.Contains(got, "No steering notes") {
+		t.Errorf("formatSteerNotes(nil) = %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
 	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
 	"github.com/LISSConsulting/RalphSpec/internal/regent"
+	"github.com/LISSConsulting/RalphSpec/internal/sandbox"
 	"github.com/LISSConsulting/RalphSpec/internal/spec"
 	"github.com/LISSConsulting/RalphSpec/internal/store"
 	"github.com/LISSConsulting/RalphSpec/internal/tui"
@@ -76,7 +78,8 @@ func runWithRegentTUI(ctx context.Context, lp *loop.Loop, cfg *config.Config, gi
 	lp.PostIteration = rgt.RunPostIterationTests
 
 	specFiles, _ := spec.List(dir)
-	model := tui.New(tuiEvents, sr, cfg.TUI.AccentColor, cfg.Project.Name, dir, specFiles, requestStop, nil)
+	model := tui.New(tuiEvents, sr, cfg.TUI.AccentColor, cfg.Project.Name, dir, specFiles, requestStop, nil).
+		WithSteering(loop.NewSteerQueue(dir))
 	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	// Forward loop events → regent state update → TUI
@@ -188,7 +191,8 @@ func runWithTUIAndState(ctx context.Context, lp *loop.Loop, dir string, gitRunne
 	st.save()
 
 	specFiles, _ := spec.List(dir)
-	model := tui.New(tuiEvents, sr, accentColor, projectName, dir, specFiles, requestStop, nil)
+	model := tui.New(tuiEvents, sr, accentColor, projectName, dir, specFiles, requestStop, nil).
+		WithSteering(loop.NewSteerQueue(dir))
 	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	// Forward loop events → state tracking → TUI
@@ -277,6 +281,13 @@ func (s *stateTracker) trackEntry(entry loop.LogEntry) {
 		s.state.Mode = entry.Mode
 		changed = true
 	}
+	if entry.Kind == loop.LogRateLimit {
+		s.state.RateLimitedUntil = entry.ResetAt
+		changed = true
+	} else if !s.state.RateLimitedUntil.IsZero() && !time.Now().Before(s.state.RateLimitedUntil) {
+		s.state.RateLimitedUntil = time.Time{}
+		changed = true
+	}
 	s.state.LastOutputAt = time.Now()
 	if changed {
 		s.save()
@@ -294,17 +305,20 @@ func (s *stateTracker) finish(err error) {
 }
 
 // loopController implements tui.LoopController for dashboard mode.
-// It starts and stops loop runs in response to TUI key presses (b/p/R/x).
+// It starts, stops, pauses and resumes loop runs in response to TUI key
+// presses (b/p/R/x/space).
 type loopController struct {
 	cfg       *config.Config
 	dir       string
 	gitRunner *git.Runner
 	sw        store.Writer
+	sessionID string // session of sw; names transcripts and commit trailers
 	tuiSend   chan<- loop.LogEntry
 	outerCtx  context.Context
 	mu        sync.Mutex
 	cancel    context.CancelFunc
-	// agent overrides the default claude binary; nil → loop.NewClaudeAgent().
+	pause     chan struct{} // closed by PauseLoop; nil when idle or already pausing
+	// agent overrides the default claude binary; nil → loop.NewClaudeAgentFor(cfg).
 	// Used in tests to inject a fast-failing fake.
 	agent claude.Agent
 }
@@ -319,6 +333,45 @@ func (lc *loopController) IsRunning() bool {
 // StartLoop starts a loop in the given mode ("build", "plan", or "smart").
 // A no-op if a loop is already running.
 func (lc *loopController) StartLoop(mode string) {
+	lc.start(mode, nil)
+}
+
+// PauseLoop asks the running loop to pause after its current iteration.
+// The loop saves a checkpoint that ResumeLoop continues from. No-op if idle.
+func (lc *loopController) PauseLoop() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.pause != nil {
+		close(lc.pause)
+		lc.pause = nil
+	}
+}
+
+// ResumeLoop continues the loop recorded in .ralph/checkpoint.json, whether
+// it was paused in this session or interrupted in an earlier one. No-op if a
+// loop is running.
+func (lc *loopController) ResumeLoop() {
+	if lc.IsRunning() {
+		return
+	}
+	cp, err := loop.LoadCheckpoint(lc.dir)
+	if err != nil {
+		entry := loop.LogEntry{Kind: loop.LogError, Timestamp: time.Now(), Message: err.Error()}
+		if errors.Is(err, loop.ErrNoCheckpoint) {
+			entry.Kind = loop.LogInfo
+			entry.Message = "Nothing to resume: no checkpoint saved"
+		}
+		select {
+		case lc.tuiSend <- entry:
+		default:
+		}
+		return
+	}
+	lc.start(string(cp.Mode), &cp)
+}
+
+// start runs a loop in the background unless one is already running.
+func (lc *loopController) start(mode string, resume *loop.Checkpoint) {
 	lc.mu.Lock()
 	if lc.cancel != nil {
 		lc.mu.Unlock()
@@ -326,9 +379,11 @@ func (lc *loopController) StartLoop(mode string) {
 	}
 	ctx, cancel := context.WithCancel(lc.outerCtx)
 	lc.cancel = cancel
+	pause := make(chan struct{})
+	lc.pause = pause
 	lc.mu.Unlock()
 
-	go lc.runLoop(ctx, mode)
+	go lc.runLoop(ctx, mode, pause, resume)
 }
 
 // StopLoop immediately cancels the running loop. No-op if idle.
@@ -341,33 +396,11 @@ func (lc *loopController) StopLoop() {
 }
 
 // runLoop executes the loop and forwards events to the TUI channel.
-func (lc *loopController) runLoop(ctx context.Context, mode string) {
-	agent := lc.agent
-	if agent == nil {
-		agent = loop.NewClaudeAgent()
-	}
-	lp := &loop.Loop{
-		Agent:  agent,
-		Git:    lc.gitRunner,
-		Config: lc.cfg,
-		Dir:    lc.dir,
-	}
-	loopEvents := make(chan loop.LogEntry, 128)
-	lp.Events = loopEvents
-
-	forwardDone := make(chan struct{})
-	go func() {
-		defer close(forwardDone)
-		for entry := range loopEvents {
-			if lc.sw != nil {
-				_ = lc.sw.Append(entry)
-			}
-			select {
-			case lc.tuiSend <- entry:
-			default:
-			}
-		}
-	}()
+// A resumed loop continues from the checkpoint in resume.
+func (lc *loopController) runLoop(ctx context.Context, mode string, pause <-chan struct{}, resume *loop.Checkpoint) {
+	lp, loopEvents, forwardDone := lc.newLoop()
+	lp.PauseAfter = pause
+	lp.Resume = resume
 
 	var runErr error
 	switch mode {
@@ -378,6 +411,11 @@ func (lc *loopController) runLoop(ctx context.Context, mode string) {
 		info, statErr := os.Stat(planPath)
 		if needsPlanPhase(info, statErr) {
 			runErr = lp.Run(ctx, loop.ModePlan, 0)
+			// A plan phase that paused leaves a paused checkpoint; resuming
+			// it finishes planning, so build does not start here.
+			if cp, err := loop.LoadCheckpoint(lc.dir); runErr == nil && err == nil && cp.Paused {
+				break
+			}
 		}
 		if runErr == nil {
 			runErr = lp.Run(ctx, loop.ModeBuild, 0)
@@ -388,19 +426,123 @@ func (lc *loopController) runLoop(ctx context.Context, mode string) {
 
 	close(loopEvents)
 	<-forwardDone
+	lc.finish()
+
+	_ = runErr
+}
 
+// RunPhase runs a spec-kit phase for the TUI's Specs panel. It shares the
+// running state with StartLoop, so StopLoop cancels it and neither starts
+// while the other runs. A phase that cannot run, including one requested
+// while a loop is running, is reported as a LogError entry carrying the
+// phase name.
+func (lc *loopController) RunPhase(req loop.PhaseRequest) {
 	lc.mu.Lock()
-	lc.cancel = nil
+	if lc.cancel != nil {
+		lc.mu.Unlock()
+		select {
+		case lc.tuiSend <- loop.LogEntry{
+			Kind:      loop.LogError,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("speckit.%s not started: a loop is already running", req.Phase),
+			Phase:     req.Phase,
+		}:
+		default:
+		}
+		return
+	}
+	ctx, cancel := context.WithCancel(lc.outerCtx)
+	lc.cancel = cancel
 	lc.mu.Unlock()
 
-	_ = runErr
+	go lc.runPhase(ctx, req)
+}
+
+func (lc *loopController) runPhase(ctx context.Context, req loop.PhaseRequest) {
+	lp, loopEvents, forwardDone := lc.newLoop()
+	if _, err := lp.RunPhase(ctx, req); err != nil && ctx.Err() == nil {
+		loopEvents <- loop.LogEntry{
+			Kind:      loop.LogError,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+			Phase:     req.Phase,
+		}
+	}
+	close(loopEvents)
+	<-forwardDone
+	lc.finish()
+}
+
+// newLoop returns a Loop whose events are appended to the session log and
+// forwarded to the TUI until loopEvents is closed, after which forwardDone
+// closes.
+func (lc *loopController) newLoop() (lp *loop.Loop, loopEvents chan loop.LogEntry, forwardDone chan struct{}) {
+	agent := lc.agent
+	if agent == nil {
+		agent = loop.NewClaudeAgentFor(lc.cfg)
+	}
+	lp = &loop.Loop{
+		Agent:     agent,
+		Git:       lc.gitRunner,
+		Config:    lc.cfg,
+		Dir:       lc.dir,
+		Steering:  loop.NewSteerQueue(lc.dir),
+		SessionID: lc.sessionID,
+	}
+	loopEvents = make(chan loop.LogEntry, 128)
+	lp.Events = loopEvents
+
+	forwardDone = make(chan struct{})
+	go func() {
+		defer close(forwardDone)
+		for entry := range loopEvents {
+			if lc.sw != nil {
+				_ = lc.sw.Append(entry)
+			}
+			select {
+			case lc.tuiSend <- entry:
+			default:
+			}
+		}
+	}()
+	return lp, loopEvents, forwardDone
+}
+
+// sessionIDOf returns the ID of the session sr records, or "" when there is
+// no session log.
+func sessionIDOf(sr store.Reader) string {
+	if sr == nil {
+		return ""
+	}
+	sum, err := sr.SessionSummary()
+	if err != nil {
+		return ""
+	}
+	return sum.SessionID
+}
+
+// finish marks the controller idle once a loop or phase has returned.
+func (lc *loopController) finish() {
+	lc.mu.Lock()
+	lc.cancel = nil
+	lc.pause = nil
+	lc.mu.Unlock()
 }
 
 // runDashboard launches the TUI in idle (dashboard) state with no loop running.
 // The user can press b/p/R to start a loop and x to stop it.
 // When [worktree] is enabled in config, an Orchestrator is created and wired
-// into the TUI so the W/x/M/D keybinds become active.
+// into the TUI so the W/x/M/D/r keybinds become active. Agents recorded in
+// .ralph/orchestrator.json by a previous session are restored first.
 func runDashboard(ctx context.Context, cfg *config.Config, dir string, sw store.Writer, sr store.Reader) error {
+	if err := checkSandbox(cfg); err != nil {
+		return err
+	}
+	agent, err := newAgent(cfg)
+	if err != nil {
+		return err
+	}
+
 	tuiEvents := make(chan loop.LogEntry, 128)
 	// Note: tuiEvents is intentionally never closed; the TUI exits when user presses q.
 
@@ -412,22 +554,54 @@ func runDashboard(ctx context.Context, cfg *config.Config, dir string, sw store.
 		sw:        sw,
 		tuiSend:   tuiEvents,
 		outerCtx:  ctx,
+		agent:     agent,
+		sessionID: sessionIDOf(sr),
 	}
 
 	specFiles, _ := spec.List(dir)
-	model := tui.New(tuiEvents, sr, cfg.TUI.AccentColor, cfg.Project.Name, dir, specFiles, nil, ctrl)
+	model := tui.New(tuiEvents, sr, cfg.TUI.AccentColor, cfg.Project.Name, dir, specFiles, nil, ctrl).
+		WithSteering(loop.NewSteerQueue(dir))
 
 	// Wire orchestrator when worktree mode is enabled.
 	if cfg.Worktree.Enabled {
-		wtRunner := worktree.NewRunner(dir)
-		wtRunner.WorktreeDir = cfg.Worktree.ResolvedWorktreeDir()
-		if err := wtRunner.Detect(); err == nil {
-			orch := orchestrator.New(cfg, wtRunner)
+		wtOps := worktree.New(cfg.Worktree, dir)
+		if err := wtOps.Detect(); err == nil {
+			orch := orchestrator.New(cfg, wtOps)
+			orch.SessionLog = sw
+			orch.StatePath = orchestrator.StatePath(dir)
+			if resumable, restoreErr := orch.Restore(); restoreErr != nil {
+				fmt.Fprintf(os.Stderr, "ralph: %v\n", restoreErr)
+			} else if len(resumable) > 0 {
+				tuiEvents <- loop.LogEntry{Kind: loop.LogInfo, Message: resumeOffer(resumable)}
+			}
 			model = model.WithOrchestrator(orch)
+		} else if cfg.Worktree.Backend != "git" {
+			fmt.Fprintf(os.Stderr, "ralph: worktree mode disabled: %v\n  set [worktree] backend = \"git\" to run parallel agents without worktrunk\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "ralph: worktree mode disabled: %v\n", err)
 		}
-		// If Detect fails (worktrunk not installed), silently skip worktree mode.
 	}
 
 	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	return finishTUI(program)
 }
+
+// checkSandbox fails fast when [sandbox] is enabled but its runtime is not
+// installed, rather than letting every Claude invocation fail later.
+func checkSandbox(cfg *config.Config) error {
+	if sb := sandbox.New(cfg.Sandbox); sb != nil {
+		return sb.Check()
+	}
+	return nil
+}
+
+// resumeOffer describes restored worktree agents that can be resumed with r
+// in the Worktrees tab.
+func resumeOffer(agents []*orchestrator.WorktreeAgent) string {
+	parts := make([]string, len(agents))
+	for i, a := range agents {
+		parts[i] = fmt.Sprintf("%s (%s)", a.Branch, a.State)
+	}
+	return fmt.Sprintf("Restored %d worktree agent(s) from .ralph/orchestrator.json: %s — press r in the Worktrees tab to resume",
+		len(agents), strings.Join(parts, ", "))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -16,7 +17,9 @@ import (
 	"github.com/LISSConsulting/RalphSpec/internal/config"
 	"github.com/LISSConsulting/RalphSpec/internal/git"
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
 	"github.com/LISSConsulting/RalphSpec/internal/regent"
+	"github.com/LISSConsulting/RalphSpec/internal/sandbox"
 	"github.com/LISSConsulting/RalphSpec/internal/store"
 	"github.com/LISSConsulting/RalphSpec/internal/tui"
 )
@@ -232,6 +235,40 @@ func TestStateTrackerLastOutputAt(t *testing.T) {
 	}
 }
 
+func TestStateTrackerRateLimit(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	st := newStateTracker(dir, "build", git.NewRunner(dir))
+
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	st.trackEntry(loop.LogEntry{Kind: loop.LogRateLimit, ResetAt: until})
+	saved, err := regent.LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.RateLimitedUntil.Equal(until) {
+		t.Errorf("saved RateLimitedUntil = %v, want %v", saved.RateLimitedUntil, until)
+	}
+
+	st.state.RateLimitedUntil = time.Now().Add(-time.Second)
+	st.trackEntry(loop.LogEntry{Kind: loop.LogInfo, Message: "Usage limit lifted — resuming"})
+	if !st.state.RateLimitedUntil.IsZero() {
+		t.Errorf("RateLimitedUntil = %v, want cleared after the reset", st.state.RateLimitedUntil)
+	}
+}
+
+func TestCheckSandbox(t *testing.T) {
+	cfg := config.Defaults()
+	if err := checkSandbox(&cfg); err != nil {
+		t.Errorf("checkSandbox() with sandbox disabled = %v", err)
+	}
+	cfg.Sandbox.Enabled = true
+	cfg.Sandbox.Runtime = "ralph-no-such-runtime"
+	if err := checkSandbox(&cfg); !errors.Is(err, sandbox.ErrRuntimeNotFound) {
+		t.Errorf("checkSandbox() = %v, want sandbox.ErrRuntimeNotFound", err)
+	}
+}
+
 // --- Tests for runWithStateTracking ---
 
 func TestRunWithStateTracking_Success(t *testing.T) {
@@ -688,6 +725,181 @@ func TestLoopController_StartLoop_ForwardGoroutine(t *testing.T) {
 	waitForIdle(t, ctrl)
 }
 
+// transcriptAgent writes one line to the run's transcript, as ClaudeAgent
+// does with the CLI's output, and reports success.
+type transcriptAgent struct{}
+
+func (transcriptAgent) Run(_ context.Context, _ string, opts claude.RunOptions) (<-chan claude.Event, error) {
+	if opts.Transcript != nil {
+		_, _ = io.WriteString(opts.Transcript, "{\"type\":\"result\"}\n")
+	}
+	ch := make(chan claude.Event, 1)
+	ch <- claude.ResultEvent(0.01, 1, "success")
+	close(ch)
+	return ch, nil
+}
+
+func TestLoopController_StartLoop_WritesTranscript(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	initGitRepo(t, dir)
+	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent()+"\n[claude]\ntranscripts = true\n")
+	writeExecTestFile(t, dir, "PLAN.md", "# Plan\n")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	s, err := store.NewJSONL(filepath.Join(dir, ".ralph", "logs"))
+	if err != nil {
+		t.Fatalf("store.NewJSONL: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	ctrl := &loopController{
+		cfg:       cfg,
+		dir:       dir,
+		gitRunner: git.NewRunner(dir),
+		sw:        s,
+		sessionID: sessionIDOf(s),
+		tuiSend:   make(chan loop.LogEntry, 128),
+		outerCtx:  context.Background(),
+		agent:     transcriptAgent{},
+	}
+	if ctrl.sessionID == "" {
+		t.Fatal("sessionIDOf returned no session for an open store")
+	}
+
+	ctrl.StartLoop("plan")
+	waitForIdle(t, ctrl)
+
+	path := filepath.Join(dir, ".ralph", "transcripts", ctrl.sessionID, "1.jsonl")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("transcript not written: %v", err)
+	}
+	if !strings.Contains(string(data), `"result"`) {
+		t.Errorf("transcript = %q", data)
+	}
+}
+
+func TestLoopController_RunPhase(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Defaults()
+	tuiSend := make(chan loop.LogEntry, 128)
+	ctrl := &loopController{
+		cfg:      &cfg,
+		dir:      dir,
+		tuiSend:  tuiSend,
+		outerCtx: context.Background(),
+		agent:    &errAgent{err: errors.New("fake: no claude")},
+	}
+	var _ tui.PhaseController = ctrl
+
+	// spec.md is missing, so plan cannot run: the error reaches the TUI
+	// tagged with the phase.
+	ctrl.RunPhase(loop.PhaseRequest{Phase: loop.PhasePlan, SpecDir: "specs/001-feat"})
+	waitForIdle(t, ctrl)
+
+	var got []loop.LogEntry
+	for len(tuiSend) > 0 {
+		got = append(got, <-tuiSend)
+	}
+	if len(got) != 1 || got[0].Kind != loop.LogError || got[0].Phase != loop.PhasePlan ||
+		!strings.Contains(got[0].Message, "spec.md not found") {
+		t.Errorf("forwarded = %+v, want one LogError for the plan phase", got)
+	}
+}
+
+func TestLoopController_RunPhase_NoopWhenRunning(t *testing.T) {
+	tuiSend := make(chan loop.LogEntry, 4)
+	ctrl := &loopController{
+		outerCtx: context.Background(),
+		cancel:   func() {},
+		tuiSend:  tuiSend,
+	}
+	ctrl.RunPhase(loop.PhaseRequest{Phase: loop.PhasePlan}) // no agent or config: must not start
+	if !ctrl.IsRunning() {
+		t.Error("RunPhase should leave the running loop in place")
+	}
+	select {
+	case e := <-tuiSend:
+		if e.Kind != loop.LogError || e.Phase != loop.PhasePlan || !strings.Contains(e.Message, "already running") {
+			t.Errorf("forwarded = %+v, want a LogError for the plan phase saying a loop is running", e)
+		}
+	default:
+		t.Error("RunPhase while running should tell the TUI why the phase did not start")
+	}
+}
+
+func TestLoopController_PauseLoop(t *testing.T) {
+	ctrl := &loopController{outerCtx: context.Background()}
+	var _ tui.PauseController = ctrl
+	ctrl.PauseLoop() // idle: must not panic
+
+	pause := make(chan struct{})
+	ctrl.pause = pause
+	ctrl.PauseLoop()
+	ctrl.PauseLoop() // second request must not close twice
+	select {
+	case <-pause:
+	default:
+		t.Error("PauseLoop should close the running loop's pause channel")
+	}
+}
+
+func TestLoopController_ResumeLoop_NoCheckpoint(t *testing.T) {
+	tuiSend := make(chan loop.LogEntry, 8)
+	ctrl := &loopController{dir: t.TempDir(), tuiSend: tuiSend, outerCtx: context.Background()}
+	ctrl.ResumeLoop()
+	if ctrl.IsRunning() {
+		t.Fatal("nothing to resume: no loop should start")
+	}
+	if len(tuiSend) != 1 {
+		t.Fatalf("forwarded %d entries, want 1", len(tuiSend))
+	}
+	if e := <-tuiSend; e.Kind != loop.LogInfo || !strings.Contains(e.Message, "Nothing to resume") {
+		t.Errorf("entry = %+v, want a Nothing to resume notice", e)
+	}
+}
+
+func TestLoopController_ResumeLoop_FromCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	initGitRepo(t, dir)
+	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent())
+	writeExecTestFile(t, dir, "PLAN.md", "# Plan\n")
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	if err := loop.SaveCheckpoint(dir, loop.Checkpoint{Mode: loop.ModePlan, Iteration: 2, MaxIter: 5, TotalCost: 0.5, Paused: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	tuiSend := make(chan loop.LogEntry, 128)
+	ctrl := &loopController{
+		cfg:       cfg,
+		dir:       dir,
+		gitRunner: git.NewRunner(dir),
+		tuiSend:   tuiSend,
+		outerCtx:  context.Background(),
+		agent:     &errAgent{err: errors.New("fake: no claude")},
+	}
+	ctrl.ResumeLoop()
+	waitForIdle(t, ctrl)
+
+	var resumed bool
+	for len(tuiSend) > 0 {
+		if e := <-tuiSend; strings.Contains(e.Message, "Resuming plan loop at iteration 3") {
+			resumed = true
+		}
+	}
+	if !resumed {
+		t.Error("ResumeLoop should continue the plan loop from iteration 3")
+	}
+}
+
 // --- Tests for finishTUI ---
 
 // TestFinishTUI_Success verifies that finishTUI returns nil when the TUI exits
@@ -711,6 +923,18 @@ func TestFinishTUI_Success(t *testing.T) {
 	}
 }
 
+func TestResumeOffer(t *testing.T) {
+	got := resumeOffer([]*orchestrator.WorktreeAgent{
+		{Branch: "001-a", State: orchestrator.StateStopped},
+		{Branch: "002-b", State: orchestrator.StateFailed},
+	})
+	for _, want := range []string{"Restored 2 worktree agent(s)", "001-a (stopped)", "002-b (failed)", "press r"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("resumeOffer missing %q: %s", want, got)
+		}
+	}
+}
+
 // initGitRepo creates a minimal git repo in dir for tests that need git operations.
 func initGitRepo(t *testing.T, dir string) {
 	t.Helper()
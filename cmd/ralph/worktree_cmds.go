@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
 	"github.com/LISSConsulting/RalphSpec/internal/worktree"
 )
 
@@ -16,7 +21,7 @@ func worktreeCmd() *cobra.Command {
 		Use:   "worktree",
 		Short: "Manage git worktrees for parallel agent workflows",
 	}
-	cmd.AddCommand(worktreeListCmd(), worktreeMergeCmd(), worktreeCleanCmd())
+	cmd.AddCommand(worktreeListCmd(), worktreeLaunchCmd(), worktreeMergeCmd(), worktreeCleanCmd())
 	return cmd
 }
 
@@ -31,7 +36,7 @@ func worktreeListCmd() *cobra.Command {
 				return fmt.Errorf("get working directory: %w", err)
 			}
 
-			wtr := worktree.NewRunner(dir)
+			wtr, _ := worktreeOps(dir)
 			if err := wtr.Detect(); err != nil {
 				return err
 			}
@@ -56,6 +61,79 @@ func worktreeListCmd() *cobra.Command {
 	return cmd
 }
 
+// worktreeLaunchCmd implements `ralph worktree launch <spec>...`.
+func worktreeLaunchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "launch <spec>...",
+		Short: "Launch build agents for specs in worktrees, queueing past max_parallel",
+		Long: `Launch a build agent in its own worktree for each spec, in argument order.
+Specs beyond [worktree] max_parallel are queued and start as running agents
+finish; [worktree] priorities decides which queued spec starts first.
+Agent output is streamed until every agent has finished. See also ralph fleet.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noColor, _ := cmd.Root().PersistentFlags().GetBool("no-color")
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+
+			wtr, cfg := worktreeOps(dir)
+			if err := wtr.Detect(); err != nil {
+				return err
+			}
+			if err := checkSandbox(cfg); err != nil {
+				return err
+			}
+
+			all, err := spec.List(dir)
+			if err != nil {
+				return fmt.Errorf("list specs: %w", err)
+			}
+			specs, err := resolveLaunchSpecs(all, args)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel, stopCh := signalContextGraceful()
+			defer cancel()
+
+			orch := orchestrator.New(cfg, wtr)
+			orch.StatePath = orchestrator.StatePath(dir)
+			return runFleet(ctx, stopCh, orch, specs, fleetOptions{formatter: lineFormatter{color: !noColor}}, os.Stdout)
+		},
+	}
+}
+
+// resolveLaunchSpecs looks up each name in specs, preserving argument order.
+func resolveLaunchSpecs(specs []spec.SpecFile, names []string) ([]spec.SpecFile, error) {
+	byName := make(map[string]spec.SpecFile, len(specs))
+	for _, sf := range specs {
+		byName[sf.Name] = sf
+	}
+	var (
+		resolved []spec.SpecFile
+		missing  []string
+	)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		sf, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		resolved = append(resolved, sf)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unknown spec(s): %s", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
 // worktreeMergeCmd implements `ralph worktree merge [branch]`.
 func worktreeMergeCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -68,7 +146,7 @@ func worktreeMergeCmd() *cobra.Command {
 				return fmt.Errorf("get working directory: %w", err)
 			}
 
-			wtr := worktree.NewRunner(dir)
+			wtr, cfg := worktreeOps(dir)
 			if err := wtr.Detect(); err != nil {
 				return err
 			}
@@ -99,6 +177,21 @@ func worktreeMergeCmd() *cobra.Command {
 				}
 			}
 
+			if cfg.Git.Strategy == "squash-spec" {
+				infos, listErr := wtr.List()
+				if listErr != nil {
+					return fmt.Errorf("list worktrees: %w", listErr)
+				}
+				for _, info := range infos {
+					if info.Branch == branch {
+						if err := squashWorktreeBranch(info.Path, branch, target); err != nil {
+							return err
+						}
+						break
+					}
+				}
+			}
+
 			if err := wtr.Merge(branch, target); err != nil {
 				return err
 			}
@@ -114,11 +207,43 @@ func worktreeMergeCmd() *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().String("target", "", "target branch to merge into (empty = worktrunk default)")
+	cmd.Flags().String("target", "", "target branch to merge into (empty = backend default)")
 	cmd.Flags().Bool("no-remove", false, "keep worktree after merge")
 	return cmd
 }
 
+// worktreeOps returns the worktree backend configured in ralph.toml for dir.
+// A missing or invalid ralph.toml falls back to the defaults (worktrunk).
+func worktreeOps(dir string) (worktree.WorktreeOps, *config.Config) {
+	cfg, err := config.Load("")
+	if err != nil {
+		defaults := config.Defaults()
+		cfg = &defaults
+	}
+	return worktree.New(cfg.Worktree, dir), cfg
+}
+
+// squashWorktreeBranch folds branch (checked out at wtPath) into a single
+// commit before it is merged ([git] strategy = "squash-spec"). The original
+// SHAs are printed; the squash commit's message lists them too, so the
+// pre-squash history can still be traced.
+func squashWorktreeBranch(wtPath, branch, target string) error {
+	gr := git.NewRunner(wtPath)
+	original, err := gr.SquashBranch(target, git.SpecSubject(branch))
+	if err != nil {
+		return fmt.Errorf("squash %s: %w", branch, err)
+	}
+	if len(original) < 2 {
+		return nil
+	}
+	commit, _ := gr.LastCommit()
+	fmt.Printf("Squashed %d commits on %s into %s:\n", len(original), branch, commit)
+	for _, c := range original {
+		fmt.Printf("  %s %s\n", c.SHA[:7], c.Subject())
+	}
+	return nil
+}
+
 // worktreeCleanCmd implements `ralph worktree clean [branch|--all]`.
 func worktreeCleanCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -131,7 +256,7 @@ func worktreeCleanCmd() *cobra.Command {
 				return fmt.Errorf("get working directory: %w", err)
 			}
 
-			wtr := worktree.NewRunner(dir)
+			wtr, _ := worktreeOps(dir)
 			if err := wtr.Detect(); err != nil {
 				return err
 			}
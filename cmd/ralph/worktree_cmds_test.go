@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
 	"github.com/LISSConsulting/RalphSpec/internal/worktree"
 )
 
@@ -74,3 +82,158 @@ func TestFormatWorktreeList_Entries(t *testing.T) {
 		t.Errorf("missing [bare] annotation: %q", got)
 	}
 }
+
+func TestSquashWorktreeBranch(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "main")
+	git := func(args ...string) string {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		out, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("checkout", "-b", "007-x")
+	writeExecTestFile(t, dir, "a.txt", "a")
+	git("add", "a.txt")
+	git("commit", "-m", "add a")
+	writeExecTestFile(t, dir, "b.txt", "b")
+	git("add", "b.txt")
+	git("commit", "-m", "add b")
+
+	out := captureStdout(func() {
+		if err := squashWorktreeBranch(dir, "007-x", "main"); err != nil {
+			t.Fatalf("squashWorktreeBranch: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Squashed 2 commits on 007-x") || !strings.Contains(out, "add b") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if got := git("rev-list", "--count", "main..HEAD"); got != "1" {
+		t.Errorf("expected 1 commit ahead of main, got %s", got)
+	}
+	if msg := git("log", "-1", "--format=%B"); !strings.Contains(msg, "feat: x") || !strings.Contains(msg, "add a") {
+		t.Errorf("squash commit should list the original commits:\n%s", msg)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ralph", "logs")); !os.IsNotExist(err) {
+		t.Errorf("squash should not create a session log: %v", err)
+	}
+}
+
+// TestWorktreeCmds_GitBackend runs merge and clean end to end with
+// [worktree] backend = "git", which needs no worktrunk on PATH.
+func TestWorktreeCmds_GitBackend(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepoOnBranch(t, dir, "main")
+	t.Chdir(dir)
+	wtDir := t.TempDir()
+	writeExecTestFile(t, dir, "ralph.toml", testConfigNoRegent()+
+		"\n[worktree]\nbackend = \"git\"\nworktree_dir = \""+filepath.ToSlash(wtDir)+"\"\n")
+	git := func(d string, args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = d
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(dir, "add", "ralph.toml")
+	git(dir, "commit", "-m", "config")
+
+	wtPath := filepath.Join(wtDir, "feat-x")
+	git(dir, "worktree", "add", "-b", "feat/x", wtPath)
+	writeExecTestFile(t, wtPath, "x.txt", "x")
+	git(wtPath, "add", "x.txt")
+	git(wtPath, "commit", "-m", "add x")
+
+	list := worktreeListCmd()
+	out := captureStdout(func() {
+		if err := list.RunE(list, nil); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, "feat/x") {
+		t.Errorf("list output missing feat/x:\n%s", out)
+	}
+
+	merge := worktreeMergeCmd()
+	out = captureStdout(func() {
+		if err := merge.RunE(merge, []string{"feat/x"}); err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Merged feat/x") {
+		t.Errorf("merge output = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "x.txt")); err != nil {
+		t.Error("x.txt should be merged into main")
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Error("worktree should be removed after merge")
+	}
+}
+
+func TestResolveLaunchSpecs(t *testing.T) {
+	specs := []spec.SpecFile{
+		{Name: "001-core", Dir: "specs/001-core"},
+		{Name: "002-api", Dir: "specs/002-api"},
+	}
+	got, err := resolveLaunchSpecs(specs, []string{"002-api", "001-core", "002-api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "002-api" || got[1].Name != "001-core" {
+		t.Errorf("resolved = %+v, want argument order without duplicates", got)
+	}
+	if _, err := resolveLaunchSpecs(specs, []string{"001-core", "999-nope"}); err == nil || !strings.Contains(err.Error(), "999-nope") {
+		t.Errorf("unknown spec error = %v", err)
+	}
+}
+
+// launchTestOps is a WorktreeOps that hands every branch the same directory.
+type launchTestOps struct{ dir string }
+
+func (o launchTestOps) Detect() error                          { return nil }
+func (o launchTestOps) Switch(string, bool) (string, error)    { return o.dir, nil }
+func (o launchTestOps) List() ([]worktree.WorktreeInfo, error) { return nil, nil }
+func (o launchTestOps) Merge(string, string) error             { return nil }
+func (o launchTestOps) Remove(string) error                    { return nil }
+
+func TestRunFleet_QueuesAndReportsFailures(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Regent.Enabled = false
+	cfg.Build.PromptFile = "BUILD.md" // missing → every agent fails fast
+	cfg.Worktree.MaxParallel = 1
+	orch := orchestrator.New(&cfg, launchTestOps{dir: t.TempDir()})
+
+	specs := []spec.SpecFile{{Name: "001-a"}, {Name: "002-b"}, {Name: "003-c"}}
+	var out strings.Builder
+	err := runFleet(context.Background(), nil, orch, specs, fleetOptions{}, &out)
+	if err == nil || !strings.Contains(err.Error(), "3 agent(s) failed") {
+		t.Errorf("err = %v, want all three agents reported as failed", err)
+	}
+	got := out.String()
+	for _, want := range []string{"Launched 001-a", "Queued 002-b", "Queued 003-c", "[002-b]", "003-c"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunFleet_CancelEmptiesQueue(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Regent.Enabled = false
+	cfg.Worktree.MaxParallel = 1
+	orch := orchestrator.New(&cfg, launchTestOps{dir: t.TempDir()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	specs := []spec.SpecFile{{Name: "001-a"}, {Name: "002-b"}}
+	_ = runFleet(ctx, nil, orch, specs, fleetOptions{}, io.Discard)
+	if len(orch.Queued()) != 0 {
+		t.Errorf("queue should be empty after cancel, got %d entries", len(orch.Queued()))
+	}
+}
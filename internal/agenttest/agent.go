@@ -0,0 +1,178 @@
+// Package agenttest provides a scripted claude.Agent for end-to-end tests
+// of the loop, Regent, store and TUI pipeline without calling Claude.
+//
+// A ScriptedAgent answers each Run with the next Run of its script: it
+// replays a recorded stream-JSON transcript (such as one saved with
+// [claude] transcripts) through the real stream parser, and runs the
+// script's side effects — writing files, committing them — at given points
+// in the transcript.
+//
+// The package is internal because the loop and claude packages it plugs into
+// are; outside this module, scripted runs go through the --agent-script flag.
+package agenttest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+// Run is the scripted behavior of one agent invocation.
+type Run struct {
+	// Transcript is a stream-JSON file to replay. A relative path is
+	// resolved against the script's directory (see Load).
+	Transcript string `toml:"transcript"`
+	// Output is inline stream-JSON, replayed when Transcript is empty.
+	Output string `toml:"output"`
+	// Steps are side effects run while the output is replayed.
+	Steps []Step `toml:"step"`
+}
+
+// Step is a side effect run after AfterLine lines of a Run's output have
+// been read by the parser: 0 runs it before any output, and a value past
+// the last line runs it once the output is exhausted. Its actions run in
+// the order the fields are listed, in the invocation's working directory.
+type Step struct {
+	AfterLine int `toml:"after_line"`
+	// Files maps slash-separated paths to the content to write.
+	Files map[string]string `toml:"files"`
+	// Remove lists paths to delete.
+	Remove []string `toml:"remove"`
+	// Func is an arbitrary side effect, for Go tests.
+	Func func(dir string) error `toml:"-"`
+	// Commit, if set, stages every change and commits it with this message.
+	Commit string `toml:"commit"`
+}
+
+// ScriptedAgent is a claude.Agent that plays its Runs in order, one per
+// call to Run. Calling Run after the last one is an error. It is safe for
+// concurrent use.
+type ScriptedAgent struct {
+	runs []Run
+	dir  string // resolves relative transcript paths
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+// New returns a ScriptedAgent that plays runs. Relative transcript paths
+// are resolved against the working directory.
+func New(runs ...Run) *ScriptedAgent {
+	return &ScriptedAgent{runs: runs}
+}
+
+// Prompts returns the prompts the agent has been run with, in order.
+func (a *ScriptedAgent) Prompts() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.prompts...)
+}
+
+// Run plays the next scripted Run. Events are parsed from the replayed
+// output exactly as ClaudeAgent parses the CLI's, opts.Transcript receives
+// a copy of it, and steps run in opts.Dir. A failing step ends the output
+// with a stream read error.
+func (a *ScriptedAgent) Run(ctx context.Context, prompt string, opts claude.RunOptions) (<-chan claude.Event, error) {
+	a.mu.Lock()
+	n := len(a.prompts)
+	a.prompts = append(a.prompts, prompt)
+	a.mu.Unlock()
+	if n >= len(a.runs) {
+		return nil, fmt.Errorf("agenttest: script has %d run(s); run %d requested", len(a.runs), n+1)
+	}
+	run := a.runs[n]
+
+	output := []byte(run.Output)
+	if run.Transcript != "" {
+		path := run.Transcript
+		if !filepath.IsAbs(path) && a.dir != "" {
+			path = filepath.Join(a.dir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("agenttest: run %d: %w", n+1, err)
+		}
+		output = data
+	}
+
+	pr, pw := io.Pipe()
+	parser := &claude.StreamParser{Transcript: opts.Transcript}
+	parsed := parser.Parse(pr)
+	go func() {
+		err := replay(ctx, pw, bytes.SplitAfter(output, []byte("\n")), run.Steps, opts.Dir)
+		if err != nil {
+			err = fmt.Errorf("agenttest: run %d: %w", n+1, err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	ch := make(chan claude.Event, 64)
+	go func() {
+		defer close(ch)
+		for ev := range parsed {
+			ch <- ev
+		}
+		if stats := parser.Stats(); stats.Skipped() > 0 {
+			ch <- claude.StreamStatsEvent(stats)
+		}
+	}()
+	return ch, nil
+}
+
+// replay writes lines to w, running each step once its line has been
+// written. It stops early, without error, when ctx is cancelled.
+func replay(ctx context.Context, w io.Writer, lines [][]byte, steps []Step, dir string) error {
+	if n := len(lines); n > 0 && len(lines[n-1]) == 0 {
+		lines = lines[:n-1]
+	}
+	for i := 0; i <= len(lines); i++ {
+		for j, s := range steps {
+			if min(max(s.AfterLine, 0), len(lines)) != i {
+				continue
+			}
+			if err := s.apply(dir); err != nil {
+				return fmt.Errorf("step %d: %w", j+1, err)
+			}
+		}
+		if i == len(lines) || ctx.Err() != nil {
+			return nil
+		}
+		if _, err := w.Write(lines[i]); err != nil {
+			return nil // the reader is gone
+		}
+	}
+	return nil
+}
+
+func (s Step) apply(dir string) error {
+	for name, content := range s.Files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	for _, name := range s.Remove {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+	if s.Func != nil {
+		if err := s.Func(dir); err != nil {
+			return err
+		}
+	}
+	if s.Commit != "" {
+		return git.NewRunner(dir).CommitPaths(s.Commit, ".")
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package agenttest_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/agenttest"
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+const output = `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Write","input":{"file_path":"auth.go"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"ok"}]}}
+{"type":"result","subtype":"success","cost_usd":0.12,"duration_ms":3000}
+`
+
+func drain(ch <-chan claude.Event) []claude.Event {
+	var events []claude.Event
+	for ev := range ch {
+		events = append(events, ev)
+	}
+	return events
+}
+
+func gitLog(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestScriptedAgent_Replays(t *testing.T) {
+	a := agenttest.New(agenttest.Run{Output: output}, agenttest.Run{Output: "not json\n"})
+
+	var transcript strings.Builder
+	ch, err := a.Run(context.Background(), "first prompt", claude.RunOptions{Transcript: &transcript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := drain(ch)
+	if len(events) != 3 || events[0].Type != claude.EventToolUse || events[1].Type != claude.EventToolResult ||
+		events[2].Type != claude.EventResult || events[2].CostUSD != 0.12 {
+		t.Errorf("events = %+v, want tool use, tool result, result", events)
+	}
+	if transcript.String() != output {
+		t.Errorf("transcript = %q, want the replayed output", transcript.String())
+	}
+
+	ch, err = a.Run(context.Background(), "second prompt", claude.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events := drain(ch); len(events) != 1 || events[0].Type != claude.EventStreamStats || events[0].Stats.Malformed != 1 {
+		t.Errorf("events = %+v, want stream stats for the malformed line", events)
+	}
+
+	if _, err := a.Run(context.Background(), "third prompt", claude.RunOptions{}); err == nil || !strings.Contains(err.Error(), "run 3 requested") {
+		t.Errorf("Run past the script = %v, want an error", err)
+	}
+	if got := a.Prompts(); len(got) != 3 || got[0] != "first prompt" {
+		t.Errorf("Prompts() = %q", got)
+	}
+}
+
+func TestScriptedAgent_Steps(t *testing.T) {
+	repo := agenttest.NewRepo(t)
+	var sawFile bool
+	a := agenttest.New(agenttest.Run{
+		Output: output,
+		Steps: []agenttest.Step{
+			{AfterLine: 1, Files: map[string]string{"internal/auth/auth.go": "package auth\n"}},
+			{AfterLine: 2, Func: func(dir string) error {
+				_, err := os.Stat(filepath.Join(dir, "internal", "auth", "auth.go"))
+				sawFile = err == nil
+				return nil
+			}, Commit: "feat(auth): add auth"},
+			{AfterLine: 99, Remove: []string{"internal/auth/auth.go"}, Commit: "chore: remove auth"},
+		},
+	})
+	ch, err := a.Run(context.Background(), "build", claude.RunOptions{Dir: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(ch)
+	if !sawFile {
+		t.Error("steps should run in order against the run's Dir")
+	}
+	if log := gitLog(t, repo); log != "chore: remove auth\nfeat(auth): add auth\ninit\n" {
+		t.Errorf("git log =\n%s", log)
+	}
+}
+
+func TestScriptedAgent_StepError(t *testing.T) {
+	a := agenttest.New(agenttest.Run{
+		Output: output,
+		Steps:  []agenttest.Step{{AfterLine: 1, Remove: []string{"missing.go"}}},
+	})
+	ch, err := a.Run(context.Background(), "build", claude.RunOptions{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := drain(ch)
+	last := events[len(events)-1]
+	if last.Type != claude.EventError || !strings.Contains(last.Error, "agenttest: run 1: step 1") {
+		t.Errorf("last event = %+v, want the step's error", last)
+	}
+	for _, ev := range events {
+		if ev.Type == claude.EventResult {
+			t.Error("output after a failed step should not be replayed")
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "transcripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "transcripts", "1.jsonl"), []byte(output), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := `[[run]]
+transcript = "transcripts/1.jsonl"
+
+[[run.step]]
+after_line = 1
+files = { "a.txt" = "hello\n" }
+
+[[run]]
+output = '{"type":"result","subtype":"success"}'
+`
+	path := filepath.Join(dir, "agent.toml")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := agenttest.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := t.TempDir()
+	ch, err := a.Run(context.Background(), "p", claude.RunOptions{Dir: work})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events := drain(ch); len(events) != 3 {
+		t.Errorf("run 1 events = %+v, want the transcript's 3", events)
+	}
+	if data, err := os.ReadFile(filepath.Join(work, "a.txt")); err != nil || string(data) != "hello\n" {
+		t.Errorf("a.txt = %q, %v", data, err)
+	}
+	ch, err = a.Run(context.Background(), "p", claude.RunOptions{Dir: work})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events := drain(ch); len(events) != 1 || events[0].Type != claude.EventResult {
+		t.Errorf("run 2 events = %+v, want the inline result", events)
+	}
+
+	for name, bad := range map[string]string{
+		"empty":   "",
+		"unknown": "[[run]]\noutptu = \"x\"\n",
+		"both":    "[[run]]\noutput = \"x\"\ntranscript = \"y\"\n",
+	} {
+		p := filepath.Join(dir, name+".toml")
+		if err := os.WriteFile(p, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := agenttest.Load(p); err == nil {
+			t.Errorf("Load(%s) should fail", name)
+		}
+	}
+}
+
+// TestLoop runs the real loop and git runner against a scripted agent that
+// commits a file during its run.
+func TestLoop(t *testing.T) {
+	repo := agenttest.NewRepo(t)
+	g := git.NewRunner(repo)
+	if err := os.WriteFile(filepath.Join(repo, "PLAN.md"), []byte("plan the work"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CommitPaths("add prompt", "PLAN.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := agenttest.New(agenttest.Run{
+		Output: output,
+		Steps:  []agenttest.Step{{AfterLine: 2, Files: map[string]string{"auth.go": "package auth\n"}, Commit: "feat: add auth"}},
+	})
+	cfg := config.Defaults()
+	cfg.Plan.MaxIterations = 1
+	cfg.Git.AutoPullRebase = false
+	cfg.Git.AutoPush = false
+	cfg.Git.ProtectedBranches = nil
+	events := make(chan loop.LogEntry, 128)
+	lp := &loop.Loop{Agent: agent, Git: g, Config: &cfg, Dir: repo, Events: events}
+	if err := lp.Run(context.Background(), loop.ModePlan, 0); err != nil {
+		t.Fatal(err)
+	}
+	close(events)
+
+	var results, complete int
+	for e := range events {
+		switch e.Kind {
+		case loop.LogToolResult:
+			results++
+		case loop.LogIterComplete:
+			complete++
+			if e.CostUSD != 0.12 {
+				t.Errorf("iteration cost = %v, want 0.12", e.CostUSD)
+			}
+		}
+	}
+	if results != 1 || complete != 1 {
+		t.Errorf("tool results = %d, iterations = %d; want 1 and 1", results, complete)
+	}
+	if prompts := agent.Prompts(); len(prompts) != 1 || !strings.Contains(prompts[0], "plan the work") {
+		t.Errorf("prompts = %q", prompts)
+	}
+	if log := gitLog(t, repo); !strings.HasPrefix(log, "feat: add auth\n") {
+		t.Errorf("git log =\n%s", log)
+	}
+}
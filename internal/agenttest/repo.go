@@ -0,0 +1,28 @@
+package agenttest
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// NewRepo creates a git repository in a temporary directory, on branch
+// main with one empty commit and a local identity, for scripted steps to
+// commit into. It returns the repository's path.
+func NewRepo(tb testing.TB) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"checkout", "--quiet", "-b", "main"},
+		{"config", "user.email", "agenttest@example.com"},
+		{"config", "user.name", "agenttest"},
+		{"commit", "--quiet", "--allow-empty", "-m", "init"},
+	} {
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			tb.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
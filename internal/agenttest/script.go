@@ -0,0 +1,51 @@
+package agenttest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// script is the TOML form of a ScriptedAgent's runs:
+//
+//	[[run]]
+//	transcript = "transcripts/1.jsonl"
+//
+//	[[run.step]]
+//	after_line = 3
+//	files = { "auth.go" = "package auth\n" }
+//	commit = "feat(auth): add token refresh"
+type script struct {
+	Runs []Run `toml:"run"`
+}
+
+// Load reads a TOML agent script: one [[run]] table per agent invocation,
+// with its [[run.step]] side effects. Transcript paths are relative to the
+// script's directory.
+func Load(path string) (*ScriptedAgent, error) {
+	var s script
+	meta, err := toml.DecodeFile(path, &s)
+	if err != nil {
+		return nil, fmt.Errorf("agenttest: decode %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("agenttest: unknown keys in %s: %s", path, strings.Join(keys, ", "))
+	}
+	if len(s.Runs) == 0 {
+		return nil, fmt.Errorf("agenttest: %s has no [[run]]", path)
+	}
+	for i, r := range s.Runs {
+		if r.Transcript != "" && r.Output != "" {
+			return nil, fmt.Errorf("agenttest: %s: run %d sets both transcript and output", path, i+1)
+		}
+	}
+	a := New(s.Runs...)
+	a.dir = filepath.Dir(path)
+	return a, nil
+}
@@ -1,13 +1,20 @@
 package claude
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // RunOptions configures a Claude CLI invocation.
 type RunOptions struct {
 	Model                 string
 	MaxTurns              int
 	DangerSkipPermissions bool
-	Dir                   string // working directory for the subprocess; empty = inherit parent
+	Dir                   string    // working directory for the subprocess; empty = inherit parent
+	AllowedTools          []string  // tools the CLI may use without asking (--allowedTools)
+	DisallowedTools       []string  // tools the CLI must refuse (--disallowedTools)
+	Resume                string    // session ID to continue (--resume); empty starts a new session
+	Transcript            io.Writer // optional: receives the raw stream-JSON output
 }
 
 // Agent is the interface for AI code agents. Claude is the default
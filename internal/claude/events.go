@@ -1,7 +1,11 @@
 // Package claude provides the Claude CLI adapter and stream-JSON event parser.
 package claude
 
-import "time"
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
 
 // EventType identifies the kind of stream-JSON event.
 type EventType string
@@ -11,8 +15,24 @@ const (
 	EventText    EventType = "text"
 	EventResult  EventType = "result"
 	EventError   EventType = "error"
+
+	// EventRateLimit is an error that means Claude refused the request
+	// because of a rate limit or an exhausted subscription usage window.
+	EventRateLimit EventType = "rate_limit"
+
+	// EventToolResult is the output of a tool call, as the agent saw it.
+	EventToolResult EventType = "tool_result"
+	// EventThinking is an extended-thinking block.
+	EventThinking EventType = "thinking"
+	// EventStreamStats reports lines of the stream that were skipped; it is
+	// sent after the last parsed event, and only if something was skipped.
+	EventStreamStats EventType = "stream_stats"
 )
 
+// MaxToolResultBytes caps the content kept from a tool result; the rest is
+// replaced by a note saying how much was dropped.
+const MaxToolResultBytes = 8 << 10
+
 // Event is a parsed stream-JSON event from Claude CLI output.
 type Event struct {
 	Type      EventType
@@ -21,17 +41,32 @@ type Event struct {
 	// ToolUse fields
 	ToolName  string
 	ToolInput map[string]any
+	// ToolUseID links a tool call to its result. A thinking event carries
+	// the ID of the tool call that follows it in the same message.
+	ToolUseID string
 
-	// Text fields
+	// Text fields; also the content of a tool result or thinking block
 	Text string
 
+	// IsError marks a tool result the tool reported as failed.
+	IsError bool
+
 	// Result fields
 	CostUSD  float64
 	Duration float64 // seconds
 	Subtype  string  // result subtype: "success", "error_max_turns", etc.
+	// SessionID identifies the CLI session, so it can be continued with
+	// RunOptions.Resume.
+	SessionID string
 
 	// Error fields
 	Error string
+
+	// RateLimit fields: when the limit resets; zero if Claude did not say.
+	ResetAt time.Time
+
+	// StreamStats fields
+	Stats StreamStats
 }
 
 // ToolUseEvent creates a tool_use event.
@@ -53,6 +88,40 @@ func TextEvent(text string) Event {
 	}
 }
 
+// ToolResultEvent creates a tool_result event for the call toolUseID.
+// content longer than MaxToolResultBytes is truncated.
+func ToolResultEvent(toolUseID, content string, isError bool) Event {
+	return Event{
+		Type:      EventToolResult,
+		Timestamp: time.Now(),
+		ToolUseID: toolUseID,
+		Text:      truncateContent(content, MaxToolResultBytes),
+		IsError:   isError,
+	}
+}
+
+// ThinkingEvent creates a thinking event.
+func ThinkingEvent(text string) Event {
+	return Event{
+		Type:      EventThinking,
+		Timestamp: time.Now(),
+		Text:      text,
+	}
+}
+
+// truncateContent cuts s to at most max bytes on a UTF-8 boundary and notes
+// how many bytes were dropped.
+func truncateContent(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n… (%d more bytes)", s[:cut], len(s)-cut)
+}
+
 // ResultEvent creates a result event with cost, duration, and exit subtype.
 func ResultEvent(costUSD, duration float64, subtype string) Event {
 	return Event{
@@ -64,6 +133,15 @@ func ResultEvent(costUSD, duration float64, subtype string) Event {
 	}
 }
 
+// StreamStatsEvent creates a stream_stats event.
+func StreamStatsEvent(stats StreamStats) Event {
+	return Event{
+		Type:      EventStreamStats,
+		Timestamp: time.Now(),
+		Stats:     stats,
+	}
+}
+
 // ErrorEvent creates an error event.
 func ErrorEvent(msg string) Event {
 	return Event{
@@ -72,3 +150,23 @@ func ErrorEvent(msg string) Event {
 		Error:     msg,
 	}
 }
+
+// RateLimitEvent creates a rate-limit event. resetAt is the zero time when
+// the reset time is unknown.
+func RateLimitEvent(msg string, resetAt time.Time) Event {
+	return Event{
+		Type:      EventRateLimit,
+		Timestamp: time.Now(),
+		Error:     msg,
+		ResetAt:   resetAt,
+	}
+}
+
+// ClassifyError returns a rate-limit event if msg describes a rate limit or
+// usage cap (see ParseRateLimit), and a plain error event otherwise.
+func ClassifyError(msg string) Event {
+	if reset, ok := ParseRateLimit(msg, time.Now()); ok {
+		return RateLimitEvent(msg, reset)
+	}
+	return ErrorEvent(msg)
+}
@@ -2,39 +2,145 @@ package claude
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
+// DefaultMaxLineBytes is the longest stream-JSON line a StreamParser
+// decodes by default. Longer lines (a tool result with a huge output) are
+// skipped and counted in StreamStats.Oversized; the stream carries on with
+// the next line.
+const DefaultMaxLineBytes = 64 << 20
+
+// StreamStats counts what a StreamParser read and what it could not use.
+type StreamStats struct {
+	Lines     int // non-empty lines read
+	Malformed int // lines that are not valid JSON
+	Unknown   int // messages of a type the parser does not recognize
+	Oversized int // lines longer than the parser's MaxLineBytes
+}
+
+// String describes the skipped lines, e.g. "2 of 140 lines skipped (1
+// malformed, 1 unknown)".
+func (s StreamStats) String() string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		what string
+	}{{s.Malformed, "malformed"}, {s.Unknown, "unknown"}, {s.Oversized, "oversized"}} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.what))
+		}
+	}
+	msg := fmt.Sprintf("%d of %d lines skipped", s.Skipped(), s.Lines)
+	if len(parts) > 0 {
+		msg += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return msg
+}
+
+// Skipped returns the number of lines that produced no events because they
+// could not be decoded or understood.
+func (s StreamStats) Skipped() int {
+	return s.Malformed + s.Unknown + s.Oversized
+}
+
+// StreamParser parses Claude CLI stream-JSON output. The zero value is
+// ready to use.
+type StreamParser struct {
+	// Transcript, if set, receives a copy of every line read, unparsed, so
+	// the output can be replayed later. Write errors stop the copy but not
+	// the parse.
+	Transcript io.Writer
+
+	// MaxLineBytes overrides DefaultMaxLineBytes when positive.
+	MaxLineBytes int
+
+	stats StreamStats
+}
+
 // ParseStream reads stream-JSON lines from r and sends parsed Events on the
 // returned channel. The channel is closed when r reaches EOF or an error.
 // This parses Claude CLI output from --output-format=stream-json --verbose.
 func ParseStream(r io.Reader) <-chan Event {
+	return new(StreamParser).Parse(r)
+}
+
+// Parse reads stream-JSON lines from r and sends parsed Events on the
+// returned channel, which is closed when r reaches EOF or an error. Lines
+// of any length up to the line limit are decoded.
+func (p *StreamParser) Parse(r io.Reader) <-chan Event {
 	ch := make(chan Event, 64)
 	go func() {
 		defer close(ch)
-		scanner := bufio.NewScanner(r)
-		// Allow up to 1MB lines (Claude can produce large tool outputs)
-		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-
-		for scanner.Scan() {
-			line := scanner.Bytes()
-			if len(line) == 0 {
-				continue
+		br := bufio.NewReaderSize(r, 64*1024)
+		for {
+			line, oversized, err := p.readLine(br)
+			line = bytes.TrimSpace(line)
+			switch {
+			case oversized:
+				p.stats.Lines++
+				p.stats.Oversized++
+			case len(line) > 0:
+				p.stats.Lines++
+				for _, ev := range p.parseLine(line) {
+					ch <- ev
+				}
 			}
-			events := parseLine(line)
-			for _, ev := range events {
-				ch <- ev
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				ch <- ErrorEvent(fmt.Sprintf("stream read error: %v", err))
+				return
 			}
-		}
-		if err := scanner.Err(); err != nil {
-			ch <- ErrorEvent(fmt.Sprintf("stream read error: %v", err))
 		}
 	}()
 	return ch
 }
 
+// Stats returns the counts for the stream. Read it once the channel Parse
+// returned is closed.
+func (p *StreamParser) Stats() StreamStats {
+	return p.stats
+}
+
+// readLine reads up to and including the next newline, copying it to the
+// transcript. A line over the limit is read to its end but not kept: line
+// is nil and oversized is true.
+func (p *StreamParser) readLine(br *bufio.Reader) (line []byte, oversized bool, err error) {
+	limit := p.MaxLineBytes
+	if limit <= 0 {
+		limit = DefaultMaxLineBytes
+	}
+	for {
+		chunk, err := br.ReadSlice('\n')
+		p.copyToTranscript(chunk)
+		if !oversized {
+			if len(line)+len(chunk) > limit {
+				line, oversized = nil, true
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err != bufio.ErrBufferFull {
+			return line, oversized, err
+		}
+	}
+}
+
+func (p *StreamParser) copyToTranscript(b []byte) {
+	if p.Transcript == nil || len(b) == 0 {
+		return
+	}
+	if _, err := p.Transcript.Write(b); err != nil {
+		p.Transcript = nil
+	}
+}
+
 // streamMessage is the top-level JSON object in Claude's stream-json output.
 type streamMessage struct {
 	Type    string          `json:"type"`
@@ -45,6 +151,8 @@ type streamMessage struct {
 	Duration float64 `json:"duration_ms"`
 	IsError  bool    `json:"is_error"`
 	Result   string  `json:"result"`
+	// SessionID is set on every message; only the result's is kept.
+	SessionID string `json:"session_id"`
 	// Error fields (type=system, subtype=error)
 	Error string `json:"error"`
 }
@@ -53,23 +161,49 @@ type messageContent struct {
 	Content []contentBlock `json:"content"`
 }
 
+// UnmarshalJSON accepts content given as a plain string, as in a user
+// prompt, which carries no blocks.
+func (m *messageContent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.Content) == 0 || raw.Content[0] == '"' {
+		return nil
+	}
+	return json.Unmarshal(raw.Content, &m.Content)
+}
+
 type contentBlock struct {
-	Type  string         `json:"type"`
-	Text  string         `json:"text"`
-	Name  string         `json:"name"`
-	Input map[string]any `json:"input"`
+	Type     string         `json:"type"`
+	Text     string         `json:"text"`
+	Thinking string         `json:"thinking"`
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Input    map[string]any `json:"input"`
+	// Tool result fields (type=tool_result, in user messages). Content is
+	// either a string or a list of content blocks.
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
 }
 
-// parseLine parses a single line of stream-JSON output into zero or more Events.
-func parseLine(line []byte) []Event {
+// parseLine parses a single line of stream-JSON output into zero or more
+// Events, counting lines it cannot decode or does not recognize.
+func (p *StreamParser) parseLine(line []byte) []Event {
 	var msg streamMessage
 	if err := json.Unmarshal(line, &msg); err != nil {
+		p.stats.Malformed++
 		return nil
 	}
 
 	switch msg.Type {
 	case "assistant":
 		return parseAssistantMessage(msg)
+	case "user":
+		return parseUserMessage(msg)
 	case "result":
 		var events []Event
 		if msg.IsError {
@@ -77,35 +211,93 @@ func parseLine(line []byte) []Event {
 			if errText == "" {
 				errText = "claude run failed"
 			}
-			events = append(events, ErrorEvent(errText))
+			events = append(events, ClassifyError(errText))
 		}
-		events = append(events, ResultEvent(msg.CostUSD, msg.Duration/1000, msg.Subtype))
-		return events
+		result := ResultEvent(msg.CostUSD, msg.Duration/1000, msg.Subtype)
+		result.SessionID = msg.SessionID
+		return append(events, result)
 	case "system":
 		if msg.Subtype == "error" {
-			return []Event{ErrorEvent(msg.Error)}
+			return []Event{ClassifyError(msg.Error)}
 		}
+	default:
+		p.stats.Unknown++
 	}
 	return nil
 }
 
-// parseAssistantMessage extracts tool_use and text events from an assistant message.
+// parseAssistantMessage extracts tool_use, text, and thinking events from an
+// assistant message. A thinking block is linked to the tool call that
+// follows it in the same message, if any.
 func parseAssistantMessage(msg streamMessage) []Event {
 	if msg.Message == nil {
 		return nil
 	}
 
 	var events []Event
+	var thinking []int // indices into events of thinking not yet linked
 	for _, block := range msg.Message.Content {
 		switch block.Type {
 		case "tool_use":
-			events = append(events, ToolUseEvent(block.Name, block.Input))
+			ev := ToolUseEvent(block.Name, block.Input)
+			ev.ToolUseID = block.ID
+			for _, i := range thinking {
+				events[i].ToolUseID = block.ID
+			}
+			thinking = nil
+			events = append(events, ev)
 		case "text":
 			text := block.Text
 			if text != "" {
 				events = append(events, TextEvent(text))
 			}
+		case "thinking":
+			if block.Thinking != "" {
+				thinking = append(thinking, len(events))
+				events = append(events, ThinkingEvent(block.Thinking))
+			}
+		}
+	}
+	return events
+}
+
+// parseUserMessage extracts tool_result events from a user message, which
+// is how the CLI reports what each tool call returned.
+func parseUserMessage(msg streamMessage) []Event {
+	if msg.Message == nil {
+		return nil
+	}
+
+	var events []Event
+	for _, block := range msg.Message.Content {
+		if block.Type == "tool_result" {
+			events = append(events, ToolResultEvent(block.ToolUseID, resultContent(block.Content), block.IsError))
 		}
 	}
 	return events
 }
+
+// resultContent returns the text of a tool result's content, which is a
+// string or a list of blocks. Non-text blocks (images) are noted by type.
+func resultContent(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var blocks []contentBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+			continue
+		}
+		parts = append(parts, "["+b.Type+"]")
+	}
+	return strings.Join(parts, "\n")
+}
@@ -3,8 +3,11 @@ package claude
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseStream(t *testing.T) {
@@ -141,7 +144,7 @@ func TestParseStream(t *testing.T) {
 		},
 		{
 			name:  "result event with is_error emits error then result",
-			input: `{"type":"result","cost_usd":0.08,"duration_ms":3000,"is_error":true,"result":"API rate limit exceeded"}`,
+			input: `{"type":"result","cost_usd":0.08,"duration_ms":3000,"is_error":true,"result":"API Error: 500 internal server error"}`,
 			events: []struct {
 				typ      EventType
 				toolName string
@@ -150,10 +153,39 @@ func TestParseStream(t *testing.T) {
 				errMsg   string
 				subtype  string
 			}{
-				{typ: EventError, errMsg: "API rate limit exceeded"},
+				{typ: EventError, errMsg: "API Error: 500 internal server error"},
 				{typ: EventResult, costUSD: 0.08},
 			},
 		},
+		{
+			name:  "result event with rate limit error emits rate_limit then result",
+			input: `{"type":"result","cost_usd":0.08,"duration_ms":3000,"is_error":true,"result":"API Error: 429 rate_limit_error"}`,
+			events: []struct {
+				typ      EventType
+				toolName string
+				text     string
+				costUSD  float64
+				errMsg   string
+				subtype  string
+			}{
+				{typ: EventRateLimit, errMsg: "API Error: 429 rate_limit_error"},
+				{typ: EventResult, costUSD: 0.08},
+			},
+		},
+		{
+			name:  "system usage limit error emits rate_limit",
+			input: `{"type":"system","subtype":"error","error":"Claude AI usage limit reached|1773158400"}`,
+			events: []struct {
+				typ      EventType
+				toolName string
+				text     string
+				costUSD  float64
+				errMsg   string
+				subtype  string
+			}{
+				{typ: EventRateLimit, errMsg: "Claude AI usage limit reached|1773158400"},
+			},
+		},
 		{
 			name:  "result event with is_error and empty result uses fallback message",
 			input: `{"type":"result","cost_usd":0.01,"duration_ms":500,"is_error":true}`,
@@ -256,6 +288,48 @@ func TestParseStream(t *testing.T) {
 	}
 }
 
+func TestParseStream_RateLimitResetAt(t *testing.T) {
+	input := `{"type":"result","is_error":true,"result":"Claude AI usage limit reached|1773158400"}` + "\n"
+	var got []Event
+	for ev := range ParseStream(strings.NewReader(input)) {
+		got = append(got, ev)
+	}
+	if len(got) == 0 || got[0].Type != EventRateLimit {
+		t.Fatalf("events = %+v, want a rate_limit event first", got)
+	}
+	if want := time.Unix(1773158400, 0); !got[0].ResetAt.Equal(want) {
+		t.Errorf("ResetAt = %v, want %v", got[0].ResetAt, want)
+	}
+}
+
+func TestParseStream_ResultSessionID(t *testing.T) {
+	input := `{"type":"system","subtype":"init","session_id":"abc-123"}
+{"type":"result","subtype":"success","session_id":"abc-123"}` + "\n"
+	var got []Event
+	for ev := range ParseStream(strings.NewReader(input)) {
+		got = append(got, ev)
+	}
+	if len(got) != 1 || got[0].Type != EventResult {
+		t.Fatalf("events = %+v, want one result", got)
+	}
+	if got[0].SessionID != "abc-123" {
+		t.Errorf("SessionID = %q, want abc-123", got[0].SessionID)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	if ev := ClassifyError("claude exited: exit status 1"); ev.Type != EventError {
+		t.Errorf("plain error classified as %q", ev.Type)
+	}
+	if ev := ClassifyError("claude exited: exit status 1: curl: (22) The requested URL returned error: 429"); ev.Type != EventError {
+		t.Errorf("third-party 429 classified as %q", ev.Type)
+	}
+	ev := ClassifyError("claude exited: exit status 1: API Error: 429 Too Many Requests")
+	if ev.Type != EventRateLimit || !ev.ResetAt.IsZero() {
+		t.Errorf("rate limit = %+v, want rate_limit with no reset time", ev)
+	}
+}
+
 // errAfterReader returns valid data first, then an error on the next read.
 type errAfterReader struct {
 	data io.Reader
@@ -322,3 +396,149 @@ func TestParseStream_ScannerError(t *testing.T) {
 		t.Errorf("event[1].Error = %q, want it to contain %q", got[1].Error, "stream read error")
 	}
 }
+
+func TestParseStream_ToolResults(t *testing.T) {
+	input := `{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"Run the tests first."},{"type":"tool_use","id":"toolu_1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"FAIL\tpkg","is_error":true}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_2","content":[{"type":"text","text":"line 1"},{"type":"image"},{"type":"text","text":"line 2"}]}]}}
+{"type":"user","message":{"content":"a plain prompt"}}` + "\n"
+	var got []Event
+	for ev := range ParseStream(strings.NewReader(input)) {
+		got = append(got, ev)
+	}
+	if len(got) != 4 {
+		t.Fatalf("events = %+v, want 4", got)
+	}
+	if got[0].Type != EventThinking || got[0].Text != "Run the tests first." || got[0].ToolUseID != "toolu_1" {
+		t.Errorf("thinking = %+v, want text linked to toolu_1", got[0])
+	}
+	if got[1].Type != EventToolUse || got[1].ToolUseID != "toolu_1" {
+		t.Errorf("tool use = %+v, want ID toolu_1", got[1])
+	}
+	if got[2].Type != EventToolResult || got[2].ToolUseID != "toolu_1" || got[2].Text != "FAIL\tpkg" || !got[2].IsError {
+		t.Errorf("string result = %+v", got[2])
+	}
+	if got[3].Type != EventToolResult || got[3].Text != "line 1\n[image]\nline 2" || got[3].IsError {
+		t.Errorf("block result = %+v", got[3])
+	}
+}
+
+func TestParseStream_ThinkingWithoutToolUse(t *testing.T) {
+	input := `{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"Done."},{"type":"text","text":"All set."}]}}` + "\n"
+	var got []Event
+	for ev := range ParseStream(strings.NewReader(input)) {
+		got = append(got, ev)
+	}
+	if len(got) != 2 || got[0].Type != EventThinking || got[0].ToolUseID != "" {
+		t.Errorf("events = %+v, want unlinked thinking then text", got)
+	}
+}
+
+func TestToolResultEvent_Truncates(t *testing.T) {
+	content := strings.Repeat("é", MaxToolResultBytes) // 2 bytes per rune
+	ev := ToolResultEvent("toolu_1", content, false)
+	body, note, ok := strings.Cut(ev.Text, "\n… (")
+	if !ok {
+		t.Fatalf("Text has no truncation note: %q", ev.Text[len(ev.Text)-40:])
+	}
+	if len(body) != MaxToolResultBytes || !strings.HasSuffix(body, "é") {
+		t.Errorf("kept %d bytes, want %d on a rune boundary", len(body), MaxToolResultBytes)
+	}
+	if want := fmt.Sprintf("%d more bytes)", len(content)-MaxToolResultBytes); note != want {
+		t.Errorf("note = %q, want %q", note, want)
+	}
+	if short := ToolResultEvent("toolu_1", "ok", false); short.Text != "ok" {
+		t.Errorf("short content = %q, want unchanged", short.Text)
+	}
+}
+
+func TestParseStream_LongLine(t *testing.T) {
+	big := strings.Repeat("x", 3<<20) // past the old 1MB scanner cap
+	input := `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"` + big + `"}]}}
+{"type":"result","subtype":"success"}` + "\n"
+	var got []Event
+	for ev := range ParseStream(strings.NewReader(input)) {
+		got = append(got, ev)
+	}
+	if len(got) != 2 || got[0].Type != EventToolResult || got[1].Type != EventResult {
+		t.Fatalf("events = %d, want the tool result and the result", len(got))
+	}
+}
+
+func TestStreamParser_Stats(t *testing.T) {
+	input := `{"type":"assistant","message":{"content":[{"type":"text","text":"hi"}]}}
+not json
+{"type":"mystery"}
+
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"` + strings.Repeat("y", 200) + `"}]}}
+{"type":"result","subtype":"success"}`
+	var transcript strings.Builder
+	p := &StreamParser{Transcript: &transcript, MaxLineBytes: 100}
+	var got []Event
+	for ev := range p.Parse(strings.NewReader(input)) {
+		got = append(got, ev)
+	}
+	if len(got) != 2 || got[0].Type != EventText || got[1].Type != EventResult {
+		t.Errorf("events = %+v, want text and result around the skipped lines", got)
+	}
+	want := StreamStats{Lines: 5, Malformed: 1, Unknown: 1, Oversized: 1}
+	if stats := p.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+	if s := p.Stats().String(); s != "3 of 5 lines skipped (1 malformed, 1 unknown, 1 oversized)" {
+		t.Errorf("String() = %q", s)
+	}
+	if transcript.String() != input {
+		t.Errorf("transcript is not a verbatim copy of the stream:\n%s", transcript.String())
+	}
+}
+
+// failWriter fails every write.
+type failWriter struct{ calls int }
+
+func (w *failWriter) Write([]byte) (int, error) {
+	w.calls++
+	return 0, fmt.Errorf("disk full")
+}
+
+func TestStreamParser_TranscriptErrorKeepsParsing(t *testing.T) {
+	w := &failWriter{}
+	p := &StreamParser{Transcript: w}
+	var got []Event
+	for ev := range p.Parse(strings.NewReader("{\"type\":\"result\"}\n{\"type\":\"result\"}\n")) {
+		got = append(got, ev)
+	}
+	if len(got) != 2 || w.calls != 1 {
+		t.Errorf("events = %d, transcript writes = %d; want 2 events and one failed write", len(got), w.calls)
+	}
+}
+
+// TestParseStream_Transcript replays a captured iteration transcript (see
+// [claude] transcripts) through the parser.
+func TestParseStream_Transcript(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "iteration.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	p := new(StreamParser)
+	var types []EventType
+	var failed Event
+	for ev := range p.Parse(f) {
+		types = append(types, ev.Type)
+		if ev.Type == EventToolResult && ev.IsError {
+			failed = ev
+		}
+	}
+	want := []EventType{EventThinking, EventToolUse, EventToolResult, EventToolUse, EventToolResult, EventText, EventResult}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Errorf("event types = %v, want %v", types, want)
+	}
+	if failed.ToolUseID != "toolu_02" || !strings.Contains(failed.Text, "expired token not refreshed") {
+		t.Errorf("failed Bash result = %+v", failed)
+	}
+	if stats := p.Stats(); stats.Lines != 8 || stats.Unknown != 1 || stats.Skipped() != 1 {
+		t.Errorf("Stats() = %+v, want 8 lines with the rate_limit_event unknown", stats)
+	}
+}
@@ -0,0 +1,94 @@
+package claude
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateLimitRe matches only the Claude CLI's own throttling signals: the API's
+// rate_limit_error type, a 429 on the CLI's "API Error:" line, and the
+// subscription cap messages ("Claude AI usage limit reached|<epoch>",
+// "5-hour limit reached"). A bare "429" or "rate limit" is not enough: tool
+// failures, paths and line numbers can contain them.
+var rateLimitRe = regexp.MustCompile(`(?i)\brate_limit_error\b|API Error: (?:429\b|[^\n]*\b(?:status(?: code)?|HTTP) 429\b)|usage limit reached|\b\d+-hour limit reached`)
+
+var (
+	// "Claude AI usage limit reached|1760000000" — reset as a Unix timestamp.
+	resetEpochRe = regexp.MustCompile(`\|(\d{10})\b`)
+	// "retry after 30 seconds", "Retry-After: 30", "try again in 5 minutes".
+	resetAfterRe = regexp.MustCompile(`(?i)(?:retry[- ]after:?|try again in)\s*(\d+)\s*(s|sec|secs|seconds?|m|min|mins|minutes?|h|hr|hrs|hours?)?\b`)
+	// "resets 3pm", "resets at 3:30 PM (Europe/London)", "reset at 15:00".
+	resetClockRe = regexp.MustCompile(`(?i)resets?\s+(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\s*\(([A-Za-z_]+(?:/[A-Za-z_+-]+)*)\))?`)
+)
+
+// ParseRateLimit reports whether msg describes a rate limit or usage cap and,
+// when the message says so, when the limit resets. The reset is the zero time
+// if msg gives none. Relative and clock-time resets are resolved against now;
+// a clock time without a date is the next occurrence after now.
+func ParseRateLimit(msg string, now time.Time) (reset time.Time, limited bool) {
+	if !rateLimitRe.MatchString(msg) {
+		return time.Time{}, false
+	}
+	if m := resetEpochRe.FindStringSubmatch(msg); m != nil {
+		if sec, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			return time.Unix(sec, 0), true
+		}
+	}
+	if m := resetAfterRe.FindStringSubmatch(msg); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := time.Second
+		switch u := strings.ToLower(m[2]); {
+		case strings.HasPrefix(u, "m"):
+			unit = time.Minute
+		case strings.HasPrefix(u, "h"):
+			unit = time.Hour
+		}
+		return now.Add(time.Duration(n) * unit), true
+	}
+	if m := resetClockRe.FindStringSubmatch(msg); m != nil {
+		if t, ok := nextClockTime(m[1], m[2], m[3], m[4], now); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, true
+}
+
+// nextClockTime returns the first time after now showing hour:minute (with an
+// optional am/pm suffix) in the named IANA zone, or now's zone.
+func nextClockTime(hour, minute, ampm, zone string, now time.Time) (time.Time, bool) {
+	h, err := strconv.Atoi(hour)
+	if err != nil {
+		return time.Time{}, false
+	}
+	m := 0
+	if minute != "" {
+		m, _ = strconv.Atoi(minute)
+	}
+	switch strings.ToLower(ampm) {
+	case "am":
+		if h == 12 {
+			h = 0
+		}
+	case "pm":
+		if h < 12 {
+			h += 12
+		}
+	}
+	if h > 23 || m > 59 {
+		return time.Time{}, false
+	}
+	loc := now.Location()
+	if zone != "" {
+		if l, err := time.LoadLocation(zone); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+	t := time.Date(local.Year(), local.Month(), local.Day(), h, m, 0, 0, loc)
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
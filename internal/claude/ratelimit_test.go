@@ -0,0 +1,58 @@
+package claude
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 20, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		msg         string
+		wantLimited bool
+		wantReset   time.Time
+	}{
+		{"unrelated error", "claude exited: exit status 1", false, time.Time{}},
+		{"other limit", "context window limit reached", false, time.Time{}},
+		{"third-party 429", "Bash failed: GitHub API returned 429 Too Many Requests", false, time.Time{}},
+		{"third-party HTTP 429", "fetch https://api.example.com/v1: HTTP 429", false, time.Time{}},
+		{"429 line number", "tool error: panic at server.go:429", false, time.Time{}},
+		{"429 in path", "open /tmp/run-429/out.txt: no such file or directory", false, time.Time{}},
+		{"rate limit in text", "TestRateLimit failed: rate limit not applied to /login", false, time.Time{}},
+		{"other API error", "API Error: 500 internal server error (see handler.go:429)", false, time.Time{}},
+		{"usage limit with epoch", "Claude AI usage limit reached|1773158400", true, time.Unix(1773158400, 0)},
+		{"rate limit without reset", "API Error: 429 rate_limit_error", true, time.Time{}},
+		{"status code 429", "API Error: Request failed with status code 429", true, time.Time{}},
+		{"retry after seconds", "API Error: 429 Too Many Requests: retry after 30 seconds", true, now.Add(30 * time.Second)},
+		{"retry-after header", "API Error: 429 rate_limit_error (Retry-After: 120)", true, now.Add(2 * time.Minute)},
+		{"try again in minutes", "rate_limit_error: Rate limit exceeded, try again in 5 minutes", true, now.Add(5 * time.Minute)},
+		{"resets later today", "5-hour limit reached ∙ resets 3pm", true, time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)},
+		{"resets tomorrow", "Usage limit reached, resets at 9:30 am", true, time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)},
+		{"resets 24h clock", "Claude usage limit reached. Your limit will reset at 16:45", true, time.Date(2026, 3, 10, 16, 45, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset, limited := ParseRateLimit(tt.msg, now)
+			if limited != tt.wantLimited {
+				t.Fatalf("limited = %v, want %v", limited, tt.wantLimited)
+			}
+			if !reset.Equal(tt.wantReset) {
+				t.Errorf("reset = %v, want %v", reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestParseRateLimit_Zone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) // 08:00 in New York
+	reset, limited := ParseRateLimit("5-hour limit reached ∙ resets 3pm (America/New_York)", now)
+	want := time.Date(2026, 3, 10, 15, 0, 0, 0, loc)
+	if !limited || !reset.Equal(want) {
+		t.Errorf("ParseRateLimit = %v, %v; want %v, true", reset, limited, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -30,16 +31,81 @@ type Config struct {
 	TUI           TUIConfig           `toml:"tui"`
 	Notifications NotificationsConfig `toml:"notifications"`
 	Worktree      WorktreeConfig      `toml:"worktree"`
+	Forge         ForgeConfig         `toml:"forge"`
+	Sandbox       SandboxConfig       `toml:"sandbox"`
+	Policy        PolicyConfig        `toml:"policy"`
 }
 
-// WorktreeConfig controls git worktree support via worktrunk.
+// PolicyConfig holds the tool-use rules checked against every tool call
+// Claude makes. Rules are tried in order and the first match decides.
+type PolicyConfig struct {
+	Rules []PolicyRule `toml:"rules"`
+}
+
+// PolicyRule matches a tool call when all of its set conditions hold.
+type PolicyRule struct {
+	Name        string `toml:"name"`
+	Tool        string `toml:"tool"`         // regexp over the whole tool name, e.g. "Bash" or "Write|Edit"; empty = any tool
+	Field       string `toml:"field"`        // input field checked by match/outside_spec, e.g. "command"; empty = every field
+	Match       string `toml:"match"`        // regexp over the field's value
+	OutsideSpec bool   `toml:"outside_spec"` // the field is a path outside the active spec directory
+	Action      string `toml:"action"`       // "allow", "warn", "stop-iteration", or "stop-loop"
+}
+
+// SandboxConfig runs the Claude CLI inside a bubblewrap jail or a rootless
+// container with only the working directory (project or worktree) writable.
+type SandboxConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Runtime string `toml:"runtime"` // "bwrap", "podman", or "docker"
+	Image   string `toml:"image"`   // container image with the claude CLI (podman/docker)
+
+	// Network is "full" or "none"; podman and docker also accept the name of
+	// a container network, e.g. one that only allows the Anthropic API.
+	Network string `toml:"network"`
+
+	ReadOnlyMounts []string `toml:"read_only_mounts"` // host paths mounted read-only, e.g. toolchains
+	HomeAllowlist  []string `toml:"home_allowlist"`   // paths under $HOME to mount; append ":rw" for read-write
+	Env            []string `toml:"env"`              // environment variables passed into a container
+}
+
+// ForgeConfig controls pull request creation on a code forge (GitHub, GitLab, Gitea).
+type ForgeConfig struct {
+	Provider string   `toml:"provider"`  // "github", "gitlab", or "gitea"; empty = disabled
+	URL      string   `toml:"url"`       // API base URL; empty = provider default (required for gitea)
+	Repo     string   `toml:"repo"`      // "owner/name" (GitLab: project path); empty = derived from origin
+	TokenEnv string   `toml:"token_env"` // environment variable holding the API token; empty = provider default
+	Base     string   `toml:"base"`      // target branch for pull requests
+	Labels   []string `toml:"labels"`    // labels applied to created/updated pull requests
+	Draft    bool     `toml:"draft"`     // open pull requests as drafts
+}
+
+// WorktreeConfig controls git worktree support via worktrunk or plain git.
 type WorktreeConfig struct {
-	Enabled      bool   `toml:"enabled"`
-	MaxParallel  int    `toml:"max_parallel"`
-	AutoMerge    bool   `toml:"auto_merge"`
-	MergeTarget  string `toml:"merge_target"`
-	PathTemplate string `toml:"path_template"` // deprecated: use worktree_dir
-	WorktreeDir  string `toml:"worktree_dir"`  // base directory for worktrees; default ~/.ralph/worktrees
+	Enabled       bool   `toml:"enabled"`
+	MaxParallel   int    `toml:"max_parallel"`
+	AutoMerge     bool   `toml:"auto_merge"`
+	MergeTarget   string `toml:"merge_target"`
+	PathTemplate  string `toml:"path_template"`  // deprecated: use worktree_dir
+	WorktreeDir   string `toml:"worktree_dir"`   // base directory for worktrees; default ~/.ralph/worktrees
+	Backend       string `toml:"backend"`        // "worktrunk" (wt CLI) or "git" (plain git, no worktrunk needed)
+	MergeStrategy string `toml:"merge_strategy"` // git backend only: "rebase", "ff", or "squash"
+
+	// OnConflict is the policy when a merge conflicts: "fail" leaves the agent in
+	// merge_failed; "agent" rebases the branch and asks Claude to resolve the
+	// conflicts in the worktree, then re-runs the test gate and retries.
+	OnConflict          string  `toml:"on_conflict"`
+	ConflictMaxAttempts int     `toml:"conflict_max_attempts"` // resolution attempts per merge
+	ConflictMaxCost     float64 `toml:"conflict_max_cost"`     // USD cap per merge; 0 = unlimited
+
+	// Priorities sets the launch-queue priority per spec name; higher starts
+	// first when more agents are launched than max_parallel allows.
+	Priorities map[string]int `toml:"priorities"`
+
+	// Governor limits shared by all worktree agents. When one agent hits a
+	// Claude rate limit or usage cap, every agent pauses until the reset time
+	// in the error, or for [claude] rate_limit_wait_seconds when none is given.
+	BudgetUSD           float64 `toml:"budget_usd"`            // total USD across agents; 0 = unlimited
+	MaxConcurrentClaude int     `toml:"max_concurrent_claude"` // concurrent Claude invocations; 0 = one per agent
 }
 
 // ResolvedWorktreeDir returns the absolute path for worktree storage.
@@ -79,6 +145,14 @@ type ClaudeConfig struct {
 	Model                 string `toml:"model"`
 	MaxTurns              int    `toml:"max_turns"`
 	DangerSkipPermissions bool   `toml:"danger_skip_permissions"`
+
+	// RateLimitWaitSeconds is how long to pause after a rate-limit or
+	// usage-limit error that gives no reset time.
+	RateLimitWaitSeconds int `toml:"rate_limit_wait_seconds"`
+
+	// Transcripts saves each iteration's raw stream-JSON output to
+	// .ralph/transcripts/<session>/<iteration>.jsonl.
+	Transcripts bool `toml:"transcripts"`
 }
 
 // PlanConfig controls the plan loop.
@@ -89,16 +163,55 @@ type PlanConfig struct {
 
 // BuildConfig controls the build loop.
 type BuildConfig struct {
-	PromptFile    string `toml:"prompt_file"`
-	MaxIterations int    `toml:"max_iterations"`
-	Roam          bool   `toml:"roam"`  // roam freely across the codebase (--roam flag overrides)
-	Focus         string `toml:"focus"` // constrain roam to a specific topic (--focus flag overrides)
+	PromptFile     string `toml:"prompt_file"`
+	MaxIterations  int    `toml:"max_iterations"`
+	Roam           bool   `toml:"roam"`             // roam freely across the codebase (--roam flag overrides)
+	Focus          string `toml:"focus"`            // constrain roam to a specific topic (--focus flag overrides)
+	OnSpecComplete string `toml:"on_spec_complete"` // action when the spec completes: "" (none) or "open_pr"
+
+	// Boundary is what happens when an iteration's commits touch files
+	// outside the globs listed in the active spec's "paths:" front-matter:
+	// "" (off), "warn", "feedback" (warn and tell the next iteration), or
+	// "revert" (restore the files in a new commit, then feed back).
+	Boundary string `toml:"boundary"`
 }
 
 // GitConfig controls git operations between iterations.
 type GitConfig struct {
 	AutoPullRebase bool `toml:"auto_pull_rebase"`
 	AutoPush       bool `toml:"auto_push"`
+
+	// CommitPolicy enforces Conventional Commits on each iteration's new
+	// commits: "" (off), "reword" (rewrite non-compliant unpushed commits and
+	// add Ralph-* trailers), or "strict" (fail the iteration with feedback).
+	CommitPolicy string `toml:"commit_policy"`
+
+	// Strategy controls how agent commits reach the remote: "as-is" (push
+	// every commit), "squash-iteration" (fold each iteration's commits into
+	// one before pushing), or "squash-spec" (squash the branch on merge).
+	Strategy string `toml:"strategy"`
+
+	// ProtectedBranches lists branch names or path.Match globs (e.g.
+	// "release/*") on which ralph never auto-pushes or auto-pull-rebases.
+	ProtectedBranches []string `toml:"protected_branches"`
+
+	// OnProtected selects what a loop does when it starts on a protected
+	// branch: "refuse" (exit with an error) or "branch" (switch to a
+	// generated ralph/<mode>-<timestamp> feature branch first).
+	OnProtected string `toml:"on_protected"`
+}
+
+// IsProtected reports whether branch matches an entry in ProtectedBranches.
+func (g GitConfig) IsProtected(branch string) bool {
+	for _, pattern := range g.ProtectedBranches {
+		if pattern == branch {
+			return true
+		}
+		if ok, err := path.Match(pattern, branch); err == nil && ok {
+			return true
+		}
+	}
+	return false
 }
 
 // RegentConfig controls the Regent supervisor.
@@ -132,6 +245,9 @@ func (c *Config) Validate() error {
 	if c.Claude.MaxTurns < 0 {
 		errs = append(errs, fmt.Errorf("claude.max_turns must be >= 0 (0 = unlimited)"))
 	}
+	if c.Claude.RateLimitWaitSeconds < 1 {
+		errs = append(errs, fmt.Errorf("claude.rate_limit_wait_seconds must be >= 1"))
+	}
 
 	if c.Regent.Enabled {
 		if c.Regent.MaxRetries < 0 {
@@ -167,6 +283,138 @@ func (c *Config) Validate() error {
 		errs = append(errs, fmt.Errorf("worktree.max_parallel must be >= 1"))
 	}
 
+	switch c.Forge.Provider {
+	case "", "github", "gitlab", "gitea":
+	default:
+		errs = append(errs, fmt.Errorf("forge.provider must be one of \"github\", \"gitlab\", \"gitea\" (empty = disabled)"))
+	}
+	if c.Forge.Provider == "gitea" && c.Forge.URL == "" {
+		errs = append(errs, fmt.Errorf("forge.url must be set when forge.provider is \"gitea\""))
+	}
+	if c.Forge.URL != "" {
+		u, parseErr := url.ParseRequestURI(c.Forge.URL)
+		if parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, fmt.Errorf("forge.url must be a valid http or https URL"))
+		}
+	}
+
+	switch c.Git.CommitPolicy {
+	case "", "reword", "strict":
+	default:
+		errs = append(errs, fmt.Errorf("git.commit_policy must be \"\", \"reword\", or \"strict\""))
+	}
+
+	switch c.Git.Strategy {
+	case "", "as-is", "squash-iteration", "squash-spec":
+	default:
+		errs = append(errs, fmt.Errorf("git.strategy must be \"as-is\", \"squash-iteration\", or \"squash-spec\""))
+	}
+	for _, pattern := range c.Git.ProtectedBranches {
+		if _, matchErr := path.Match(pattern, ""); matchErr != nil {
+			errs = append(errs, fmt.Errorf("git.protected_branches: invalid pattern %q", pattern))
+		}
+	}
+	switch c.Git.OnProtected {
+	case "", "refuse", "branch":
+	default:
+		errs = append(errs, fmt.Errorf("git.on_protected must be \"refuse\" or \"branch\""))
+	}
+
+	switch c.Worktree.Backend {
+	case "", "worktrunk", "git":
+	default:
+		errs = append(errs, fmt.Errorf("worktree.backend must be \"worktrunk\" or \"git\""))
+	}
+	switch c.Worktree.MergeStrategy {
+	case "", "rebase", "ff", "squash":
+	default:
+		errs = append(errs, fmt.Errorf("worktree.merge_strategy must be \"rebase\", \"ff\", or \"squash\""))
+	}
+	switch c.Worktree.OnConflict {
+	case "", "fail":
+	case "agent":
+		if c.Worktree.ConflictMaxAttempts < 1 {
+			errs = append(errs, fmt.Errorf("worktree.conflict_max_attempts must be >= 1 when on_conflict is \"agent\""))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("worktree.on_conflict must be \"fail\" or \"agent\""))
+	}
+	if c.Worktree.ConflictMaxCost < 0 {
+		errs = append(errs, fmt.Errorf("worktree.conflict_max_cost must be >= 0"))
+	}
+	if c.Worktree.BudgetUSD < 0 {
+		errs = append(errs, fmt.Errorf("worktree.budget_usd must be >= 0 (0 = unlimited)"))
+	}
+	if c.Worktree.MaxConcurrentClaude < 0 {
+		errs = append(errs, fmt.Errorf("worktree.max_concurrent_claude must be >= 0 (0 = one per agent)"))
+	}
+
+	if c.Sandbox.Enabled {
+		switch c.Sandbox.Runtime {
+		case "bwrap":
+			if c.Sandbox.Network != "full" && c.Sandbox.Network != "none" {
+				errs = append(errs, fmt.Errorf("sandbox.network must be \"full\" or \"none\" with runtime \"bwrap\""))
+			}
+		case "podman", "docker":
+			if c.Sandbox.Image == "" {
+				errs = append(errs, fmt.Errorf("sandbox.image must be set with runtime %q", c.Sandbox.Runtime))
+			}
+			if c.Sandbox.Network == "" {
+				errs = append(errs, fmt.Errorf("sandbox.network must not be empty"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("sandbox.runtime must be \"bwrap\", \"podman\", or \"docker\""))
+		}
+		for _, p := range c.Sandbox.HomeAllowlist {
+			rel := strings.TrimSuffix(p, ":rw")
+			if rel == "" || filepath.IsAbs(rel) || strings.HasPrefix(filepath.Clean(rel), "..") {
+				errs = append(errs, fmt.Errorf("sandbox.home_allowlist: %q must be a path relative to the home directory", p))
+			}
+		}
+		for _, p := range c.Sandbox.ReadOnlyMounts {
+			if !filepath.IsAbs(p) {
+				errs = append(errs, fmt.Errorf("sandbox.read_only_mounts: %q must be an absolute path", p))
+			}
+		}
+	}
+
+	for i, r := range c.Policy.Rules {
+		label := r.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		switch r.Action {
+		case "allow", "warn", "stop-iteration", "stop-loop":
+		default:
+			errs = append(errs, fmt.Errorf("policy.rules %s: action must be \"allow\", \"warn\", \"stop-iteration\", or \"stop-loop\"", label))
+		}
+		if r.Tool == "" && r.Match == "" && !r.OutsideSpec {
+			errs = append(errs, fmt.Errorf("policy.rules %s: set at least one of tool, match, or outside_spec", label))
+		}
+		if _, err := regexp.Compile(r.Tool); err != nil {
+			errs = append(errs, fmt.Errorf("policy.rules %s: invalid tool pattern: %w", label, err))
+		}
+		if _, err := regexp.Compile(r.Match); err != nil {
+			errs = append(errs, fmt.Errorf("policy.rules %s: invalid match pattern: %w", label, err))
+		}
+	}
+
+	switch c.Build.OnSpecComplete {
+	case "":
+	case "open_pr":
+		if c.Forge.Provider == "" {
+			errs = append(errs, fmt.Errorf("forge.provider must be set when build.on_spec_complete is \"open_pr\""))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("build.on_spec_complete must be \"\" or \"open_pr\""))
+	}
+
+	switch c.Build.Boundary {
+	case "", "warn", "feedback", "revert":
+	default:
+		errs = append(errs, fmt.Errorf("build.boundary must be \"\", \"warn\", \"feedback\", or \"revert\""))
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -177,6 +425,7 @@ func Defaults() Config {
 		Claude: ClaudeConfig{
 			Model:                 "sonnet",
 			DangerSkipPermissions: true,
+			RateLimitWaitSeconds:  900,
 		},
 		Plan: PlanConfig{
 			PromptFile:    "PLAN.md",
@@ -188,8 +437,11 @@ func Defaults() Config {
 			Roam:          false,
 		},
 		Git: GitConfig{
-			AutoPullRebase: true,
-			AutoPush:       true,
+			AutoPullRebase:    true,
+			AutoPush:          true,
+			Strategy:          "as-is",
+			ProtectedBranches: []string{"main", "master", "release/*"},
+			OnProtected:       "refuse",
 		},
 		Regent: RegentConfig{
 			Enabled:               true,
@@ -210,10 +462,25 @@ func Defaults() Config {
 			OnStop:     true,
 		},
 		Worktree: WorktreeConfig{
-			Enabled:     false,
-			MaxParallel: 5,
-			AutoMerge:   false,
-			MergeTarget: "",
+			Enabled:             false,
+			MaxParallel:         5,
+			AutoMerge:           false,
+			MergeTarget:         "",
+			Backend:             "worktrunk",
+			MergeStrategy:       "rebase",
+			OnConflict:          "fail",
+			ConflictMaxAttempts: 3,
+			ConflictMaxCost:     2.00,
+		},
+		Forge: ForgeConfig{
+			Base: "main",
+		},
+		Sandbox: SandboxConfig{
+			Enabled:       false,
+			Runtime:       "bwrap",
+			Network:       "full",
+			HomeAllowlist: []string{".claude:rw", ".claude.json:rw", ".gitconfig"},
+			Env:           []string{"ANTHROPIC_API_KEY"},
 		},
 	}
 }
@@ -294,6 +561,8 @@ name = ""
 model = "sonnet"
 max_turns = 0  # 0 = unlimited agentic turns per iteration
 danger_skip_permissions = true
+rate_limit_wait_seconds = 900 # pause on a usage/rate limit that gives no reset time
+transcripts = false # save raw Claude output to .ralph/transcripts/
 
 [plan]
 prompt_file = "PLAN.md"
@@ -304,10 +573,16 @@ prompt_file = "BUILD.md"
 max_iterations = 0  # 0 = unlimited
 roam = false        # roam freely across the codebase (--roam flag overrides)
 focus = ""          # constrain roam to a specific topic (--focus flag overrides)
+on_spec_complete = "" # "open_pr" = open/update a pull request when the spec completes
+boundary = ""       # "warn", "feedback", or "revert" when commits leave the spec's paths: globs
 
 [git]
 auto_pull_rebase = true
 auto_push = true
+commit_policy = ""     # "reword" or "strict" to enforce Conventional Commits
+strategy = "as-is"     # "squash-iteration" or "squash-spec" to squash agent commits
+protected_branches = ["main", "master", "release/*"]  # never auto-push/pull here
+on_protected = "refuse" # or "branch" to switch to a generated feature branch
 
 [regent]
 enabled = true
@@ -328,12 +603,47 @@ on_error = true    # notify on loop error
 on_stop = true     # notify when loop finishes or is stopped
 
 [worktree]
-enabled = false        # enable git worktree support via worktrunk
+enabled = false        # enable git worktree support
 max_parallel = 5       # maximum number of concurrent worktree agents
 auto_merge = false     # automatically merge on spec-complete + tests pass
 merge_target = ""      # branch to merge into (empty = branch worktree was created from)
 path_template = ""     # deprecated: use worktree_dir
 worktree_dir = ""      # base directory for worktrees (default: ~/.ralph/worktrees)
+backend = "worktrunk"  # or "git" to manage worktrees with plain git (no worktrunk needed)
+merge_strategy = "rebase" # git backend: "rebase", "ff", or "squash"
+on_conflict = "fail"   # or "agent" to have Claude resolve merge conflicts in the worktree
+conflict_max_attempts = 3 # resolution attempts per merge (on_conflict = "agent")
+conflict_max_cost = 2.00  # USD cap on conflict resolution per merge; 0 = unlimited
+budget_usd = 0.0       # total spend across all worktree agents; 0 = unlimited
+max_concurrent_claude = 0 # Claude invocations running at once across agents; 0 = one per agent
+priorities = {}        # launch-queue priority per spec, e.g. { "001-core" = 10 }; higher starts first
+
+[forge]
+provider = ""          # "github", "gitlab", or "gitea" (empty = disabled)
+url = ""               # API base URL (empty = provider default; required for gitea)
+repo = ""              # "owner/name" (empty = derived from the origin remote)
+token_env = ""         # env var holding the API token (default: GITHUB_TOKEN / GITLAB_TOKEN / GITEA_TOKEN)
+base = "main"          # target branch for pull requests
+labels = []            # labels applied to pull requests
+draft = false          # open pull requests as drafts
+
+[sandbox]
+enabled = false        # run Claude in a jail with only the project/worktree writable
+runtime = "bwrap"      # "bwrap", "podman", or "docker"
+image = ""             # container image with the claude CLI (podman/docker)
+network = "full"       # "full", "none", or (podman/docker) a container network name
+read_only_mounts = []  # extra host paths mounted read-only, e.g. ["/usr/local/go"]
+home_allowlist = [".claude:rw", ".claude.json:rw", ".gitconfig"] # paths under $HOME; ":rw" = writable
+env = ["ANTHROPIC_API_KEY"] # environment variables passed into a container
+
+# Tool-use policy: checked against every tool call, first match wins.
+# action = "allow", "warn", "stop-iteration", or "stop-loop".
+# [[policy.rules]]
+# name = "no force push"
+# tool = "Bash"
+# field = "command"
+# match = 'git\s+push\s+.*(--force|-f\b)'
+# action = "stop-loop"
 `
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return "", fmt.Errorf("config: write %s: %w", path, err)
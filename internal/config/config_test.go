@@ -18,6 +18,8 @@ func TestDefaults(t *testing.T) {
 		{"claude.model", cfg.Claude.Model, "sonnet"},
 		{"claude.max_turns", cfg.Claude.MaxTurns, 0},
 		{"claude.danger_skip_permissions", cfg.Claude.DangerSkipPermissions, true},
+		{"claude.rate_limit_wait_seconds", cfg.Claude.RateLimitWaitSeconds, 900},
+		{"claude.transcripts", cfg.Claude.Transcripts, false},
 		{"plan.prompt_file", cfg.Plan.PromptFile, "PLAN.md"},
 		{"plan.max_iterations", cfg.Plan.MaxIterations, 3},
 		{"build.prompt_file", cfg.Build.PromptFile, "BUILD.md"},
@@ -41,6 +43,9 @@ func TestDefaults(t *testing.T) {
 		{"worktree.max_parallel", cfg.Worktree.MaxParallel, 5},
 		{"worktree.auto_merge", cfg.Worktree.AutoMerge, false},
 		{"worktree.merge_target", cfg.Worktree.MergeTarget, ""},
+		{"sandbox.enabled", cfg.Sandbox.Enabled, false},
+		{"sandbox.runtime", cfg.Sandbox.Runtime, "bwrap"},
+		{"sandbox.network", cfg.Sandbox.Network, "full"},
 	}
 
 	for _, tt := range tests {
@@ -63,6 +68,7 @@ name = "TestProject"
 model = "opus"
 max_turns = 25
 danger_skip_permissions = false
+transcripts = true
 
 [plan]
 prompt_file = "MY_PLAN.md"
@@ -87,6 +93,9 @@ hang_timeout_seconds = 600
 [tui]
 accent_color = "#FF0000"
 log_retention = 10
+
+[worktree]
+priorities = { "001-core" = 10, "002-api" = -1 }
 `
 		path := filepath.Join(dir, "ralph.toml")
 		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
@@ -107,6 +116,7 @@ log_retention = 10
 			{"claude.model", cfg.Claude.Model, "opus"},
 			{"claude.max_turns", cfg.Claude.MaxTurns, 25},
 			{"claude.danger_skip_permissions", cfg.Claude.DangerSkipPermissions, false},
+			{"claude.transcripts", cfg.Claude.Transcripts, true},
 			{"plan.prompt_file", cfg.Plan.PromptFile, "MY_PLAN.md"},
 			{"plan.max_iterations", cfg.Plan.MaxIterations, 5},
 			{"build.prompt_file", cfg.Build.PromptFile, "MY_BUILD.md"},
@@ -118,6 +128,8 @@ log_retention = 10
 			{"regent.test_command", cfg.Regent.TestCommand, "go test ./..."},
 			{"regent.max_retries", cfg.Regent.MaxRetries, 5},
 			{"regent.retry_backoff_seconds", cfg.Regent.RetryBackoffSeconds, 60},
+			{"worktree.priorities[001-core]", cfg.Worktree.Priorities["001-core"], 10},
+			{"worktree.priorities[002-api]", cfg.Worktree.Priorities["002-api"], -1},
 			{"regent.hang_timeout_seconds", cfg.Regent.HangTimeoutSeconds, 600},
 			{"tui.accent_color", cfg.TUI.AccentColor, "#FF0000"},
 			{"tui.log_retention", cfg.TUI.LogRetention, 10},
@@ -159,6 +171,41 @@ name = "Partial"
 		}
 	})
 
+	t.Run("policy rules", func(t *testing.T) {
+		dir := t.TempDir()
+		content := `
+[[policy.rules]]
+name = "no force push"
+tool = "Bash"
+field = "command"
+match = 'git\s+push\s+.*--force'
+action = "stop-loop"
+
+[[policy.rules]]
+tool = "Write|Edit"
+outside_spec = true
+action = "warn"
+`
+		path := filepath.Join(dir, "ralph.toml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cfg.Policy.Rules) != 2 {
+			t.Fatalf("policy.rules: got %d rules, want 2", len(cfg.Policy.Rules))
+		}
+		if r := cfg.Policy.Rules[0]; r.Name != "no force push" || r.Field != "command" || r.Action != "stop-loop" {
+			t.Errorf("policy.rules[0] = %+v", r)
+		}
+		if r := cfg.Policy.Rules[1]; !r.OutsideSpec || r.Tool != "Write|Edit" {
+			t.Errorf("policy.rules[1] = %+v", r)
+		}
+	})
+
 	t.Run("missing file returns error", func(t *testing.T) {
 		_, err := Load("/nonexistent/ralph.toml")
 		if err == nil {
@@ -585,6 +632,212 @@ func TestValidate(t *testing.T) {
 			name:   "worktree.merge_target set is valid",
 			modify: func(c *Config) { c.Worktree.MergeTarget = "main" },
 		},
+		{
+			name:    "unknown forge.provider",
+			modify:  func(c *Config) { c.Forge.Provider = "bitbucket" },
+			wantErr: "forge.provider must be one of",
+		},
+		{
+			name:   "github forge.provider without url is valid",
+			modify: func(c *Config) { c.Forge.Provider = "github" },
+		},
+		{
+			name:    "gitea forge.provider requires url",
+			modify:  func(c *Config) { c.Forge.Provider = "gitea" },
+			wantErr: "forge.url must be set when forge.provider is \"gitea\"",
+		},
+		{
+			name: "invalid forge.url",
+			modify: func(c *Config) {
+				c.Forge.Provider = "gitlab"
+				c.Forge.URL = "gitlab.example.com"
+			},
+			wantErr: "forge.url must be a valid http or https URL",
+		},
+		{
+			name:    "build.on_spec_complete open_pr requires forge.provider",
+			modify:  func(c *Config) { c.Build.OnSpecComplete = "open_pr" },
+			wantErr: "forge.provider must be set when build.on_spec_complete",
+		},
+		{
+			name: "build.on_spec_complete open_pr with forge is valid",
+			modify: func(c *Config) {
+				c.Build.OnSpecComplete = "open_pr"
+				c.Forge.Provider = "github"
+			},
+		},
+		{
+			name:    "unknown build.on_spec_complete",
+			modify:  func(c *Config) { c.Build.OnSpecComplete = "merge" },
+			wantErr: "build.on_spec_complete must be",
+		},
+		{
+			name:   "build.boundary revert is valid",
+			modify: func(c *Config) { c.Build.Boundary = "revert" },
+		},
+		{
+			name:    "unknown build.boundary",
+			modify:  func(c *Config) { c.Build.Boundary = "block" },
+			wantErr: "build.boundary must be",
+		},
+		{
+			name:   "git.commit_policy reword is valid",
+			modify: func(c *Config) { c.Git.CommitPolicy = "reword" },
+		},
+		{
+			name:   "git.commit_policy strict is valid",
+			modify: func(c *Config) { c.Git.CommitPolicy = "strict" },
+		},
+		{
+			name:    "unknown git.commit_policy",
+			modify:  func(c *Config) { c.Git.CommitPolicy = "lenient" },
+			wantErr: "git.commit_policy must be",
+		},
+		{
+			name:   "git.strategy squash-iteration is valid",
+			modify: func(c *Config) { c.Git.Strategy = "squash-iteration" },
+		},
+		{
+			name:   "empty git.strategy means as-is",
+			modify: func(c *Config) { c.Git.Strategy = "" },
+		},
+		{
+			name:    "unknown git.strategy",
+			modify:  func(c *Config) { c.Git.Strategy = "rebase" },
+			wantErr: "git.strategy must be",
+		},
+		{
+			name:   "git.on_protected branch is valid",
+			modify: func(c *Config) { c.Git.OnProtected = "branch" },
+		},
+		{
+			name:    "unknown git.on_protected",
+			modify:  func(c *Config) { c.Git.OnProtected = "force" },
+			wantErr: "git.on_protected must be",
+		},
+		{
+			name:   "worktree.backend git is valid",
+			modify: func(c *Config) { c.Worktree.Backend = "git"; c.Worktree.MergeStrategy = "squash" },
+		},
+		{
+			name:    "unknown worktree.backend",
+			modify:  func(c *Config) { c.Worktree.Backend = "jj" },
+			wantErr: "worktree.backend must be",
+		},
+		{
+			name:    "unknown worktree.merge_strategy",
+			modify:  func(c *Config) { c.Worktree.MergeStrategy = "octopus" },
+			wantErr: "worktree.merge_strategy must be",
+		},
+		{
+			name:   "worktree.on_conflict agent is valid",
+			modify: func(c *Config) { c.Worktree.OnConflict = "agent" },
+		},
+		{
+			name:    "unknown worktree.on_conflict",
+			modify:  func(c *Config) { c.Worktree.OnConflict = "theirs" },
+			wantErr: "worktree.on_conflict must be",
+		},
+		{
+			name:    "on_conflict agent with zero attempts",
+			modify:  func(c *Config) { c.Worktree.OnConflict = "agent"; c.Worktree.ConflictMaxAttempts = 0 },
+			wantErr: "worktree.conflict_max_attempts must be >= 1",
+		},
+		{
+			name:    "negative worktree.conflict_max_cost",
+			modify:  func(c *Config) { c.Worktree.ConflictMaxCost = -1 },
+			wantErr: "worktree.conflict_max_cost must be >= 0",
+		},
+		{
+			name:    "negative worktree.budget_usd",
+			modify:  func(c *Config) { c.Worktree.BudgetUSD = -5 },
+			wantErr: "worktree.budget_usd must be >= 0",
+		},
+		{
+			name:    "negative worktree.max_concurrent_claude",
+			modify:  func(c *Config) { c.Worktree.MaxConcurrentClaude = -1 },
+			wantErr: "worktree.max_concurrent_claude must be >= 0",
+		},
+		{
+			name:    "zero claude.rate_limit_wait_seconds",
+			modify:  func(c *Config) { c.Claude.RateLimitWaitSeconds = 0 },
+			wantErr: "claude.rate_limit_wait_seconds must be >= 1",
+		},
+		{
+			name:   "enabled bwrap sandbox with defaults is valid",
+			modify: func(c *Config) { c.Sandbox.Enabled = true },
+		},
+		{
+			name:   "disabled sandbox is not validated",
+			modify: func(c *Config) { c.Sandbox.Runtime = "firejail" },
+		},
+		{
+			name:    "invalid sandbox.runtime",
+			modify:  func(c *Config) { c.Sandbox.Enabled, c.Sandbox.Runtime = true, "firejail" },
+			wantErr: "sandbox.runtime must be",
+		},
+		{
+			name:    "bwrap with a named network",
+			modify:  func(c *Config) { c.Sandbox.Enabled, c.Sandbox.Network = true, "anthropic-only" },
+			wantErr: "sandbox.network must be \"full\" or \"none\"",
+		},
+		{
+			name: "podman with a named network is valid",
+			modify: func(c *Config) {
+				c.Sandbox.Enabled, c.Sandbox.Runtime, c.Sandbox.Image, c.Sandbox.Network = true, "podman", "ralph/claude", "anthropic-only"
+			},
+		},
+		{
+			name:    "docker without image",
+			modify:  func(c *Config) { c.Sandbox.Enabled, c.Sandbox.Runtime = true, "docker" },
+			wantErr: "sandbox.image must be set",
+		},
+		{
+			name:    "absolute sandbox.home_allowlist entry",
+			modify:  func(c *Config) { c.Sandbox.Enabled, c.Sandbox.HomeAllowlist = true, []string{"/etc"} },
+			wantErr: "sandbox.home_allowlist",
+		},
+		{
+			name:    "sandbox.home_allowlist entry escaping home",
+			modify:  func(c *Config) { c.Sandbox.Enabled, c.Sandbox.HomeAllowlist = true, []string{"../other:rw"} },
+			wantErr: "sandbox.home_allowlist",
+		},
+		{
+			name:    "relative sandbox.read_only_mounts entry",
+			modify:  func(c *Config) { c.Sandbox.Enabled, c.Sandbox.ReadOnlyMounts = true, []string{"go"} },
+			wantErr: "sandbox.read_only_mounts",
+		},
+		{
+			name: "valid policy rules",
+			modify: func(c *Config) {
+				c.Policy.Rules = []PolicyRule{
+					{Name: "no force push", Tool: "Bash", Field: "command", Match: `git push .*--force`, Action: "stop-loop"},
+					{Tool: "Write|Edit", OutsideSpec: true, Action: "warn"},
+					{Tool: "WebFetch", Action: "stop-iteration"},
+					{Tool: "Read", Action: "allow"},
+				}
+			},
+		},
+		{
+			name:    "policy rule with unknown action",
+			modify:  func(c *Config) { c.Policy.Rules = []PolicyRule{{Name: "x", Tool: "Bash", Action: "kill"}} },
+			wantErr: "policy.rules x: action must be",
+		},
+		{
+			name:    "policy rule without conditions",
+			modify:  func(c *Config) { c.Policy.Rules = []PolicyRule{{Action: "warn"}} },
+			wantErr: "policy.rules #1: set at least one of tool, match, or outside_spec",
+		},
+		{
+			name:    "policy rule with invalid regexp",
+			modify:  func(c *Config) { c.Policy.Rules = []PolicyRule{{Tool: "Bash", Match: "rm -rf (", Action: "warn"}} },
+			wantErr: "policy.rules #1: invalid match pattern",
+		},
+		{
+			name:    "malformed git.protected_branches pattern",
+			modify:  func(c *Config) { c.Git.ProtectedBranches = []string{"release/["} },
+			wantErr: "git.protected_branches: invalid pattern",
+		},
 	}
 
 	for _, tt := range tests {
@@ -687,3 +940,24 @@ func TestResolvedWorktreeDir(t *testing.T) {
 		}
 	})
 }
+
+func TestGitConfigIsProtected(t *testing.T) {
+	g := Defaults().Git
+	for branch, want := range map[string]bool{
+		"main":           true,
+		"master":         true,
+		"release/1.2":    true,
+		"release/1.2/rc": false,
+		"007-feature":    false,
+		"mainline":       false,
+	} {
+		if got := g.IsProtected(branch); got != want {
+			t.Errorf("IsProtected(%q) = %v, want %v", branch, got, want)
+		}
+	}
+
+	g.ProtectedBranches = nil
+	if g.IsProtected("main") {
+		t.Error("empty protected_branches should protect nothing")
+	}
+}
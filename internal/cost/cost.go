@@ -0,0 +1,283 @@
+// Package cost aggregates iteration costs from every stored session log into
+// per-spec, per-day and per-model totals, cost per completed task, and a
+// projection of what the rest of a spec's tasks will cost.
+package cost
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// dayLayout is the key format of per-day buckets.
+const dayLayout = "2006-01-02"
+
+// Record is the cost of one completed iteration.
+type Record struct {
+	Session   string    `json:"session"`
+	Spec      string    `json:"spec"`  // "" when the iteration had no active spec
+	Model     string    `json:"model"` // "" = Claude CLI default
+	Mode      string    `json:"mode"`
+	Iteration int       `json:"iteration"`
+	At        time.Time `json:"at"` // when the iteration finished
+	CostUSD   float64   `json:"cost_usd"`
+	Subtype   string    `json:"subtype"`
+}
+
+// Load reads the iterations of every session log in <dir>/.ralph/logs,
+// oldest first. Iterations logged before the spec was recorded per
+// iteration are attributed to the spec their session's branch resolves to.
+// Unreadable logs are skipped.
+func Load(dir string) ([]Record, error) {
+	paths, err := store.Sessions(filepath.Join(dir, ".ralph", "logs"))
+	if err != nil {
+		return nil, err
+	}
+	branchSpec := map[string]string{}
+	var records []Record
+	for _, path := range paths {
+		s, openErr := store.Open(path)
+		if openErr != nil {
+			continue
+		}
+		sum, _ := s.SessionSummary()
+		iters, _ := s.Iterations()
+		_ = s.Close()
+		for _, it := range iters {
+			r := Record{
+				Session:   sum.SessionID,
+				Spec:      it.Spec,
+				Model:     it.Model,
+				Mode:      it.Mode,
+				Iteration: it.Number,
+				At:        it.EndAt,
+				CostUSD:   it.CostUSD,
+				Subtype:   it.Subtype,
+			}
+			if r.At.IsZero() {
+				r.At = sum.StartedAt
+			}
+			if r.Spec == "" && sum.Branch != "" {
+				name, ok := branchSpec[sum.Branch]
+				if !ok {
+					if as, err := spec.Resolve(dir, "", sum.Branch); err == nil {
+						name = as.Name
+					}
+					branchSpec[sum.Branch] = name
+				}
+				r.Spec = name
+			}
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// Filter selects records.
+type Filter struct {
+	Since time.Time // zero = all time
+	Spec  string    // "" = all specs
+}
+
+// Apply returns the records that match f.
+func (f Filter) Apply(records []Record) []Record {
+	var out []Record
+	for _, r := range records {
+		if !f.Since.IsZero() && r.At.Before(f.Since) {
+			continue
+		}
+		if f.Spec != "" && r.Spec != f.Spec {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Bucket is the spend of one spec, day or model.
+type Bucket struct {
+	Key        string  `json:"key"`
+	CostUSD    float64 `json:"cost_usd"`
+	Iterations int     `json:"iterations"`
+}
+
+// SpecCost is a spec's spend with its task progress.
+type SpecCost struct {
+	Bucket
+	TasksDone  int     `json:"tasks_done"`
+	TasksTotal int     `json:"tasks_total"`
+	PerTask    float64 `json:"cost_per_task_usd"` // 0 when no task is done
+}
+
+// Projection estimates the cost of a spec's unchecked tasks.
+type Projection struct {
+	Spec           string  `json:"spec"`
+	TasksRemaining int     `json:"tasks_remaining"`
+	PerTask        float64 `json:"cost_per_task_usd"`
+	CostUSD        float64 `json:"cost_usd"`
+	// FromAverage is set when the spec has no completed task yet, so the
+	// all-spec average cost per task is used.
+	FromAverage bool `json:"from_average"`
+}
+
+// Report is the cost breakdown of a set of records.
+type Report struct {
+	TotalUSD   float64     `json:"total_usd"`
+	Iterations int         `json:"iterations"`
+	Sessions   int         `json:"sessions"`
+	PerTask    float64     `json:"cost_per_task_usd"` // across specs with completed tasks
+	Specs      []SpecCost  `json:"specs"`             // highest spend first
+	Days       []Bucket    `json:"days"`              // oldest first, including days without spend
+	Models     []Bucket    `json:"models"`            // highest spend first
+	Projection *Projection `json:"projection,omitempty"`
+}
+
+// Summarize builds the report for records. Task progress is read from
+// <dir>/specs/<spec>/tasks.md; active names the spec whose remaining cost
+// is projected ("" for none).
+func Summarize(dir string, records []Record, active string) Report {
+	var rep Report
+	specs := map[string]*SpecCost{}
+	models := map[string]*Bucket{}
+	days := map[string]*Bucket{}
+	sessions := map[string]bool{}
+	var first, last time.Time
+	for _, r := range records {
+		rep.TotalUSD += r.CostUSD
+		rep.Iterations++
+		sessions[r.Session] = true
+		addSpec(specs, r.Spec, r.CostUSD)
+		modelKey := r.Model
+		if modelKey == "" {
+			modelKey = "default"
+		}
+		addBucket(models, modelKey, r.CostUSD)
+		day := r.At.Local().Format(dayLayout)
+		addBucket(days, day, r.CostUSD)
+		if first.IsZero() || r.At.Before(first) {
+			first = r.At
+		}
+		if r.At.After(last) {
+			last = r.At
+		}
+	}
+	rep.Sessions = len(sessions)
+
+	var taskCost float64
+	var tasksDone int
+	for name, sc := range specs {
+		if name != "" {
+			if p, err := spec.ReadTaskProgress(filepath.Join(dir, "specs", name)); err == nil {
+				sc.TasksDone, sc.TasksTotal = p.Done, p.Total
+			}
+		}
+		if sc.TasksDone > 0 {
+			sc.PerTask = sc.CostUSD / float64(sc.TasksDone)
+			taskCost += sc.CostUSD
+			tasksDone += sc.TasksDone
+		}
+		rep.Specs = append(rep.Specs, *sc)
+	}
+	if tasksDone > 0 {
+		rep.PerTask = taskCost / float64(tasksDone)
+	}
+	sort.Slice(rep.Specs, func(i, j int) bool { return byCost(rep.Specs[i].Bucket, rep.Specs[j].Bucket) })
+
+	for _, b := range models {
+		rep.Models = append(rep.Models, *b)
+	}
+	sort.Slice(rep.Models, func(i, j int) bool { return byCost(rep.Models[i], rep.Models[j]) })
+
+	if !first.IsZero() {
+		end := dayStart(last)
+		for d := dayStart(first); !d.After(end); d = d.AddDate(0, 0, 1) {
+			key := d.Format(dayLayout)
+			if b, ok := days[key]; ok {
+				rep.Days = append(rep.Days, *b)
+			} else {
+				rep.Days = append(rep.Days, Bucket{Key: key})
+			}
+		}
+	}
+
+	if active != "" {
+		rep.Projection = project(dir, active, specs[active], rep.PerTask)
+	}
+	return rep
+}
+
+// project estimates the cost of the active spec's unchecked tasks, or
+// returns nil when its tasks cannot be read or no cost per task is known.
+func project(dir, name string, sc *SpecCost, average float64) *Projection {
+	p, err := spec.ReadTaskProgress(filepath.Join(dir, "specs", name))
+	if err != nil || p.Total == 0 {
+		return nil
+	}
+	proj := &Projection{Spec: name, TasksRemaining: p.Remaining()}
+	if sc != nil && sc.PerTask > 0 {
+		proj.PerTask = sc.PerTask
+	} else if average > 0 {
+		proj.PerTask = average
+		proj.FromAverage = true
+	} else {
+		return nil
+	}
+	proj.CostUSD = proj.PerTask * float64(proj.TasksRemaining)
+	return proj
+}
+
+func addSpec(specs map[string]*SpecCost, key string, usd float64) {
+	sc, ok := specs[key]
+	if !ok {
+		sc = &SpecCost{Bucket: Bucket{Key: key}}
+		specs[key] = sc
+	}
+	sc.CostUSD += usd
+	sc.Iterations++
+}
+
+func addBucket(m map[string]*Bucket, key string, usd float64) {
+	b, ok := m[key]
+	if !ok {
+		b = &Bucket{Key: key}
+		m[key] = b
+	}
+	b.CostUSD += usd
+	b.Iterations++
+}
+
+// byCost orders buckets by spend, highest first, then by key.
+func byCost(a, b Bucket) bool {
+	if a.CostUSD != b.CostUSD {
+		return a.CostUSD > b.CostUSD
+	}
+	return a.Key < b.Key
+}
+
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// ParseSince parses a --since value: a date (2006-01-02) or a duration of
+// days or hours back from now, such as 7d or 36h.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(dayLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	var n int
+	var unit string
+	if _, err := fmt.Sscanf(s, "%d%s", &n, &unit); err == nil && n >= 0 {
+		switch unit {
+		case "d":
+			return now.AddDate(0, 0, -n), nil
+		case "h":
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cost: invalid --since %q (want YYYY-MM-DD, 7d or 36h)", s)
+}
@@ -0,0 +1,191 @@
+package cost
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// writeSession writes a session log named id to <dir>/.ralph/logs.
+func writeSession(t *testing.T, dir, id string, entries ...loop.LogEntry) {
+	t.Helper()
+	logs := filepath.Join(dir, ".ralph", "logs")
+	if err := os.MkdirAll(logs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(logs, id+".jsonl"), []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// iteration returns the start and complete entries of one iteration.
+func iteration(n int, spec, model string, at time.Time, usd float64) []loop.LogEntry {
+	return []loop.LogEntry{
+		{Kind: loop.LogIterStart, Iteration: n, Mode: "build", Branch: "feat", Spec: spec, Model: model, Timestamp: at},
+		{Kind: loop.LogIterComplete, Iteration: n, CostUSD: usd, Subtype: "success", Timestamp: at},
+	}
+}
+
+func writeTasks(t *testing.T, dir, spec, tasks string) {
+	t.Helper()
+	specDir := filepath.Join(dir, "specs", spec)
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "tasks.md"), []byte(tasks), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var day1 = time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeSession(t, dir, "100-1", append(iteration(1, "001-auth", "sonnet", day1, 0.5), iteration(2, "001-auth", "", day1, 0.25)...)...)
+	// An older log without per-iteration spec is attributed via its branch.
+	writeTasks(t, dir, "002-billing", "- [ ] a\n")
+	writeSession(t, dir, "200-1",
+		loop.LogEntry{Kind: loop.LogIterStart, Iteration: 1, Branch: "002-billing", Timestamp: day1},
+		loop.LogEntry{Kind: loop.LogIterComplete, Iteration: 1, CostUSD: 1, Timestamp: day1.Add(time.Hour)},
+	)
+
+	records, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Load returned %d records, want 3: %+v", len(records), records)
+	}
+	if r := records[0]; r.Session != "100-1" || r.Spec != "001-auth" || r.Model != "sonnet" || r.CostUSD != 0.5 {
+		t.Errorf("records[0] = %+v", r)
+	}
+	if r := records[2]; r.Spec != "002-billing" || !r.At.Equal(day1.Add(time.Hour)) {
+		t.Errorf("records[2] = %+v, want 002-billing from the branch, finished an hour in", r)
+	}
+}
+
+func TestLoad_NoLogs(t *testing.T) {
+	records, err := Load(t.TempDir())
+	if err != nil || len(records) != 0 {
+		t.Errorf("Load with no logs = %v, %v; want nothing", records, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	records := []Record{
+		{Spec: "a", At: day1},
+		{Spec: "b", At: day1.AddDate(0, 0, 2)},
+		{Spec: "a", At: day1.AddDate(0, 0, 3)},
+	}
+	if got := (Filter{Spec: "a"}).Apply(records); len(got) != 2 {
+		t.Errorf("Spec filter kept %d, want 2", len(got))
+	}
+	if got := (Filter{Since: day1.AddDate(0, 0, 1)}).Apply(records); len(got) != 2 {
+		t.Errorf("Since filter kept %d, want 2", len(got))
+	}
+	if got := (Filter{Since: day1.AddDate(0, 0, 1), Spec: "a"}).Apply(records); len(got) != 1 {
+		t.Errorf("combined filter kept %d, want 1", len(got))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	dir := t.TempDir()
+	writeTasks(t, dir, "001-auth", "- [x] a\n- [x] b\n- [ ] c\n- [ ] d\n- [ ] e\n")
+	writeTasks(t, dir, "002-billing", "- [ ] a\n- [ ] b\n")
+	records := []Record{
+		{Session: "s1", Spec: "001-auth", Model: "sonnet", At: day1, CostUSD: 1.0},
+		{Session: "s1", Spec: "001-auth", At: day1, CostUSD: 0.5},
+		{Session: "s2", Spec: "002-billing", Model: "opus", At: day1.AddDate(0, 0, 2), CostUSD: 2.0},
+	}
+
+	rep := Summarize(dir, records, "001-auth")
+	if rep.TotalUSD != 3.5 || rep.Iterations != 3 || rep.Sessions != 2 {
+		t.Errorf("totals = $%.2f / %d iterations / %d sessions, want $3.50 / 3 / 2", rep.TotalUSD, rep.Iterations, rep.Sessions)
+	}
+	if len(rep.Specs) != 2 || rep.Specs[0].Key != "002-billing" {
+		t.Fatalf("Specs = %+v, want billing first", rep.Specs)
+	}
+	auth := rep.Specs[1]
+	if auth.TasksDone != 2 || auth.TasksTotal != 5 || auth.PerTask != 0.75 {
+		t.Errorf("auth = %+v, want 2/5 tasks at $0.75 each", auth)
+	}
+	if rep.PerTask != 0.75 {
+		t.Errorf("PerTask = %v, want 0.75 (billing has no done tasks)", rep.PerTask)
+	}
+	if len(rep.Days) != 3 || rep.Days[0].CostUSD != 1.5 || rep.Days[1].CostUSD != 0 || rep.Days[2].CostUSD != 2 {
+		t.Errorf("Days = %+v, want three days including the empty one", rep.Days)
+	}
+	if len(rep.Models) != 3 || rep.Models[0].Key != "opus" {
+		t.Errorf("Models = %+v, want opus first", rep.Models)
+	}
+	if p := rep.Projection; p == nil || p.TasksRemaining != 3 || p.CostUSD != 2.25 || p.FromAverage {
+		t.Errorf("Projection = %+v, want 3 tasks × $0.75 = $2.25", p)
+	}
+
+	// No completed task yet: the all-spec average is used.
+	if p := Summarize(dir, records, "002-billing").Projection; p == nil || !p.FromAverage || p.CostUSD != 1.5 {
+		t.Errorf("billing projection = %+v, want 2 tasks × $0.75 average", p)
+	}
+	if p := Summarize(dir, records, "003-missing").Projection; p != nil {
+		t.Errorf("a spec without tasks.md should not be projected, got %+v", p)
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	rep := Summarize(t.TempDir(), nil, "")
+	if rep.TotalUSD != 0 || len(rep.Days) != 0 || rep.Projection != nil {
+		t.Errorf("empty report = %+v", rep)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"36h", now.Add(-36 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "yesterday", "7w", "-3d"} {
+		if _, err := ParseSince(bad, now); err == nil {
+			t.Errorf("ParseSince(%q) should fail", bad)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	err := WriteCSV(&b, []Record{{Session: "s1", Spec: "001-auth", Mode: "build", Iteration: 2, At: day1, CostUSD: 0.125, Subtype: "success"}})
+	if err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || lines[0] != strings.Join(csvHeader, ",") {
+		t.Fatalf("csv = %q", b.String())
+	}
+	if !strings.Contains(lines[1], ",s1,001-auth,default,build,2,0.1250,success") {
+		t.Errorf("row = %q", lines[1])
+	}
+}
@@ -0,0 +1,44 @@
+package cost
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader is the column row written by WriteCSV.
+var csvHeader = []string{"date", "session", "spec", "model", "mode", "iteration", "cost_usd", "subtype"}
+
+// WriteCSV writes one row per record, for spreadsheets and billing exports.
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("cost: write csv: %w", err)
+	}
+	for _, r := range records {
+		model := r.Model
+		if model == "" {
+			model = "default"
+		}
+		row := []string{
+			r.At.Local().Format(time.RFC3339),
+			r.Session,
+			r.Spec,
+			model,
+			r.Mode,
+			strconv.Itoa(r.Iteration),
+			strconv.FormatFloat(r.CostUSD, 'f', 4, 64),
+			r.Subtype,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("cost: write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("cost: write csv: %w", err)
+	}
+	return nil
+}
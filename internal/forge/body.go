@@ -0,0 +1,114 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// Report is the input for a generated pull request body.
+type Report struct {
+	SpecName string            // e.g. "007-worktree-support"
+	SpecPath string            // repo-relative spec directory, e.g. "specs/007-worktree-support"
+	SpecText string            // contents of spec.md (may be empty)
+	Tasks    spec.TaskProgress // checkbox progress from tasks.md
+	Sessions []SessionReport   // oldest first
+}
+
+// SessionReport holds the completed iterations of one ralph session.
+type SessionReport struct {
+	ID         string
+	Iterations []store.IterationSummary
+}
+
+// TotalCost sums the cost of every iteration across all sessions.
+func (r Report) TotalCost() float64 {
+	var total float64
+	for _, s := range r.Sessions {
+		for _, it := range s.Iterations {
+			total += it.CostUSD
+		}
+	}
+	return total
+}
+
+// Title derives the pull request title from the first "# " heading of
+// spec.md, dropping the speckit "Feature Specification:" prefix. Falls back
+// to the spec name when spec.md has no heading.
+func (r Report) Title() string {
+	for _, line := range strings.Split(r.SpecText, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		title := strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		title = strings.TrimSpace(strings.TrimPrefix(title, "Feature Specification:"))
+		if title != "" {
+			return title
+		}
+	}
+	return r.SpecName
+}
+
+// Body renders the Markdown pull request body: task progress, a table of
+// iteration summaries per session, the total cost, and the full spec.md in
+// a collapsed section.
+func (r Report) Body() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Summary\n\n")
+	fmt.Fprintf(&b, "Implements spec `%s`", r.SpecName)
+	if r.SpecPath != "" {
+		fmt.Fprintf(&b, " (`%s`)", r.SpecPath)
+	}
+	b.WriteString(".\n\n")
+
+	b.WriteString("## Progress\n\n")
+	if r.Tasks.Total > 0 {
+		fmt.Fprintf(&b, "- Tasks: %d/%d complete (%d%%)\n", r.Tasks.Done, r.Tasks.Total, r.Tasks.Percent())
+	} else {
+		b.WriteString("- Tasks: no tasks.md checklist\n")
+	}
+	iters := 0
+	for _, s := range r.Sessions {
+		iters += len(s.Iterations)
+	}
+	fmt.Fprintf(&b, "- Iterations: %d across %d session(s)\n", iters, len(r.Sessions))
+	fmt.Fprintf(&b, "- Total cost: $%.2f\n\n", r.TotalCost())
+
+	if iters > 0 {
+		b.WriteString("## Iterations\n\n")
+		b.WriteString("| Session | # | Mode | Result | Cost | Duration | Commit |\n")
+		b.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, s := range r.Sessions {
+			for _, it := range s.Iterations {
+				fmt.Fprintf(&b, "| %s | %d | %s | %s | $%.2f | %s | %s |\n",
+					s.ID, it.Number, it.Mode, it.Subtype, it.CostUSD,
+					formatDuration(it.Duration), escapeCell(it.Commit))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if strings.TrimSpace(r.SpecText) != "" {
+		b.WriteString("<details>\n<summary>spec.md</summary>\n\n")
+		b.WriteString(strings.TrimSpace(r.SpecText))
+		b.WriteString("\n\n</details>\n\n")
+	}
+
+	b.WriteString("---\n_Generated by RalphSpec._\n")
+	return b.String()
+}
+
+// formatDuration renders seconds as a compact duration (e.g. "1m30s").
+func formatDuration(secs float64) string {
+	return time.Duration(secs * float64(time.Second)).Round(time.Second).String()
+}
+
+// escapeCell keeps pipe characters in commit subjects from breaking the table.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,69 @@
+package forge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+func TestReport_Title(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"# Feature Specification: Git Worktree Support\n\nbody", "Git Worktree Support"},
+		{"intro\n# Plain Title\n", "Plain Title"},
+		{"## Only subheadings\n", "007-x"},
+		{"", "007-x"},
+	}
+	for _, tt := range tests {
+		r := Report{SpecName: "007-x", SpecText: tt.text}
+		if got := r.Title(); got != tt.want {
+			t.Errorf("Title(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestReport_Body(t *testing.T) {
+	r := Report{
+		SpecName: "007-x",
+		SpecPath: "specs/007-x",
+		SpecText: "# Feature Specification: X\n\nDetails.",
+		Tasks:    spec.TaskProgress{Done: 3, Total: 4},
+		Sessions: []SessionReport{
+			{ID: "100-1", Iterations: []store.IterationSummary{
+				{Number: 1, Mode: "build", Subtype: "success", CostUSD: 0.5, Duration: 90, Commit: "abc feat: a|b"},
+			}},
+			{ID: "200-2", Iterations: []store.IterationSummary{
+				{Number: 1, Mode: "build", Subtype: "success", CostUSD: 0.25, Duration: 30},
+			}},
+		},
+	}
+	body := r.Body()
+	for _, want := range []string{
+		"Implements spec `007-x` (`specs/007-x`)",
+		"Tasks: 3/4 complete (75%)",
+		"Iterations: 2 across 2 session(s)",
+		"Total cost: $0.75",
+		"| 100-1 | 1 | build | success | $0.50 | 1m30s | abc feat: a\\|b |",
+		"<summary>spec.md</summary>",
+		"Details.",
+		"Generated by RalphSpec",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestReport_BodyEmpty(t *testing.T) {
+	body := Report{SpecName: "x"}.Body()
+	if !strings.Contains(body, "no tasks.md checklist") {
+		t.Errorf("expected no-tasks note:\n%s", body)
+	}
+	if strings.Contains(body, "## Iterations") || strings.Contains(body, "<details>") {
+		t.Errorf("empty report should omit iterations and spec sections:\n%s", body)
+	}
+}
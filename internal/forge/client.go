@@ -0,0 +1,88 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// client is the JSON-over-HTTP helper shared by all adapters.
+type client struct {
+	http *http.Client
+}
+
+// do sends a JSON request and decodes a JSON response into out (when non-nil).
+// header is applied to the request for authentication. Non-2xx responses are
+// returned as errors that include the status and the forge's message.
+func (c *client) do(ctx context.Context, method, url string, header http.Header, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("forge: marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("forge: build request: %w", err)
+	}
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("forge: %s %s: %w", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("forge: read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("forge: %s %s: %s: %s", method, url, resp.Status, errorMessage(data))
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("forge: decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// errorMessage extracts a human-readable message from a forge error body.
+// GitHub and Gitea use {"message": ...}; GitLab uses {"message": ...} or
+// {"error": ...}. Falls back to the raw (trimmed) body.
+func errorMessage(data []byte) string {
+	var e struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(data, &e) == nil {
+		if e.Message != nil {
+			return fmt.Sprintf("%v", e.Message)
+		}
+		if e.Error != "" {
+			return e.Error
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// joinURL joins a base URL and a path without doubling slashes.
+func joinURL(base, path string) string {
+	return strings.TrimSuffix(base, "/") + path
+}
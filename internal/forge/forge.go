@@ -0,0 +1,158 @@
+// Package forge opens and updates pull requests on code forges (GitHub,
+// GitLab, Gitea) through their HTTP APIs. Adapters share the Forge interface
+// so callers can publish a completed spec without knowing which forge hosts
+// the repository.
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+)
+
+// PullRequest describes the desired content of a pull (merge) request.
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string // source branch
+	Base  string // target branch
+	Draft bool
+}
+
+// PR identifies a pull request that exists on the forge.
+type PR struct {
+	Number int
+	URL    string
+}
+
+// Forge is the interface implemented by each forge adapter.
+type Forge interface {
+	// FindOpen returns the open pull request whose source branch is head.
+	// The boolean is false when no such pull request exists.
+	FindOpen(ctx context.Context, head string) (PR, bool, error)
+
+	// Create opens a new pull request.
+	Create(ctx context.Context, pr PullRequest) (PR, error)
+
+	// Update replaces the title and body of an existing pull request.
+	Update(ctx context.Context, number int, pr PullRequest) (PR, error)
+
+	// AddComment posts a comment on an existing pull request.
+	AddComment(ctx context.Context, number int, body string) error
+
+	// SetLabels replaces the labels of an existing pull request.
+	SetLabels(ctx context.Context, number int, labels []string) error
+}
+
+// defaultTokenEnv returns the environment variable consulted for the API
+// token when forge.token_env is empty.
+func defaultTokenEnv(provider string) string {
+	switch provider {
+	case "gitlab":
+		return "GITLAB_TOKEN"
+	case "gitea":
+		return "GITEA_TOKEN"
+	default:
+		return "GITHUB_TOKEN"
+	}
+}
+
+// New builds the adapter for cfg.Provider. repo is the "owner/name" path
+// (already resolved from cfg.Repo or the origin remote). The API token is
+// read from the environment variable named by cfg.TokenEnv.
+func New(cfg config.ForgeConfig, repo string) (Forge, error) {
+	if repo == "" {
+		return nil, fmt.Errorf("forge: repository not set; configure forge.repo")
+	}
+	envName := cfg.TokenEnv
+	if envName == "" {
+		envName = defaultTokenEnv(cfg.Provider)
+	}
+	token := os.Getenv(envName)
+	if token == "" {
+		return nil, fmt.Errorf("forge: $%s is not set", envName)
+	}
+
+	c := &client{http: &http.Client{Timeout: 30 * time.Second}}
+	switch cfg.Provider {
+	case "github":
+		return newGitHub(c, cfg.URL, repo, token), nil
+	case "gitlab":
+		return newGitLab(c, cfg.URL, repo, token), nil
+	case "gitea":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("forge: url is required for gitea")
+		}
+		return newGitea(c, cfg.URL, repo, token), nil
+	case "":
+		return nil, fmt.Errorf("forge: no provider configured; set forge.provider in ralph.toml")
+	default:
+		return nil, fmt.Errorf("forge: unknown provider %q", cfg.Provider)
+	}
+}
+
+// RepoFromRemote extracts the "owner/name" path from a git remote URL.
+// Both SSH ("git@github.com:owner/name.git") and HTTPS
+// ("https://github.com/owner/name") forms are supported; nested GitLab groups
+// are preserved. Returns "" when the URL cannot be parsed.
+func RepoFromRemote(remote string) string {
+	remote = strings.TrimSpace(remote)
+	remote = strings.TrimSuffix(remote, "/")
+	remote = strings.TrimSuffix(remote, ".git")
+
+	var path string
+	switch {
+	case strings.Contains(remote, "://"):
+		rest := remote[strings.Index(remote, "://")+3:]
+		slash := strings.IndexByte(rest, '/')
+		if slash < 0 {
+			return ""
+		}
+		path = rest[slash+1:]
+	case strings.Contains(remote, ":"):
+		path = remote[strings.LastIndex(remote, ":")+1:]
+	default:
+		return ""
+	}
+	if !strings.Contains(path, "/") {
+		return ""
+	}
+	return path
+}
+
+// Publish creates the pull request, or updates it when one is already open
+// for pr.Head, and then applies labels. created reports whether a new pull
+// request was opened.
+func Publish(ctx context.Context, f Forge, pr PullRequest, labels []string) (result PR, created bool, err error) {
+	existing, found, err := f.FindOpen(ctx, pr.Head)
+	if err != nil {
+		return PR{}, false, err
+	}
+	if found {
+		result, err = f.Update(ctx, existing.Number, pr)
+		if err != nil {
+			return PR{}, false, err
+		}
+		if result.URL == "" {
+			result.URL = existing.URL
+		}
+	} else {
+		result, err = f.Create(ctx, pr)
+		if err != nil {
+			return PR{}, false, err
+		}
+		created = true
+	}
+
+	if len(labels) > 0 {
+		if err := f.SetLabels(ctx, result.Number, labels); err != nil {
+			return result, created, err
+		}
+	}
+	return result, created, nil
+}
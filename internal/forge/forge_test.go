@@ -0,0 +1,258 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+)
+
+// recordedRequest captures one request received by the fake forge server.
+type recordedRequest struct {
+	Method string
+	Path   string // path plus raw query
+	Header http.Header
+	Body   map[string]any
+}
+
+// fakeServer is an httptest server that replies from a route table keyed by
+// "METHOD /path" (query string excluded) and records every request.
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func newFakeServer(t *testing.T, routes map[string]string) *fakeServer {
+	t.Helper()
+	fs := &fakeServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if len(data) > 0 {
+			_ = json.Unmarshal(data, &body)
+		}
+		path := r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			path += "?" + r.URL.RawQuery
+		}
+		fs.mu.Lock()
+		fs.requests = append(fs.requests, recordedRequest{Method: r.Method, Path: path, Header: r.Header.Clone(), Body: body})
+		fs.mu.Unlock()
+
+		resp, ok := routes[r.Method+" "+r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"Not Found"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, resp)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *fakeServer) last() recordedRequest {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if len(fs.requests) == 0 {
+		return recordedRequest{}
+	}
+	return fs.requests[len(fs.requests)-1]
+}
+
+func testClient() *client {
+	return &client{http: http.DefaultClient}
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "gh")
+	t.Setenv("MY_TOKEN", "mine")
+	t.Setenv("GITEA_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		cfg     config.ForgeConfig
+		repo    string
+		wantErr string
+		check   func(t *testing.T, f Forge)
+	}{
+		{
+			name: "github default token",
+			cfg:  config.ForgeConfig{Provider: "github"},
+			repo: "o/r",
+			check: func(t *testing.T, f Forge) {
+				g, ok := f.(*GitHub)
+				if !ok {
+					t.Fatalf("want *GitHub, got %T", f)
+				}
+				if g.token != "gh" || g.baseURL != defaultGitHubURL {
+					t.Errorf("got token %q url %q", g.token, g.baseURL)
+				}
+			},
+		},
+		{
+			name: "gitlab custom token env",
+			cfg:  config.ForgeConfig{Provider: "gitlab", TokenEnv: "MY_TOKEN"},
+			repo: "g/s/r",
+			check: func(t *testing.T, f Forge) {
+				if g, ok := f.(*GitLab); !ok || g.token != "mine" {
+					t.Errorf("got %T %+v", f, f)
+				}
+			},
+		},
+		{name: "missing token", cfg: config.ForgeConfig{Provider: "gitea", URL: "http://x"}, repo: "o/r", wantErr: "$GITEA_TOKEN"},
+		{name: "missing repo", cfg: config.ForgeConfig{Provider: "github"}, wantErr: "repository not set"},
+		{name: "no provider", cfg: config.ForgeConfig{}, repo: "o/r", wantErr: "no provider"},
+		{name: "unknown provider", cfg: config.ForgeConfig{Provider: "bitbucket"}, repo: "o/r", wantErr: "unknown provider"},
+		{name: "gitea without url", cfg: config.ForgeConfig{Provider: "gitea", TokenEnv: "MY_TOKEN"}, repo: "o/r", wantErr: "url is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.cfg, tt.repo)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("want error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestRepoFromRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"git@github.com:owner/name.git", "owner/name"},
+		{"https://github.com/owner/name", "owner/name"},
+		{"https://github.com/owner/name.git\n", "owner/name"},
+		{"ssh://git@gitlab.com/group/sub/name.git", "group/sub/name"},
+		{"https://gitea.example.com:3000/owner/name/", "owner/name"},
+		{"/local/path", ""},
+		{"https://example.com", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := RepoFromRemote(tt.remote); got != tt.want {
+			t.Errorf("RepoFromRemote(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+// stubForge records Publish calls without any HTTP.
+type stubForge struct {
+	existing *PR
+	calls    []string
+	labels   []string
+	failOn   string
+}
+
+func (s *stubForge) fail(op string) error {
+	s.calls = append(s.calls, op)
+	if s.failOn == op {
+		return errors.New(op + " failed")
+	}
+	return nil
+}
+
+func (s *stubForge) FindOpen(context.Context, string) (PR, bool, error) {
+	if err := s.fail("find"); err != nil {
+		return PR{}, false, err
+	}
+	if s.existing != nil {
+		return *s.existing, true, nil
+	}
+	return PR{}, false, nil
+}
+
+func (s *stubForge) Create(context.Context, PullRequest) (PR, error) {
+	return PR{Number: 7, URL: "new"}, s.fail("create")
+}
+
+func (s *stubForge) Update(_ context.Context, n int, _ PullRequest) (PR, error) {
+	return PR{Number: n}, s.fail("update")
+}
+
+func (s *stubForge) AddComment(context.Context, int, string) error { return s.fail("comment") }
+
+func (s *stubForge) SetLabels(_ context.Context, _ int, labels []string) error {
+	s.labels = labels
+	return s.fail("labels")
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates when none open", func(t *testing.T) {
+		f := &stubForge{}
+		pr, created, err := Publish(ctx, f, PullRequest{Head: "feat"}, []string{"ralph"})
+		if err != nil || !created || pr.Number != 7 {
+			t.Fatalf("got %+v created=%v err=%v", pr, created, err)
+		}
+		if strings.Join(f.calls, ",") != "find,create,labels" {
+			t.Errorf("calls: %v", f.calls)
+		}
+		if len(f.labels) != 1 || f.labels[0] != "ralph" {
+			t.Errorf("labels: %v", f.labels)
+		}
+	})
+
+	t.Run("updates existing and keeps URL", func(t *testing.T) {
+		f := &stubForge{existing: &PR{Number: 3, URL: "old"}}
+		pr, created, err := Publish(ctx, f, PullRequest{Head: "feat"}, nil)
+		if err != nil || created || pr.Number != 3 || pr.URL != "old" {
+			t.Fatalf("got %+v created=%v err=%v", pr, created, err)
+		}
+		if strings.Join(f.calls, ",") != "find,update" {
+			t.Errorf("calls: %v", f.calls)
+		}
+	})
+
+	for _, op := range []string{"find", "create", "labels"} {
+		t.Run("error from "+op, func(t *testing.T) {
+			f := &stubForge{failOn: op}
+			if _, _, err := Publish(ctx, f, PullRequest{}, []string{"x"}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestClient_ErrorIncludesMessage(t *testing.T) {
+	fs := newFakeServer(t, nil)
+	err := testClient().do(context.Background(), http.MethodGet, fs.URL+"/x", nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "Not Found") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"message":"Validation Failed"}`, "Validation Failed"},
+		{`{"message":["title is too long"]}`, "[title is too long]"},
+		{`{"error":"insufficient_scope"}`, "insufficient_scope"},
+		{"  plain text  ", "plain text"},
+	}
+	for _, tt := range tests {
+		if got := errorMessage([]byte(tt.body)); got != tt.want {
+			t.Errorf("errorMessage(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
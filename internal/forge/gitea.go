@@ -0,0 +1,101 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Gitea is the Forge adapter for the Gitea (and Forgejo) REST API.
+// baseURL is the server root (e.g. "https://gitea.example.com"); the
+// /api/v1 prefix is appended automatically when absent.
+type Gitea struct {
+	c       *client
+	baseURL string
+	repo    string // "owner/name"
+	token   string
+}
+
+func newGitea(c *client, baseURL, repo, token string) *Gitea {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/api/v1") {
+		baseURL += "/api/v1"
+	}
+	return &Gitea{c: c, baseURL: baseURL, repo: repo, token: token}
+}
+
+type giteaPR struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+}
+
+func (g *Gitea) header() http.Header {
+	h := http.Header{}
+	h.Set("Authorization", "token "+g.token)
+	return h
+}
+
+func (g *Gitea) url(format string, args ...any) string {
+	return joinURL(g.baseURL, "/repos/"+g.repo+fmt.Sprintf(format, args...))
+}
+
+// FindOpen lists open pull requests and matches on head.ref, since Gitea's
+// list endpoint does not filter by source branch.
+func (g *Gitea) FindOpen(ctx context.Context, head string) (PR, bool, error) {
+	var prs []giteaPR
+	if err := g.c.do(ctx, http.MethodGet, g.url("/pulls?state=open&limit=50"), g.header(), nil, &prs); err != nil {
+		return PR{}, false, err
+	}
+	for _, p := range prs {
+		if p.Head.Ref == head {
+			return PR{Number: p.Number, URL: p.HTMLURL}, true, nil
+		}
+	}
+	return PR{}, false, nil
+}
+
+// Create opens a pull request. Drafts use Gitea's "WIP: " title prefix.
+func (g *Gitea) Create(ctx context.Context, pr PullRequest) (PR, error) {
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(title, "WIP:") {
+		title = "WIP: " + title
+	}
+	in := map[string]any{
+		"title": title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}
+	var out giteaPR
+	if err := g.c.do(ctx, http.MethodPost, g.url("/pulls"), g.header(), in, &out); err != nil {
+		return PR{}, err
+	}
+	return PR{Number: out.Number, URL: out.HTMLURL}, nil
+}
+
+// Update edits the pull request title and body.
+func (g *Gitea) Update(ctx context.Context, number int, pr PullRequest) (PR, error) {
+	in := map[string]any{"title": pr.Title, "body": pr.Body}
+	var out giteaPR
+	if err := g.c.do(ctx, http.MethodPatch, g.url("/pulls/%d", number), g.header(), in, &out); err != nil {
+		return PR{}, err
+	}
+	return PR{Number: out.Number, URL: out.HTMLURL}, nil
+}
+
+// AddComment posts an issue comment on the pull request.
+func (g *Gitea) AddComment(ctx context.Context, number int, body string) error {
+	in := map[string]any{"body": body}
+	return g.c.do(ctx, http.MethodPost, g.url("/issues/%d/comments", number), g.header(), in, nil)
+}
+
+// SetLabels replaces the pull request's labels. Gitea 1.20+ accepts label
+// names in place of numeric IDs.
+func (g *Gitea) SetLabels(ctx context.Context, number int, labels []string) error {
+	in := map[string]any{"labels": labels}
+	return g.c.do(ctx, http.MethodPut, g.url("/issues/%d/labels", number), g.header(), in, nil)
+}
@@ -0,0 +1,56 @@
+package forge
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGitea(t *testing.T) {
+	ctx := context.Background()
+	fs := newFakeServer(t, map[string]string{
+		"GET /api/v1/repos/o/r/pulls":              `[{"number":1,"html_url":"u1","head":{"ref":"other"}},{"number":2,"html_url":"u2","head":{"ref":"feat"}}]`,
+		"POST /api/v1/repos/o/r/pulls":             `{"number":3,"html_url":"u3"}`,
+		"PATCH /api/v1/repos/o/r/pulls/2":          `{"number":2,"html_url":"u2"}`,
+		"POST /api/v1/repos/o/r/issues/2/comments": `{}`,
+		"PUT /api/v1/repos/o/r/issues/2/labels":    `[]`,
+	})
+	g := newGitea(testClient(), fs.URL+"/", "o/r", "tok")
+
+	pr, found, err := g.FindOpen(ctx, "feat")
+	if err != nil || !found || pr.Number != 2 || pr.URL != "u2" {
+		t.Fatalf("FindOpen: %+v %v %v", pr, found, err)
+	}
+	if got := fs.last().Header.Get("Authorization"); got != "token tok" {
+		t.Errorf("Authorization: %q", got)
+	}
+
+	if _, found, _ := g.FindOpen(ctx, "missing"); found {
+		t.Error("FindOpen(missing): want not found")
+	}
+
+	pr, err = g.Create(ctx, PullRequest{Title: "T", Body: "B", Head: "feat", Base: "main", Draft: true})
+	if err != nil || pr.Number != 3 {
+		t.Fatalf("Create: %+v %v", pr, err)
+	}
+	if body := fs.last().Body; body["title"] != "WIP: T" || body["head"] != "feat" || body["base"] != "main" {
+		t.Errorf("Create body: %v", body)
+	}
+
+	if _, err := g.Update(ctx, 2, PullRequest{Title: "T2", Body: "B2"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := g.AddComment(ctx, 2, "hi"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	if err := g.SetLabels(ctx, 2, []string{"a"}); err != nil {
+		t.Fatalf("SetLabels: %v", err)
+	}
+}
+
+func TestNewGitea_APIPrefix(t *testing.T) {
+	for _, base := range []string{"https://g.example", "https://g.example/", "https://g.example/api/v1"} {
+		if got := newGitea(testClient(), base, "o/r", "t").baseURL; got != "https://g.example/api/v1" {
+			t.Errorf("newGitea(%q).baseURL = %q", base, got)
+		}
+	}
+}
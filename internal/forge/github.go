@@ -0,0 +1,98 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultGitHubURL is the public GitHub REST API root.
+const defaultGitHubURL = "https://api.github.com"
+
+// GitHub is the Forge adapter for the GitHub REST API (github.com or GHES).
+type GitHub struct {
+	c       *client
+	baseURL string
+	repo    string // "owner/name"
+	token   string
+}
+
+func newGitHub(c *client, baseURL, repo, token string) *GitHub {
+	if baseURL == "" {
+		baseURL = defaultGitHubURL
+	}
+	return &GitHub{c: c, baseURL: baseURL, repo: repo, token: token}
+}
+
+type githubPR struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (g *GitHub) header() http.Header {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer "+g.token)
+	h.Set("Accept", "application/vnd.github+json")
+	return h
+}
+
+func (g *GitHub) url(format string, args ...any) string {
+	return joinURL(g.baseURL, "/repos/"+g.repo+fmt.Sprintf(format, args...))
+}
+
+// FindOpen lists open pull requests filtered by "<owner>:<head>".
+func (g *GitHub) FindOpen(ctx context.Context, head string) (PR, bool, error) {
+	owner := g.repo
+	if i := strings.IndexByte(owner, '/'); i >= 0 {
+		owner = owner[:i]
+	}
+	q := url.Values{"state": {"open"}, "head": {owner + ":" + head}}
+	var prs []githubPR
+	if err := g.c.do(ctx, http.MethodGet, g.url("/pulls?%s", q.Encode()), g.header(), nil, &prs); err != nil {
+		return PR{}, false, err
+	}
+	if len(prs) == 0 {
+		return PR{}, false, nil
+	}
+	return PR{Number: prs[0].Number, URL: prs[0].HTMLURL}, true, nil
+}
+
+// Create opens a pull request via POST /repos/{repo}/pulls.
+func (g *GitHub) Create(ctx context.Context, pr PullRequest) (PR, error) {
+	in := map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"draft": pr.Draft,
+	}
+	var out githubPR
+	if err := g.c.do(ctx, http.MethodPost, g.url("/pulls"), g.header(), in, &out); err != nil {
+		return PR{}, err
+	}
+	return PR{Number: out.Number, URL: out.HTMLURL}, nil
+}
+
+// Update edits the title and body via PATCH /repos/{repo}/pulls/{n}.
+func (g *GitHub) Update(ctx context.Context, number int, pr PullRequest) (PR, error) {
+	in := map[string]any{"title": pr.Title, "body": pr.Body}
+	var out githubPR
+	if err := g.c.do(ctx, http.MethodPatch, g.url("/pulls/%d", number), g.header(), in, &out); err != nil {
+		return PR{}, err
+	}
+	return PR{Number: out.Number, URL: out.HTMLURL}, nil
+}
+
+// AddComment posts an issue comment on the pull request.
+func (g *GitHub) AddComment(ctx context.Context, number int, body string) error {
+	in := map[string]any{"body": body}
+	return g.c.do(ctx, http.MethodPost, g.url("/issues/%d/comments", number), g.header(), in, nil)
+}
+
+// SetLabels replaces the pull request's labels via PUT /issues/{n}/labels.
+func (g *GitHub) SetLabels(ctx context.Context, number int, labels []string) error {
+	in := map[string]any{"labels": labels}
+	return g.c.do(ctx, http.MethodPut, g.url("/issues/%d/labels", number), g.header(), in, nil)
+}
@@ -0,0 +1,76 @@
+package forge
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGitHub(t *testing.T) {
+	ctx := context.Background()
+	fs := newFakeServer(t, map[string]string{
+		"GET /repos/o/r/pulls":               `[{"number":12,"html_url":"https://gh/pr/12"}]`,
+		"POST /repos/o/r/pulls":              `{"number":13,"html_url":"https://gh/pr/13"}`,
+		"PATCH /repos/o/r/pulls/12":          `{"number":12,"html_url":"https://gh/pr/12"}`,
+		"POST /repos/o/r/issues/12/comments": `{}`,
+		"PUT /repos/o/r/issues/12/labels":    `[]`,
+	})
+	g := newGitHub(testClient(), fs.URL, "o/r", "tok")
+
+	pr, found, err := g.FindOpen(ctx, "feat")
+	if err != nil || !found || pr.Number != 12 || pr.URL != "https://gh/pr/12" {
+		t.Fatalf("FindOpen: %+v %v %v", pr, found, err)
+	}
+	req := fs.last()
+	if req.Path != "/repos/o/r/pulls?head=o%3Afeat&state=open" {
+		t.Errorf("FindOpen path: %q", req.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer tok" {
+		t.Errorf("Authorization: %q", got)
+	}
+
+	pr, err = g.Create(ctx, PullRequest{Title: "T", Body: "B", Head: "feat", Base: "main", Draft: true})
+	if err != nil || pr.Number != 13 {
+		t.Fatalf("Create: %+v %v", pr, err)
+	}
+	body := fs.last().Body
+	if body["title"] != "T" || body["head"] != "feat" || body["base"] != "main" || body["draft"] != true {
+		t.Errorf("Create body: %v", body)
+	}
+
+	if _, err := g.Update(ctx, 12, PullRequest{Title: "T2", Body: "B2"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if body := fs.last().Body; body["title"] != "T2" || body["body"] != "B2" {
+		t.Errorf("Update body: %v", body)
+	}
+
+	if err := g.AddComment(ctx, 12, "hi"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	if fs.last().Body["body"] != "hi" {
+		t.Errorf("AddComment body: %v", fs.last().Body)
+	}
+
+	if err := g.SetLabels(ctx, 12, []string{"a", "b"}); err != nil {
+		t.Fatalf("SetLabels: %v", err)
+	}
+	if labels, _ := fs.last().Body["labels"].([]any); len(labels) != 2 {
+		t.Errorf("SetLabels body: %v", fs.last().Body)
+	}
+}
+
+func TestGitHub_FindOpenNone(t *testing.T) {
+	fs := newFakeServer(t, map[string]string{"GET /repos/o/r/pulls": `[]`})
+	g := newGitHub(testClient(), fs.URL, "o/r", "tok")
+	if _, found, err := g.FindOpen(context.Background(), "feat"); err != nil || found {
+		t.Errorf("want not found, got found=%v err=%v", found, err)
+	}
+}
+
+func TestGitHub_Error(t *testing.T) {
+	fs := newFakeServer(t, nil)
+	g := newGitHub(testClient(), fs.URL, "o/r", "tok")
+	if _, err := g.Create(context.Background(), PullRequest{}); err == nil {
+		t.Error("expected error from 404")
+	}
+}
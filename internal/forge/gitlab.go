@@ -0,0 +1,97 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultGitLabURL is the gitlab.com REST API root.
+const defaultGitLabURL = "https://gitlab.com/api/v4"
+
+// GitLab is the Forge adapter for the GitLab REST API. Pull requests map to
+// merge requests; Number is the merge request IID.
+type GitLab struct {
+	c       *client
+	baseURL string
+	project string // "group/subgroup/name"
+	token   string
+}
+
+func newGitLab(c *client, baseURL, project, token string) *GitLab {
+	if baseURL == "" {
+		baseURL = defaultGitLabURL
+	}
+	return &GitLab{c: c, baseURL: baseURL, project: project, token: token}
+}
+
+type gitlabMR struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+func (g *GitLab) header() http.Header {
+	h := http.Header{}
+	h.Set("PRIVATE-TOKEN", g.token)
+	return h
+}
+
+func (g *GitLab) url(format string, args ...any) string {
+	return joinURL(g.baseURL, "/projects/"+url.PathEscape(g.project)+fmt.Sprintf(format, args...))
+}
+
+// FindOpen lists opened merge requests with the given source branch.
+func (g *GitLab) FindOpen(ctx context.Context, head string) (PR, bool, error) {
+	q := url.Values{"state": {"opened"}, "source_branch": {head}}
+	var mrs []gitlabMR
+	if err := g.c.do(ctx, http.MethodGet, g.url("/merge_requests?%s", q.Encode()), g.header(), nil, &mrs); err != nil {
+		return PR{}, false, err
+	}
+	if len(mrs) == 0 {
+		return PR{}, false, nil
+	}
+	return PR{Number: mrs[0].IID, URL: mrs[0].WebURL}, true, nil
+}
+
+// Create opens a merge request. Drafts use GitLab's "Draft: " title prefix.
+func (g *GitLab) Create(ctx context.Context, pr PullRequest) (PR, error) {
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+	in := map[string]any{
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+		"title":         title,
+		"description":   pr.Body,
+	}
+	var out gitlabMR
+	if err := g.c.do(ctx, http.MethodPost, g.url("/merge_requests"), g.header(), in, &out); err != nil {
+		return PR{}, err
+	}
+	return PR{Number: out.IID, URL: out.WebURL}, nil
+}
+
+// Update edits the merge request title and description.
+func (g *GitLab) Update(ctx context.Context, number int, pr PullRequest) (PR, error) {
+	in := map[string]any{"title": pr.Title, "description": pr.Body}
+	var out gitlabMR
+	if err := g.c.do(ctx, http.MethodPut, g.url("/merge_requests/%d", number), g.header(), in, &out); err != nil {
+		return PR{}, err
+	}
+	return PR{Number: out.IID, URL: out.WebURL}, nil
+}
+
+// AddComment posts a note on the merge request.
+func (g *GitLab) AddComment(ctx context.Context, number int, body string) error {
+	in := map[string]any{"body": body}
+	return g.c.do(ctx, http.MethodPost, g.url("/merge_requests/%d/notes", number), g.header(), in, nil)
+}
+
+// SetLabels replaces the merge request's labels (comma-separated in GitLab).
+func (g *GitLab) SetLabels(ctx context.Context, number int, labels []string) error {
+	in := map[string]any{"labels": strings.Join(labels, ",")}
+	return g.c.do(ctx, http.MethodPut, g.url("/merge_requests/%d", number), g.header(), in, nil)
+}
@@ -0,0 +1,65 @@
+package forge
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGitLab(t *testing.T) {
+	ctx := context.Background()
+	const project = "/projects/grp%2Fsub%2Fr"
+	fs := newFakeServer(t, map[string]string{
+		"GET " + project + "/merge_requests":          `[{"iid":4,"web_url":"https://gl/mr/4"}]`,
+		"POST " + project + "/merge_requests":         `{"iid":5,"web_url":"https://gl/mr/5"}`,
+		"PUT " + project + "/merge_requests/4":        `{"iid":4,"web_url":"https://gl/mr/4"}`,
+		"POST " + project + "/merge_requests/4/notes": `{}`,
+	})
+	g := newGitLab(testClient(), fs.URL, "grp/sub/r", "tok")
+
+	pr, found, err := g.FindOpen(ctx, "feat")
+	if err != nil || !found || pr.Number != 4 || pr.URL != "https://gl/mr/4" {
+		t.Fatalf("FindOpen: %+v %v %v", pr, found, err)
+	}
+	req := fs.last()
+	if req.Path != project+"/merge_requests?source_branch=feat&state=opened" {
+		t.Errorf("FindOpen path: %q", req.Path)
+	}
+	if got := req.Header.Get("PRIVATE-TOKEN"); got != "tok" {
+		t.Errorf("PRIVATE-TOKEN: %q", got)
+	}
+
+	pr, err = g.Create(ctx, PullRequest{Title: "T", Body: "B", Head: "feat", Base: "main", Draft: true})
+	if err != nil || pr.Number != 5 {
+		t.Fatalf("Create: %+v %v", pr, err)
+	}
+	body := fs.last().Body
+	if body["title"] != "Draft: T" || body["source_branch"] != "feat" || body["target_branch"] != "main" || body["description"] != "B" {
+		t.Errorf("Create body: %v", body)
+	}
+
+	if _, err := g.Update(ctx, 4, PullRequest{Title: "T2", Body: "B2"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if body := fs.last().Body; body["title"] != "T2" || body["description"] != "B2" {
+		t.Errorf("Update body: %v", body)
+	}
+
+	if err := g.AddComment(ctx, 4, "hi"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	if err := g.SetLabels(ctx, 4, []string{"a", "b"}); err != nil {
+		t.Fatalf("SetLabels: %v", err)
+	}
+	if got := fs.last().Body["labels"]; got != "a,b" {
+		t.Errorf("SetLabels labels: %v", got)
+	}
+}
+
+func TestGitLab_FindOpenNone(t *testing.T) {
+	fs := newFakeServer(t, map[string]string{"GET /projects/o%2Fr/merge_requests": `[]`})
+	g := newGitLab(testClient(), fs.URL, "o/r", "tok")
+	if _, found, err := g.FindOpen(context.Background(), "feat"); err != nil || found {
+		t.Errorf("want not found, got found=%v err=%v", found, err)
+	}
+}
@@ -0,0 +1,333 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Commit is one commit in a range returned by CommitsBetween.
+type Commit struct {
+	SHA     string   // full SHA
+	Parents []string // full parent SHAs
+	Message string   // full commit message (subject, body, trailers)
+}
+
+// Subject returns the first line of the commit message.
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// HeadSHA returns the full SHA of HEAD.
+func (r *Runner) HeadSHA() (string, error) {
+	out, err := r.run("rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git head sha: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// CommitsBetween returns the commits reachable from to but not from, oldest
+// first. An empty from returns every commit reachable from to.
+func (r *Runner) CommitsBetween(from, to string) ([]Commit, error) {
+	rangeArg := to
+	if from != "" {
+		rangeArg = from + ".." + to
+	}
+	out, err := r.run("log", "--reverse", commitLogFormat, rangeArg)
+	if err != nil {
+		return nil, fmt.Errorf("git log %s: %w", rangeArg, err)
+	}
+	return parseCommitLog(out), nil
+}
+
+// CommitsWithTrailer returns the commits reachable from HEAD whose trailer
+// block sets key to value, oldest first.
+func (r *Runner) CommitsWithTrailer(key, value string) ([]Commit, error) {
+	out, err := r.run("log", "--reverse", "--fixed-strings", "--grep="+key+": "+value, commitLogFormat, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("git log --grep %s: %w", key, err)
+	}
+	var commits []Commit
+	for _, c := range parseCommitLog(out) {
+		if ParseTrailers(c.Message)[key] == value {
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
+
+// commitLogFormat is the git log --format read by parseCommitLog. Fields are
+// separated by US (0x1f) and records by RS (0x1e) so that multi-line
+// messages survive the round trip.
+const commitLogFormat = "--format=%H%x1f%P%x1f%B%x1e"
+
+// parseCommitLog parses git log output written with commitLogFormat.
+func parseCommitLog(out string) []Commit {
+	var commits []Commit
+	for _, rec := range strings.Split(out, "\x1e") {
+		rec = strings.TrimLeft(rec, "\n")
+		if rec == "" {
+			continue
+		}
+		fields := strings.SplitN(rec, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			SHA:     fields[0],
+			Parents: strings.Fields(fields[1]),
+			Message: strings.TrimRight(fields[2], "\n"),
+		})
+	}
+	return commits
+}
+
+// IsPushed reports whether sha is already contained in origin/<branch>.
+// Returns false when the remote branch does not exist.
+func (r *Runner) IsPushed(sha, branch string) bool {
+	_, err := r.run("merge-base", "--is-ancestor", sha, "origin/"+branch)
+	return err == nil
+}
+
+// AddedLines returns the lines added to path by the commit sha (without the
+// leading "+"). path may be absolute or relative to the repository root.
+func (r *Runner) AddedLines(sha, path string) ([]string, error) {
+	out, err := r.run("show", "--format=", "--unified=0", sha, "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("git show %s: %w", sha, err)
+	}
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+			lines = append(lines, line[1:])
+		}
+	}
+	return lines, nil
+}
+
+// RewordCommits rewrites the messages of the commits in from..HEAD. messages
+// maps a full commit SHA to its new message; commits not in the map keep
+// their message. Trees and authorship are preserved and the working tree is
+// untouched: each commit is recreated with commit-tree and the branch is
+// moved with update-ref. Merge commits in the range are rejected. Returns the
+// new HEAD SHA.
+func (r *Runner) RewordCommits(from string, messages map[string]string) (string, error) {
+	oldHead, err := r.HeadSHA()
+	if err != nil {
+		return "", err
+	}
+	commits, err := r.CommitsBetween(from, oldHead)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range commits {
+		if len(c.Parents) > 1 {
+			return "", fmt.Errorf("git reword: %s is a merge commit", c.SHA[:7])
+		}
+	}
+
+	parent := ""
+	if from != "" {
+		out, revErr := r.run("rev-parse", from)
+		if revErr != nil {
+			return "", fmt.Errorf("git reword: resolve %s: %w", from, revErr)
+		}
+		parent = strings.TrimSpace(out)
+	}
+
+	rewritten := false
+	for _, c := range commits {
+		msg, ok := messages[c.SHA]
+		if !ok || msg == c.Message {
+			if !rewritten {
+				// Unchanged prefix: keep the original commit and its SHA.
+				parent = c.SHA
+				continue
+			}
+			msg = c.Message
+		}
+		newSHA, ctErr := r.recommit(c.SHA, parent, msg)
+		if ctErr != nil {
+			return "", ctErr
+		}
+		parent = newSHA
+		rewritten = true
+	}
+	if !rewritten {
+		return oldHead, nil
+	}
+
+	if _, err := r.run("update-ref", "-m", "ralph: reword commits", "HEAD", parent, oldHead); err != nil {
+		return "", fmt.Errorf("git reword: update-ref: %w", err)
+	}
+	return parent, nil
+}
+
+// recommit creates a copy of sha with the given parent and message,
+// preserving its tree and author identity and date.
+func (r *Runner) recommit(sha, parent, msg string) (string, error) {
+	out, err := r.run("log", "-1", "--date=raw", "--format=%T%x1f%an%x1f%ae%x1f%ad", sha)
+	if err != nil {
+		return "", fmt.Errorf("git reword: read %s: %w", sha, err)
+	}
+	fields := strings.SplitN(strings.TrimSpace(out), "\x1f", 4)
+	if len(fields) != 4 {
+		return "", fmt.Errorf("git reword: unexpected metadata for %s", sha)
+	}
+	args := []string{"commit-tree", fields[0]}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	args = append(args, "-F", "-")
+	env := []string{
+		"GIT_AUTHOR_NAME=" + fields[1],
+		"GIT_AUTHOR_EMAIL=" + fields[2],
+		"GIT_AUTHOR_DATE=" + fields[3],
+	}
+	newSHA, err := r.runInput(env, msg+"\n", args...)
+	if err != nil {
+		return "", fmt.Errorf("git reword: commit-tree %s: %w", sha, err)
+	}
+	return strings.TrimSpace(newSHA), nil
+}
+
+// runInput is like run but feeds stdin to the command and appends env to the
+// process environment.
+func (r *Runner) runInput(env []string, stdin string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("%s: %w", errMsg, err)
+	}
+	return stdout.String(), nil
+}
+
+// SoftReset moves HEAD to ref, keeping the index and working tree.
+func (r *Runner) SoftReset(ref string) error {
+	if _, err := r.run("reset", "--soft", ref); err != nil {
+		return fmt.Errorf("git reset --soft %s: %w", ref, err)
+	}
+	return nil
+}
+
+// Commit records the staged changes with message.
+func (r *Runner) Commit(message string) error {
+	if _, err := r.runInput(nil, message+"\n", "commit", "--quiet", "-F", "-"); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+	return nil
+}
+
+// CommitPaths stages paths and commits only them with message, leaving any
+// other staged changes staged.
+func (r *Runner) CommitPaths(message string, paths ...string) error {
+	if _, err := r.run(append([]string{"add", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("git add: %w", err)
+	}
+	args := append([]string{"commit", "--quiet", "-F", "-", "--"}, paths...)
+	if _, err := r.runInput(nil, message+"\n", args...); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+	return nil
+}
+
+// MergeBase returns the best common ancestor of a and b.
+func (r *Runner) MergeBase(a, b string) (string, error) {
+	out, err := r.run("merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// FileAt returns the contents of path as of ref.
+func (r *Runner) FileAt(ref, path string) (string, error) {
+	out, err := r.run("show", ref+":"+path)
+	if err != nil {
+		return "", fmt.Errorf("git show %s:%s: %w", ref, path, err)
+	}
+	return out, nil
+}
+
+// DefaultBranch returns the branch origin/HEAD points at, falling back to a
+// local "main" or "master" branch.
+func (r *Runner) DefaultBranch() (string, error) {
+	if out, err := r.run("symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(out), "origin/"), nil
+	}
+	for _, b := range []string{"main", "master"} {
+		if _, err := r.run("rev-parse", "--verify", "--quiet", "refs/heads/"+b); err == nil {
+			return b, nil
+		}
+	}
+	return "", fmt.Errorf("git default branch: no origin/HEAD, main, or master")
+}
+
+// Squash folds the commits in base..HEAD into a single commit with message
+// using a soft reset, and returns the original commits (oldest first). Fewer
+// than two commits are left untouched. Staged changes are rejected so they
+// are not folded into the squash commit by accident.
+func (r *Runner) Squash(base, message string) ([]Commit, error) {
+	commits, err := r.CommitsBetween(base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) < 2 {
+		return commits, nil
+	}
+	if _, err := r.run("diff", "--cached", "--quiet"); err != nil {
+		return nil, fmt.Errorf("git squash: index has staged changes")
+	}
+	head, err := r.HeadSHA()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.SoftReset(base); err != nil {
+		return nil, err
+	}
+	if err := r.Commit(message); err != nil {
+		// Restore the original history so nothing is lost.
+		_ = r.SoftReset(head)
+		return nil, err
+	}
+	return commits, nil
+}
+
+// SquashBranch squashes every commit on the current branch since it forked
+// from target into one commit whose message is subject followed by the list
+// of squashed commits. An empty target uses DefaultBranch.
+func (r *Runner) SquashBranch(target, subject string) ([]Commit, error) {
+	if target == "" {
+		var err error
+		if target, err = r.DefaultBranch(); err != nil {
+			return nil, err
+		}
+	}
+	base, err := r.MergeBase(target, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	commits, err := r.CommitsBetween(base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) < 2 {
+		return commits, nil
+	}
+	return r.Squash(base, SquashMessage(subject, commits))
+}
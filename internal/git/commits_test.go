@@ -0,0 +1,343 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// commitFile writes content to name and commits it with msg.
+func commitFile(t *testing.T, dir, name, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"git", "add", name},
+		{"git", "commit", "-m", msg},
+	} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %s (%v)", args, out, err)
+		}
+	}
+}
+
+func TestCommitsBetween(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, err := r.HeadSHA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "a.txt", "a", "Add a\n\nWith a body.")
+	commitFile(t, dir, "b.txt", "b", "fix: b")
+
+	commits, err := r.CommitsBetween(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("want 2 commits, got %d", len(commits))
+	}
+	if commits[0].Subject() != "Add a" || commits[0].Message != "Add a\n\nWith a body." {
+		t.Errorf("first commit: %+v", commits[0])
+	}
+	if commits[1].Subject() != "fix: b" {
+		t.Errorf("second commit subject: %q", commits[1].Subject())
+	}
+	if len(commits[0].Parents) != 1 || commits[0].Parents[0] != base {
+		t.Errorf("first commit parents: %v", commits[0].Parents)
+	}
+
+	all, err := r.CommitsBetween("", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("CommitsBetween(\"\", HEAD): want 3, got %d", len(all))
+	}
+}
+
+func TestAddedLines(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	commitFile(t, dir, "tasks.md", "- [ ] T001 one\n- [ ] T002 two\n", "tasks")
+	commitFile(t, dir, "tasks.md", "- [x] T001 one\n- [ ] T002 two\n", "check T001")
+	head, _ := r.HeadSHA()
+
+	lines, err := r.AddedLines(head, filepath.Join(dir, "tasks.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0] != "- [x] T001 one" {
+		t.Errorf("AddedLines = %q", lines)
+	}
+}
+
+func TestIsPushed(t *testing.T) {
+	workDir, _ := initTestRepoWithRemote(t)
+	r := NewRunner(workDir)
+	pushed, _ := r.HeadSHA()
+	commitFile(t, workDir, "x.txt", "x", "local only")
+	local, _ := r.HeadSHA()
+
+	if !r.IsPushed(pushed, "main") {
+		t.Error("initial commit should be pushed")
+	}
+	if r.IsPushed(local, "main") {
+		t.Error("local commit should not be pushed")
+	}
+	if r.IsPushed(pushed, "no-such-branch") {
+		t.Error("missing remote branch should report not pushed")
+	}
+}
+
+func TestRewordCommits(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, _ := r.HeadSHA()
+	commitFile(t, dir, "a.txt", "a", "fix: keep me")
+	commitFile(t, dir, "b.txt", "b", "Add b")
+	commitFile(t, dir, "c.txt", "c", "chore: c")
+
+	before, _ := r.CommitsBetween(base, "HEAD")
+	newHead, err := r.RewordCommits(base, map[string]string{
+		before[1].SHA: "feat: add b\n\nRalph-Iteration: 1",
+	})
+	if err != nil {
+		t.Fatalf("RewordCommits: %v", err)
+	}
+
+	after, _ := r.CommitsBetween(base, "HEAD")
+	if len(after) != 3 {
+		t.Fatalf("want 3 commits after reword, got %d", len(after))
+	}
+	if after[0].SHA != before[0].SHA {
+		t.Error("unchanged prefix commit should keep its SHA")
+	}
+	if after[1].Message != "feat: add b\n\nRalph-Iteration: 1" {
+		t.Errorf("reworded message: %q", after[1].Message)
+	}
+	if after[2].SHA == before[2].SHA || after[2].Message != "chore: c" {
+		t.Errorf("descendant should be recreated with same message: %+v", after[2])
+	}
+	if head, _ := r.HeadSHA(); head != newHead {
+		t.Errorf("HEAD = %s, want %s", head, newHead)
+	}
+	if dirty, _ := r.HasUncommittedChanges(); dirty {
+		t.Error("reword should leave the working tree clean")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.txt")); err != nil {
+		t.Errorf("files should be preserved: %v", err)
+	}
+
+	// Author is preserved.
+	out, _ := r.run("log", "-1", "--format=%an <%ae>", after[1].SHA)
+	if strings.TrimSpace(out) != "Test <test@test.com>" {
+		t.Errorf("author = %q", out)
+	}
+}
+
+func TestRewordCommits_NoChanges(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, _ := r.HeadSHA()
+	commitFile(t, dir, "a.txt", "a", "fix: a")
+	head, _ := r.HeadSHA()
+
+	got, err := r.RewordCommits(base, nil)
+	if err != nil || got != head {
+		t.Errorf("RewordCommits(nil) = %s, %v; want %s", got, err, head)
+	}
+}
+
+func TestRewordCommits_RejectsMerge(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, _ := r.HeadSHA()
+	for _, args := range [][]string{
+		{"git", "checkout", "-b", "side"},
+		{"git", "commit", "--allow-empty", "-m", "side"},
+		{"git", "checkout", "main"},
+		{"git", "commit", "--allow-empty", "-m", "main"},
+		{"git", "merge", "--no-ff", "-m", "merge side", "side"},
+	} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %s (%v)", args, out, err)
+		}
+	}
+	if _, err := r.RewordCommits(base, nil); err == nil || !strings.Contains(err.Error(), "merge commit") {
+		t.Errorf("expected merge-commit error, got %v", err)
+	}
+}
+
+func TestSquash(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, _ := r.HeadSHA()
+	commitFile(t, dir, "a.txt", "a", "feat: a")
+	commitFile(t, dir, "b.txt", "b", "fix: b")
+
+	orig, err := r.Squash(base, "feat: a and b")
+	if err != nil {
+		t.Fatalf("Squash: %v", err)
+	}
+	if len(orig) != 2 || orig[0].Subject() != "feat: a" {
+		t.Errorf("original commits: %+v", orig)
+	}
+	after, _ := r.CommitsBetween(base, "HEAD")
+	if len(after) != 1 || after[0].Message != "feat: a and b" {
+		t.Fatalf("after squash: %+v", after)
+	}
+	for _, f := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("%s missing after squash", f)
+		}
+	}
+	if dirty, _ := r.HasUncommittedChanges(); dirty {
+		t.Error("working tree should be clean after squash")
+	}
+}
+
+func TestSquash_SingleCommitUntouched(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, _ := r.HeadSHA()
+	commitFile(t, dir, "a.txt", "a", "feat: a")
+	head, _ := r.HeadSHA()
+
+	orig, err := r.Squash(base, "ignored")
+	if err != nil || len(orig) != 1 {
+		t.Fatalf("Squash = %v, %v", orig, err)
+	}
+	if now, _ := r.HeadSHA(); now != head {
+		t.Error("single commit should not be rewritten")
+	}
+}
+
+func TestSquash_RejectsStagedChanges(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	base, _ := r.HeadSHA()
+	commitFile(t, dir, "a.txt", "a", "feat: a")
+	commitFile(t, dir, "b.txt", "b", "feat: b")
+	head, _ := r.HeadSHA()
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.run("add", "c.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Squash(base, "x"); err == nil || !strings.Contains(err.Error(), "staged") {
+		t.Errorf("expected staged-changes error, got %v", err)
+	}
+	if now, _ := r.HeadSHA(); now != head {
+		t.Error("HEAD should be unchanged after rejected squash")
+	}
+}
+
+func TestSquashBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	if _, err := r.run("checkout", "-b", "007-x"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "a.txt", "a", "feat: a")
+	commitFile(t, dir, "b.txt", "b", "fix: b")
+
+	orig, err := r.SquashBranch("", "feat: x")
+	if err != nil {
+		t.Fatalf("SquashBranch: %v", err)
+	}
+	if len(orig) != 2 {
+		t.Fatalf("want 2 original commits, got %d", len(orig))
+	}
+	msg, _ := r.run("log", "-1", "--format=%B")
+	want := "feat: x\n\nSquashed commits:\n- " + orig[0].SHA[:7] + " feat: a\n- " + orig[1].SHA[:7] + " fix: b"
+	if strings.TrimSpace(msg) != want {
+		t.Errorf("message:\n%s\nwant:\n%s", msg, want)
+	}
+	count, _ := r.run("rev-list", "--count", "main..HEAD")
+	if strings.TrimSpace(count) != "1" {
+		t.Errorf("want 1 commit ahead of main, got %s", count)
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	got, err := NewRunner(dir).DefaultBranch()
+	if err != nil || got != "main" {
+		t.Errorf("DefaultBranch = %q, %v", got, err)
+	}
+}
+
+func TestFileAt(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.FileAt("main", "README.md")
+	if err != nil || got != "# test\n" {
+		t.Errorf("FileAt = %q, %v; want the committed content", got, err)
+	}
+	if _, err := r.FileAt("main", "missing.md"); err == nil {
+		t.Error("expected an error for a path missing at ref")
+	}
+}
+
+func TestCommitPaths(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	for name, content := range map[string]string{"report.md": "# report", "other.txt": "staged"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := r.run("add", "other.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.CommitPaths("docs: add report", "report.md"); err != nil {
+		t.Fatalf("CommitPaths: %v", err)
+	}
+	files, err := r.run("show", "--name-only", "--format=%s", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(files, "docs: add report") || !strings.Contains(files, "report.md") || strings.Contains(files, "other.txt") {
+		t.Errorf("HEAD = %q, want only report.md committed", files)
+	}
+	staged, _ := r.run("diff", "--cached", "--name-only")
+	if strings.TrimSpace(staged) != "other.txt" {
+		t.Errorf("staged = %q, want other.txt left staged", staged)
+	}
+}
+
+func TestCommitsWithTrailer(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	commitFile(t, dir, "a.txt", "a", "feat: a\n\nRalph-Session: s1")
+	commitFile(t, dir, "b.txt", "b", "feat: b\n\nRalph-Session: s10")
+	commitFile(t, dir, "c.txt", "c", "docs: quote Ralph-Session: s1 in the subject")
+	commitFile(t, dir, "d.txt", "d", "fix: d\n\nRalph-Iteration: 2\nRalph-Session: s1")
+
+	commits, err := r.CommitsWithTrailer("Ralph-Session", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject())
+	}
+	if got := strings.Join(subjects, ","); got != "feat: a,fix: d" {
+		t.Errorf("commits = %s, want feat: a,fix: d", got)
+	}
+}
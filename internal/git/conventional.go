@@ -0,0 +1,162 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// conventionalRe matches a Conventional Commits header:
+// "type(scope)!: description" with the scope and "!" optional.
+var conventionalRe = regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([\w./-]+\))?!?: \S`)
+
+// IsConventional reports whether subject is a valid Conventional Commits header.
+func IsConventional(subject string) bool {
+	return conventionalRe.MatchString(subject)
+}
+
+// ConventionalSubject rewrites a free-form subject into a Conventional
+// Commits header, inferring the type from its leading verb (e.g. "Add" →
+// feat, "Fix" → fix). Subjects that already comply are returned unchanged.
+func ConventionalSubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if IsConventional(subject) {
+		return subject
+	}
+	// Drop a loose "type:" prefix with the wrong shape (e.g. "Feat:", "feature:").
+	if prefix, rest, ok := strings.Cut(subject, ":"); ok && !strings.ContainsAny(prefix, " \t") && strings.TrimSpace(rest) != "" {
+		subject = strings.TrimSpace(rest)
+	}
+
+	first, _, _ := strings.Cut(strings.ToLower(subject), " ")
+	typ := "chore"
+	switch first {
+	case "add", "adds", "added", "implement", "implements", "implemented", "introduce", "create", "support":
+		typ = "feat"
+	case "fix", "fixes", "fixed", "correct", "resolve", "resolves", "handle", "prevent":
+		typ = "fix"
+	case "refactor", "refactors", "rename", "move", "extract", "simplify", "clean", "cleanup", "restructure":
+		typ = "refactor"
+	case "test", "tests", "cover":
+		typ = "test"
+	case "doc", "docs", "document", "documents":
+		typ = "docs"
+	case "speed", "optimize", "optimise":
+		typ = "perf"
+	case "revert":
+		typ = "revert"
+	}
+	return typ + ": " + lowerFirst(subject)
+}
+
+// lowerFirst lower-cases the first rune of s unless the first word looks like
+// an acronym or identifier (contains another upper-case letter).
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	word, _, _ := strings.Cut(s, " ")
+	for _, c := range word[size:] {
+		if unicode.IsUpper(c) {
+			return s
+		}
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+// Trailer is a "Key: value" line in a commit message's trailer block.
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// trailerRe matches one git trailer line.
+var trailerRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*): (.+)$`)
+
+// ParseTrailers returns the trailers in the last paragraph of msg, keyed by
+// trailer name. Returns an empty map when the last paragraph is not a
+// trailer block.
+func ParseTrailers(msg string) map[string]string {
+	out := make(map[string]string)
+	paras := strings.Split(strings.TrimSpace(msg), "\n\n")
+	if len(paras) < 2 {
+		return out
+	}
+	for _, line := range strings.Split(paras[len(paras)-1], "\n") {
+		m := trailerRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return map[string]string{}
+		}
+		out[m[1]] = m[2]
+	}
+	return out
+}
+
+// AddTrailers appends trailers to msg, joining an existing trailer block when
+// present. Trailers whose key is already set are replaced in place.
+func AddTrailers(msg string, trailers []Trailer) string {
+	msg = strings.TrimRight(msg, "\n")
+	existing := ParseTrailers(msg)
+
+	var block []string
+	body := msg
+	if len(existing) > 0 {
+		idx := strings.LastIndex(msg, "\n\n")
+		body = msg[:idx]
+		block = strings.Split(msg[idx+2:], "\n")
+	}
+
+	for _, t := range trailers {
+		if t.Value == "" {
+			continue
+		}
+		line := t.Key + ": " + t.Value
+		replaced := false
+		for i, l := range block {
+			if strings.HasPrefix(l, t.Key+": ") {
+				block[i] = line
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			block = append(block, line)
+		}
+	}
+	if len(block) == 0 {
+		return msg
+	}
+	return body + "\n\n" + strings.Join(block, "\n")
+}
+
+// SquashMessage builds the message for a commit that replaces commits: the
+// subject, then a list of the original short SHAs and subjects.
+func SquashMessage(subject string, commits []Commit) string {
+	var b strings.Builder
+	b.WriteString(subject)
+	b.WriteString("\n\nSquashed commits:\n")
+	for _, c := range commits {
+		sha := c.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		b.WriteString("- " + sha + " " + c.Subject() + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// SpecSubject returns a Conventional Commits subject for a squashed spec
+// branch, e.g. "007-worktree-support" → "feat: worktree support".
+func SpecSubject(name string) string {
+	trimmed := strings.TrimLeft(name, "0123456789")
+	if trimmed != name {
+		trimmed = strings.TrimLeft(trimmed, "-_")
+	}
+	if trimmed == "" {
+		trimmed = name
+	}
+	trimmed = strings.NewReplacer("-", " ", "_", " ", "/", " ").Replace(trimmed)
+	return "feat: " + trimmed
+}
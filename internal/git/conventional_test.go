@@ -0,0 +1,119 @@
+package git
+
+import "testing"
+
+func TestIsConventional(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    bool
+	}{
+		{"feat: add forge package", true},
+		{"fix(loop): handle nil agent", true},
+		{"refactor!: drop legacy flag", true},
+		{"chore(deps)!: bump toml", true},
+		{"Add forge package", false},
+		{"feat:missing space", false},
+		{"feature: not a known type", false},
+		{"feat: ", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsConventional(tt.subject); got != tt.want {
+			t.Errorf("IsConventional(%q) = %v, want %v", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestConventionalSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{"feat: already fine", "feat: already fine"},
+		{"Add forge package", "feat: add forge package"},
+		{"Fix race in watcher", "fix: fix race in watcher"},
+		{"Refactor parser", "refactor: refactor parser"},
+		{"Update README", "chore: update README"},
+		{"TUI header tweaks", "chore: TUI header tweaks"},
+		{"Feat: wrong case", "chore: wrong case"},
+		{"Docs: explain config", "chore: explain config"},
+		{"Document the forge config", "docs: document the forge config"},
+	}
+	for _, tt := range tests {
+		if got := ConventionalSubject(tt.subject); got != tt.want {
+			t.Errorf("ConventionalSubject(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrailers(t *testing.T) {
+	msg := "feat: x\n\nBody text.\n\nRalph-Spec: 007-x\nRalph-Iteration: 3"
+	got := ParseTrailers(msg)
+	if got["Ralph-Spec"] != "007-x" || got["Ralph-Iteration"] != "3" || len(got) != 2 {
+		t.Errorf("ParseTrailers = %v", got)
+	}
+
+	if got := ParseTrailers("feat: x\n\nJust a body: with colon and more words\nsecond line"); len(got) != 0 {
+		t.Errorf("non-trailer paragraph parsed as trailers: %v", got)
+	}
+	if got := ParseTrailers("Key: value"); len(got) != 0 {
+		t.Errorf("subject-only message parsed as trailers: %v", got)
+	}
+}
+
+func TestAddTrailers(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		add  []Trailer
+		want string
+	}{
+		{
+			name: "new block",
+			msg:  "feat: x\n",
+			add:  []Trailer{{"Ralph-Spec", "007-x"}, {"Ralph-Task", ""}, {"Ralph-Iteration", "2"}},
+			want: "feat: x\n\nRalph-Spec: 007-x\nRalph-Iteration: 2",
+		},
+		{
+			name: "joins existing block",
+			msg:  "feat: x\n\nbody\n\nSigned-off-by: A <a@b>",
+			add:  []Trailer{{"Ralph-Iteration", "2"}},
+			want: "feat: x\n\nbody\n\nSigned-off-by: A <a@b>\nRalph-Iteration: 2",
+		},
+		{
+			name: "replaces existing key",
+			msg:  "feat: x\n\nRalph-Iteration: 1",
+			add:  []Trailer{{"Ralph-Iteration", "2"}},
+			want: "feat: x\n\nRalph-Iteration: 2",
+		},
+		{
+			name: "nothing to add",
+			msg:  "feat: x",
+			add:  []Trailer{{"Ralph-Task", ""}},
+			want: "feat: x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddTrailers(tt.msg, tt.add); got != tt.want {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecSubject(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"007-worktree-support", "feat: worktree support"},
+		{"feature/login_page", "feat: feature login page"},
+		{"123", "feat: 123"},
+	}
+	for _, tt := range tests {
+		if got := SpecSubject(tt.name); got != tt.want {
+			t.Errorf("SpecSubject(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
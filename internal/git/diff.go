@@ -0,0 +1,119 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileChange is one file touched in a commit range returned by ChangedFiles.
+type FileChange struct {
+	Status string // "A" added, "M" modified, "D" deleted, "T" type changed
+	Path   string // slash-separated, relative to the repository root
+}
+
+// ChangedFiles returns the files that differ between the commits from and
+// to, in path order. Renames are reported as a deletion and an addition.
+func (r *Runner) ChangedFiles(from, to string) ([]FileChange, error) {
+	out, err := r.run("diff", "--name-status", "--no-renames", "-z", from, to, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s %s: %w", from, to, err)
+	}
+	// -z output alternates status and path, each NUL-terminated, so unusual
+	// file names need no unquoting.
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	var files []FileChange
+	for i := 0; i+1 < len(fields); i += 2 {
+		files = append(files, FileChange{Status: fields[i], Path: fields[i+1]})
+	}
+	return files, nil
+}
+
+// RestoreFiles commits the contents of paths as they were at ref: modified
+// files are reset, files added since ref are deleted, and files deleted since
+// ref come back. Only paths are committed, so unrelated staged changes stay
+// staged. Paths are relative to the repository root.
+func (r *Runner) RestoreFiles(ref string, paths []string, message string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := append([]string{"restore", "--source", ref, "--staged", "--worktree", "--"}, paths...)
+	if _, err := r.run(args...); err != nil {
+		return fmt.Errorf("git restore: %w", err)
+	}
+	args = append([]string{"commit", "--quiet", "-F", "-", "--"}, paths...)
+	if _, err := r.runInput(nil, message+"\n", args...); err != nil {
+		return fmt.Errorf("git commit: %w", err)
+	}
+	return nil
+}
+
+// Diff returns the unified diff between the commits from and to. Prefixes
+// and color are fixed so user configuration cannot change the format
+// ParseDiff expects.
+func (r *Runner) Diff(from, to string) (string, error) {
+	out, err := r.run("diff", "--no-color", "--no-ext-diff", "--no-renames",
+		"--src-prefix=a/", "--dst-prefix=b/", from, to, "--")
+	if err != nil {
+		return "", fmt.Errorf("git diff %s %s: %w", from, to, err)
+	}
+	return out, nil
+}
+
+// FileDiff is one file's section of a unified diff.
+type FileDiff struct {
+	Path    string // new path, or the old path for a deleted file
+	Added   int    // number of "+" lines
+	Deleted int    // number of "-" lines
+	Binary  bool
+	Hunks   []Hunk
+}
+
+// Hunk is one "@@" section of a FileDiff. Lines keep their leading " ",
+// "+", "-", or "\" marker.
+type Hunk struct {
+	Header string
+	Lines  []string
+}
+
+// ParseDiff splits the output of Diff into per-file sections.
+func ParseDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var oldPath string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, FileDiff{})
+			cur = &files[len(files)-1]
+			oldPath = ""
+			// Fallback for sections without ---/+++ lines (mode changes,
+			// binary files): "diff --git a/x b/x".
+			if _, b, ok := strings.Cut(line, " b/"); ok {
+				cur.Path = b
+			}
+		case cur == nil:
+		case len(cur.Hunks) == 0 && strings.HasPrefix(line, "--- "):
+			oldPath = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case len(cur.Hunks) == 0 && strings.HasPrefix(line, "+++ "):
+			if p := strings.TrimPrefix(line, "+++ "); p != "/dev/null" {
+				cur.Path = strings.TrimPrefix(p, "b/")
+			} else if oldPath != "" {
+				cur.Path = oldPath
+			}
+		case len(cur.Hunks) == 0 && strings.HasPrefix(line, "Binary files "):
+			cur.Binary = true
+		case strings.HasPrefix(line, "@@"):
+			cur.Hunks = append(cur.Hunks, Hunk{Header: line})
+		case len(cur.Hunks) > 0 && line != "":
+			h := &cur.Hunks[len(cur.Hunks)-1]
+			h.Lines = append(h.Lines, line)
+			switch line[0] {
+			case '+':
+				cur.Added++
+			case '-':
+				cur.Deleted++
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,131 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	commitFile(t, dir, "keep.txt", "v1", "keep")
+	commitFile(t, dir, "gone.txt", "bye", "gone")
+	base, _ := r.HeadSHA()
+
+	commitFile(t, dir, "keep.txt", "v2", "modify")
+	commitFile(t, dir, "new file.txt", "hi", "add")
+	cmd := exec.Command("git", "rm", "-q", "gone.txt")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git rm: %s", out)
+	}
+	commitFile(t, dir, "keep.txt", "v3", "modify again and delete")
+
+	files, err := r.ChangedFiles(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FileChange{{"D", "gone.txt"}, {"M", "keep.txt"}, {"A", "new file.txt"}}
+	if !slices.Equal(files, want) {
+		t.Errorf("ChangedFiles = %+v, want %+v", files, want)
+	}
+
+	none, err := r.ChangedFiles("HEAD", "HEAD")
+	if err != nil || len(none) != 0 {
+		t.Errorf("ChangedFiles(HEAD, HEAD) = %+v, %v", none, err)
+	}
+}
+
+func TestRestoreFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	commitFile(t, dir, "in.txt", "v1", "in")
+	commitFile(t, dir, "out.txt", "v1", "out")
+	base, _ := r.HeadSHA()
+
+	commitFile(t, dir, "in.txt", "v2", "change in")
+	commitFile(t, dir, "out.txt", "v2", "change out")
+	commitFile(t, dir, "added.txt", "x", "add")
+	// Unrelated staged change that must not be swept into the restore commit.
+	if err := os.WriteFile(filepath.Join(dir, "staged.txt"), []byte("s"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "add", "staged.txt")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add: %s", out)
+	}
+
+	if err := r.RestoreFiles(base, []string{"out.txt", "added.txt"}, "revert: restore out-of-bounds files"); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := r.ChangedFiles(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []FileChange{{"M", "in.txt"}}; !slices.Equal(files, want) {
+		t.Errorf("after restore, base..HEAD = %+v, want %+v", files, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "added.txt")); !os.IsNotExist(err) {
+		t.Error("added.txt should be removed from the working tree")
+	}
+	if dirty, _ := r.HasUncommittedChanges(); !dirty {
+		t.Error("staged.txt should still be staged")
+	}
+	last, _ := r.LastCommit()
+	if !strings.HasSuffix(last, "revert: restore out-of-bounds files") {
+		t.Errorf("LastCommit = %q", last)
+	}
+}
+
+func TestDiffAndParseDiff(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	commitFile(t, dir, "a.go", "package a\n\nfunc A() {}\n", "a")
+	commitFile(t, dir, "old.txt", "bye\n", "old")
+	base, _ := r.HeadSHA()
+
+	commitFile(t, dir, "a.go", "package a\n\nfunc A() int { return 1 }\n", "change a")
+	commitFile(t, dir, "b.txt", "one\ntwo\n", "add b")
+	cmd := exec.Command("git", "rm", "-q", "old.txt")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git rm: %s", out)
+	}
+	commitFile(t, dir, "b.txt", "one\ntwo\n", "delete old")
+
+	diff, err := r.Diff(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := ParseDiff(diff)
+	if len(files) != 3 {
+		t.Fatalf("ParseDiff: %d files, want 3:\n%s", len(files), diff)
+	}
+	got := make(map[string]FileDiff)
+	for _, f := range files {
+		got[f.Path] = f
+	}
+	if a := got["a.go"]; a.Added != 1 || a.Deleted != 1 || len(a.Hunks) != 1 || !strings.HasPrefix(a.Hunks[0].Header, "@@") {
+		t.Errorf("a.go = %+v", a)
+	}
+	if b := got["b.txt"]; b.Added != 2 || b.Deleted != 0 || !slices.Equal(b.Hunks[0].Lines, []string{"+one", "+two"}) {
+		t.Errorf("b.txt = %+v", b)
+	}
+	if old := got["old.txt"]; old.Added != 0 || old.Deleted != 1 {
+		t.Errorf("old.txt (deleted) = %+v", old)
+	}
+}
+
+func TestParseDiff_Binary(t *testing.T) {
+	diff := "diff --git a/img.png b/img.png\nnew file mode 100644\nindex 0000000..e69de29\nBinary files /dev/null and b/img.png differ\n"
+	files := ParseDiff(diff)
+	if len(files) != 1 || files[0].Path != "img.png" || !files[0].Binary || len(files[0].Hunks) != 0 {
+		t.Errorf("ParseDiff = %+v", files)
+	}
+}
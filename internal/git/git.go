@@ -136,6 +136,34 @@ func (r *Runner) DiffFromRemote(branch string) (bool, error) {
 	return true, nil
 }
 
+// NeedsForcePush reports whether pushing HEAD to origin/<branch> would
+// rewrite remote history, i.e. origin/<branch> exists but is not an ancestor
+// of HEAD. Ralph never force-pushes; callers refuse the push instead.
+func (r *Runner) NeedsForcePush(branch string) bool {
+	if !r.HasRemoteBranch(branch) {
+		return false
+	}
+	_, err := r.run("merge-base", "--is-ancestor", fmt.Sprintf("origin/%s", branch), "HEAD")
+	return err != nil
+}
+
+// CreateBranch creates a new branch at HEAD and checks it out.
+func (r *Runner) CreateBranch(name string) error {
+	if _, err := r.run("checkout", "-b", name); err != nil {
+		return fmt.Errorf("git create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// RemoteURL returns the fetch URL of the origin remote.
+func (r *Runner) RemoteURL() (string, error) {
+	out, err := r.run("remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("git remote url: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
 // run executes a git command and returns its combined output.
 func (r *Runner) run(args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
@@ -525,6 +525,63 @@ func TestHasRemoteBranch(t *testing.T) {
 	})
 }
 
+func TestNeedsForcePush(t *testing.T) {
+	workDir, _ := initTestRepoWithRemote(t)
+	r := NewRunner(workDir)
+	if r.NeedsForcePush("main") {
+		t.Error("in-sync branch should not need a force push")
+	}
+	if r.NeedsForcePush("unpushed") {
+		t.Error("branch without a remote should not need a force push")
+	}
+
+	if err := os.WriteFile(filepath.Join(workDir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"git", "add", "new.txt"},
+		{"git", "commit", "-m", "ahead"},
+	} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %s (%v)", args, out, err)
+		}
+	}
+	if r.NeedsForcePush("main") {
+		t.Error("fast-forward push should not need a force push")
+	}
+
+	// Rewriting the pushed commit makes origin/main unreachable from HEAD.
+	cmd := exec.Command("git", "reset", "--hard", "HEAD~1")
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("reset failed: %s (%v)", out, err)
+	}
+	cmd = exec.Command("git", "commit", "--amend", "-m", "rewritten")
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("amend failed: %s (%v)", out, err)
+	}
+	if !r.NeedsForcePush("main") {
+		t.Error("rewritten history should need a force push")
+	}
+}
+
+func TestCreateBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	if err := r.CreateBranch("ralph/build-1"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if branch, _ := r.CurrentBranch(); branch != "ralph/build-1" {
+		t.Errorf("CurrentBranch = %q, want ralph/build-1", branch)
+	}
+	if err := r.CreateBranch("ralph/build-1"); err == nil {
+		t.Error("expected error creating an existing branch")
+	}
+}
+
 func TestDiffFromRemote(t *testing.T) {
 	t.Run("no diff when in sync", func(t *testing.T) {
 		workDir, _ := initTestRepoWithRemote(t)
@@ -579,3 +636,18 @@ func TestDiffFromRemote(t *testing.T) {
 		}
 	})
 }
+
+func TestRemoteURL(t *testing.T) {
+	workDir, remoteDir := initTestRepoWithRemote(t)
+	got, err := NewRunner(workDir).RemoteURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != remoteDir {
+		t.Errorf("got %q, want %q", got, remoteDir)
+	}
+
+	if _, err := NewRunner(initTestRepo(t)).RemoteURL(); err == nil {
+		t.Error("expected error when origin is not configured")
+	}
+}
@@ -0,0 +1,66 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Rebase rebases the current branch onto target. When the rebase stops on
+// conflicts it is left in progress and the conflicted paths are returned with
+// a nil error, so the caller can resolve them and continue or abort. Any other
+// failure aborts the rebase and returns an error.
+func (r *Runner) Rebase(target string) ([]string, error) {
+	if _, err := r.run("rebase", target); err != nil {
+		conflicts, listErr := r.ConflictedFiles()
+		if listErr == nil && len(conflicts) > 0 {
+			return conflicts, nil
+		}
+		_ = r.RebaseAbort()
+		return nil, fmt.Errorf("git rebase %s: %w", target, err)
+	}
+	return nil, nil
+}
+
+// RebaseInProgress reports whether a rebase is stopped in the working tree.
+func (r *Runner) RebaseInProgress() bool {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		out, err := r.run("rev-parse", "--git-path", name)
+		if err != nil {
+			continue
+		}
+		p := strings.TrimSpace(out)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(r.Dir, p)
+		}
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RebaseAbort aborts an in-progress rebase, restoring the branch.
+func (r *Runner) RebaseAbort() error {
+	if _, err := r.run("rebase", "--abort"); err != nil {
+		return fmt.Errorf("git rebase --abort: %w", err)
+	}
+	return nil
+}
+
+// ConflictedFiles returns the paths with unresolved merge conflicts. Paths
+// are NUL-separated (-z) so ones with spaces or quotes come back verbatim.
+func (r *Runner) ConflictedFiles() ([]string, error) {
+	out, err := r.run("diff", "--name-only", "-z", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("git conflicted files: %w", err)
+	}
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
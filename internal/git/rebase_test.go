@@ -0,0 +1,105 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// branchOff creates branch at HEAD in dir and checks it out.
+func branchOff(t *testing.T, dir, branch string) {
+	t.Helper()
+	cmd := exec.Command("git", "checkout", "-b", branch)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("checkout -b %s: %s (%v)", branch, out, err)
+	}
+}
+
+func checkout(t *testing.T, dir, branch string) {
+	t.Helper()
+	cmd := exec.Command("git", "checkout", branch)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("checkout %s: %s (%v)", branch, out, err)
+	}
+}
+
+func TestRebase_Clean(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	branchOff(t, dir, "feat")
+	commitFile(t, dir, "a.txt", "a", "feat: a")
+	checkout(t, dir, "main")
+	commitFile(t, dir, "b.txt", "b", "feat: b")
+	checkout(t, dir, "feat")
+
+	conflicts, err := r.Rebase("main")
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("Rebase = %v, %v; want clean", conflicts, err)
+	}
+	if r.RebaseInProgress() {
+		t.Error("no rebase should be in progress")
+	}
+	if _, err := r.run("merge-base", "--is-ancestor", "main", "HEAD"); err != nil {
+		t.Error("feat should contain main after the rebase")
+	}
+}
+
+func TestRebase_Conflict(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	branchOff(t, dir, "feat")
+	commitFile(t, dir, "same.txt", "feat side", "feat: same")
+	checkout(t, dir, "main")
+	commitFile(t, dir, "same.txt", "main side", "feat: main same")
+	checkout(t, dir, "feat")
+
+	conflicts, err := r.Rebase("main")
+	if err != nil {
+		t.Fatalf("Rebase: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "same.txt" {
+		t.Errorf("conflicts = %v, want [same.txt]", conflicts)
+	}
+	if !r.RebaseInProgress() {
+		t.Fatal("rebase should be left in progress")
+	}
+	if err := r.RebaseAbort(); err != nil {
+		t.Fatalf("RebaseAbort: %v", err)
+	}
+	if r.RebaseInProgress() {
+		t.Error("rebase should be aborted")
+	}
+	if branch, _ := r.CurrentBranch(); branch != "feat" {
+		t.Errorf("branch after abort = %q, want feat", branch)
+	}
+}
+
+func TestConflictedFiles_PathWithSpace(t *testing.T) {
+	dir := initTestRepo(t)
+	r := NewRunner(dir)
+	branchOff(t, dir, "feat")
+	commitFile(t, dir, "my notes.txt", "feat side", "feat: notes")
+	checkout(t, dir, "main")
+	commitFile(t, dir, "my notes.txt", "main side", "feat: main notes")
+	checkout(t, dir, "feat")
+
+	conflicts, err := r.Rebase("main")
+	if err != nil {
+		t.Fatalf("Rebase: %v", err)
+	}
+	defer func() { _ = r.RebaseAbort() }()
+	if len(conflicts) != 1 || conflicts[0] != "my notes.txt" {
+		t.Errorf("conflicts = %q, want [my notes.txt]", conflicts)
+	}
+}
+
+func TestRebase_UnknownTarget(t *testing.T) {
+	r := NewRunner(initTestRepo(t))
+	if _, err := r.Rebase("nope"); err == nil {
+		t.Error("expected error rebasing onto a missing branch")
+	}
+	if r.RebaseInProgress() {
+		t.Error("failed rebase must not be left in progress")
+	}
+}
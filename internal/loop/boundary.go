@@ -0,0 +1,192 @@
+package loop
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+)
+
+// DiffOps is the optional file-diff capability used to show each
+// iteration's changed files and to enforce [build] boundary. *git.Runner
+// satisfies it. When Loop.Git does not implement DiffOps both are skipped.
+type DiffOps interface {
+	ChangedFiles(from, to string) ([]git.FileChange, error)
+	RestoreFiles(ref string, paths []string, message string) error
+}
+
+// loadBoundary reads the active spec's paths: globs when [build] boundary
+// is set. Roam mode and specs without paths are unbounded.
+func (l *Loop) loadBoundary() {
+	l.boundary = nil
+	if l.Config.Build.Boundary == "" || l.Roam || l.SpecDir == "" {
+		return
+	}
+	dir := l.SpecDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(l.Dir, dir)
+	}
+	globs, err := spec.ReadPaths(dir)
+	if err != nil {
+		l.emit(LogEntry{
+			Kind:    LogError,
+			Message: fmt.Sprintf("Spec boundary not enforced: %v", err),
+		})
+		return
+	}
+	if len(globs) == 0 {
+		l.emit(LogEntry{
+			Kind:    LogInfo,
+			Message: fmt.Sprintf("Spec boundary not enforced: %s declares no paths: in spec.md or plan.md", l.SpecDir),
+		})
+		return
+	}
+	l.boundary = globs
+}
+
+// boundaryPrompt tells Claude which paths the active spec may touch.
+func boundaryPrompt(globs []string) string {
+	if len(globs) == 0 {
+		return ""
+	}
+	return "\n\nOnly change files matching these paths (and the spec directory itself): " +
+		strings.Join(globs, ", ") + ". Changes elsewhere are flagged after the iteration."
+}
+
+// inBounds reports whether the repository-relative path may be changed by
+// the active spec: the spec directory is always allowed.
+func (l *Loop) inBounds(path string) bool {
+	if spec.MatchPath(l.specRel(), path) {
+		return true
+	}
+	for _, g := range l.boundary {
+		if spec.MatchPath(g, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// specRel returns the spec directory relative to the working directory,
+// slash-separated like the paths git reports.
+func (l *Loop) specRel() string {
+	dir := l.SpecDir
+	if filepath.IsAbs(dir) {
+		if base, err := filepath.Abs(l.Dir); err == nil {
+			if rel, err := filepath.Rel(base, dir); err == nil {
+				dir = rel
+			}
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+// checkFiles returns the files changed by the iteration's commits
+// (base..HEAD), marking those outside the spec boundary, and applies the
+// [build] boundary action to them. Returns nil when nothing changed or
+// Loop.Git does not implement DiffOps.
+func (l *Loop) checkFiles(base string) []ChangedFile {
+	do, ok := l.Git.(DiffOps)
+	if !ok || base == "" {
+		return nil
+	}
+	changes, err := do.ChangedFiles(base, "HEAD")
+	if err != nil || len(changes) == 0 {
+		return nil
+	}
+
+	files := make([]ChangedFile, len(changes))
+	var outside []string
+	for i, c := range changes {
+		files[i] = ChangedFile{Status: c.Status, Path: c.Path}
+		if l.boundary != nil && !l.inBounds(c.Path) {
+			files[i].OutOfBounds = true
+			outside = append(outside, c.Path)
+		}
+	}
+	if len(outside) == 0 {
+		return files
+	}
+
+	action := l.Config.Build.Boundary
+	l.emit(LogEntry{
+		Kind:    LogError,
+		Message: fmt.Sprintf("Spec boundary: %s changed outside %s: %s", plural(len(outside), "file"), l.Spec, strings.Join(outside, ", ")),
+	})
+	if action == "warn" {
+		return files
+	}
+
+	reverted := false
+	if action == "revert" {
+		body := "Files changed outside the paths of spec " + l.Spec + ":\n\n- " + strings.Join(outside, "\n- ")
+		if err := do.RestoreFiles(base, outside, "revert: restore files outside the spec boundary\n\n"+body); err != nil {
+			l.emit(LogEntry{
+				Kind:    LogError,
+				Message: fmt.Sprintf("Spec boundary: revert failed: %v", err),
+			})
+		} else {
+			reverted = true
+			commit, _ := l.Git.LastCommit()
+			l.emit(LogEntry{
+				Kind:    LogInfo,
+				Message: fmt.Sprintf("Spec boundary: restored %s in %s", plural(len(outside), "file"), commit),
+				Commit:  commit,
+			})
+		}
+	}
+
+	feedback := "## Spec Boundary\n\nThe previous iteration changed files outside the paths this spec may touch (" +
+		strings.Join(l.boundary, ", ") + "):\n\n- " + strings.Join(outside, "\n- ")
+	if reverted {
+		feedback += "\n\nThose changes have been reverted."
+	}
+	feedback += "\n\nKeep changes within the spec's paths. If the spec genuinely needs another path, add it to the paths: list in spec.md or plan.md."
+	l.appendFeedback(feedback)
+	return files
+}
+
+// emitFiles reports iteration n's changed files and its commit range, from
+// base (the commit before the iteration) to head (LastCommit once the
+// iteration's commits were reworded, squashed, and pushed).
+func (l *Loop) emitFiles(n int, files []ChangedFile, base, head string) {
+	if len(files) == 0 {
+		return
+	}
+	var outside int
+	for _, f := range files {
+		if f.OutOfBounds {
+			outside++
+		}
+	}
+	msg := fmt.Sprintf("%d file(s) changed", len(files))
+	if outside > 0 {
+		msg += fmt.Sprintf(", %d outside the spec boundary", outside)
+	}
+	l.emit(LogEntry{
+		Kind:       LogFiles,
+		Message:    msg,
+		Iteration:  n,
+		Files:      files,
+		BaseCommit: base,
+		Commit:     head,
+	})
+}
+
+// appendFeedback queues text for the next iteration's prompt, after any
+// feedback already queued.
+func (l *Loop) appendFeedback(text string) {
+	if l.pendingFeedback != "" {
+		l.pendingFeedback += "\n\n"
+	}
+	l.pendingFeedback += text
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
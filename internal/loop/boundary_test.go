@@ -0,0 +1,183 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// initBoundaryRepo is initPolicyRepo with a spec.md that limits the spec to
+// internal/core/**.
+func initBoundaryRepo(t *testing.T) string {
+	t.Helper()
+	dir := initPolicyRepo(t)
+	specMD := "---\npaths:\n  - internal/core/**\n---\n# Spec\n"
+	if err := os.WriteFile(filepath.Join(dir, "specs", "007-x", "spec.md"), []byte(specMD), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-m", "docs: spec")
+	return dir
+}
+
+// writeAndCommit writes each file (relative to dir) and commits them.
+func writeAndCommit(t *testing.T, dir, msg string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-m", msg)
+}
+
+func runBoundaryLoop(t *testing.T, dir, action string, maxIter int, agent *committingAgent) (*Loop, []LogEntry) {
+	t.Helper()
+	lp := newPolicyLoop(t, dir, "", agent)
+	lp.Config.Build.Boundary = action
+	entries := collectEntries(lp)
+	if err := lp.Run(context.Background(), ModeBuild, maxIter); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return lp, entries()
+}
+
+func TestBoundary_FilesEntryMarksOutOfBounds(t *testing.T) {
+	dir := initBoundaryRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		writeAndCommit(t, dir, "feat: core", "internal/core/a.go", "specs/007-x/notes.md", ".github/workflows/ci.yml")
+	}}
+	_, entries := runBoundaryLoop(t, dir, "warn", 1, agent)
+
+	var files []ChangedFile
+	var warned bool
+	for _, e := range entries {
+		switch {
+		case e.Kind == LogFiles:
+			files = e.Files
+			if e.Iteration != 1 || !strings.Contains(e.Message, "1 outside") {
+				t.Errorf("files entry = %+v", e)
+			}
+		case e.Kind == LogError && strings.Contains(e.Message, "Spec boundary"):
+			warned = true
+		}
+	}
+	want := []ChangedFile{
+		{Status: "A", Path: ".github/workflows/ci.yml", OutOfBounds: true},
+		{Status: "A", Path: "internal/core/a.go"},
+		{Status: "A", Path: "specs/007-x/notes.md"},
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("Files = %+v, want %+v", files, want)
+	}
+	if !warned {
+		t.Error("expected a spec boundary warning")
+	}
+	if !strings.Contains(agent.prompts[0], "internal/core/**") {
+		t.Error("prompt should list the spec's paths")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".github", "workflows", "ci.yml")); err != nil {
+		t.Error("warn must not revert anything")
+	}
+}
+
+func TestBoundary_Feedback(t *testing.T) {
+	dir := initBoundaryRepo(t)
+	agent := &committingAgent{commit: func(n int) {
+		if n == 1 {
+			writeAndCommit(t, dir, "feat: stray", "cmd/tool/main.go")
+		}
+	}}
+	runBoundaryLoop(t, dir, "feedback", 2, agent)
+
+	if len(agent.prompts) != 2 {
+		t.Fatalf("prompts = %d, want 2", len(agent.prompts))
+	}
+	if strings.Contains(agent.prompts[0], "## Spec Boundary") {
+		t.Error("first prompt should have no boundary feedback")
+	}
+	if !strings.Contains(agent.prompts[1], "## Spec Boundary") || !strings.Contains(agent.prompts[1], "cmd/tool/main.go") {
+		t.Errorf("second prompt should name the stray file:\n%s", agent.prompts[1])
+	}
+	if strings.Contains(agent.prompts[1], "reverted") {
+		t.Error("feedback mode must not claim a revert")
+	}
+}
+
+func TestBoundary_Revert(t *testing.T) {
+	dir := initBoundaryRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		writeAndCommit(t, dir, "feat: mixed", "internal/core/a.go", "cmd/tool/main.go")
+	}}
+	lp, _ := runBoundaryLoop(t, dir, "revert", 1, agent)
+
+	if _, err := os.Stat(filepath.Join(dir, "cmd", "tool", "main.go")); !os.IsNotExist(err) {
+		t.Error("out-of-bounds file should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "internal", "core", "a.go")); err != nil {
+		t.Error("in-bounds file should be kept")
+	}
+	if subject := gitRun(t, dir, "log", "-1", "--format=%s"); subject != "revert: restore files outside the spec boundary" {
+		t.Errorf("HEAD subject = %q", subject)
+	}
+	if !strings.Contains(lp.pendingFeedback, "reverted") {
+		t.Errorf("pendingFeedback = %q", lp.pendingFeedback)
+	}
+}
+
+func TestBoundary_NoPathsNotEnforced(t *testing.T) {
+	dir := initPolicyRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		writeAndCommit(t, dir, "feat: anywhere", "cmd/tool/main.go")
+	}}
+	_, entries := runBoundaryLoop(t, dir, "revert", 1, agent)
+
+	var notice bool
+	for _, e := range entries {
+		if e.Kind == LogInfo && strings.Contains(e.Message, "declares no paths") {
+			notice = true
+		}
+		if e.Kind == LogFiles && e.Files[0].OutOfBounds {
+			t.Error("nothing is out of bounds without paths")
+		}
+	}
+	if !notice {
+		t.Error("expected a notice that the boundary is not enforced")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cmd", "tool", "main.go")); err != nil {
+		t.Error("nothing should be reverted")
+	}
+}
+
+func TestBoundary_OffStillListsFiles(t *testing.T) {
+	dir := initBoundaryRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		writeAndCommit(t, dir, "feat: anywhere", "cmd/tool/main.go")
+	}}
+	_, entries := runBoundaryLoop(t, dir, "", 1, agent)
+
+	var files []ChangedFile
+	var base, head string
+	for _, e := range entries {
+		if e.Kind == LogFiles {
+			files, base, head = e.Files, e.BaseCommit, e.Commit
+		}
+	}
+	if want := []ChangedFile{{Status: "A", Path: "cmd/tool/main.go"}}; !slices.Equal(files, want) {
+		t.Errorf("Files = %+v, want %+v", files, want)
+	}
+	if want := gitRun(t, dir, "rev-parse", "--short", "HEAD~1"); !strings.HasPrefix(want, base) && !strings.HasPrefix(base, want) {
+		t.Errorf("BaseCommit = %q, want the commit before the iteration (%s)", base, want)
+	}
+	if !strings.Contains(head, "feat: anywhere") {
+		t.Errorf("Commit = %q, want the iteration's commit", head)
+	}
+}
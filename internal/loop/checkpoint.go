@@ -0,0 +1,103 @@
+package loop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// checkpointFile is the checkpoint's path within the working directory.
+const checkpointFile = ".ralph/checkpoint.json"
+
+// ErrNoCheckpoint is returned by LoadCheckpoint when no checkpoint is saved.
+var ErrNoCheckpoint = errors.New("loop: no checkpoint to resume")
+
+// Checkpoint is the loop's position after its last completed iteration,
+// saved to .ralph/checkpoint.json so a paused or interrupted loop can resume
+// with the same iteration numbering and running cost.
+type Checkpoint struct {
+	Mode        Mode      `json:"mode"`
+	Iteration   int       `json:"iteration"` // last completed iteration
+	MaxIter     int       `json:"max_iterations"`
+	TotalCost   float64   `json:"total_cost_usd"`
+	PrevSubtype string    `json:"prev_subtype"` // result subtype of that iteration, for spec-completion detection
+	Branch      string    `json:"branch"`
+	Paused      bool      `json:"paused"` // saved by a pause request rather than after a routine iteration
+	At          time.Time `json:"at"`
+}
+
+// SaveCheckpoint writes cp to .ralph/checkpoint.json in dir with a
+// write-then-rename, so a crash never leaves a partial checkpoint.
+func SaveCheckpoint(dir string, cp Checkpoint) error {
+	path := filepath.Join(dir, filepath.FromSlash(checkpointFile))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("loop: create checkpoint dir: %w", err)
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("loop: marshal checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".checkpoint-*.tmp")
+	if err != nil {
+		return fmt.Errorf("loop: write checkpoint: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(tmp.Name(), path)
+	}
+	if writeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("loop: write checkpoint: %w", writeErr)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads the checkpoint saved in dir, or returns
+// ErrNoCheckpoint if there is none.
+func LoadCheckpoint(dir string) (Checkpoint, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(checkpointFile)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Checkpoint{}, ErrNoCheckpoint
+		}
+		return Checkpoint{}, fmt.Errorf("loop: read checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("loop: parse checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// ClearCheckpoint removes the checkpoint saved in dir, if any.
+func ClearCheckpoint(dir string) error {
+	err := os.Remove(filepath.Join(dir, filepath.FromSlash(checkpointFile)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("loop: remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// saveCheckpoint records the loop's position, logging rather than failing
+// when the file cannot be written.
+func (l *Loop) saveCheckpoint(cp Checkpoint) {
+	cp.At = time.Now()
+	if err := SaveCheckpoint(l.Dir, cp); err != nil {
+		l.emit(LogEntry{Kind: LogInfo, Message: fmt.Sprintf("Checkpoint not saved: %v", err)})
+	}
+}
+
+// clearCheckpoint removes the checkpoint once the loop has run to its end,
+// so a later --resume does not restart a finished run.
+func (l *Loop) clearCheckpoint() {
+	if err := ClearCheckpoint(l.Dir); err != nil {
+		l.emit(LogEntry{Kind: LogInfo, Message: fmt.Sprintf("Checkpoint not cleared: %v", err)})
+	}
+}
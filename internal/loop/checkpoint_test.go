@@ -0,0 +1,182 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+)
+
+func TestCheckpoint_SaveLoadClear(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadCheckpoint(dir); !errors.Is(err, ErrNoCheckpoint) {
+		t.Fatalf("LoadCheckpoint with none saved: err = %v, want ErrNoCheckpoint", err)
+	}
+
+	want := Checkpoint{Mode: ModeBuild, Iteration: 3, MaxIter: 10, TotalCost: 1.25, PrevSubtype: "success", Branch: "feat", Paused: true}
+	if err := SaveCheckpoint(dir, want); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	got, err := LoadCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if got != want {
+		t.Errorf("LoadCheckpoint = %+v, want %+v", got, want)
+	}
+
+	if err := ClearCheckpoint(dir); err != nil {
+		t.Fatalf("ClearCheckpoint: %v", err)
+	}
+	if err := ClearCheckpoint(dir); err != nil {
+		t.Errorf("ClearCheckpoint with none saved: %v", err)
+	}
+	if _, err := LoadCheckpoint(dir); !errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("after clear: err = %v, want ErrNoCheckpoint", err)
+	}
+}
+
+func TestLoadCheckpoint_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, filepath.FromSlash(checkpointFile))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoint(dir); err == nil || errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("corrupt checkpoint: err = %v, want a parse error", err)
+	}
+}
+
+func TestRun_PauseSavesCheckpoint(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.10, 1.0, "error_max_turns")}}
+	cfg := defaultTestConfig()
+	cfg.Build.MaxIterations = 5
+	lp, buf := setupTestLoop(t, agent, &mockGit{branch: "feat", lastCommit: "abc"}, cfg)
+
+	pauseCh := make(chan struct{})
+	var once sync.Once
+	calls := 0
+	lp.PauseAfter = pauseCh
+	lp.PostIteration = func() {
+		if calls++; calls == 2 {
+			once.Do(func() { close(pauseCh) })
+		}
+	}
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if agent.calls != 2 {
+		t.Errorf("agent calls = %d, want 2 (pause after the second iteration)", agent.calls)
+	}
+	if !strings.Contains(buf.String(), "Paused after iteration 2") {
+		t.Errorf("log should report the pause:\n%s", buf.String())
+	}
+	cp, err := LoadCheckpoint(lp.Dir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if cp.Mode != ModeBuild || cp.Iteration != 2 || cp.MaxIter != 5 || !cp.Paused ||
+		cp.PrevSubtype != "error_max_turns" || cp.Branch != "feat" || cp.TotalCost < 0.199 || cp.TotalCost > 0.201 {
+		t.Errorf("checkpoint = %+v, want paused build at iteration 2 of 5, $0.20", cp)
+	}
+}
+
+func TestRun_CheckpointsEachIteration(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.10, 1.0, "error_max_turns")}}
+	cfg := defaultTestConfig()
+	cfg.Build.MaxIterations = 3
+	lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, cfg)
+
+	var seen []int
+	lp.PostIteration = func() {
+		// PostIteration runs before the checkpoint, so it sees the previous one.
+		if cp, err := LoadCheckpoint(lp.Dir); err == nil {
+			seen = append(seen, cp.Iteration)
+		}
+	}
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 2 {
+		t.Errorf("checkpointed iterations = %v, want [1 2]", seen)
+	}
+	if _, err := LoadCheckpoint(lp.Dir); !errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("a finished loop should clear its checkpoint: err = %v", err)
+	}
+}
+
+func TestRun_ResumeFromCheckpoint(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.10, 1.0, "error_max_turns")}}
+	cfg := defaultTestConfig()
+	cfg.Build.MaxIterations = 20 // ignored: the checkpoint's max wins
+	lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, cfg)
+	events := make(chan LogEntry, 128)
+	lp.Events = events
+	lp.Resume = &Checkpoint{Mode: ModeBuild, Iteration: 3, MaxIter: 5, TotalCost: 1.00, PrevSubtype: "error_max_turns"}
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if agent.calls != 2 {
+		t.Errorf("agent calls = %d, want 2 (iterations 4 and 5)", agent.calls)
+	}
+	if lp.Resume != nil {
+		t.Error("Run should consume Resume")
+	}
+	var iters []int
+	var start, done LogEntry
+	for _, e := range drain(events) {
+		switch e.Kind {
+		case LogIterStart:
+			iters = append(iters, e.Iteration)
+		case LogDone:
+			done = e
+		case LogInfo:
+			if start.Message == "" {
+				start = e
+			}
+		}
+	}
+	if !strings.Contains(start.Message, "Resuming build loop at iteration 4") || start.TotalCost != 1.00 {
+		t.Errorf("start entry = %+v, want the resume message carrying $1.00", start)
+	}
+	if done.TotalCost < 1.199 || done.TotalCost > 1.201 {
+		t.Errorf("LogDone TotalCost = %v, want 1.20 (checkpoint cost carried on)", done.TotalCost)
+	}
+	if len(iters) != 2 || iters[0] != 4 || iters[1] != 5 {
+		t.Errorf("LogIterStart iterations = %v, want [4 5]", iters)
+	}
+}
+
+func TestRun_ResumeCarriesSpecCompletion(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.10, 1.0, "success")}}
+	cfg := defaultTestConfig()
+	lp, buf := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, cfg)
+	lp.Resume = &Checkpoint{Mode: ModeBuild, Iteration: 2, MaxIter: 10, PrevSubtype: "success"}
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if agent.calls != 1 || !strings.Contains(buf.String(), "Spec complete (3 iterations") {
+		t.Errorf("a resumed success with no commits should complete the spec (calls %d):\n%s", agent.calls, buf.String())
+	}
+}
+
+func TestRun_ResumeModeMismatch(t *testing.T) {
+	agent := &mockAgent{}
+	lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main"}, defaultTestConfig())
+	lp.Resume = &Checkpoint{Mode: ModePlan, Iteration: 1}
+	err := lp.Run(context.Background(), ModeBuild, 0)
+	if err == nil || !strings.Contains(err.Error(), "plan loop") || agent.calls != 0 {
+		t.Errorf("resuming a plan checkpoint in build mode: err = %v, calls %d", err, agent.calls)
+	}
+}
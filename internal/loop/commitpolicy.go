@@ -0,0 +1,171 @@
+package loop
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+// CommitOps is the optional history-editing capability used by
+// [git] commit_policy and strategy. *git.Runner satisfies it. When Loop.Git
+// does not implement CommitOps both features are skipped.
+type CommitOps interface {
+	CommitsBetween(from, to string) ([]git.Commit, error)
+	IsPushed(sha, branch string) bool
+	AddedLines(sha, path string) ([]string, error)
+	RewordCommits(from string, messages map[string]string) (string, error)
+	Squash(base, message string) ([]git.Commit, error)
+}
+
+// Commit trailer keys written by the commit policy. ralph history uses them
+// to map commits back to the iteration that produced them.
+const (
+	TrailerSpec      = "Ralph-Spec"
+	TrailerTask      = "Ralph-Task"
+	TrailerIteration = "Ralph-Iteration"
+	TrailerSession   = "Ralph-Session"
+)
+
+// SubtypeCommitPolicy is the iteration subtype reported when the strict
+// commit policy rejects the iteration's commits.
+const SubtypeCommitPolicy = "error_commit_policy"
+
+var (
+	taskIDRe      = regexp.MustCompile(`\bT\d{3,}\b`)
+	checkedTaskRe = regexp.MustCompile(`^\s*[-*+]\s+\[[xX]\]\s+(T\d{3,})\b`)
+)
+
+// enforceCommitPolicy checks the unpushed commits in base..HEAD against the
+// configured commit policy. In "reword" mode non-compliant subjects are
+// rewritten; in both modes compliant commits gain Ralph-* trailers. In
+// "strict" mode any non-compliant commit fails the check: feedback is queued
+// for the next prompt and the range is re-checked next iteration. Returns
+// false when the iteration should be treated as failed (and not pushed).
+func (l *Loop) enforceCommitPolicy(n int, branch, base string) bool {
+	co, ok := l.Git.(CommitOps)
+	if !ok {
+		return true
+	}
+	if l.policyBase != "" {
+		base = l.policyBase
+	}
+	commits, err := co.CommitsBetween(base, "HEAD")
+	if err != nil {
+		l.emit(LogEntry{
+			Kind:    LogInfo,
+			Message: fmt.Sprintf("Commit policy check skipped: %v", err),
+		})
+		return true
+	}
+
+	strict := l.Config.Git.CommitPolicy == "strict"
+	messages := make(map[string]string)
+	var violations []git.Commit
+	for _, c := range commits {
+		if len(c.Parents) > 1 || co.IsPushed(c.SHA, branch) {
+			continue
+		}
+		subject := c.Subject()
+		if !git.IsConventional(subject) {
+			if strict {
+				violations = append(violations, c)
+				continue
+			}
+			subject = git.ConventionalSubject(subject)
+		}
+		msg := subject
+		if _, rest, found := strings.Cut(c.Message, "\n"); found {
+			msg += "\n" + rest
+		}
+		msg = git.AddTrailers(msg, l.commitTrailers(co, c, n))
+		if msg != c.Message {
+			messages[c.SHA] = msg
+		}
+	}
+
+	if len(violations) > 0 {
+		l.policyBase = base
+		var lines []string
+		for _, c := range violations {
+			lines = append(lines, fmt.Sprintf("- %s %s", shortSHA(c.SHA), c.Subject()))
+		}
+		l.emit(LogEntry{
+			Kind:    LogError,
+			Message: fmt.Sprintf("Commit policy: %d commit(s) are not Conventional Commits — not pushing", len(violations)),
+		})
+		l.appendFeedback("## Commit Policy Feedback\n\n" +
+			"These unpushed commits do not follow Conventional Commits (\"type(scope): description\", " +
+			"e.g. \"feat(loop): add commit policy\"):\n\n" + strings.Join(lines, "\n") +
+			"\n\nReword them before continuing, and use Conventional Commits for every new commit.")
+		return false
+	}
+	l.policyBase = ""
+
+	if len(messages) == 0 {
+		return true
+	}
+	if _, err := co.RewordCommits(base, messages); err != nil {
+		l.emit(LogEntry{
+			Kind:    LogError,
+			Message: fmt.Sprintf("Commit policy: reword failed: %v", err),
+		})
+		return true
+	}
+	commit, _ := l.Git.LastCommit()
+	l.emit(LogEntry{
+		Kind:    LogInfo,
+		Message: fmt.Sprintf("Commit policy: rewrote %d commit message(s)", len(messages)),
+		Commit:  commit,
+	})
+	return true
+}
+
+// commitTrailers returns the Ralph-* trailers for commit c of iteration n.
+// The task trailer lists task IDs named in the message or checked off in the
+// spec's tasks.md by this commit.
+func (l *Loop) commitTrailers(co CommitOps, c git.Commit, n int) []git.Trailer {
+	ids := taskIDRe.FindAllString(c.Message, -1)
+	if len(ids) == 0 && l.SpecDir != "" {
+		added, _ := co.AddedLines(c.SHA, filepath.Join(l.SpecDir, "tasks.md"))
+		for _, line := range added {
+			if m := checkedTaskRe.FindStringSubmatch(line); m != nil {
+				ids = append(ids, m[1])
+			}
+		}
+	}
+	return []git.Trailer{
+		{Key: TrailerSpec, Value: l.Spec},
+		{Key: TrailerTask, Value: strings.Join(dedupe(ids), ", ")},
+		{Key: TrailerIteration, Value: strconv.Itoa(n)},
+		{Key: TrailerSession, Value: l.SessionID},
+	}
+}
+
+// commitSHA extracts the SHA from a LastCommit result ("<sha> <subject>").
+func commitSHA(lastCommit string) string {
+	sha, _, _ := strings.Cut(lastCommit, " ")
+	return sha
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func dedupe(ss []string) []string {
+	seen := make(map[string]bool, len(ss))
+	out := ss[:0]
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
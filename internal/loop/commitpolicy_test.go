@@ -0,0 +1,197 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+// committingAgent is a claude.Agent that runs commit(n) as its side effect
+// on the n-th call (1-based) and then reports success.
+type committingAgent struct {
+	calls   int
+	prompts []string
+	commit  func(n int)
+}
+
+func (a *committingAgent) Run(_ context.Context, prompt string, _ claude.RunOptions) (<-chan claude.Event, error) {
+	a.calls++
+	a.prompts = append(a.prompts, prompt)
+	if a.commit != nil {
+		a.commit(a.calls)
+	}
+	ch := make(chan claude.Event, 1)
+	ch <- claude.ResultEvent(0.1, 1, "success")
+	close(ch)
+	return ch, nil
+}
+
+// gitRun runs a git command in dir, failing the test on error.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// initPolicyRepo creates a repo on branch "007-x" with one commit and a
+// spec directory containing tasks.md.
+func initPolicyRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	gitRun(t, dir, "init")
+	gitRun(t, dir, "checkout", "-b", "007-x")
+	gitRun(t, dir, "config", "user.email", "test@test.com")
+	gitRun(t, dir, "config", "user.name", "Test")
+	if err := os.MkdirAll(filepath.Join(dir, "specs", "007-x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "specs", "007-x", "tasks.md"), []byte("- [ ] T001 one\n- [ ] T002 two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-m", "chore: init")
+	return dir
+}
+
+func newPolicyLoop(t *testing.T, dir, policy string, agent claude.Agent) *Loop {
+	t.Helper()
+	cfg := defaultTestConfig()
+	cfg.Git.AutoPullRebase = false
+	cfg.Git.AutoPush = false
+	cfg.Git.CommitPolicy = policy
+	cfg.Build.MaxIterations = 1
+	if err := os.WriteFile(filepath.Join(dir, cfg.Build.PromptFile), []byte("build prompt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", cfg.Build.PromptFile)
+	gitRun(t, dir, "commit", "-m", "chore: prompt")
+	return &Loop{
+		Agent:     agent,
+		Git:       git.NewRunner(dir),
+		Config:    cfg,
+		Log:       &strings.Builder{},
+		Dir:       dir,
+		Spec:      "007-x",
+		SpecDir:   filepath.Join(dir, "specs", "007-x"),
+		SessionID: "100-1",
+	}
+}
+
+func TestCommitPolicy_Reword(t *testing.T) {
+	dir := initPolicyRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		if err := os.WriteFile(filepath.Join(dir, "specs", "007-x", "tasks.md"), []byte("- [x] T001 one\n- [ ] T002 two\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "commit", "-am", "Add the first thing")
+		if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", "b.txt")
+		gitRun(t, dir, "commit", "-m", "fix(b): handle T002 edge")
+	}}
+	lp := newPolicyLoop(t, dir, "reword", agent)
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	msgs := strings.Split(gitRun(t, dir, "log", "-2", "--format=%B%x00"), "\x00")
+	latest, first := strings.TrimSpace(msgs[0]), strings.TrimSpace(msgs[1])
+
+	wantFirst := "feat: add the first thing\n\nRalph-Spec: 007-x\nRalph-Task: T001\nRalph-Iteration: 1\nRalph-Session: 100-1"
+	if first != wantFirst {
+		t.Errorf("first commit:\n%s\nwant:\n%s", first, wantFirst)
+	}
+	trailers := git.ParseTrailers(latest)
+	if !strings.HasPrefix(latest, "fix(b): handle T002 edge") || trailers[TrailerTask] != "T002" || trailers[TrailerIteration] != "1" {
+		t.Errorf("latest commit:\n%s", latest)
+	}
+	if out := gitRun(t, dir, "status", "--porcelain"); out != "" {
+		t.Errorf("working tree should be clean, got %q", out)
+	}
+}
+
+func TestCommitPolicy_Strict(t *testing.T) {
+	dir := initPolicyRepo(t)
+	agent := &committingAgent{commit: func(n int) {
+		if n == 1 {
+			if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			gitRun(t, dir, "add", "a.txt")
+			gitRun(t, dir, "commit", "-m", "Random message")
+			return
+		}
+		// Second iteration: the agent fixes the message as instructed.
+		gitRun(t, dir, "commit", "--amend", "-m", "feat: add a")
+	}}
+	lp := newPolicyLoop(t, dir, "strict", agent)
+	ch := make(chan LogEntry, 64)
+	lp.Events = ch
+
+	if err := lp.Run(context.Background(), ModeBuild, 2); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	close(ch)
+
+	var policyErr bool
+	for e := range ch {
+		if e.Kind == LogError && strings.Contains(e.Message, "Commit policy") {
+			policyErr = true
+		}
+	}
+	if !policyErr {
+		t.Error("expected a commit policy LogError")
+	}
+	if len(agent.prompts) != 2 {
+		t.Fatalf("want 2 agent calls, got %d", len(agent.prompts))
+	}
+	if !strings.Contains(agent.prompts[1], "## Commit Policy Feedback") || !strings.Contains(agent.prompts[1], "Random message") {
+		t.Errorf("second prompt missing feedback:\n%s", agent.prompts[1])
+	}
+	if strings.Contains(agent.prompts[0], "Commit Policy Feedback") {
+		t.Error("first prompt should not contain feedback")
+	}
+
+	// After the fix, the amended commit is re-checked and gets trailers.
+	msg := gitRun(t, dir, "log", "-1", "--format=%B")
+	if !strings.HasPrefix(msg, "feat: add a") || git.ParseTrailers(msg)[TrailerIteration] != "2" {
+		t.Errorf("final commit:\n%s", msg)
+	}
+}
+
+func TestCommitPolicy_UnsupportedGit(t *testing.T) {
+	// mockGit does not implement CommitOps, so the policy is a no-op.
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.1, 1, "success")}}
+	g := &mockGit{branch: "feat", diffFromRemote: true, lastCommitSequence: []string{"h0", "h0", "h1"}}
+	cfg := defaultTestConfig()
+	cfg.Git.CommitPolicy = "strict"
+	lp, _ := setupTestLoop(t, agent, g, cfg)
+	if err := lp.Run(context.Background(), ModeBuild, 1); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if g.pushCalls != 1 {
+		t.Errorf("push should proceed when policy is unsupported, got %d calls", g.pushCalls)
+	}
+}
+
+func TestCommitSHA(t *testing.T) {
+	if got := commitSHA("abc1234 feat: x"); got != "abc1234" {
+		t.Errorf("commitSHA = %q", got)
+	}
+	if got := commitSHA(""); got != "" {
+		t.Errorf("commitSHA(\"\") = %q", got)
+	}
+}
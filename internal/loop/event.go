@@ -19,6 +19,15 @@ const (
 	LogRegent                       // Regent supervisor message
 	LogSpecComplete                 // Spec boundary reached — success with no new commits (default mode)
 	LogSweepComplete                // Roam complete — no spec boundary (--roam mode)
+	LogRateLimit                    // Claude usage/rate limit hit — loop sleeping until ResetAt
+	LogPolicy                       // Tool call matched a [[policy.rules]] entry
+	LogFiles                        // Files changed by an iteration's commits (Files)
+	LogPhaseStart                   // Spec-kit phase starting (Phase)
+	LogPhaseDone                    // Spec-kit phase finished (Phase, Subtype, ClaudeSession)
+	LogSteer                        // Operator steering note added to an iteration's prompt
+	LogPaused                       // Loop paused after an iteration; a checkpoint was saved
+	LogToolResult                   // Output of a tool call (ToolUseID, Content, IsError)
+	LogThinking                     // Claude extended-thinking block (Content)
 )
 
 // LogEntry is a structured event emitted by the loop during execution.
@@ -32,6 +41,13 @@ type LogEntry struct {
 	// ToolUse fields
 	ToolName  string
 	ToolInput string
+	// ToolUseID links a LogToolUse entry to its LogToolResult, and a
+	// LogThinking entry to the call that followed it. Content is the tool
+	// result (truncated, see claude.MaxToolResultBytes) or thinking text, and
+	// IsError marks a failed tool call.
+	ToolUseID string
+	Content   string
+	IsError   bool
 
 	// Cost/timing fields
 	CostUSD   float64
@@ -44,9 +60,43 @@ type LogEntry struct {
 	MaxIter   int
 
 	// Git state
-	Branch string
-	Commit string
+	Branch   string
+	Commit   string
+	Squashed []string // original commit SHAs folded into Commit by a squash strategy
+
+	// PushGuard is the push-safety status shown in the TUI header, e.g.
+	// "dry-run" or "refused (protected)". Empty means pushes are unrestricted.
+	PushGuard string
 
 	// Mode (plan/build)
 	Mode string
+
+	// ResetAt is when a usage or rate limit lifts (LogRateLimit only).
+	ResetAt time.Time
+
+	// Files are the files changed by the iteration's commits, and BaseCommit
+	// the SHA of the commit the iteration started from (LogFiles only; Commit
+	// is the iteration's last commit).
+	Files      []ChangedFile
+	BaseCommit string
+
+	// Phase is the spec-kit phase a LogPhaseStart or LogPhaseDone entry
+	// belongs to (or a LogError reporting that the phase could not run), and
+	// ClaudeSession the Claude CLI session it ran in, which a follow-up
+	// PhaseRequest can resume (LogPhaseDone only).
+	Phase         string
+	ClaudeSession string
+
+	// Spec is the active spec and Model the configured Claude model ("" for
+	// the CLI default) an iteration runs with (LogIterStart only), so cost
+	// can be reported per spec and per model.
+	Spec  string
+	Model string
+}
+
+// ChangedFile is one file in a LogFiles entry.
+type ChangedFile struct {
+	Status      string // "A", "M", "D", or "T" as reported by git diff --name-status
+	Path        string // relative to the repository root
+	OutOfBounds bool   // outside the active spec's paths: globs ([build] boundary)
 }
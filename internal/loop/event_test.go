@@ -95,6 +95,59 @@ func TestEmitToChannelWithToolUse(t *testing.T) {
 	}
 }
 
+func TestEmitToChannelWithToolResults(t *testing.T) {
+	ch := make(chan LogEntry, 16)
+	use := claude.ToolUseEvent("Bash", map[string]any{"command": "go test ./..."})
+	use.ToolUseID = "toolu_1"
+	thinking := claude.ThinkingEvent("Check the tests.\nThen commit.")
+	thinking.ToolUseID = "toolu_1"
+	agent := &mockAgent{
+		events: []claude.Event{
+			thinking,
+			use,
+			claude.ToolResultEvent("toolu_1", "--- FAIL: TestX\nFAIL\n", true),
+			claude.ToolResultEvent("toolu_9", "", false),
+			claude.ResultEvent(0.05, 1.0, "success"),
+		},
+	}
+	git := &mockGit{branch: "main", lastCommit: "abc test"}
+	cfg := defaultTestConfig()
+	cfg.Plan.MaxIterations = 1
+
+	lp, _ := setupTestLoop(t, agent, git, cfg)
+	lp.Events = ch
+
+	if err := lp.Run(context.Background(), ModePlan, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	close(ch)
+	var got []LogEntry
+	for e := range ch {
+		switch e.Kind {
+		case LogThinking, LogToolUse, LogToolResult:
+			got = append(got, e)
+		}
+	}
+	if len(got) != 4 {
+		t.Fatalf("entries = %+v, want thinking, tool use, and two results", got)
+	}
+	if got[0].Kind != LogThinking || got[0].Message != "thinking: Check the tests." || got[0].ToolUseID != "toolu_1" {
+		t.Errorf("thinking = %+v", got[0])
+	}
+	if got[1].ToolUseID != "toolu_1" {
+		t.Errorf("tool use ToolUseID = %q, want toolu_1", got[1].ToolUseID)
+	}
+	failed := got[2]
+	if failed.Kind != LogToolResult || failed.ToolName != "Bash" || !failed.IsError ||
+		failed.Content != "--- FAIL: TestX\nFAIL\n" || failed.Message != "result: Bash  error  (2 lines)" {
+		t.Errorf("failed result = %+v", failed)
+	}
+	if unknown := got[3]; unknown.ToolName != "" || unknown.Message != "result: ok  (empty)" {
+		t.Errorf("unmatched result = %+v", unknown)
+	}
+}
+
 func TestEmitFallsBackToWriter(t *testing.T) {
 	agent := &mockAgent{
 		events: []claude.Event{claude.ResultEvent(0.10, 1.0, "success")},
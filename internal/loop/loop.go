@@ -44,11 +44,41 @@ type Loop struct {
 	Dir              string          // working directory for prompt file resolution
 	PostIteration    func()          // optional: called after each iteration (e.g., test-gated rollback)
 	StopAfter        <-chan struct{} // optional: closed to request graceful stop after current iteration
+	PauseAfter       <-chan struct{} // optional: closed to pause after current iteration (see Resume)
 	NotificationHook func(LogEntry)  // optional: called on every emitted event for external notifications
 	Roam             bool            // roam freely across the codebase (--roam flag)
 	Spec             string          // active spec name for prompt augmentation (empty = no augmentation)
 	SpecDir          string          // active spec directory for prompt augmentation
 	Focus            string          // constrain roam to a specific topic (empty = no constraint)
+
+	// OnSpecComplete is called after LogSpecComplete is emitted in build mode
+	// (never in roam mode), e.g. to open a pull request. A non-empty message is logged as
+	// LogInfo; an error is logged as LogError and does not fail the loop.
+	OnSpecComplete func(ctx context.Context) (string, error)
+
+	// Resume continues the run recorded in a checkpoint: iteration numbering,
+	// running cost and spec-completion state carry on from it. Run consumes
+	// it, so a Regent restart of the same Loop begins a fresh run.
+	Resume *Checkpoint
+
+	// Steering is the queue of operator notes added to the next iteration's
+	// prompt; nil disables steering.
+	Steering *SteerQueue
+
+	SessionID  string // session log ID, written to the Ralph-Session commit trailer
+	DryRunPush bool   // log what would be pushed instead of pushing (--dry-run-push)
+
+	pendingFeedback string // appended to the next iteration's prompt, then cleared
+	policyBase      string // commit-policy range start carried over after a strict failure
+
+	// rateLimit is the usage or rate limit hit by the current iteration, if
+	// any; Run sleeps until it lifts and then repeats the iteration.
+	rateLimit *claude.Event
+
+	policy     *toolPolicy // compiled [[policy.rules]]; nil = no rules
+	policyStop error       // set when a "stop-loop" rule fired during the iteration
+
+	boundary []string // active spec's paths: globs ([build] boundary); nil = unbounded
 }
 
 // Run executes the loop in the given mode. It runs iterations until the
@@ -66,6 +96,14 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 	}
 
 	promptFile, maxIter := l.modeConfig(mode)
+	resume := l.Resume
+	l.Resume = nil
+	if resume != nil {
+		if resume.Mode != mode {
+			return fmt.Errorf("loop: checkpoint is for a %s loop, not %s", resume.Mode, mode)
+		}
+		maxIter = resume.MaxIter
+	}
 	if maxOverride > 0 {
 		maxIter = maxOverride
 	}
@@ -76,6 +114,12 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 		return fmt.Errorf("loop: read prompt %s: %w", promptFile, err)
 	}
 
+	policy, err := newToolPolicy(l.Config.Policy.Rules)
+	if err != nil {
+		return err
+	}
+	l.policy = policy
+
 	// Augment prompt with spec context guardrails when applicable.
 	prompt := augmentPrompt(string(promptBytes), l.Spec, l.SpecDir, l.Roam, l.Focus)
 
@@ -83,23 +127,46 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 	if err != nil {
 		return fmt.Errorf("loop: get branch: %w", err)
 	}
+	branch, err = l.guardBranch(mode, branch)
+	if err != nil {
+		return err
+	}
 
 	// Include current HEAD commit so TUI footer shows it from the start,
 	// rather than showing "—" until the first push.
 	commit, _ := l.Git.LastCommit()
 
-	l.emit(LogEntry{
+	start := LogEntry{
 		Kind:    LogInfo,
 		Message: fmt.Sprintf("Starting %s loop on branch %s (max: %s)", mode, branch, iterLabel(maxIter)),
 		Branch:  branch,
 		Commit:  commit,
 		MaxIter: maxIter,
 		Mode:    string(mode),
-	})
+	}
+	if resume != nil {
+		start.Message = fmt.Sprintf("Resuming %s loop at iteration %d on branch %s (max: %s, $%.2f so far)",
+			mode, resume.Iteration+1, branch, iterLabel(maxIter), resume.TotalCost)
+		start.TotalCost = resume.TotalCost
+	}
+	if l.DryRunPush && l.Config.Git.AutoPush {
+		start.Message += " — dry-run push"
+		start.PushGuard = PushGuardDryRun
+	}
+	l.emit(start)
+
+	l.loadBoundary()
+	prompt += boundaryPrompt(l.boundary)
 
 	var totalCost float64
 	var prevSubtype string
-	for i := 1; maxIter == 0 || i <= maxIter; i++ {
+	first := 1
+	if resume != nil {
+		totalCost = resume.TotalCost
+		prevSubtype = resume.PrevSubtype
+		first = resume.Iteration + 1
+	}
+	for i := first; maxIter == 0 || i <= maxIter; i++ {
 		select {
 		case <-ctx.Done():
 			l.emit(LogEntry{
@@ -116,21 +183,48 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 		}
 		totalCost += cost
 
+		if l.policyStop != nil {
+			err := l.policyStop
+			l.policyStop = nil
+			l.emit(LogEntry{
+				Kind:      LogStopped,
+				Message:   fmt.Sprintf("Loop stopped by tool-use policy: %v", err),
+				TotalCost: totalCost,
+			})
+			return fmt.Errorf("loop: iteration %d: %w", i, err)
+		}
+
+		// A usage-limited iteration did no work: wait for the limit to lift
+		// and repeat it rather than counting it or reading it as a signal.
+		if l.rateLimit != nil {
+			stopped, err := l.waitForReset(ctx)
+			if err != nil || stopped {
+				return err
+			}
+			i--
+			continue
+		}
+
 		// Spec completion detection: two-signal check — previous iteration
 		// reported "success" and this iteration produced no new commits.
 		if prevSubtype == "success" && !commitsProduced {
 			if l.Roam {
+				l.clearCheckpoint()
 				l.emit(LogEntry{
 					Kind:      LogSweepComplete,
 					Message:   fmt.Sprintf("Roam complete (%d iterations, $%.2f)", i, totalCost),
 					TotalCost: totalCost,
 				})
 			} else {
+				l.clearCheckpoint()
 				l.emit(LogEntry{
 					Kind:      LogSpecComplete,
 					Message:   fmt.Sprintf("Spec complete (%d iterations, $%.2f)", i, totalCost),
 					TotalCost: totalCost,
 				})
+				if mode == ModeBuild {
+					l.runSpecCompleteHook(ctx)
+				}
 			}
 			return nil
 		}
@@ -147,6 +241,28 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 			TotalCost: totalCost,
 		})
 
+		// Checkpoint so a pause, a stop or a crash can resume from here.
+		paused := l.pauseRequested() && (maxIter == 0 || i < maxIter)
+		l.saveCheckpoint(Checkpoint{
+			Mode:        mode,
+			Iteration:   i,
+			MaxIter:     maxIter,
+			TotalCost:   totalCost,
+			PrevSubtype: prevSubtype,
+			Branch:      branch,
+			Paused:      paused,
+		})
+		if paused {
+			l.emit(LogEntry{
+				Kind:      LogPaused,
+				Message:   fmt.Sprintf("Paused after iteration %d — resume to continue at iteration %d", i, i+1),
+				Iteration: i,
+				TotalCost: totalCost,
+				Mode:      string(mode),
+			})
+			return nil
+		}
+
 		// Check for user-requested graceful stop (TUI 's' key).
 		if l.StopAfter != nil {
 			select {
@@ -161,6 +277,7 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 		}
 	}
 
+	l.clearCheckpoint()
 	l.emit(LogEntry{
 		Kind:      LogDone,
 		Message:   fmt.Sprintf("Loop complete — %s iterations done, total cost: $%.2f", iterLabel(maxIter), totalCost),
@@ -170,6 +287,98 @@ func (l *Loop) Run(ctx context.Context, mode Mode, maxOverride int) error {
 	return nil
 }
 
+// pauseRequested reports whether PauseAfter has been closed.
+func (l *Loop) pauseRequested() bool {
+	if l.PauseAfter == nil {
+		return false
+	}
+	select {
+	case <-l.PauseAfter:
+		return true
+	default:
+		return false
+	}
+}
+
+// requeueInput puts back the feedback and steering notes taken by an
+// iteration that hit a usage limit or could not start Claude, ahead of
+// anything queued since.
+func (l *Loop) requeueInput(feedback string, notes []SteerNote) {
+	if feedback != "" {
+		if l.pendingFeedback != "" {
+			feedback += "\n\n" + l.pendingFeedback
+		}
+		l.pendingFeedback = feedback
+	}
+	if len(notes) == 0 {
+		return
+	}
+	if err := l.Steering.Restore(notes); err != nil {
+		l.emit(LogEntry{Kind: LogError, Message: fmt.Sprintf("Steering notes lost: %v", err)})
+		return
+	}
+	l.emit(LogEntry{Kind: LogInfo, Message: fmt.Sprintf("Requeued %s", plural(len(notes), "steering note"))})
+}
+
+// waitForReset sleeps until the usage limit recorded by the last iteration
+// lifts, or for [claude] rate_limit_wait_seconds when Claude gave no reset
+// time. Returns stopped=true if a graceful stop was requested meanwhile, or
+// the context error if it was cancelled.
+func (l *Loop) waitForReset(ctx context.Context) (stopped bool, err error) {
+	rl := l.rateLimit
+	l.rateLimit = nil
+
+	now := time.Now()
+	until := rl.ResetAt
+	if !until.After(now) {
+		until = now.Add(time.Duration(l.Config.Claude.RateLimitWaitSeconds) * time.Second)
+	}
+	l.emit(LogEntry{
+		Kind: LogRateLimit,
+		Message: fmt.Sprintf("Usage limit reached — resuming at %s (in %s): %s",
+			until.Local().Format("15:04"), until.Sub(now).Round(time.Second), rl.Error),
+		ResetAt: until,
+	})
+
+	timer := time.NewTimer(time.Until(until))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.emit(LogEntry{
+			Kind:    LogStopped,
+			Message: fmt.Sprintf("Loop stopped: %v", ctx.Err()),
+		})
+		return false, ctx.Err()
+	case <-l.StopAfter:
+		l.emit(LogEntry{
+			Kind:    LogStopped,
+			Message: "Stop requested — exiting while waiting for the usage limit",
+		})
+		return true, nil
+	case <-timer.C:
+	}
+	l.emit(LogEntry{Kind: LogInfo, Message: "Usage limit lifted — resuming"})
+	return false, nil
+}
+
+// runSpecCompleteHook invokes OnSpecComplete and logs its outcome.
+func (l *Loop) runSpecCompleteHook(ctx context.Context) {
+	if l.OnSpecComplete == nil {
+		return
+	}
+	msg, err := l.OnSpecComplete(ctx)
+	if err != nil {
+		l.emit(LogEntry{
+			Kind:    LogError,
+			Message: fmt.Sprintf("Spec-complete action failed: %v", err),
+		})
+		return
+	}
+	if msg != "" {
+		l.emit(LogEntry{Kind: LogInfo, Message: msg})
+	}
+}
+
 func (l *Loop) iteration(ctx context.Context, n, maxIter int, prompt, branch string) (cost float64, subtype string, commitsProduced bool, err error) {
 	l.emit(LogEntry{
 		Kind:      LogIterStart,
@@ -177,6 +386,8 @@ func (l *Loop) iteration(ctx context.Context, n, maxIter int, prompt, branch str
 		Iteration: n,
 		MaxIter:   maxIter,
 		Branch:    branch,
+		Spec:      l.Spec,
+		Model:     l.Config.Claude.Model,
 	})
 
 	// Stash uncommitted changes before pulling
@@ -213,31 +424,60 @@ func (l *Loop) iteration(ctx context.Context, n, maxIter int, prompt, branch str
 	// Capture HEAD before Claude runs to detect new commits afterward.
 	headBefore, _ := l.Git.LastCommit()
 
+	// Take feedback and steering notes only now, so an iteration that fails
+	// before Claude starts leaves them for the next one.
+	feedback := l.pendingFeedback
+	if feedback != "" {
+		prompt += "\n\n" + feedback
+		l.pendingFeedback = ""
+	}
+	guidance, notes := l.takeSteering(n)
+	if guidance != "" {
+		prompt += "\n\n" + guidance
+	}
+
 	// Run Claude
 	l.emit(LogEntry{
 		Kind:    LogInfo,
 		Message: "Running Claude...",
 	})
-	events, agentErr := l.Agent.Run(ctx, prompt, claude.RunOptions{
+	// A tool-use policy violation kills this invocation without cancelling
+	// the loop's context.
+	claudeCtx, killClaude := context.WithCancel(ctx)
+	defer killClaude()
+	allowed, disallowed := l.policy.cliTools()
+	transcript, closeTranscript := l.openTranscript(n)
+	defer closeTranscript()
+	events, agentErr := l.Agent.Run(claudeCtx, prompt, claude.RunOptions{
 		Model:                 l.Config.Claude.Model,
 		MaxTurns:              l.Config.Claude.MaxTurns,
 		DangerSkipPermissions: l.Config.Claude.DangerSkipPermissions,
 		Dir:                   l.Dir,
+		AllowedTools:          allowed,
+		DisallowedTools:       disallowed,
+		Transcript:            transcript,
 	})
 	if agentErr != nil {
+		l.requeueInput(feedback, notes)
 		return 0, "", false, fmt.Errorf("start claude: %w", agentErr)
 	}
 
 	// Drain events
+	policyStopped := false
+	tools := toolNames{}
+	var complete *LogEntry // held back until the iteration is known not to be usage-limited
 	for ev := range events {
 		switch ev.Type {
 		case claude.EventToolUse:
-			l.emit(LogEntry{
-				Kind:      LogToolUse,
-				Message:   fmt.Sprintf("tool: %s  %s", ev.ToolName, summarizeInput(ev.ToolInput)),
-				ToolName:  ev.ToolName,
-				ToolInput: summarizeInput(ev.ToolInput),
-			})
+			l.emit(tools.toolUseEntry(ev))
+			if !policyStopped && l.applyToolPolicy(ev) {
+				policyStopped = true
+				killClaude()
+			}
+		case claude.EventToolResult:
+			l.emit(tools.resultEntry(ev))
+		case claude.EventThinking:
+			l.emit(thinkingEntry(ev))
 		case claude.EventText:
 			if ev.Text != "" {
 				l.emit(LogEntry{
@@ -248,28 +488,69 @@ func (l *Loop) iteration(ctx context.Context, n, maxIter int, prompt, branch str
 		case claude.EventResult:
 			cost = ev.CostUSD
 			subtype = ev.Subtype
+			if policyStopped {
+				subtype = SubtypeToolPolicy
+			}
 			msg := fmt.Sprintf("Iteration %d complete — $%.2f — %.1fs", n, ev.CostUSD, ev.Duration)
-			if ev.Subtype != "" {
-				msg += fmt.Sprintf(" — %s", ev.Subtype)
+			if subtype != "" {
+				msg += fmt.Sprintf(" — %s", subtype)
 			}
-			l.emit(LogEntry{
+			complete = &LogEntry{
 				Kind:      LogIterComplete,
 				Message:   msg,
 				Iteration: n,
 				CostUSD:   ev.CostUSD,
 				Duration:  ev.Duration,
-				Subtype:   ev.Subtype,
-			})
+				Subtype:   subtype,
+			}
 		case claude.EventError:
 			l.emit(LogEntry{
 				Kind:    LogError,
 				Message: fmt.Sprintf("Error: %s", ev.Error),
 			})
+		case claude.EventRateLimit:
+			rl := ev
+			l.rateLimit = &rl
+		case claude.EventStreamStats:
+			l.emit(LogEntry{
+				Kind:    LogInfo,
+				Message: fmt.Sprintf("Claude output: %s", ev.Stats),
+			})
 		}
 	}
 
+	if policyStopped {
+		subtype = SubtypeToolPolicy
+	}
+
+	// A usage-limited iteration is repeated (see Run): it does not complete,
+	// and the retry gets the feedback and steering notes this attempt took.
+	if l.rateLimit != nil {
+		l.requeueInput(feedback, notes)
+	} else if complete != nil {
+		l.emit(*complete)
+	}
+
+	// Enforce [build] boundary before the commit policy, so a revert commit
+	// is checked and pushed with the rest of the iteration.
+	files := l.checkFiles(commitSHA(headBefore))
+
+	// Enforce the commit policy before anything is pushed. Nothing from an
+	// iteration that tripped a stop-loop rule is pushed either.
+	policyOK := l.policyStop == nil
+	if policyOK && l.Config.Git.CommitPolicy != "" {
+		policyOK = l.enforceCommitPolicy(n, branch, commitSHA(headBefore))
+		if !policyOK {
+			subtype = SubtypeCommitPolicy
+		}
+	}
+
+	if policyOK && l.Config.Git.Strategy == "squash-iteration" {
+		l.squashIteration(n, branch, commitSHA(headBefore))
+	}
+
 	// Push if there are new local commits
-	if l.Config.Git.AutoPush {
+	if l.Config.Git.AutoPush && policyOK {
 		if pushErr := l.pushIfNeeded(branch); pushErr != nil {
 			l.emit(LogEntry{
 				Kind:    LogError,
@@ -281,6 +562,7 @@ func (l *Loop) iteration(ctx context.Context, n, maxIter int, prompt, branch str
 	// Detect whether Claude produced new commits during this iteration.
 	headAfter, _ := l.Git.LastCommit()
 	commitsProduced = headBefore != headAfter
+	l.emitFiles(n, files, commitSHA(headBefore), headAfter)
 
 	return cost, subtype, commitsProduced, nil
 }
@@ -316,6 +598,13 @@ func (l *Loop) pushIfNeeded(branch string) error {
 	if err == nil && !hasChanges {
 		return nil
 	}
+	if bo, ok := l.Git.(BranchOps); ok && bo.NeedsForcePush(branch) {
+		return fmt.Errorf("refusing to push %s: origin/%s is not an ancestor of HEAD and ralph never force-pushes", branch, branch)
+	}
+	if l.DryRunPush {
+		l.logDryRunPush(branch)
+		return nil
+	}
 	l.emit(LogEntry{
 		Kind:    LogGitPush,
 		Message: fmt.Sprintf("Pushing %s", branch),
@@ -407,7 +696,9 @@ func augmentPrompt(prompt, spec, specDir string, roam bool, focus string) string
 	return prompt
 }
 
-func summarizeInput(input map[string]any) string {
+// SummarizeInput returns the most descriptive argument of a tool call (file
+// path, command, pattern, ...) for one-line log display.
+func SummarizeInput(input map[string]any) string {
 	// Check well-known field names in priority order.
 	for _, key := range []string{
 		"file_path", "command", "path", "url", "pattern", // core tools
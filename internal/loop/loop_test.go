@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/LISSConsulting/RalphSpec/internal/claude"
 	"github.com/LISSConsulting/RalphSpec/internal/config"
@@ -88,6 +89,8 @@ func (m *mockGit) LastCommit() (string, error) {
 
 func defaultTestConfig() *config.Config {
 	cfg := config.Defaults()
+	// Most tests run on "main"; the protected-branch guard has its own tests.
+	cfg.Git.ProtectedBranches = nil
 	return &cfg
 }
 
@@ -523,6 +526,31 @@ func TestTextEventInEventChannel(t *testing.T) {
 	}
 }
 
+func TestIterStartCarriesSpecAndModel(t *testing.T) {
+	ch := make(chan LogEntry, 32)
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.10, 1.0, "success")}}
+	cfg := defaultTestConfig()
+	cfg.Plan.MaxIterations = 1
+	cfg.Claude.Model = "sonnet"
+
+	lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, cfg)
+	lp.Events = ch
+	lp.Spec = "001-auth"
+	if err := lp.Run(context.Background(), ModePlan, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(ch)
+	for e := range ch {
+		if e.Kind == LogIterStart {
+			if e.Spec != "001-auth" || e.Model != "sonnet" {
+				t.Errorf("LogIterStart Spec/Model = %q/%q, want 001-auth/sonnet", e.Spec, e.Model)
+			}
+			return
+		}
+	}
+	t.Fatal("expected a LogIterStart entry")
+}
+
 func TestSubtypeInLogOutput(t *testing.T) {
 	t.Run("subtype included in iteration complete message", func(t *testing.T) {
 		agent := &mockAgent{
@@ -672,9 +700,9 @@ func TestSummarizeInput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := summarizeInput(tt.input)
+			got := SummarizeInput(tt.input)
 			if got != tt.want {
-				t.Errorf("summarizeInput(%v) = %q, want %q", tt.input, got, tt.want)
+				t.Errorf("SummarizeInput(%v) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
@@ -1249,6 +1277,65 @@ func TestSpecCompletion(t *testing.T) {
 	})
 }
 
+// TestOnSpecComplete verifies the spec-complete hook runs after LogSpecComplete
+// and that its message or error is surfaced as a log entry.
+func TestOnSpecComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		err      error
+		roam     bool
+		wantKind LogKind
+		wantText string
+		wantCall bool
+	}{
+		{name: "message logged as info", msg: "Opened pull request #3", wantKind: LogInfo, wantText: "Opened pull request #3", wantCall: true},
+		{name: "error logged", err: errors.New("no token"), wantKind: LogError, wantText: "Spec-complete action failed: no token", wantCall: true},
+		{name: "not called in roam mode", roam: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &mockAgent{
+				events: []claude.Event{claude.ResultEvent(0.10, 1.0, "success")},
+			}
+			git := &mockGit{
+				branch:             "feat/spec",
+				lastCommitSequence: []string{"h0", "h1", "h2", "h2", "h2"},
+			}
+			cfg := defaultTestConfig()
+			cfg.Build.MaxIterations = 0
+
+			ch := make(chan LogEntry, 32)
+			lp, _ := setupTestLoop(t, agent, git, cfg)
+			lp.Events = ch
+			lp.Roam = tt.roam
+			called := false
+			lp.OnSpecComplete = func(context.Context) (string, error) {
+				called = true
+				return tt.msg, tt.err
+			}
+
+			if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if called != tt.wantCall {
+				t.Fatalf("hook called = %v, want %v", called, tt.wantCall)
+			}
+			close(ch)
+			if !tt.wantCall {
+				return
+			}
+			var last LogEntry
+			for e := range ch {
+				last = e
+			}
+			if last.Kind != tt.wantKind || last.Message != tt.wantText {
+				t.Errorf("last entry = %v %q, want %v %q", last.Kind, last.Message, tt.wantKind, tt.wantText)
+			}
+		})
+	}
+}
+
 // TestRoamCompletion verifies that roam mode emits LogSweepComplete instead of
 // LogSpecComplete when the two-signal completion fires.
 func TestRoamCompletion(t *testing.T) {
@@ -1465,3 +1552,178 @@ func TestPromptAugmentationInRun(t *testing.T) {
 		}
 	})
 }
+
+// sequenceAgent is a claude.Agent that replays a different event list on each
+// call, repeating the last one once the list runs out.
+type sequenceAgent struct {
+	sessions [][]claude.Event
+	calls    int
+	prompts  []string
+}
+
+func (a *sequenceAgent) Run(_ context.Context, prompt string, _ claude.RunOptions) (<-chan claude.Event, error) {
+	events := a.sessions[min(a.calls, len(a.sessions)-1)]
+	a.calls++
+	a.prompts = append(a.prompts, prompt)
+	ch := make(chan claude.Event, len(events))
+	for _, ev := range events {
+		ch <- ev
+	}
+	close(ch)
+	return ch, nil
+}
+
+func TestRateLimitWait(t *testing.T) {
+	t.Run("sleeps until reset and repeats the iteration", func(t *testing.T) {
+		reset := time.Now().Add(50 * time.Millisecond)
+		agent := &sequenceAgent{sessions: [][]claude.Event{
+			{claude.RateLimitEvent("Claude AI usage limit reached", reset), claude.ResultEvent(0.01, 1, "error_during_execution")},
+			{claude.ResultEvent(0.10, 2, "success")},
+		}}
+		cfg := defaultTestConfig()
+		lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, cfg)
+		events := make(chan LogEntry, 128)
+		lp.Events = events
+
+		if err := lp.Run(context.Background(), ModeBuild, 1); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if agent.calls != 2 {
+			t.Errorf("agent calls = %d, want 2 (the limited iteration is repeated)", agent.calls)
+		}
+		close(events)
+		var sawLimit, sawDone bool
+		for e := range events {
+			switch e.Kind {
+			case LogRateLimit:
+				sawLimit = true
+				if !e.ResetAt.Equal(reset) {
+					t.Errorf("ResetAt = %v, want %v", e.ResetAt, reset)
+				}
+			case LogError:
+				t.Errorf("rate limit logged as an error: %q", e.Message)
+			case LogDone:
+				sawDone = true
+			}
+		}
+		if !sawLimit || !sawDone {
+			t.Errorf("sawLimit = %v, sawDone = %v; want both", sawLimit, sawDone)
+		}
+	})
+
+	t.Run("retry keeps feedback and steering notes", func(t *testing.T) {
+		agent := &sequenceAgent{sessions: [][]claude.Event{
+			{claude.RateLimitEvent("usage limit reached", time.Now().Add(20*time.Millisecond)), claude.ResultEvent(0, 1, "error_during_execution")},
+			{claude.ResultEvent(0.10, 2, "success")},
+		}}
+		lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, defaultTestConfig())
+		events := make(chan LogEntry, 128)
+		lp.Events = events
+		lp.pendingFeedback = "## Boundary\n\nrevert the stray edit"
+		lp.Steering = NewSteerQueue(lp.Dir)
+		if _, err := lp.Steering.Add("skip the migration"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := lp.Run(context.Background(), ModeBuild, 1); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if len(agent.prompts) != 2 {
+			t.Fatalf("agent calls = %d, want 2", len(agent.prompts))
+		}
+		for _, want := range []string{"revert the stray edit", "skip the migration"} {
+			if !strings.Contains(agent.prompts[1], want) {
+				t.Errorf("retry prompt missing %q:\n%s", want, agent.prompts[1])
+			}
+		}
+		if notes, _ := lp.Steering.Pending(); len(notes) != 0 {
+			t.Errorf("retry should consume the requeued notes, %d left", len(notes))
+		}
+		close(events)
+		var completes []LogEntry
+		for e := range events {
+			if e.Kind == LogIterComplete {
+				completes = append(completes, e)
+			}
+		}
+		if len(completes) != 1 || completes[0].Iteration != 1 || completes[0].Subtype != "success" {
+			t.Errorf("iteration complete entries = %+v, want one for the successful retry", completes)
+		}
+	})
+
+	t.Run("failed iteration keeps feedback and steering notes", func(t *testing.T) {
+		for _, tc := range []struct {
+			name  string
+			agent *mockAgent
+			git   *mockGit
+		}{
+			{"stash fails", &mockAgent{}, &mockGit{branch: "main", dirty: true, stashErr: errors.New("stash failed")}},
+			{"claude fails to start", &mockAgent{err: errors.New("exec: claude not found")}, &mockGit{branch: "main", lastCommit: "abc"}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				lp, _ := setupTestLoop(t, tc.agent, tc.git, defaultTestConfig())
+				lp.pendingFeedback = "## Boundary\n\nrevert the stray edit"
+				lp.Steering = NewSteerQueue(lp.Dir)
+				if _, err := lp.Steering.Add("skip the migration"); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := lp.Run(context.Background(), ModeBuild, 1); err == nil {
+					t.Fatal("expected Run to fail")
+				}
+				notes, err := lp.Steering.Pending()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(notes) != 1 || notes[0].Text != "skip the migration" {
+					t.Errorf("pending notes = %+v, want the note back", notes)
+				}
+				if !strings.Contains(lp.pendingFeedback, "revert the stray edit") {
+					t.Errorf("pendingFeedback = %q, want the feedback back", lp.pendingFeedback)
+				}
+			})
+		}
+	})
+
+	t.Run("falls back to rate_limit_wait_seconds and honours stop", func(t *testing.T) {
+		agent := &sequenceAgent{sessions: [][]claude.Event{
+			{claude.RateLimitEvent("API Error: 429 rate_limit_error", time.Time{})},
+		}}
+		cfg := defaultTestConfig()
+		cfg.Claude.RateLimitWaitSeconds = 3600
+		lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, cfg)
+		stopCh := make(chan struct{})
+		lp.StopAfter = stopCh
+		var resetAt time.Time
+		lp.NotificationHook = func(e LogEntry) {
+			if e.Kind == LogRateLimit {
+				resetAt = e.ResetAt
+				close(stopCh)
+			}
+		}
+
+		start := time.Now()
+		if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if d := resetAt.Sub(start); d < 59*time.Minute || d > 61*time.Minute {
+			t.Errorf("reset in %v, want about an hour", d)
+		}
+		if agent.calls != 1 {
+			t.Errorf("agent calls = %d, want 1", agent.calls)
+		}
+	})
+
+	t.Run("cancellation interrupts the wait", func(t *testing.T) {
+		agent := &sequenceAgent{sessions: [][]claude.Event{
+			{claude.RateLimitEvent("usage limit reached", time.Now().Add(time.Hour))},
+		}}
+		lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main", lastCommit: "abc"}, defaultTestConfig())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if err := lp.Run(ctx, ModeBuild, 0); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Run = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
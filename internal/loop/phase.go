@@ -0,0 +1,123 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+)
+
+// Spec-kit phases RunPhase can run, in their usual order.
+const (
+	PhaseSpecify = "specify"
+	PhaseClarify = "clarify"
+	PhasePlan    = "plan"
+	PhaseTasks   = "tasks"
+)
+
+// phaseRequires maps each phase to the spec file it needs and the phase
+// that writes that file.
+var phaseRequires = map[string][2]string{
+	PhaseClarify: {"spec.md", PhaseSpecify},
+	PhasePlan:    {"spec.md", PhaseSpecify},
+	PhaseTasks:   {"plan.md", PhasePlan},
+}
+
+// PhaseRequest asks RunPhase to run one spec-kit phase for a spec.
+type PhaseRequest struct {
+	Phase   string // PhaseSpecify, PhaseClarify, PhasePlan, or PhaseTasks
+	SpecDir string // spec directory, relative to Loop.Dir or absolute
+	Input   string // specify: the feature description; with Resume: the user's reply
+	Resume  string // Claude session to continue, e.g. to answer clarify questions
+}
+
+// RunPhase runs the speckit.<phase> skill for req.SpecDir through the
+// stream-json agent, emitting its tool calls and text like an iteration,
+// between a LogPhaseStart and a LogPhaseDone entry. It returns the Claude
+// session ID, so questions the phase asked can be answered with a follow-up
+// request that sets Resume and carries the reply as Input.
+func (l *Loop) RunPhase(ctx context.Context, req PhaseRequest) (string, error) {
+	skill := "speckit." + req.Phase
+	switch req.Phase {
+	case PhaseSpecify, PhaseClarify, PhasePlan, PhaseTasks:
+	default:
+		return "", fmt.Errorf("loop: unknown spec-kit phase %q", req.Phase)
+	}
+	dir := req.SpecDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(l.Dir, dir)
+	}
+	if need, ok := phaseRequires[req.Phase]; ok && req.Resume == "" {
+		if _, err := os.Stat(filepath.Join(dir, need[0])); err != nil {
+			return "", fmt.Errorf("loop: %s: %s not found in %s; run %s first", skill, need[0], req.SpecDir, need[1])
+		}
+	}
+
+	prompt := req.Input
+	if req.Resume == "" {
+		prompt = "/" + skill
+		if req.Input != "" {
+			prompt += " " + req.Input
+		}
+		prompt += "\n\nFeature directory: " + req.SpecDir
+	}
+
+	msg := fmt.Sprintf("Running %s for %s", skill, req.SpecDir)
+	if req.Resume != "" {
+		msg = fmt.Sprintf("Continuing %s for %s", skill, req.SpecDir)
+	}
+	l.emit(LogEntry{Kind: LogPhaseStart, Message: msg, Phase: req.Phase})
+
+	events, err := l.Agent.Run(ctx, prompt, claude.RunOptions{
+		Model:                 l.Config.Claude.Model,
+		MaxTurns:              l.Config.Claude.MaxTurns,
+		DangerSkipPermissions: l.Config.Claude.DangerSkipPermissions,
+		Dir:                   l.Dir,
+		Resume:                req.Resume,
+	})
+	if err != nil {
+		return "", fmt.Errorf("loop: start %s: %w", skill, err)
+	}
+
+	var session string
+	tools := toolNames{}
+	for ev := range events {
+		switch ev.Type {
+		case claude.EventToolUse:
+			l.emit(tools.toolUseEntry(ev))
+		case claude.EventToolResult:
+			l.emit(tools.resultEntry(ev))
+		case claude.EventThinking:
+			l.emit(thinkingEntry(ev))
+		case claude.EventText:
+			if ev.Text != "" {
+				l.emit(LogEntry{Kind: LogText, Message: ev.Text})
+			}
+		case claude.EventError, claude.EventRateLimit:
+			l.emit(LogEntry{Kind: LogError, Message: fmt.Sprintf("Error: %s", ev.Error)})
+		case claude.EventResult:
+			session = ev.SessionID
+			msg := fmt.Sprintf("%s complete — $%.2f — %.1fs", skill, ev.CostUSD, ev.Duration)
+			if ev.Subtype != "" {
+				msg += fmt.Sprintf(" — %s", ev.Subtype)
+			}
+			l.emit(LogEntry{
+				Kind:          LogPhaseDone,
+				Message:       msg,
+				CostUSD:       ev.CostUSD,
+				Duration:      ev.Duration,
+				Subtype:       ev.Subtype,
+				Phase:         req.Phase,
+				ClaudeSession: ev.SessionID,
+			})
+		}
+	}
+
+	if ctx.Err() != nil {
+		l.emit(LogEntry{Kind: LogStopped, Message: skill + " stopped"})
+		return session, ctx.Err()
+	}
+	return session, nil
+}
@@ -0,0 +1,148 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+)
+
+// optsAgent is a mockAgent that also records the RunOptions it was given.
+type optsAgent struct {
+	mockAgent
+	opts claude.RunOptions
+}
+
+func (a *optsAgent) Run(ctx context.Context, prompt string, opts claude.RunOptions) (<-chan claude.Event, error) {
+	a.opts = opts
+	return a.mockAgent.Run(ctx, prompt, opts)
+}
+
+func setupPhaseLoop(t *testing.T, agent claude.Agent, files ...string) (*Loop, chan LogEntry) {
+	t.Helper()
+	lp, _ := setupTestLoop(t, agent, &mockGit{branch: "main"}, defaultTestConfig())
+	specDir := filepath.Join(lp.Dir, "specs", "001-feat")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(specDir, f), []byte("# "+f), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events := make(chan LogEntry, 32)
+	lp.Events = events
+	return lp, events
+}
+
+func TestRunPhase(t *testing.T) {
+	agent := &optsAgent{mockAgent: mockAgent{events: []claude.Event{
+		claude.ToolUseEvent("Write", map[string]any{"file_path": "specs/001-feat/plan.md"}),
+		claude.TextEvent("Plan written."),
+		{Type: claude.EventResult, CostUSD: 0.12, Duration: 3, Subtype: "success", SessionID: "sess-9"},
+	}}}
+	lp, events := setupPhaseLoop(t, agent, "spec.md")
+
+	session, err := lp.RunPhase(context.Background(), PhaseRequest{Phase: PhasePlan, SpecDir: "specs/001-feat"})
+	if err != nil {
+		t.Fatalf("RunPhase: %v", err)
+	}
+	if session != "sess-9" {
+		t.Errorf("session = %q, want sess-9", session)
+	}
+	if !strings.HasPrefix(agent.lastPrompt, "/speckit.plan\n") || !strings.Contains(agent.lastPrompt, "Feature directory: specs/001-feat") {
+		t.Errorf("prompt = %q", agent.lastPrompt)
+	}
+	if agent.opts.Resume != "" || agent.opts.Dir != lp.Dir {
+		t.Errorf("opts = %+v", agent.opts)
+	}
+
+	got := drain(events)
+	kinds := []LogKind{LogPhaseStart, LogToolUse, LogText, LogPhaseDone}
+	if len(got) != len(kinds) {
+		t.Fatalf("events = %+v, want kinds %v", got, kinds)
+	}
+	for i, k := range kinds {
+		if got[i].Kind != k {
+			t.Errorf("event[%d].Kind = %v, want %v", i, got[i].Kind, k)
+		}
+	}
+	done := got[3]
+	if done.Phase != PhasePlan || done.Subtype != "success" || done.ClaudeSession != "sess-9" || done.CostUSD != 0.12 {
+		t.Errorf("done = %+v", done)
+	}
+}
+
+func TestRunPhase_SpecifyInput(t *testing.T) {
+	agent := &optsAgent{}
+	lp, _ := setupPhaseLoop(t, agent)
+	if _, err := lp.RunPhase(context.Background(), PhaseRequest{Phase: PhaseSpecify, SpecDir: "specs/001-feat", Input: "add dark mode"}); err != nil {
+		t.Fatalf("RunPhase: %v", err)
+	}
+	if !strings.HasPrefix(agent.lastPrompt, "/speckit.specify add dark mode\n") {
+		t.Errorf("prompt = %q", agent.lastPrompt)
+	}
+}
+
+func TestRunPhase_Resume(t *testing.T) {
+	agent := &optsAgent{}
+	// No spec.md: a resumed session skips the prerequisite check.
+	lp, _ := setupPhaseLoop(t, agent)
+	_, err := lp.RunPhase(context.Background(), PhaseRequest{Phase: PhaseClarify, SpecDir: "specs/001-feat", Input: "B", Resume: "sess-1"})
+	if err != nil {
+		t.Fatalf("RunPhase: %v", err)
+	}
+	if agent.lastPrompt != "B" || agent.opts.Resume != "sess-1" {
+		t.Errorf("prompt = %q, Resume = %q; want the bare answer resuming sess-1", agent.lastPrompt, agent.opts.Resume)
+	}
+}
+
+func TestRunPhase_Prerequisites(t *testing.T) {
+	tests := []struct {
+		phase string
+		files []string
+		want  string // error substring; empty = no error
+	}{
+		{PhaseClarify, nil, "spec.md not found"},
+		{PhasePlan, nil, "spec.md not found"},
+		{PhaseTasks, []string{"spec.md"}, "plan.md not found"},
+		{PhaseTasks, []string{"spec.md", "plan.md"}, ""},
+		{PhaseSpecify, nil, ""},
+		{"implement", nil, "unknown spec-kit phase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phase, func(t *testing.T) {
+			agent := &optsAgent{}
+			lp, _ := setupPhaseLoop(t, agent, tt.files...)
+			_, err := lp.RunPhase(context.Background(), PhaseRequest{Phase: tt.phase, SpecDir: "specs/001-feat"})
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("RunPhase: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("RunPhase err = %v, want %q", err, tt.want)
+			}
+			if agent.calls != 0 {
+				t.Error("agent ran despite a failed prerequisite")
+			}
+		})
+	}
+}
+
+func TestRunPhase_Cancelled(t *testing.T) {
+	lp, events := setupPhaseLoop(t, &optsAgent{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := lp.RunPhase(ctx, PhaseRequest{Phase: PhaseSpecify, SpecDir: "specs/001-feat", Input: "x"}); err == nil {
+		t.Fatal("RunPhase with a cancelled context returned nil")
+	}
+	got := drain(events)
+	if len(got) == 0 || got[len(got)-1].Kind != LogStopped {
+		t.Errorf("events = %+v, want LogStopped last", got)
+	}
+}
@@ -0,0 +1,101 @@
+package loop
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrProtectedBranch is returned by Run when the loop starts on a branch in
+// [git] protected_branches and on_protected = "refuse". The Regent treats it
+// as permanent and does not retry.
+var ErrProtectedBranch = errors.New("branch is protected")
+
+// BranchOps is the optional capability used by the push guards: leaving a
+// protected branch and refusing pushes that would need --force.
+// *git.Runner satisfies it. When Loop.Git does not implement BranchOps the
+// force-push check is skipped and on_protected = "branch" falls back to
+// refusing.
+type BranchOps interface {
+	CreateBranch(name string) error
+	NeedsForcePush(branch string) bool
+}
+
+// Push guard states shown in the TUI header via LogEntry.PushGuard.
+const (
+	PushGuardDryRun  = "dry-run"
+	PushGuardRefused = "refused (protected)"
+)
+
+// guardBranch enforces [git] protected_branches before the first iteration.
+// A protected branch is only a problem when the loop would push or
+// pull-rebase on it. Returns the branch the loop should run on: branch itself,
+// or a generated feature branch when on_protected = "branch".
+func (l *Loop) guardBranch(mode Mode, branch string) (string, error) {
+	g := l.Config.Git
+	pushes := g.AutoPush && !l.DryRunPush
+	if (!pushes && !g.AutoPullRebase) || !g.IsProtected(branch) {
+		return branch, nil
+	}
+
+	if g.OnProtected == "branch" {
+		if bo, ok := l.Git.(BranchOps); ok {
+			name := fmt.Sprintf("ralph/%s-%s", mode, time.Now().Format("20060102-150405"))
+			if err := bo.CreateBranch(name); err != nil {
+				return "", fmt.Errorf("loop: leave protected branch %s: %w", branch, err)
+			}
+			l.emit(LogEntry{
+				Kind:      LogInfo,
+				Message:   fmt.Sprintf("Branch %s is protected — switched to %s", branch, name),
+				Branch:    name,
+				PushGuard: "switched from " + branch,
+			})
+			return name, nil
+		}
+	}
+
+	l.emit(LogEntry{
+		Kind: LogError,
+		Message: fmt.Sprintf("Refusing to start on protected branch %s (git.protected_branches): "+
+			"switch to a feature branch, set git.on_protected = \"branch\", or disable auto_push and auto_pull_rebase", branch),
+		Branch:    branch,
+		PushGuard: PushGuardRefused,
+	})
+	return "", fmt.Errorf("loop: %s: %w", branch, ErrProtectedBranch)
+}
+
+// logDryRunPush reports what pushIfNeeded would have pushed without
+// contacting the remote (--dry-run-push).
+func (l *Loop) logDryRunPush(branch string) {
+	co, ok := l.Git.(CommitOps)
+	if !ok || !l.Git.HasRemoteBranch(branch) {
+		commit, _ := l.Git.LastCommit()
+		l.emit(LogEntry{
+			Kind:      LogGitPush,
+			Message:   fmt.Sprintf("Dry run: would push %s to origin (last commit: %s)", branch, commit),
+			Branch:    branch,
+			PushGuard: PushGuardDryRun,
+		})
+		return
+	}
+	commits, err := co.CommitsBetween("origin/"+branch, "HEAD")
+	if err != nil {
+		l.emit(LogEntry{
+			Kind:    LogInfo,
+			Message: fmt.Sprintf("Dry run: cannot list unpushed commits: %v", err),
+		})
+		return
+	}
+	l.emit(LogEntry{
+		Kind:      LogGitPush,
+		Message:   fmt.Sprintf("Dry run: would push %d commit(s) to origin/%s", len(commits), branch),
+		Branch:    branch,
+		PushGuard: PushGuardDryRun,
+	})
+	for _, c := range commits {
+		l.emit(LogEntry{
+			Kind:    LogInfo,
+			Message: fmt.Sprintf("  %s %s", shortSHA(c.SHA), c.Subject()),
+		})
+	}
+}
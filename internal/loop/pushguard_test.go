@@ -0,0 +1,195 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+// initRemoteRepo creates a repo on branch with one commit pushed to a bare
+// origin and returns the working directory.
+func initRemoteRepo(t *testing.T, branch string) string {
+	t.Helper()
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "--bare")
+	dir := t.TempDir()
+	gitRun(t, dir, "init")
+	gitRun(t, dir, "checkout", "-b", branch)
+	gitRun(t, dir, "config", "user.email", "test@test.com")
+	gitRun(t, dir, "config", "user.name", "Test")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "chore: init")
+	gitRun(t, dir, "remote", "add", "origin", remote)
+	gitRun(t, dir, "push", "-u", "origin", branch)
+	return dir
+}
+
+func newGuardLoop(t *testing.T, dir string, agent claude.Agent) (*Loop, chan LogEntry) {
+	t.Helper()
+	cfg := defaultTestConfig()
+	cfg.Git.ProtectedBranches = []string{"main", "release/*"}
+	cfg.Build.MaxIterations = 1
+	if err := os.WriteFile(filepath.Join(dir, cfg.Build.PromptFile), []byte("build prompt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", cfg.Build.PromptFile)
+	gitRun(t, dir, "commit", "-m", "chore: prompt")
+	events := make(chan LogEntry, 64)
+	return &Loop{
+		Agent:  agent,
+		Git:    git.NewRunner(dir),
+		Config: cfg,
+		Dir:    dir,
+		Events: events,
+	}, events
+}
+
+func drain(ch chan LogEntry) []LogEntry {
+	close(ch)
+	var entries []LogEntry
+	for e := range ch {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestGuardBranch_RefusesProtected(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.1, 1, "success")}}
+	g := &mockGit{branch: "release/2.0"}
+	cfg := defaultTestConfig()
+	cfg.Git.ProtectedBranches = []string{"main", "release/*"}
+	lp, _ := setupTestLoop(t, agent, g, cfg)
+	events := make(chan LogEntry, 8)
+	lp.Events = events
+
+	err := lp.Run(context.Background(), ModeBuild, 1)
+	if !errors.Is(err, ErrProtectedBranch) {
+		t.Fatalf("expected ErrProtectedBranch, got %v", err)
+	}
+	if agent.calls != 0 || g.pullCalls != 0 || g.pushCalls != 0 {
+		t.Errorf("nothing should run on a protected branch: agent=%d pull=%d push=%d", agent.calls, g.pullCalls, g.pushCalls)
+	}
+	entries := drain(events)
+	if len(entries) != 1 || entries[0].Kind != LogError || entries[0].PushGuard != PushGuardRefused {
+		t.Errorf("expected one refusal entry, got %+v", entries)
+	}
+}
+
+func TestGuardBranch_AllowsWhenGitAutomationOff(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.1, 1, "success")}}
+	g := &mockGit{branch: "main"}
+	cfg := defaultTestConfig()
+	cfg.Git.ProtectedBranches = []string{"main"}
+	cfg.Git.AutoPush = false
+	cfg.Git.AutoPullRebase = false
+	lp, _ := setupTestLoop(t, agent, g, cfg)
+
+	if err := lp.Run(context.Background(), ModeBuild, 1); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if agent.calls != 1 {
+		t.Errorf("expected the loop to run, got %d agent calls", agent.calls)
+	}
+}
+
+func TestGuardBranch_SwitchesToFeatureBranch(t *testing.T) {
+	dir := initRemoteRepo(t, "main")
+	agent := &committingAgent{commit: func(int) {
+		gitRun(t, dir, "commit", "--allow-empty", "-m", "feat: work")
+	}}
+	lp, events := newGuardLoop(t, dir, agent)
+	lp.Config.Git.OnProtected = "branch"
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	branch := gitRun(t, dir, "branch", "--show-current")
+	if !strings.HasPrefix(branch, "ralph/build-") {
+		t.Fatalf("expected a generated ralph/build-* branch, got %q", branch)
+	}
+	if got := gitRun(t, dir, "rev-parse", "origin/"+branch); got != gitRun(t, dir, "rev-parse", "HEAD") {
+		t.Error("work should be pushed to the generated branch")
+	}
+	if gitRun(t, dir, "rev-parse", "origin/main") == gitRun(t, dir, "rev-parse", "HEAD") {
+		t.Error("protected branch must not be pushed")
+	}
+	var switched bool
+	for _, e := range drain(events) {
+		if strings.HasPrefix(e.PushGuard, "switched from main") {
+			switched = true
+		}
+	}
+	if !switched {
+		t.Error("expected a switched-branch entry for the TUI header")
+	}
+}
+
+func TestDryRunPush(t *testing.T) {
+	dir := initRemoteRepo(t, "main")
+	agent := &committingAgent{commit: func(int) {
+		gitRun(t, dir, "commit", "--allow-empty", "-m", "feat: work")
+	}}
+	lp, events := newGuardLoop(t, dir, agent)
+	lp.Config.Git.AutoPullRebase = false
+	lp.DryRunPush = true
+
+	// Dry-run push makes the protected branch safe: nothing reaches origin.
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := gitRun(t, dir, "rev-list", "--count", "origin/main..HEAD"); got != "2" {
+		t.Errorf("expected 2 unpushed commits, got %s", got)
+	}
+	var msgs []string
+	var guard string
+	for _, e := range drain(events) {
+		msgs = append(msgs, e.Message)
+		if e.PushGuard != "" {
+			guard = e.PushGuard
+		}
+	}
+	out := strings.Join(msgs, "\n")
+	for _, want := range []string{"Dry run: would push 2 commit(s) to origin/main", "feat: work", "chore: prompt"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+	if guard != PushGuardDryRun {
+		t.Errorf("PushGuard = %q, want %q", guard, PushGuardDryRun)
+	}
+}
+
+func TestPushRefusesForcePush(t *testing.T) {
+	dir := initRemoteRepo(t, "feat")
+	agent := &committingAgent{commit: func(int) {
+		if err := os.WriteFile(filepath.Join(dir, "x.txt"), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", "x.txt")
+		gitRun(t, dir, "commit", "--amend", "-m", "feat: rewritten")
+	}}
+	lp, events := newGuardLoop(t, dir, agent)
+	lp.Config.Git.AutoPullRebase = false
+	gitRun(t, dir, "push", "origin", "feat") // include the prompt commit
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if gitRun(t, dir, "log", "-1", "--format=%s", "origin/feat") != "chore: prompt" {
+		t.Error("remote history must not be rewritten")
+	}
+	var refused bool
+	for _, e := range drain(events) {
+		if e.Kind == LogError && strings.Contains(e.Message, "never force-pushes") {
+			refused = true
+		}
+	}
+	if !refused {
+		t.Error("expected a force-push refusal error")
+	}
+}
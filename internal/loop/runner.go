@@ -5,10 +5,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/sandbox"
 )
 
 // ClaudeAgent implements claude.Agent by spawning the Claude CLI as a subprocess.
@@ -16,13 +20,27 @@ import (
 type ClaudeAgent struct {
 	// Executable is the path to the Claude CLI binary. Defaults to "claude".
 	Executable string
+
+	// Sandbox, when set, runs the CLI inside a bwrap jail or container with
+	// only the working directory writable.
+	Sandbox *sandbox.Sandbox
 }
 
+// containerStopDelay is how long a cancelled container run gets to stop
+// before its client process is killed.
+const containerStopDelay = 10 * time.Second
+
 // NewClaudeAgent creates a ClaudeAgent that uses the default "claude" binary.
 func NewClaudeAgent() *ClaudeAgent {
 	return &ClaudeAgent{Executable: "claude"}
 }
 
+// NewClaudeAgentFor creates a ClaudeAgent that runs inside the sandbox
+// configured in cfg's [sandbox] section, if enabled.
+func NewClaudeAgentFor(cfg *config.Config) *ClaudeAgent {
+	return &ClaudeAgent{Executable: "claude", Sandbox: sandbox.New(cfg.Sandbox)}
+}
+
 // Run spawns the Claude CLI with the given prompt and streams parsed events back
 // on the returned channel. The channel is closed when the process exits.
 func (a *ClaudeAgent) Run(ctx context.Context, prompt string, opts claude.RunOptions) (<-chan claude.Event, error) {
@@ -33,11 +51,32 @@ func (a *ClaudeAgent) Run(ctx context.Context, prompt string, opts claude.RunOpt
 		exe = "claude"
 	}
 
+	if a.Sandbox != nil {
+		argv, err := a.Sandbox.Wrap(opts.Dir, append([]string{exe}, args...))
+		if err != nil {
+			return nil, fmt.Errorf("claude agent: %w", err)
+		}
+		exe, args = argv[0], argv[1:]
+	}
+
 	cmd := exec.CommandContext(ctx, exe, args...)
 	if opts.Dir != "" {
 		cmd.Dir = opts.Dir
 	}
 	isolateProcess(cmd)
+	if a.Sandbox != nil && a.Sandbox.Container() {
+		// Killing the engine's client would leave the container running;
+		// interrupt it so it stops the container, then kill it if it hangs.
+		// Where interrupting is not possible (os.Interrupt is unsupported on
+		// Windows), kill it straight away.
+		cmd.Cancel = func() error {
+			if err := cmd.Process.Signal(os.Interrupt); err != nil {
+				return cmd.Process.Kill()
+			}
+			return nil
+		}
+		cmd.WaitDelay = containerStopDelay
+	}
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("claude agent: stdout pipe: %w", err)
@@ -50,7 +89,8 @@ func (a *ClaudeAgent) Run(ctx context.Context, prompt string, opts claude.RunOpt
 		return nil, fmt.Errorf("claude agent: start: %w", err)
 	}
 
-	parsed := claude.ParseStream(stdout)
+	parser := &claude.StreamParser{Transcript: opts.Transcript}
+	parsed := parser.Parse(stdout)
 
 	ch := make(chan claude.Event, 64)
 	go func() {
@@ -58,6 +98,9 @@ func (a *ClaudeAgent) Run(ctx context.Context, prompt string, opts claude.RunOpt
 		for ev := range parsed {
 			ch <- ev
 		}
+		if stats := parser.Stats(); stats.Skipped() > 0 {
+			ch <- claude.StreamStatsEvent(stats)
+		}
 		if err := cmd.Wait(); err != nil {
 			// Context cancellation produces a non-zero exit — that's expected
 			if ctx.Err() == nil {
@@ -65,7 +108,7 @@ func (a *ClaudeAgent) Run(ctx context.Context, prompt string, opts claude.RunOpt
 				if detail := strings.TrimSpace(stderrBuf.String()); detail != "" {
 					msg = fmt.Sprintf("claude exited: %v: %s", err, detail)
 				}
-				ch <- claude.ErrorEvent(msg)
+				ch <- claude.ClassifyError(msg)
 			}
 		}
 	}()
@@ -89,5 +132,14 @@ func (a *ClaudeAgent) buildArgs(prompt string, opts claude.RunOptions) []string
 	if opts.DangerSkipPermissions {
 		args = append(args, "--dangerously-skip-permissions")
 	}
+	if len(opts.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(opts.AllowedTools, ","))
+	}
+	if len(opts.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(opts.DisallowedTools, ","))
+	}
+	if opts.Resume != "" {
+		args = append(args, "--resume", opts.Resume)
+	}
 	return args
 }
@@ -2,6 +2,7 @@ package loop
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,8 @@ import (
 	"time"
 
 	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/sandbox"
 )
 
 // init runs as a fake Claude subprocess when _FAKE_CLAUDE=1 is set.
@@ -45,6 +48,28 @@ func TestNewClaudeAgent(t *testing.T) {
 	}
 }
 
+func TestNewClaudeAgentFor(t *testing.T) {
+	cfg := config.Defaults()
+	if agent := NewClaudeAgentFor(&cfg); agent.Sandbox != nil {
+		t.Error("sandbox should be nil when [sandbox] is disabled")
+	}
+	cfg.Sandbox.Enabled = true
+	if agent := NewClaudeAgentFor(&cfg); agent.Sandbox == nil || agent.Executable != "claude" {
+		t.Errorf("NewClaudeAgentFor() = %+v, want a sandboxed claude agent", agent)
+	}
+}
+
+func TestClaudeAgentRun_SandboxRuntimeMissing(t *testing.T) {
+	agent := &ClaudeAgent{
+		Executable: "claude",
+		Sandbox:    sandbox.New(config.SandboxConfig{Enabled: true, Runtime: "ralph-no-such-runtime", Network: "full"}),
+	}
+	_, err := agent.Run(context.Background(), "test", claude.RunOptions{Dir: t.TempDir()})
+	if !errors.Is(err, sandbox.ErrRuntimeNotFound) {
+		t.Fatalf("Run() = %v, want sandbox.ErrRuntimeNotFound", err)
+	}
+}
+
 func TestBuildArgs(t *testing.T) {
 	agent := &ClaudeAgent{}
 
@@ -64,7 +89,7 @@ func TestBuildArgs(t *testing.T) {
 				"--output-format", "stream-json",
 				"--verbose",
 			},
-			excludes: []string{"--model", "--dangerously-skip-permissions", "--max-turns"},
+			excludes: []string{"--model", "--dangerously-skip-permissions", "--max-turns", "--allowedTools", "--disallowedTools", "--resume"},
 		},
 		{
 			name:   "with model",
@@ -96,6 +121,24 @@ func TestBuildArgs(t *testing.T) {
 				"--dangerously-skip-permissions",
 			},
 		},
+		{
+			name:   "with tool policy lists",
+			prompt: "test",
+			opts:   claude.RunOptions{AllowedTools: []string{"Read", "Grep"}, DisallowedTools: []string{"WebFetch"}},
+			contains: []string{
+				"--allowedTools", "Read,Grep",
+				"--disallowedTools", "WebFetch",
+			},
+		},
+		{
+			name:   "with resume",
+			prompt: "answer",
+			opts:   claude.RunOptions{Resume: "sess-1"},
+			contains: []string{
+				"-p", "answer",
+				"--resume", "sess-1",
+			},
+		},
 		{
 			name:   "all options",
 			prompt: "full test",
@@ -170,6 +213,35 @@ func TestClaudeAgentRun(t *testing.T) {
 		}
 	})
 
+	t.Run("skipped lines are reported and the transcript is copied", func(t *testing.T) {
+		output := `{"type":"assistant","message":{"content":[{"type":"text","text":"hi"}]}}
+not json
+{"type":"result","cost_usd":0.10,"duration_ms":2500}`
+		agent := setUpFakeClaude(t, exe, 0, output, "")
+
+		var transcript strings.Builder
+		ch, err := agent.Run(context.Background(), "test prompt", claude.RunOptions{Transcript: &transcript})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var events []claude.Event
+		for ev := range ch {
+			events = append(events, ev)
+		}
+
+		if len(events) != 3 {
+			t.Fatalf("expected 3 events, got %+v", events)
+		}
+		last := events[2]
+		if last.Type != claude.EventStreamStats || last.Stats.Malformed != 1 || last.Stats.Lines != 3 {
+			t.Errorf("last event = %+v, want stream stats with 1 malformed line", last)
+		}
+		if transcript.String() != output {
+			t.Errorf("transcript = %q, want the raw output", transcript.String())
+		}
+	})
+
 	t.Run("non-zero exit sends error event", func(t *testing.T) {
 		output := `{"type":"result","cost_usd":0.05,"duration_ms":1000}`
 		agent := setUpFakeClaude(t, exe, 1, output, "")
@@ -195,7 +267,7 @@ func TestClaudeAgentRun(t *testing.T) {
 	})
 
 	t.Run("non-zero exit includes stderr in error", func(t *testing.T) {
-		agent := setUpFakeClaude(t, exe, 1, "", "API rate limit exceeded")
+		agent := setUpFakeClaude(t, exe, 1, "", "API authentication failed")
 
 		ch, err := agent.Run(context.Background(), "test", claude.RunOptions{})
 		if err != nil {
@@ -214,7 +286,32 @@ func TestClaudeAgentRun(t *testing.T) {
 		if last.Type != claude.EventError {
 			t.Fatalf("last event type: expected %q, got %q", claude.EventError, last.Type)
 		}
-		if !strings.Contains(last.Error, "API rate limit exceeded") {
+		if !strings.Contains(last.Error, "API authentication failed") {
+			t.Errorf("error should contain stderr text, got: %s", last.Error)
+		}
+	})
+
+	t.Run("non-zero exit on a usage limit emits rate_limit", func(t *testing.T) {
+		agent := setUpFakeClaude(t, exe, 1, "", "API Error: 429 rate_limit_error")
+
+		ch, err := agent.Run(context.Background(), "test", claude.RunOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var events []claude.Event
+		for ev := range ch {
+			events = append(events, ev)
+		}
+
+		if len(events) == 0 {
+			t.Fatal("expected at least 1 event")
+		}
+		last := events[len(events)-1]
+		if last.Type != claude.EventRateLimit {
+			t.Fatalf("last event type: expected %q, got %q", claude.EventRateLimit, last.Type)
+		}
+		if !strings.Contains(last.Error, "API Error: 429 rate_limit_error") {
 			t.Errorf("error should contain stderr text, got: %s", last.Error)
 		}
 	})
@@ -0,0 +1,72 @@
+package loop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+// squashIteration folds the unpushed commits of iteration n (base..HEAD)
+// into one commit before pushing ([git] strategy = "squash-iteration"). The
+// generated message keeps the first commit's subject, lists the originals,
+// and carries the Ralph-* trailers so history can map it back to the
+// iteration. The original SHAs are recorded in the emitted log entry.
+func (l *Loop) squashIteration(n int, branch, base string) {
+	co, ok := l.Git.(CommitOps)
+	if !ok {
+		return
+	}
+	commits, err := co.CommitsBetween(base, "HEAD")
+	if err != nil || len(commits) < 2 {
+		return
+	}
+	for _, c := range commits {
+		if co.IsPushed(c.SHA, branch) {
+			l.emit(LogEntry{
+				Kind:    LogInfo,
+				Message: fmt.Sprintf("Squash skipped: %s is already pushed", shortSHA(c.SHA)),
+			})
+			return
+		}
+	}
+
+	subject := commits[0].Subject()
+	if l.Config.Git.CommitPolicy != "" {
+		subject = git.ConventionalSubject(subject)
+	}
+	var tasks []string
+	for _, c := range commits {
+		for _, t := range l.commitTrailers(co, c, n) {
+			if t.Key == TrailerTask && t.Value != "" {
+				tasks = append(tasks, strings.Split(t.Value, ", ")...)
+			}
+		}
+	}
+	msg := git.AddTrailers(git.SquashMessage(subject, commits), []git.Trailer{
+		{Key: TrailerSpec, Value: l.Spec},
+		{Key: TrailerTask, Value: strings.Join(dedupe(tasks), ", ")},
+		{Key: TrailerIteration, Value: fmt.Sprintf("%d", n)},
+		{Key: TrailerSession, Value: l.SessionID},
+	})
+
+	if _, err := co.Squash(base, msg); err != nil {
+		l.emit(LogEntry{
+			Kind:    LogError,
+			Message: fmt.Sprintf("Squash failed: %v", err),
+		})
+		return
+	}
+	original := make([]string, len(commits))
+	for i, c := range commits {
+		original[i] = c.SHA
+	}
+	commit, _ := l.Git.LastCommit()
+	l.emit(LogEntry{
+		Kind:      LogInfo,
+		Message:   fmt.Sprintf("Squashed %d commits into %s", len(commits), commit),
+		Iteration: n,
+		Commit:    commit,
+		Squashed:  original,
+	})
+}
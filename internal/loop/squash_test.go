@@ -0,0 +1,81 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+func TestSquashIteration(t *testing.T) {
+	dir := initPolicyRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		if err := os.WriteFile(filepath.Join(dir, "specs", "007-x", "tasks.md"), []byte("- [x] T001 one\n- [ ] T002 two\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "commit", "-am", "feat: first")
+		if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", "b.txt")
+		gitRun(t, dir, "commit", "-m", "fix: second")
+	}}
+	lp := newPolicyLoop(t, dir, "", agent)
+	lp.Config.Git.Strategy = "squash-iteration"
+	base := gitRun(t, dir, "rev-parse", "HEAD")
+	ch := make(chan LogEntry, 64)
+	lp.Events = ch
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	close(ch)
+
+	if count := gitRun(t, dir, "rev-list", "--count", base+"..HEAD"); count != "1" {
+		t.Fatalf("want 1 commit after squash, got %s", count)
+	}
+	msg := gitRun(t, dir, "log", "-1", "--format=%B")
+	if !strings.HasPrefix(msg, "feat: first\n\nSquashed commits:\n") || !strings.Contains(msg, " fix: second") {
+		t.Errorf("squash message:\n%s", msg)
+	}
+	trailers := git.ParseTrailers(msg)
+	if trailers[TrailerTask] != "T001" || trailers[TrailerIteration] != "1" || trailers[TrailerSession] != "100-1" {
+		t.Errorf("trailers: %v", trailers)
+	}
+
+	var squashed []string
+	for e := range ch {
+		if len(e.Squashed) > 0 {
+			squashed = e.Squashed
+		}
+	}
+	if len(squashed) != 2 {
+		t.Fatalf("want 2 original SHAs in log entry, got %v", squashed)
+	}
+	if out := gitRun(t, dir, "cat-file", "-t", squashed[0]); out != "commit" {
+		t.Errorf("original SHA %s should still resolve, got %q", squashed[0], out)
+	}
+}
+
+func TestSquashIteration_SingleCommitUntouched(t *testing.T) {
+	dir := initPolicyRepo(t)
+	agent := &committingAgent{commit: func(int) {
+		if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", "a.txt")
+		gitRun(t, dir, "commit", "-m", "feat: only")
+	}}
+	lp := newPolicyLoop(t, dir, "", agent)
+	lp.Config.Git.Strategy = "squash-iteration"
+
+	if err := lp.Run(context.Background(), ModeBuild, 0); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if msg := gitRun(t, dir, "log", "-1", "--format=%B"); msg != "feat: only" {
+		t.Errorf("single commit should be left alone, got %q", msg)
+	}
+}
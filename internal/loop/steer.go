@@ -0,0 +1,188 @@
+package loop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SteerNote is operator guidance queued for the next iteration's prompt.
+type SteerNote struct {
+	ID   string    `json:"id"`
+	Text string    `json:"text"`
+	At   time.Time `json:"at"`
+}
+
+// steerDir is the queue directory within the working directory.
+const steerDir = ".ralph/steering"
+
+// SteerQueue is the queue of steering notes for a working directory. Each
+// note is its own file in .ralph/steering, written with a rename, so the TUI
+// and `ralph steer` in another process can queue and remove notes while the
+// loop consumes them, without locking.
+type SteerQueue struct {
+	dir string
+}
+
+// NewSteerQueue returns the steering queue of the working directory dir.
+func NewSteerQueue(dir string) *SteerQueue {
+	return &SteerQueue{dir: filepath.Join(dir, filepath.FromSlash(steerDir))}
+}
+
+// Add queues text as a note and returns it.
+func (q *SteerQueue) Add(text string) (SteerNote, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return SteerNote{}, errors.New("loop: steering note is empty")
+	}
+	if err := os.MkdirAll(q.dir, 0o755); err != nil {
+		return SteerNote{}, fmt.Errorf("loop: create steering dir: %w", err)
+	}
+	now := time.Now()
+	note := SteerNote{ID: strconv.FormatInt(now.UnixNano(), 10), Text: text, At: now}
+	if err := q.write(note); err != nil {
+		return SteerNote{}, err
+	}
+	return note, nil
+}
+
+// Restore puts taken notes back in the queue with their original IDs and
+// times, so they keep their place ahead of notes queued since.
+func (q *SteerQueue) Restore(notes []SteerNote) error {
+	if len(notes) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(q.dir, 0o755); err != nil {
+		return fmt.Errorf("loop: create steering dir: %w", err)
+	}
+	for _, note := range notes {
+		if err := q.write(note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write stores note in its own file, via a rename.
+func (q *SteerQueue) write(note SteerNote) error {
+	data, err := json.Marshal(note)
+	if err != nil {
+		return fmt.Errorf("loop: marshal steering note: %w", err)
+	}
+	// Dot-prefixed temp files are skipped by Pending until the rename.
+	tmp, err := os.CreateTemp(q.dir, ".note-*.tmp")
+	if err != nil {
+		return fmt.Errorf("loop: write steering note: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(tmp.Name(), q.path(note.ID))
+	}
+	if writeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("loop: write steering note: %w", writeErr)
+	}
+	return nil
+}
+
+// Pending returns the queued notes, oldest first.
+func (q *SteerQueue) Pending() ([]SteerNote, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("loop: read steering queue: %w", err)
+	}
+	var notes []SteerNote
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(q.dir, name))
+		if err != nil {
+			continue // taken or removed since ReadDir
+		}
+		var note SteerNote
+		if json.Unmarshal(data, &note) != nil {
+			continue
+		}
+		notes = append(notes, note)
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].At.Before(notes[j].At) })
+	return notes, nil
+}
+
+// Remove deletes the queued note with the given ID.
+func (q *SteerQueue) Remove(id string) error {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return fmt.Errorf("loop: invalid steering note ID %q", id)
+	}
+	if err := os.Remove(q.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("loop: steering note %s is not queued", id)
+		}
+		return fmt.Errorf("loop: remove steering note: %w", err)
+	}
+	return nil
+}
+
+// Take removes and returns the queued notes, oldest first. A note removed
+// by someone else in the meantime is left out.
+func (q *SteerQueue) Take() ([]SteerNote, error) {
+	notes, err := q.Pending()
+	if err != nil {
+		return nil, err
+	}
+	taken := notes[:0]
+	for _, n := range notes {
+		if os.Remove(q.path(n.ID)) == nil {
+			taken = append(taken, n)
+		}
+	}
+	return taken, nil
+}
+
+func (q *SteerQueue) path(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
+
+// takeSteering consumes the queued steering notes, records each as a
+// LogSteer entry for iteration n, and returns the prompt section that
+// carries them, or "" when none are queued, along with the notes taken.
+func (l *Loop) takeSteering(n int) (string, []SteerNote) {
+	if l.Steering == nil {
+		return "", nil
+	}
+	notes, err := l.Steering.Take()
+	if err != nil {
+		l.emit(LogEntry{Kind: LogError, Message: fmt.Sprintf("Steering notes unavailable: %v", err)})
+		return "", nil
+	}
+	if len(notes) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	b.WriteString("## Operator Guidance\n\n")
+	b.WriteString("The operator sent these notes while watching the loop. Follow them; where they conflict with earlier instructions, they win.\n")
+	for _, note := range notes {
+		b.WriteString("\n- " + strings.ReplaceAll(note.Text, "\n", "\n  "))
+		l.emit(LogEntry{
+			Kind:      LogSteer,
+			Message:   "Operator guidance: " + note.Text,
+			Iteration: n,
+		})
+	}
+	return b.String(), notes
+}
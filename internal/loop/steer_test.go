@@ -0,0 +1,123 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+)
+
+func TestSteerQueue(t *testing.T) {
+	q := NewSteerQueue(t.TempDir())
+	if notes, err := q.Pending(); err != nil || len(notes) != 0 {
+		t.Fatalf("Pending on a new queue = %v, %v; want none", notes, err)
+	}
+	if _, err := q.Add("   "); err == nil {
+		t.Error("Add of a blank note should fail")
+	}
+
+	a, err := q.Add("skip the migration")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	b, err := q.Add("use the existing table")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	notes, err := q.Pending()
+	if err != nil || len(notes) != 2 || notes[0].ID != a.ID || notes[1].ID != b.ID {
+		t.Fatalf("Pending = %+v, %v; want both notes oldest first", notes, err)
+	}
+
+	if err := q.Remove(a.ID); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := q.Remove(a.ID); err == nil || !strings.Contains(err.Error(), "not queued") {
+		t.Errorf("second Remove = %v, want not queued", err)
+	}
+	if err := q.Remove("../x"); err == nil {
+		t.Error("Remove should reject IDs that are not plain names")
+	}
+
+	taken, err := q.Take()
+	if err != nil || len(taken) != 1 || taken[0].Text != "use the existing table" {
+		t.Fatalf("Take = %+v, %v; want the remaining note", taken, err)
+	}
+	if notes, _ := q.Pending(); len(notes) != 0 {
+		t.Errorf("Pending after Take = %+v, want none", notes)
+	}
+
+	c, _ := q.Add("newer note")
+	if err := q.Restore(taken); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	notes, err = q.Pending()
+	if err != nil || len(notes) != 2 || notes[0].ID != b.ID || notes[1].ID != c.ID {
+		t.Errorf("Pending after Restore = %+v, %v; want the restored note ahead of the newer one", notes, err)
+	}
+}
+
+func TestSteerQueue_SkipsTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	q := NewSteerQueue(dir)
+	if _, err := q.Add("note"); err != nil {
+		t.Fatal(err)
+	}
+	// A note still being written by another process.
+	if err := os.WriteFile(filepath.Join(dir, ".ralph", "steering", ".note-1.tmp"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if notes, err := q.Pending(); err != nil || len(notes) != 1 {
+		t.Errorf("Pending = %+v, %v; want only the complete note", notes, err)
+	}
+}
+
+func TestIteration_OperatorGuidance(t *testing.T) {
+	agent := &mockAgent{events: []claude.Event{claude.ResultEvent(0.1, 1, "success")}}
+	lp, _ := setupTestLoop(t, agent, &mockGit{branch: "feat"}, defaultTestConfig())
+	events := make(chan LogEntry, 32)
+	lp.Events = events
+	lp.Steering = NewSteerQueue(lp.Dir)
+	if _, err := lp.Steering.Add("skip the migration"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lp.Run(context.Background(), ModeBuild, 2); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(agent.lastPrompt, "build prompt") || strings.Contains(agent.lastPrompt, "Operator Guidance") {
+		t.Errorf("second iteration prompt should not repeat consumed guidance:\n%s", agent.lastPrompt)
+	}
+
+	var steer []LogEntry
+	for _, e := range drain(events) {
+		if e.Kind == LogSteer {
+			steer = append(steer, e)
+		}
+	}
+	if len(steer) != 1 || steer[0].Iteration != 1 || !strings.Contains(steer[0].Message, "skip the migration") {
+		t.Errorf("LogSteer entries = %+v, want one for iteration 1", steer)
+	}
+}
+
+func TestTakeSteering_PromptSection(t *testing.T) {
+	lp, _ := setupTestLoop(t, &mockAgent{}, &mockGit{}, defaultTestConfig())
+	if got, _ := lp.takeSteering(1); got != "" {
+		t.Errorf("takeSteering without a queue = %q, want empty", got)
+	}
+	lp.Steering = NewSteerQueue(lp.Dir)
+	_, _ = lp.Steering.Add("first")
+	_, _ = lp.Steering.Add("second\nline")
+	got, notes := lp.takeSteering(3)
+	if len(notes) != 2 {
+		t.Errorf("takeSteering returned %d notes, want 2", len(notes))
+	}
+	for _, want := range []string{"## Operator Guidance", "\n- first", "\n- second\n  line"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("section missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package loop
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+)
+
+// ErrPolicyViolation is returned by Run when a tool call matches a
+// [[policy.rules]] entry with action "stop-loop". The Regent treats it as
+// permanent and does not retry.
+var ErrPolicyViolation = errors.New("stopped by tool-use policy")
+
+// SubtypeToolPolicy is the iteration subtype reported when a tool call
+// matching a "stop-iteration" or "stop-loop" rule ended the iteration.
+const SubtypeToolPolicy = "error_tool_policy"
+
+// Tool-use policy actions.
+const (
+	PolicyAllow         = "allow"
+	PolicyWarn          = "warn"
+	PolicyStopIteration = "stop-iteration"
+	PolicyStopLoop      = "stop-loop"
+)
+
+// pathFields are the tool input fields holding file paths, checked by
+// outside_spec rules that do not name a field.
+var pathFields = []string{"file_path", "path", "notebook_path"}
+
+// plainToolRe matches a literal tool name that the Claude CLI's
+// --allowedTools/--disallowedTools flags accept.
+var plainToolRe = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// policyRule is a compiled [[policy.rules]] entry.
+type policyRule struct {
+	config.PolicyRule
+	label string
+	tool  *regexp.Regexp // nil = any tool
+	match *regexp.Regexp // nil = no input pattern
+}
+
+// toolPolicy checks tool calls against the configured rules.
+type toolPolicy struct {
+	rules []policyRule
+}
+
+// newToolPolicy compiles rules. Returns nil when there are none.
+func newToolPolicy(rules []config.PolicyRule) (*toolPolicy, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	p := &toolPolicy{rules: make([]policyRule, 0, len(rules))}
+	for i, r := range rules {
+		pr := policyRule{PolicyRule: r, label: fmt.Sprintf("%q", r.Name)}
+		if r.Name == "" {
+			pr.label = fmt.Sprintf("#%d", i+1)
+		}
+		var err error
+		if r.Tool != "" {
+			if pr.tool, err = regexp.Compile(`^(?:` + r.Tool + `)$`); err != nil {
+				return nil, fmt.Errorf("loop: policy rule %s: tool: %w", pr.label, err)
+			}
+		}
+		if r.Match != "" {
+			if pr.match, err = regexp.Compile(r.Match); err != nil {
+				return nil, fmt.Errorf("loop: policy rule %s: match: %w", pr.label, err)
+			}
+		}
+		p.rules = append(p.rules, pr)
+	}
+	return p, nil
+}
+
+// check returns the first rule matching a call to tool with input, run in
+// dir while specDir is the active spec directory (empty in roam mode, where
+// outside_spec rules never match).
+func (p *toolPolicy) check(tool string, input map[string]any, dir, specDir string) (policyRule, bool) {
+	if p == nil {
+		return policyRule{}, false
+	}
+	for _, r := range p.rules {
+		if r.matches(tool, input, dir, specDir) {
+			return r, true
+		}
+	}
+	return policyRule{}, false
+}
+
+func (r policyRule) matches(tool string, input map[string]any, dir, specDir string) bool {
+	if r.tool != nil && !r.tool.MatchString(tool) {
+		return false
+	}
+	if r.match != nil && !anyField(input, r.Field, nil, r.match.MatchString) {
+		return false
+	}
+	if r.OutsideSpec {
+		if specDir == "" {
+			return false
+		}
+		outside := func(v string) bool { return v != "" && !insideDir(v, dir, specDir) }
+		if !anyField(input, r.Field, pathFields, outside) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyField reports whether pred holds for the named input field, or for any
+// of the defaults when field is empty (every field when defaults is nil too).
+// Non-string values are formatted with %v.
+func anyField(input map[string]any, field string, defaults []string, pred func(string) bool) bool {
+	keys := defaults
+	switch {
+	case field != "":
+		keys = []string{field}
+	case keys == nil:
+		for k := range input {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	for _, k := range keys {
+		v, ok := input[k]
+		if !ok {
+			continue
+		}
+		s, isString := v.(string)
+		if !isString {
+			s = fmt.Sprint(v)
+		}
+		if pred(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// insideDir reports whether path (relative to base when not absolute) is
+// within dir (also relative to base when not absolute).
+func insideDir(path, base, dir string) bool {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(base, dir)
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cliTools returns the tool names the Claude CLI can enforce itself: rules
+// that name plain tools with no input conditions. Allow rules become
+// --allowedTools. A stop rule becomes --disallowedTools unless an earlier
+// allow rule could let some call to that tool through.
+func (p *toolPolicy) cliTools() (allowed, disallowed []string) {
+	if p == nil {
+		return nil, nil
+	}
+	var allows []policyRule
+	for _, r := range p.rules {
+		if r.Action == PolicyAllow {
+			allows = append(allows, r)
+		}
+		names, ok := r.plainTools()
+		if !ok {
+			continue
+		}
+		switch r.Action {
+		case PolicyAllow:
+			allowed = append(allowed, names...)
+		case PolicyStopIteration, PolicyStopLoop:
+		next:
+			for _, name := range names {
+				for _, a := range allows {
+					if a.tool == nil || a.tool.MatchString(name) {
+						continue next
+					}
+				}
+				disallowed = append(disallowed, name)
+			}
+		}
+	}
+	return allowed, disallowed
+}
+
+// plainTools returns the literal tool names of a rule with no input
+// conditions, e.g. "Write|Edit" → [Write Edit].
+func (r policyRule) plainTools() ([]string, bool) {
+	if r.Tool == "" || r.Match != "" || r.OutsideSpec {
+		return nil, false
+	}
+	names := strings.Split(r.Tool, "|")
+	for _, n := range names {
+		if !plainToolRe.MatchString(n) {
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+// applyToolPolicy checks a tool call against the policy and logs a match.
+// Returns true when the Claude invocation must be killed: "stop-iteration"
+// also queues feedback for the next prompt, and "stop-loop" sets policyStop
+// so Run ends after this iteration.
+func (l *Loop) applyToolPolicy(ev claude.Event) bool {
+	specDir := l.SpecDir
+	if l.Roam {
+		specDir = ""
+	}
+	r, ok := l.policy.check(ev.ToolName, ev.ToolInput, l.Dir, specDir)
+	if !ok || r.Action == PolicyAllow {
+		return false
+	}
+	summary := SummarizeInput(ev.ToolInput)
+	l.emit(LogEntry{
+		Kind:      LogPolicy,
+		Message:   fmt.Sprintf("Policy rule %s (%s): %s  %s", r.label, r.Action, ev.ToolName, summary),
+		ToolName:  ev.ToolName,
+		ToolInput: summary,
+	})
+	switch r.Action {
+	case PolicyStopIteration:
+		l.appendFeedback(fmt.Sprintf("## Tool-use policy\n\nThe previous iteration was stopped because a %s call (%s) "+
+			"violated the project's tool-use policy (rule %s). Do not attempt it again; find another way or leave the task for a human.",
+			ev.ToolName, summary, r.label))
+		return true
+	case PolicyStopLoop:
+		l.policyStop = fmt.Errorf("%w: rule %s matched %s %s", ErrPolicyViolation, r.label, ev.ToolName, summary)
+		return true
+	}
+	return false
+}
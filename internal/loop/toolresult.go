@@ -0,0 +1,71 @@
+package loop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+)
+
+// toolNames remembers the tool name of each call in a Claude invocation, so
+// results (which carry only the call's ID) can be labelled.
+type toolNames map[string]string
+
+// toolUseEntry returns the LogToolUse entry for ev and records its name.
+func (t toolNames) toolUseEntry(ev claude.Event) LogEntry {
+	if ev.ToolUseID != "" {
+		t[ev.ToolUseID] = ev.ToolName
+	}
+	return LogEntry{
+		Kind:      LogToolUse,
+		Message:   fmt.Sprintf("tool: %s  %s", ev.ToolName, SummarizeInput(ev.ToolInput)),
+		ToolName:  ev.ToolName,
+		ToolInput: SummarizeInput(ev.ToolInput),
+		ToolUseID: ev.ToolUseID,
+	}
+}
+
+// resultEntry returns the LogToolResult entry for ev. Message is a one-line
+// summary; the output itself is in Content.
+func (t toolNames) resultEntry(ev claude.Event) LogEntry {
+	name := t[ev.ToolUseID]
+	status := "ok"
+	if ev.IsError {
+		status = "error"
+	}
+	msg := fmt.Sprintf("result: %s  %s", status, lineCount(ev.Text))
+	if name != "" {
+		msg = fmt.Sprintf("result: %s  %s  %s", name, status, lineCount(ev.Text))
+	}
+	return LogEntry{
+		Kind:      LogToolResult,
+		Message:   msg,
+		ToolName:  name,
+		ToolUseID: ev.ToolUseID,
+		Content:   ev.Text,
+		IsError:   ev.IsError,
+	}
+}
+
+// thinkingEntry returns the LogThinking entry for ev.
+func thinkingEntry(ev claude.Event) LogEntry {
+	first, _, _ := strings.Cut(strings.TrimSpace(ev.Text), "\n")
+	return LogEntry{
+		Kind:      LogThinking,
+		Message:   "thinking: " + first,
+		ToolUseID: ev.ToolUseID,
+		Content:   ev.Text,
+	}
+}
+
+// lineCount describes the length of s, e.g. "(3 lines)".
+func lineCount(s string) string {
+	n := strings.Count(strings.TrimRight(s, "\n"), "\n") + 1
+	if strings.TrimSpace(s) == "" {
+		return "(empty)"
+	}
+	if n == 1 {
+		return "(1 line)"
+	}
+	return fmt.Sprintf("(%d lines)", n)
+}
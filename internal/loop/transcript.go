@@ -0,0 +1,54 @@
+package loop
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// transcriptDir is where raw Claude output is saved, per session, when
+// [claude] transcripts is on.
+const transcriptDir = ".ralph/transcripts"
+
+// transcriptPath returns the transcript file of the given attempt at
+// iteration n of session in the working directory dir: "<n>.jsonl" for the
+// first attempt and "<n>-<attempt>.jsonl" for retries of the same iteration,
+// such as after a usage limit.
+func transcriptPath(dir, session string, n, attempt int) string {
+	name := fmt.Sprintf("%d.jsonl", n)
+	if attempt > 1 {
+		name = fmt.Sprintf("%d-%d.jsonl", n, attempt)
+	}
+	return filepath.Join(dir, filepath.FromSlash(transcriptDir), session, name)
+}
+
+// openTranscript creates the transcript file for iteration n when
+// transcripts are enabled and the loop has a session. An existing transcript
+// of the iteration is kept and the next attempt's file is created instead.
+// It returns a nil writer otherwise, or when the file cannot be created
+// (which is logged but does not stop the iteration), and a func that closes
+// the file.
+func (l *Loop) openTranscript(n int) (io.Writer, func()) {
+	if !l.Config.Claude.Transcripts || l.SessionID == "" {
+		return nil, func() {}
+	}
+	dir := filepath.Dir(transcriptPath(l.Dir, l.SessionID, n, 1))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		l.emit(LogEntry{Kind: LogInfo, Message: fmt.Sprintf("Transcript not saved: %v", err)})
+		return nil, func() {}
+	}
+	for attempt := 1; ; attempt++ {
+		f, err := os.OpenFile(transcriptPath(l.Dir, l.SessionID, n, attempt), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			l.emit(LogEntry{Kind: LogInfo, Message: fmt.Sprintf("Transcript not saved: %v", err)})
+			return nil, func() {}
+		}
+		return f, func() { _ = f.Close() }
+	}
+}
@@ -0,0 +1,92 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+)
+
+// transcriptAgent writes a raw line to the run's transcript, as ClaudeAgent
+// does with the CLI's output, then reports a skipped line.
+type transcriptAgent struct{ runs int }
+
+func (a *transcriptAgent) Run(_ context.Context, _ string, opts claude.RunOptions) (<-chan claude.Event, error) {
+	a.runs++
+	if opts.Transcript != nil {
+		_, _ = fmt.Fprintf(opts.Transcript, "{\"run\":%d}\n", a.runs)
+	}
+	ch := make(chan claude.Event, 2)
+	ch <- claude.ResultEvent(0.01, 1, "success")
+	ch <- claude.StreamStatsEvent(claude.StreamStats{Lines: 4, Unknown: 1})
+	close(ch)
+	return ch, nil
+}
+
+func TestTranscripts(t *testing.T) {
+	git := &mockGit{branch: "main", lastCommit: "abc test"}
+	cfg := defaultTestConfig()
+	cfg.Plan.MaxIterations = 2
+	cfg.Claude.Transcripts = true
+
+	agent := &transcriptAgent{}
+	lp, buf := setupTestLoop(t, agent, git, cfg)
+	lp.SessionID = "20260102-030405"
+	if err := lp.Run(context.Background(), ModePlan, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for n := 1; n <= 2; n++ {
+		data, err := os.ReadFile(filepath.Join(lp.Dir, ".ralph", "transcripts", lp.SessionID, fmt.Sprintf("%d.jsonl", n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("{\"run\":%d}\n", n); string(data) != want {
+			t.Errorf("transcript %d = %q, want %q", n, data, want)
+		}
+	}
+	if !strings.Contains(buf.String(), "Claude output: 1 of 4 lines skipped (1 unknown)") {
+		t.Errorf("skipped lines not logged:\n%s", buf.String())
+	}
+}
+
+func TestTranscripts_RetryKeepsFirstAttempt(t *testing.T) {
+	cfg := defaultTestConfig()
+	cfg.Claude.Transcripts = true
+	lp, _ := setupTestLoop(t, &transcriptAgent{}, &mockGit{}, cfg)
+	lp.SessionID = "20260102-030405"
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		w, closeFn := lp.openTranscript(3)
+		if w == nil {
+			t.Fatalf("attempt %d: no transcript writer", attempt)
+		}
+		_, _ = fmt.Fprintf(w, "attempt %d\n", attempt)
+		closeFn()
+	}
+	for attempt, name := range map[int]string{1: "3.jsonl", 2: "3-2.jsonl"} {
+		data, err := os.ReadFile(filepath.Join(lp.Dir, ".ralph", "transcripts", lp.SessionID, name))
+		if want := fmt.Sprintf("attempt %d\n", attempt); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+}
+
+func TestTranscripts_Off(t *testing.T) {
+	git := &mockGit{branch: "main", lastCommit: "abc test"}
+	cfg := defaultTestConfig()
+	cfg.Plan.MaxIterations = 1
+
+	lp, _ := setupTestLoop(t, &transcriptAgent{}, git, cfg)
+	lp.SessionID = "20260102-030405"
+	if err := lp.Run(context.Background(), ModePlan, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(lp.Dir, ".ralph", "transcripts")); !os.IsNotExist(err) {
+		t.Errorf("transcripts dir created with transcripts off: %v", err)
+	}
+}
@@ -45,7 +45,7 @@ func (n *Notifier) Hook(entry loop.LogEntry) {
 		if n.onComplete {
 			go n.post(entry.Message)
 		}
-	case loop.LogError:
+	case loop.LogError, loop.LogRateLimit, loop.LogPolicy:
 		if n.onError {
 			go n.post(entry.Message)
 		}
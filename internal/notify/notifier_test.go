@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -110,6 +111,30 @@ func TestHook_OnError(t *testing.T) {
 	}
 }
 
+func TestHook_OnError_RateLimit(t *testing.T) {
+	srv, collect := captureServer(t)
+
+	n := New(srv.URL, "", false, true, false)
+	n.Hook(loop.LogEntry{Kind: loop.LogRateLimit, Message: "Usage limit reached — resuming at 03:00"})
+
+	reqs := waitForRequests(t, collect, 1)
+	if reqs[0].body != "Usage limit reached — resuming at 03:00" {
+		t.Errorf("body = %q", reqs[0].body)
+	}
+}
+
+func TestHook_OnError_Policy(t *testing.T) {
+	srv, collect := captureServer(t)
+
+	n := New(srv.URL, "", false, true, false)
+	n.Hook(loop.LogEntry{Kind: loop.LogPolicy, Message: `Policy rule "force push" (stop-loop): Bash  git push --force`})
+
+	reqs := waitForRequests(t, collect, 1)
+	if !strings.Contains(reqs[0].body, "force push") {
+		t.Errorf("body = %q", reqs[0].body)
+	}
+}
+
 func TestHook_OnError_Disabled(t *testing.T) {
 	srv, collect := captureServer(t)
 
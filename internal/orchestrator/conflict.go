@@ -0,0 +1,356 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/regent"
+)
+
+// ErrResolvingConflict is returned (wrapped) by Merge when the merge failed
+// and [worktree] on_conflict = "agent" handed the branch to a resolution
+// agent. The outcome arrives later on MergedEvents; the agent ends in
+// StateMerged or StateMergeFailed.
+var ErrResolvingConflict = errors.New("merge conflict handed to resolution agent")
+
+// errResolveStopped ends conflict resolution cancelled by StopAll.
+var errResolveStopped = errors.New("stopped before the conflict was resolved")
+
+const (
+	maxSpecContext    = 4000 // bytes of each spec.md and tasks.md included in the prompt
+	maxTargetCommits  = 20   // target-side commits listed in the prompt
+	maxTargetSpecs    = 3    // target-side specs whose files are included
+	maxFeedbackOutput = 4000 // bytes of test/merge output carried into the next attempt
+)
+
+// resolveConflict runs the on_conflict = "agent" policy for a failed merge:
+// rebase the branch onto the target, let Claude resolve any conflicts in the
+// worktree, re-run the Regent test gate, and retry the merge. Attempts and
+// spend are capped by conflict_max_attempts and conflict_max_cost. Every step
+// is emitted to MergedEvents tagged with branch. Cancelling ctx (see StopAll)
+// stops the Claude session and gives up. Runs in its own goroutine.
+func (o *Orchestrator) resolveConflict(ctx context.Context, agent *WorktreeAgent, branch string, mergeErr error) {
+	defer o.resolveWg.Done()
+	defer func() {
+		o.mu.Lock()
+		cancel := agent.cancelResolve
+		agent.cancelResolve = nil
+		o.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+	}()
+
+	wc := o.cfg.Worktree
+	gr := git.NewRunner(agent.WorktreePath)
+	lastErr := mergeErr
+	feedback := ""
+	var spent float64
+
+	o.emitToMerged(branch, loop.LogEntry{
+		Kind:    loop.LogInfo,
+		Message: fmt.Sprintf("worktree %s: merge failed — handing conflict to agent: %v", branch, mergeErr),
+		Branch:  branch,
+	})
+
+	target := o.MergeTarget
+	if target == "" {
+		var err error
+		if target, err = gr.DefaultBranch(); err != nil {
+			o.finishResolve(agent, branch, fmt.Errorf("resolve merge target: %w", err))
+			return
+		}
+	}
+	commits, _ := gr.CommitsBetween(branch, target)
+	targetContext := targetContext(commits)
+	targetSpecs := targetSpecs(gr, target, agent.SpecName, commits)
+
+	for attempt := 1; attempt <= wc.ConflictMaxAttempts; attempt++ {
+		if ctx.Err() != nil {
+			lastErr = errResolveStopped
+			break
+		}
+		if wc.ConflictMaxCost > 0 && spent >= wc.ConflictMaxCost {
+			lastErr = fmt.Errorf("conflict budget $%.2f exhausted ($%.2f spent): %w", wc.ConflictMaxCost, spent, lastErr)
+			break
+		}
+
+		o.emitToMerged(branch, loop.LogEntry{
+			Kind:    loop.LogInfo,
+			Message: fmt.Sprintf("worktree %s: conflict attempt %d/%d — rebasing onto %s", branch, attempt, wc.ConflictMaxAttempts, target),
+			Branch:  branch,
+		})
+		conflicts, err := gr.Rebase(target)
+		if err != nil {
+			lastErr = err
+			break
+		}
+
+		if len(conflicts) > 0 || feedback != "" {
+			if len(conflicts) > 0 {
+				o.emitToMerged(branch, loop.LogEntry{
+					Kind:    loop.LogInfo,
+					Message: fmt.Sprintf("worktree %s: conflicts in %s", branch, strings.Join(conflicts, ", ")),
+					Branch:  branch,
+				})
+			}
+			prompt := conflictPrompt(branch, target, conflicts, o.specContext(agent), targetContext, targetSpecs, feedback)
+			cost, runErr := o.runResolver(ctx, agent, branch, prompt)
+			spent += cost
+			o.mu.Lock()
+			agent.TotalCost += cost
+			o.mu.Unlock()
+			if runErr == nil && ctx.Err() != nil {
+				runErr = errResolveStopped
+			}
+			if runErr != nil {
+				if gr.RebaseInProgress() {
+					_ = gr.RebaseAbort()
+				}
+				lastErr = runErr
+				break
+			}
+			if gr.RebaseInProgress() {
+				_ = gr.RebaseAbort()
+				lastErr = errors.New("agent left the rebase unfinished")
+				feedback = "The previous attempt left the rebase unfinished. Resolve every conflict, stage the files, and run `git rebase --continue` until the rebase completes."
+				o.emitToMerged(branch, loop.LogEntry{
+					Kind:    loop.LogError,
+					Message: fmt.Sprintf("worktree %s: %v — rebase aborted", branch, lastErr),
+					Branch:  branch,
+				})
+				continue
+			}
+		}
+
+		if tc := o.cfg.Regent.TestCommand; tc != "" {
+			res, testErr := regent.RunTests(agent.WorktreePath, tc)
+			if testErr != nil {
+				lastErr = testErr
+				break
+			}
+			if !res.Passed {
+				lastErr = errors.New("tests failed after conflict resolution")
+				feedback = "The branch was rebased but the test command failed. Fix the failures and commit:\n\n" + truncate(res.Output, maxFeedbackOutput)
+				o.emitToMerged(branch, loop.LogEntry{
+					Kind:    loop.LogInfo,
+					Message: fmt.Sprintf("worktree %s: tests failed after conflict resolution\n%s", branch, res.Output),
+					Branch:  branch,
+				})
+				continue
+			}
+		}
+
+		if err := o.WorktreeOps.Merge(branch, o.MergeTarget); err != nil {
+			lastErr = err
+			feedback = "The merge still failed after the rebase:\n\n" + truncate(err.Error(), maxFeedbackOutput)
+			o.emitToMerged(branch, loop.LogEntry{
+				Kind:    loop.LogError,
+				Message: fmt.Sprintf("worktree %s: merge retry failed: %v", branch, err),
+				Branch:  branch,
+			})
+			continue
+		}
+
+		o.mu.Lock()
+		agent.State = StateMerged
+		agent.Error = nil
+		o.mu.Unlock()
+		o.save()
+		o.emitToMerged(branch, loop.LogEntry{
+			Kind:      loop.LogInfo,
+			Message:   fmt.Sprintf("worktree %s: conflict resolved and merged (attempt %d, $%.2f)", branch, attempt, spent),
+			Branch:    branch,
+			TotalCost: spent,
+		})
+		return
+	}
+
+	o.finishResolve(agent, branch, lastErr)
+}
+
+// finishResolve leaves agent in StateMergeFailed after conflict resolution
+// gave up, and reports why.
+func (o *Orchestrator) finishResolve(agent *WorktreeAgent, branch string, err error) {
+	o.mu.Lock()
+	agent.State = StateMergeFailed
+	agent.Error = err
+	o.mu.Unlock()
+	o.save()
+	o.emitToMerged(branch, loop.LogEntry{
+		Kind:    loop.LogError,
+		Message: fmt.Sprintf("worktree %s: conflict resolution gave up: %v", branch, err),
+		Branch:  branch,
+	})
+}
+
+// runResolver runs one Claude session in the agent's worktree, streaming its
+// events to MergedEvents, and returns the session cost.
+func (o *Orchestrator) runResolver(ctx context.Context, agent *WorktreeAgent, branch, prompt string) (float64, error) {
+	events, err := o.governed(branch).Run(ctx, prompt, claude.RunOptions{
+		Model:                 o.cfg.Claude.Model,
+		MaxTurns:              o.cfg.Claude.MaxTurns,
+		DangerSkipPermissions: o.cfg.Claude.DangerSkipPermissions,
+		Dir:                   agent.WorktreePath,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("start claude: %w", err)
+	}
+
+	var cost float64
+	for ev := range events {
+		switch ev.Type {
+		case claude.EventToolUse:
+			o.emitToMerged(branch, loop.LogEntry{
+				Kind:      loop.LogToolUse,
+				Message:   fmt.Sprintf("tool: %s  %s", ev.ToolName, loop.SummarizeInput(ev.ToolInput)),
+				ToolName:  ev.ToolName,
+				ToolInput: loop.SummarizeInput(ev.ToolInput),
+				Branch:    branch,
+			})
+		case claude.EventText:
+			if ev.Text != "" {
+				o.emitToMerged(branch, loop.LogEntry{Kind: loop.LogText, Message: ev.Text, Branch: branch})
+			}
+		case claude.EventResult:
+			cost += ev.CostUSD
+			o.emitToMerged(branch, loop.LogEntry{
+				Kind:     loop.LogInfo,
+				Message:  fmt.Sprintf("worktree %s: resolution session complete — $%.2f — %.1fs", branch, ev.CostUSD, ev.Duration),
+				Branch:   branch,
+				CostUSD:  ev.CostUSD,
+				Duration: ev.Duration,
+				Subtype:  ev.Subtype,
+			})
+		case claude.EventError:
+			o.emitToMerged(branch, loop.LogEntry{Kind: loop.LogError, Message: fmt.Sprintf("Error: %s", ev.Error), Branch: branch})
+		}
+	}
+	return cost, nil
+}
+
+// specContext returns the branch's spec.md and tasks.md (truncated), or ""
+// when the agent has no spec directory.
+func (o *Orchestrator) specContext(agent *WorktreeAgent) string {
+	if agent.SpecDir == "" {
+		return ""
+	}
+	dir := agent.SpecDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(agent.WorktreePath, dir)
+	}
+	return specExcerpt("", func(name string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		return string(data), err
+	})
+}
+
+// targetContext summarises what landed on target since branch forked: one
+// line per commit with its Ralph-Spec trailer, so the agent knows which
+// spec's intent it is merging against.
+func targetContext(commits []git.Commit) string {
+	if len(commits) > maxTargetCommits {
+		commits = commits[len(commits)-maxTargetCommits:]
+	}
+	var b strings.Builder
+	for _, c := range commits {
+		fmt.Fprintf(&b, "- %s %s", shortSHA(c.SHA), c.Subject())
+		if spec := git.ParseTrailers(c.Message)[loop.TrailerSpec]; spec != "" {
+			fmt.Fprintf(&b, " (spec: %s)", spec)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// targetSpecs returns the spec.md and tasks.md of the specs named in the
+// Ralph-Spec trailers of commits, as they are on target, most recent first.
+// The branch's own spec is skipped; its context comes from specContext.
+func targetSpecs(gr *git.Runner, target, ownSpec string, commits []git.Commit) string {
+	seen := map[string]bool{ownSpec: true}
+	var b strings.Builder
+	for i := len(commits) - 1; i >= 0 && len(seen) <= maxTargetSpecs; i-- {
+		name := git.ParseTrailers(commits[i].Message)[loop.TrailerSpec]
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		b.WriteString(specExcerpt(name+"/", func(file string) (string, error) {
+			return gr.FileAt(target, "specs/"+name+"/"+file)
+		}))
+	}
+	return b.String()
+}
+
+// specExcerpt formats a spec's spec.md and tasks.md, each truncated under a
+// heading of prefix plus the file name, using read to load them. Missing
+// files are skipped.
+func specExcerpt(prefix string, read func(name string) (string, error)) string {
+	var b strings.Builder
+	for _, name := range []string{"spec.md", "tasks.md"} {
+		data, err := read(name)
+		if err != nil || strings.TrimSpace(data) == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s%s\n\n%s\n\n", prefix, name, strings.TrimSpace(truncate(data, maxSpecContext)))
+	}
+	return b.String()
+}
+
+// conflictPrompt builds the instructions for a resolution session.
+func conflictPrompt(branch, target string, conflicts []string, specContext, targetContext, targetSpecs, feedback string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are resolving a failed merge of branch %s into %s. ", branch, target)
+	b.WriteString("Preserve the intent of both sides; do not drop either side's changes to make the conflict go away.\n\n")
+	if len(conflicts) > 0 {
+		fmt.Fprintf(&b, "A rebase of %s onto %s is in progress and stopped with conflicts in:\n\n", branch, target)
+		for _, f := range conflicts {
+			fmt.Fprintf(&b, "- %s\n", f)
+		}
+		b.WriteString("\nResolve the conflict markers in each file, `git add` it, and run `git rebase --continue`. " +
+			"Repeat until the rebase completes. Do not abort the rebase or push.\n")
+	}
+	if specContext != "" {
+		fmt.Fprintf(&b, "\n## Branch spec (%s)\n\n%s\n", branch, specContext)
+	}
+	if targetContext != "" {
+		fmt.Fprintf(&b, "\n## Changes on %s since the branch forked\n\n%s", target, targetContext)
+	}
+	if targetSpecs != "" {
+		fmt.Fprintf(&b, "\n## Specs behind those changes (as on %s)\n\n%s", target, targetSpecs)
+	}
+	if feedback != "" {
+		fmt.Fprintf(&b, "\n## Previous attempt\n\n%s\n", feedback)
+	}
+	return b.String()
+}
+
+// claudeAgent returns the agent used for worktree loops and conflict
+// resolution.
+func (o *Orchestrator) claudeAgent() claude.Agent {
+	if o.Agent != nil {
+		return o.Agent
+	}
+	return loop.NewClaudeAgentFor(o.cfg)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n… (truncated)"
+}
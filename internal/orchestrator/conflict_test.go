@@ -0,0 +1,313 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/worktree"
+)
+
+// resolverAgent is a claude.Agent that runs resolve in the session's working
+// directory and reports cost per session.
+type resolverAgent struct {
+	cost    float64
+	resolve func(dir string)
+	prompts []string
+}
+
+func (a *resolverAgent) Run(_ context.Context, prompt string, opts claude.RunOptions) (<-chan claude.Event, error) {
+	a.prompts = append(a.prompts, prompt)
+	if a.resolve != nil {
+		a.resolve(opts.Dir)
+	}
+	ch := make(chan claude.Event, 2)
+	ch <- claude.ToolUseEvent("Edit", map[string]any{"file_path": "same.txt"})
+	ch <- claude.ResultEvent(a.cost, 1, "success")
+	close(ch)
+	return ch, nil
+}
+
+// blockingResolver is a claude.Agent whose session runs until ctx is
+// cancelled, like a resolution agent that is still working.
+type blockingResolver struct {
+	started chan struct{}
+}
+
+func (a *blockingResolver) Run(ctx context.Context, _ string, _ claude.RunOptions) (<-chan claude.Event, error) {
+	ch := make(chan claude.Event)
+	go func() {
+		defer close(ch)
+		close(a.started)
+		<-ctx.Done()
+	}()
+	return ch, nil
+}
+
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newConflictOrchestrator sets up a repo whose feat/x worktree conflicts with
+// main on same.txt, and an orchestrator using the git worktree backend with
+// on_conflict = "agent".
+func newConflictOrchestrator(t *testing.T, agent claude.Agent) (*Orchestrator, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "-b", "main")
+	gitIn(t, dir, "config", "user.email", "test@test.com")
+	gitIn(t, dir, "config", "user.name", "Test")
+	gitIn(t, dir, "commit", "--allow-empty", "-m", "chore: init")
+
+	ops := worktree.NewGitRunner(dir, t.TempDir())
+	wt, err := ops.Switch("feat/x", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(d, content, msg string) {
+		if err := os.WriteFile(filepath.Join(d, "same.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitIn(t, d, "add", "same.txt")
+		gitIn(t, d, "commit", "-m", msg)
+	}
+	write(wt, "branch side\n", "feat: branch side")
+	write(dir, "main side\n", "feat: main side")
+
+	o := New(defaultCfg(), ops)
+	o.cfg.Worktree.OnConflict = "agent"
+	o.cfg.Worktree.ConflictMaxAttempts = 2
+	o.cfg.Worktree.ConflictMaxCost = 0
+	o.MergeTarget = "main"
+	o.Agent = agent
+	o.agents["feat/x"] = &WorktreeAgent{Branch: "feat/x", State: StateCompleted, WorktreePath: wt}
+	return o, dir, wt
+}
+
+func drainMerged(o *Orchestrator) []string {
+	var msgs []string
+	for {
+		select {
+		case ev := <-o.MergedEvents:
+			msgs = append(msgs, ev.Entry.Message)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestMerge_OnConflictAgent_Resolves(t *testing.T) {
+	agent := &resolverAgent{cost: 0.25}
+	agent.resolve = func(dir string) {
+		if err := os.WriteFile(filepath.Join(dir, "same.txt"), []byte("main side\nbranch side\n"), 0644); err != nil {
+			t.Error(err)
+		}
+		gitIn(t, dir, "add", "same.txt")
+		gitIn(t, dir, "rebase", "--continue")
+	}
+	o, dir, _ := newConflictOrchestrator(t, agent)
+
+	err := o.Merge("feat/x")
+	if !errors.Is(err, ErrResolvingConflict) {
+		t.Fatalf("Merge err = %v, want ErrResolvingConflict", err)
+	}
+	o.resolveWg.Wait()
+
+	a := o.agents["feat/x"]
+	if a.State != StateMerged {
+		t.Fatalf("state = %v (%v), want merged", a.State, a.Error)
+	}
+	if a.TotalCost != 0.25 {
+		t.Errorf("TotalCost = %v, want resolution cost 0.25", a.TotalCost)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "same.txt"))
+	if string(data) != "main side\nbranch side\n" {
+		t.Errorf("main has %q, want the resolved content", data)
+	}
+	if len(agent.prompts) != 1 || !strings.Contains(agent.prompts[0], "same.txt") ||
+		!strings.Contains(agent.prompts[0], "feat: main side") {
+		t.Errorf("prompt should list the conflict and the target-side commits:\n%v", agent.prompts)
+	}
+	out := strings.Join(drainMerged(o), "\n")
+	for _, want := range []string{"handing conflict to agent", "conflicts in same.txt", "tool: Edit", "conflict resolved and merged"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("events missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMerge_OnConflictAgent_TargetSpecContext(t *testing.T) {
+	agent := &resolverAgent{}
+	o, dir, _ := newConflictOrchestrator(t, agent)
+	o.cfg.Worktree.ConflictMaxAttempts = 1
+	specDir := filepath.Join(dir, "specs", "002-y")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"spec.md": "# Y\nDo Y.\n", "tasks.md": "- [ ] wire up Y\n"} {
+		if err := os.WriteFile(filepath.Join(specDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitIn(t, dir, "add", "specs")
+	gitIn(t, dir, "commit", "-m", "feat: y\n\nRalph-Spec: 002-y")
+
+	_ = o.Merge("feat/x")
+	o.resolveWg.Wait()
+
+	if len(agent.prompts) != 1 {
+		t.Fatalf("agent ran %d times, want 1", len(agent.prompts))
+	}
+	for _, want := range []string{"(spec: 002-y)", "### 002-y/spec.md", "Do Y.", "### 002-y/tasks.md", "wire up Y"} {
+		if !strings.Contains(agent.prompts[0], want) {
+			t.Errorf("prompt missing %q:\n%s", want, agent.prompts[0])
+		}
+	}
+}
+
+func TestMerge_OnConflictAgent_GivesUpAfterMaxAttempts(t *testing.T) {
+	agent := &resolverAgent{cost: 0.1} // never resolves anything
+	o, _, wt := newConflictOrchestrator(t, agent)
+
+	if err := o.Merge("feat/x"); !errors.Is(err, ErrResolvingConflict) {
+		t.Fatalf("Merge err = %v", err)
+	}
+	o.resolveWg.Wait()
+
+	if s, _ := o.AgentState("feat/x"); s != StateMergeFailed {
+		t.Fatalf("state = %v, want merge_failed", s)
+	}
+	if len(agent.prompts) != 2 {
+		t.Errorf("agent ran %d times, want conflict_max_attempts = 2", len(agent.prompts))
+	}
+	if !strings.Contains(agent.prompts[1], "left the rebase unfinished") {
+		t.Error("second attempt should carry feedback from the first")
+	}
+	if branch := gitIn(t, wt, "symbolic-ref", "--short", "HEAD"); branch != "feat/x" {
+		t.Errorf("rebase should be aborted, HEAD is %q", branch)
+	}
+	if !strings.Contains(strings.Join(drainMerged(o), "\n"), "conflict resolution gave up") {
+		t.Error("expected a give-up event")
+	}
+}
+
+func TestMerge_OnConflictAgent_CostCap(t *testing.T) {
+	agent := &resolverAgent{cost: 5}
+	o, _, _ := newConflictOrchestrator(t, agent)
+	o.cfg.Worktree.ConflictMaxAttempts = 3
+	o.cfg.Worktree.ConflictMaxCost = 1
+
+	_ = o.Merge("feat/x")
+	o.resolveWg.Wait()
+
+	if len(agent.prompts) != 1 {
+		t.Errorf("agent ran %d times, want 1 before the cost cap stops it", len(agent.prompts))
+	}
+	a := o.agents["feat/x"]
+	if a.State != StateMergeFailed || !strings.Contains(a.Error.Error(), "budget") {
+		t.Errorf("state = %v, err = %v; want merge_failed with budget error", a.State, a.Error)
+	}
+}
+
+func TestStopAll_CancelsConflictResolution(t *testing.T) {
+	agent := &blockingResolver{started: make(chan struct{})}
+	o, _, wt := newConflictOrchestrator(t, agent)
+
+	if err := o.Merge("feat/x"); !errors.Is(err, ErrResolvingConflict) {
+		t.Fatalf("Merge err = %v, want ErrResolvingConflict", err)
+	}
+	select {
+	case <-agent.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("resolution agent never started")
+	}
+
+	o.StopAll()
+	done := make(chan struct{})
+	go func() {
+		o.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Wait did not return after StopAll cancelled the resolver")
+	}
+
+	a := o.agents["feat/x"]
+	if a.State != StateMergeFailed || !errors.Is(a.Error, errResolveStopped) {
+		t.Errorf("state = %v, err = %v; want merge_failed, stopped", a.State, a.Error)
+	}
+	if branch := gitIn(t, wt, "symbolic-ref", "--short", "HEAD"); branch != "feat/x" {
+		t.Errorf("rebase should be aborted, HEAD is %q", branch)
+	}
+}
+
+func TestMerge_OnConflictFail_NoAgent(t *testing.T) {
+	agent := &resolverAgent{}
+	o, _, _ := newConflictOrchestrator(t, agent)
+	o.cfg.Worktree.OnConflict = "fail"
+
+	err := o.Merge("feat/x")
+	if err == nil || errors.Is(err, ErrResolvingConflict) {
+		t.Fatalf("Merge err = %v, want a plain merge failure", err)
+	}
+	if o.agents["feat/x"].State != StateMergeFailed || len(agent.prompts) != 0 {
+		t.Error("on_conflict = \"fail\" must not start a resolution agent")
+	}
+}
+
+func TestMerge_OnConflictAgent_NonConflictErrorReturned(t *testing.T) {
+	agent := &resolverAgent{}
+	o, dir, _ := newConflictOrchestrator(t, agent)
+	if err := os.WriteFile(filepath.Join(dir, "dirty.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := o.Merge("feat/x")
+	if err == nil || errors.Is(err, ErrResolvingConflict) || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("Merge err = %v, want the dirty-target error", err)
+	}
+	o.resolveWg.Wait()
+	if o.agents["feat/x"].State != StateMergeFailed || len(agent.prompts) != 0 {
+		t.Error("a non-conflict merge failure must not start a resolution agent")
+	}
+}
+
+func TestMerge_RefusedWhileResolving(t *testing.T) {
+	o := newTestOrchestrator(&fakeWorktreeOps{})
+	o.agents["feat/x"] = &WorktreeAgent{Branch: "feat/x", State: StateResolving}
+	if err := o.Merge("feat/x"); err == nil || !strings.Contains(err.Error(), "already in progress") {
+		t.Errorf("Merge while resolving = %v, want refusal", err)
+	}
+	if err := o.Clean("feat/x"); err == nil {
+		t.Error("Clean while resolving should be refused")
+	}
+}
+
+func TestConflictPrompt(t *testing.T) {
+	p := conflictPrompt("feat/x", "main", []string{"a.go"}, "# Spec\nDo X.", "- abc1234 feat: y (spec: 002-y)\n",
+		"### 002-y/spec.md\n\nDo Y.\n", "tests failed")
+	for _, want := range []string{"feat/x into main", "- a.go", "git rebase --continue", "Do X.", "spec: 002-y", "Do Y.", "tests failed"} {
+		if !strings.Contains(p, want) {
+			t.Errorf("prompt missing %q:\n%s", want, p)
+		}
+	}
+	if strings.Contains(conflictPrompt("b", "main", nil, "", "", "", "fix tests"), "rebase --continue") {
+		t.Error("prompt without conflicts should not ask to continue a rebase")
+	}
+}
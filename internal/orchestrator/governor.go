@@ -0,0 +1,287 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// ErrBudgetExhausted is returned to an agent that tries to start a Claude
+// invocation after [worktree] budget_usd has been spent.
+var ErrBudgetExhausted = errors.New("orchestrator: global budget exhausted")
+
+// governor coordinates Claude usage across every worktree agent: a global
+// cost budget, a cap on concurrent Claude invocations, and a shared pause
+// when any agent hits a rate limit or usage cap.
+type governor struct {
+	mu          sync.Mutex
+	budget      float64 // 0 = unlimited
+	spent       float64
+	exhausted   bool
+	pausedUntil time.Time
+	wait        time.Duration // pause when the error gives no reset time
+	slots       chan struct{} // nil = no cap on concurrent invocations
+	now         func() time.Time
+
+	// onPause and onExhausted report governor decisions; both are called
+	// without g.mu held.
+	onPause     func(branch string, until time.Time, reason string)
+	onExhausted func(spent float64)
+}
+
+func newGovernor(cfg *config.Config) *governor {
+	g := &governor{
+		budget: cfg.Worktree.BudgetUSD,
+		wait:   time.Duration(cfg.Claude.RateLimitWaitSeconds) * time.Second,
+		now:    time.Now,
+	}
+	if g.wait <= 0 {
+		g.wait = 15 * time.Minute
+	}
+	if cfg.Worktree.MaxConcurrentClaude > 0 {
+		g.slots = make(chan struct{}, cfg.Worktree.MaxConcurrentClaude)
+	}
+	return g
+}
+
+// governorHeartbeat is how often an agent waiting on the governor reports
+// that it is still waiting. The messages also keep the agent's Regent hang
+// timer from firing during a long pause.
+var governorHeartbeat = time.Minute
+
+// tryAcquire takes an invocation slot if no pause is in effect and one is
+// free, without blocking.
+func (g *governor) tryAcquire() bool {
+	g.mu.Lock()
+	paused := g.pausedUntil.After(g.now())
+	g.mu.Unlock()
+	if paused {
+		return false
+	}
+	if g.slots == nil {
+		return true
+	}
+	select {
+	case g.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// status returns the end of the current pause (zero or past when not paused)
+// and whether the budget is spent.
+func (g *governor) status() (pausedUntil time.Time, exhausted bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.pausedUntil, g.exhausted
+}
+
+// release frees the slot taken by tryAcquire or acquire.
+func (g *governor) release() {
+	if g.slots != nil {
+		<-g.slots
+	}
+}
+
+// pause holds every agent's next invocation until reset, or for the
+// configured wait when reset is unknown or already past. An earlier pause
+// that ends later is kept.
+func (g *governor) pause(branch string, reset time.Time, reason string) {
+	now := g.now()
+	if !reset.After(now) {
+		reset = now.Add(g.wait)
+	}
+	g.mu.Lock()
+	extended := reset.After(g.pausedUntil)
+	if extended {
+		g.pausedUntil = reset
+	}
+	g.mu.Unlock()
+	if extended && g.onPause != nil {
+		g.onPause(branch, reset, reason)
+	}
+}
+
+// spend records the cost of a finished invocation. Crossing the budget fires
+// onExhausted once.
+func (g *governor) spend(cost float64) {
+	g.mu.Lock()
+	g.spent += cost
+	crossed := g.budget > 0 && !g.exhausted && g.spent >= g.budget
+	if crossed {
+		g.exhausted = true
+	}
+	spent := g.spent
+	g.mu.Unlock()
+	if crossed && g.onExhausted != nil {
+		g.onExhausted(spent)
+	}
+}
+
+// governedAgent runs an agent's Claude invocations through the governor. A
+// rate-limited invocation is not reported to the loop: its error and result
+// are dropped and the same prompt is re-run once the pause ends, so it costs
+// neither an iteration nor a Regent retry.
+type governedAgent struct {
+	inner  claude.Agent
+	gov    *governor
+	branch string
+}
+
+func (a *governedAgent) Run(ctx context.Context, prompt string, opts claude.RunOptions) (<-chan claude.Event, error) {
+	if _, exhausted := a.gov.status(); exhausted {
+		return nil, ErrBudgetExhausted
+	}
+
+	// Start right away when nothing holds us back, so start errors reach the
+	// loop as before; otherwise wait in the background.
+	var events <-chan claude.Event
+	if a.gov.tryAcquire() {
+		ev, err := a.inner.Run(ctx, prompt, opts)
+		if err != nil {
+			a.gov.release()
+			return nil, err
+		}
+		events = ev
+	}
+
+	out := make(chan claude.Event, 64)
+	go func() {
+		defer close(out)
+		for {
+			if events == nil {
+				if err := a.acquire(ctx, out); err != nil {
+					if ctx.Err() == nil {
+						out <- claude.ErrorEvent(err.Error())
+					}
+					return
+				}
+				ev, err := a.inner.Run(ctx, prompt, opts)
+				if err != nil {
+					a.gov.release()
+					out <- claude.ErrorEvent(fmt.Sprintf("start claude: %v", err))
+					return
+				}
+				events = ev
+			}
+			limited := a.forward(events, out)
+			a.gov.release()
+			if !limited {
+				return
+			}
+			events = nil // re-run the same prompt once the pause ends
+		}
+	}()
+	return out, nil
+}
+
+// acquire waits until no pause is in effect and an invocation slot is free,
+// sending a status line to out every governorHeartbeat while it waits.
+func (a *governedAgent) acquire(ctx context.Context, out chan<- claude.Event) error {
+	ticker := time.NewTicker(governorHeartbeat)
+	defer ticker.Stop()
+	for {
+		until, exhausted := a.gov.status()
+		if exhausted {
+			return ErrBudgetExhausted
+		}
+		if wait := until.Sub(a.gov.now()); wait > 0 {
+			out <- claude.TextEvent(fmt.Sprintf("Paused for rate limit — resuming at %s (%s left)",
+				until.Local().Format("15:04:05"), wait.Round(time.Second)))
+			timer := time.NewTimer(min(wait, governorHeartbeat))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+		if a.gov.tryAcquire() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case a.gov.slots <- struct{}{}:
+			return nil
+		case <-ticker.C:
+			out <- claude.TextEvent("Waiting for a free Claude slot (max_concurrent_claude)")
+		}
+	}
+}
+
+// forward copies one invocation's events to out, recording its cost. Returns
+// true if the invocation hit a rate limit; its remaining events are dropped.
+func (a *governedAgent) forward(events <-chan claude.Event, out chan<- claude.Event) (limited bool) {
+	for ev := range events {
+		if limited {
+			if ev.Type == claude.EventResult {
+				a.gov.spend(ev.CostUSD)
+			}
+			continue
+		}
+		switch ev.Type {
+		case claude.EventRateLimit:
+			limited = true
+			a.gov.pause(a.branch, ev.ResetAt, ev.Error)
+			continue
+		case claude.EventError:
+			if reset, ok := claude.ParseRateLimit(ev.Error, a.gov.now()); ok {
+				limited = true
+				a.gov.pause(a.branch, reset, ev.Error)
+				continue
+			}
+		case claude.EventResult:
+			a.gov.spend(ev.CostUSD)
+		}
+		out <- ev
+	}
+	return limited
+}
+
+// governed returns the Claude agent for branch, routed through the governor.
+func (o *Orchestrator) governed(branch string) claude.Agent {
+	return &governedAgent{inner: o.claudeAgent(), gov: o.gov, branch: branch}
+}
+
+// announcePause tells every running agent's log that Claude calls are paused.
+func (o *Orchestrator) announcePause(branch string, until time.Time, reason string) {
+	msg := fmt.Sprintf("rate limit hit by %s — pausing all agents until %s: %s",
+		branch, until.Local().Format("2006-01-02 15:04:05"), reason)
+	for _, b := range o.activeBranches() {
+		o.emitToMerged(b, loop.LogEntry{Kind: loop.LogRegent, Message: msg, Branch: b})
+	}
+}
+
+// budgetExhausted stops every agent after its current iteration and empties
+// the launch queue once [worktree] budget_usd is spent.
+func (o *Orchestrator) budgetExhausted(spent float64) {
+	msg := fmt.Sprintf("global budget of $%.2f spent ($%.2f) — stopping all agents", o.cfg.Worktree.BudgetUSD, spent)
+	for _, b := range o.activeBranches() {
+		o.emitToMerged(b, loop.LogEntry{Kind: loop.LogError, Message: msg, Branch: b})
+	}
+	o.StopAll()
+}
+
+// activeBranches returns the branches of running or resolving agents.
+func (o *Orchestrator) activeBranches() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var branches []string
+	for b, a := range o.agents {
+		if a.State == StateRunning || a.State == StateResolving {
+			branches = append(branches, b)
+		}
+	}
+	sort.Strings(branches)
+	return branches
+}
@@ -0,0 +1,279 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// sessionAgent is a claude.Agent whose sessions are scripted per call. Each
+// call's events are sent on an unbuffered channel fed by a goroutine, and the
+// session ends when its release channel (if any) is closed.
+type sessionAgent struct {
+	mu       sync.Mutex
+	calls    int
+	sessions [][]claude.Event
+	release  []chan struct{}
+}
+
+func (a *sessionAgent) Run(_ context.Context, _ string, _ claude.RunOptions) (<-chan claude.Event, error) {
+	a.mu.Lock()
+	n := a.calls
+	a.calls++
+	var events []claude.Event
+	if n < len(a.sessions) {
+		events = a.sessions[n]
+	}
+	var done chan struct{}
+	if n < len(a.release) {
+		done = a.release[n]
+	}
+	a.mu.Unlock()
+
+	ch := make(chan claude.Event)
+	go func() {
+		defer close(ch)
+		for _, ev := range events {
+			ch <- ev
+		}
+		if done != nil {
+			<-done
+		}
+	}()
+	return ch, nil
+}
+
+func (a *sessionAgent) callCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls
+}
+
+func collect(t *testing.T, ch <-chan claude.Event) []claude.Event {
+	t.Helper()
+	var events []claude.Event
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return events
+			}
+			events = append(events, ev)
+		case <-timeout:
+			t.Fatal("event channel was not closed")
+		}
+	}
+}
+
+func fastHeartbeat(t *testing.T) {
+	t.Helper()
+	prev := governorHeartbeat
+	governorHeartbeat = 10 * time.Millisecond
+	t.Cleanup(func() { governorHeartbeat = prev })
+}
+
+func governorCfg(budget float64, maxClaude int) *config.Config {
+	return &config.Config{
+		Claude:   config.ClaudeConfig{RateLimitWaitSeconds: 1},
+		Worktree: config.WorktreeConfig{BudgetUSD: budget, MaxConcurrentClaude: maxClaude},
+	}
+}
+
+func TestGovernedAgent_RateLimitPausesAndReruns(t *testing.T) {
+	fastHeartbeat(t)
+	g := newGovernor(governorCfg(0, 0))
+	g.wait = 50 * time.Millisecond
+	var paused []string
+	g.onPause = func(branch string, _ time.Time, _ string) { paused = append(paused, branch) }
+
+	inner := &sessionAgent{sessions: [][]claude.Event{
+		{claude.TextEvent("working"), claude.ErrorEvent("API Error: 429 rate_limit_error"), claude.ResultEvent(0.10, 1, "error_during_execution")},
+		{claude.TextEvent("done"), claude.ResultEvent(0.50, 2, "success")},
+	}}
+	agent := &governedAgent{inner: inner, gov: g, branch: "feat/a"}
+
+	ch, err := agent.Run(context.Background(), "prompt", claude.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := collect(t, ch)
+
+	if inner.callCount() != 2 {
+		t.Errorf("inner agent called %d times, want 2 (re-run after the pause)", inner.callCount())
+	}
+	var results int
+	for _, ev := range events {
+		if ev.Type == claude.EventError {
+			t.Errorf("rate-limit error leaked to the loop: %q", ev.Error)
+		}
+		if ev.Type == claude.EventResult {
+			results++
+			if ev.Subtype != "success" {
+				t.Errorf("result from the rate-limited session leaked: %+v", ev)
+			}
+		}
+	}
+	if results != 1 {
+		t.Errorf("got %d result events, want 1", results)
+	}
+	if len(paused) != 1 || paused[0] != "feat/a" {
+		t.Errorf("onPause calls = %v", paused)
+	}
+	if g.spent != 0.60 {
+		t.Errorf("spent = %v, want both sessions counted (0.60)", g.spent)
+	}
+}
+
+func TestGovernedAgent_RateLimitEventPauses(t *testing.T) {
+	fastHeartbeat(t)
+	g := newGovernor(governorCfg(0, 0))
+	var until time.Time
+	g.onPause = func(_ string, u time.Time, _ string) { until = u }
+	reset := time.Now().Add(50 * time.Millisecond)
+
+	inner := &sessionAgent{sessions: [][]claude.Event{
+		{claude.RateLimitEvent("Claude AI usage limit reached", reset)},
+		{claude.ResultEvent(0.20, 1, "success")},
+	}}
+	ch, err := (&governedAgent{inner: inner, gov: g, branch: "feat/a"}).Run(context.Background(), "prompt", claude.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range collect(t, ch) {
+		if ev.Type == claude.EventRateLimit {
+			t.Error("rate-limit event leaked to the loop")
+		}
+	}
+	if inner.callCount() != 2 {
+		t.Errorf("inner agent called %d times, want 2", inner.callCount())
+	}
+	if !until.Equal(reset) {
+		t.Errorf("paused until %v, want the event's reset time %v", until, reset)
+	}
+}
+
+func TestGovernedAgent_PauseHoldsOtherAgents(t *testing.T) {
+	fastHeartbeat(t)
+	g := newGovernor(governorCfg(0, 0))
+	g.pause("feat/a", time.Now().Add(100*time.Millisecond), "usage limit reached")
+
+	inner := &sessionAgent{sessions: [][]claude.Event{{claude.ResultEvent(0, 1, "success")}}}
+	agent := &governedAgent{inner: inner, gov: g, branch: "feat/b"}
+	ch, err := agent.Run(context.Background(), "prompt", claude.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.callCount() != 0 {
+		t.Fatal("Claude should not start while the governor is paused")
+	}
+	events := collect(t, ch)
+	if inner.callCount() != 1 {
+		t.Errorf("Claude should start once the pause ends, calls = %d", inner.callCount())
+	}
+	if len(events) < 2 || events[0].Type != claude.EventText {
+		t.Errorf("expected a pause status line before the result, got %+v", events)
+	}
+}
+
+func TestGovernedAgent_ConcurrencyCap(t *testing.T) {
+	fastHeartbeat(t)
+	g := newGovernor(governorCfg(0, 1))
+	first := make(chan struct{})
+	inner := &sessionAgent{
+		sessions: [][]claude.Event{{claude.ResultEvent(0, 1, "success")}, {claude.ResultEvent(0, 1, "success")}},
+		release:  []chan struct{}{first},
+	}
+
+	a, err := (&governedAgent{inner: inner, gov: g, branch: "feat/a"}).Run(context.Background(), "p", claude.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := (&governedAgent{inner: inner, gov: g, branch: "feat/b"}).Run(context.Background(), "p", claude.RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if inner.callCount() != 1 {
+		t.Fatalf("second invocation started while the only slot was taken (calls = %d)", inner.callCount())
+	}
+
+	close(first)
+	collect(t, a)
+	collect(t, b)
+	if inner.callCount() != 2 {
+		t.Errorf("second invocation should run once the slot frees, calls = %d", inner.callCount())
+	}
+}
+
+func TestGovernedAgent_BudgetExhausted(t *testing.T) {
+	g := newGovernor(governorCfg(1.00, 0))
+	var fired int
+	g.onExhausted = func(float64) { fired++ }
+
+	inner := &sessionAgent{sessions: [][]claude.Event{{claude.ResultEvent(0.75, 1, "success")}, {claude.ResultEvent(0.50, 1, "success")}}}
+	agent := &governedAgent{inner: inner, gov: g, branch: "feat/a"}
+	for range 2 {
+		ch, err := agent.Run(context.Background(), "p", claude.RunOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		collect(t, ch)
+	}
+	if fired != 1 {
+		t.Errorf("onExhausted fired %d times, want 1", fired)
+	}
+	if _, err := agent.Run(context.Background(), "p", claude.RunOptions{}); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Run after budget spent = %v, want ErrBudgetExhausted", err)
+	}
+}
+
+func TestOrchestrator_BudgetExhaustedStopsAgents(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Worktree.BudgetUSD = 1.00
+	o := New(cfg, &fakeWorktreeOps{})
+	o.agents["feat/a"] = &WorktreeAgent{Branch: "feat/a", State: StateRunning, StopCh: make(chan struct{})}
+	o.queue = append(o.queue, &queueEntry{agent: &WorktreeAgent{Branch: "feat/q", State: StateQueued}, ctx: context.Background()})
+
+	o.gov.spend(1.50)
+
+	if s, _ := o.AgentState("feat/a"); s != StateStopped {
+		t.Errorf("running agent state = %v, want stopped", s)
+	}
+	if len(o.Queued()) != 0 {
+		t.Error("launch queue should be emptied")
+	}
+	select {
+	case ev := <-o.MergedEvents:
+		if ev.Branch != "feat/a" || ev.Entry.Kind != loop.LogError {
+			t.Errorf("budget event = %+v", ev)
+		}
+	default:
+		t.Error("expected a budget event on MergedEvents")
+	}
+}
+
+func TestOrchestrator_AnnouncePause(t *testing.T) {
+	o := New(defaultCfg(), &fakeWorktreeOps{})
+	o.agents["feat/a"] = &WorktreeAgent{Branch: "feat/a", State: StateRunning}
+	o.agents["feat/b"] = &WorktreeAgent{Branch: "feat/b", State: StateRunning}
+	o.agents["feat/done"] = &WorktreeAgent{Branch: "feat/done", State: StateCompleted}
+
+	o.gov.pause("feat/a", time.Now().Add(time.Hour), "usage limit reached")
+	o.gov.pause("feat/b", time.Now().Add(time.Minute), "usage limit reached") // shorter: not re-announced
+
+	var branches []string
+	for len(o.MergedEvents) > 0 {
+		ev := <-o.MergedEvents
+		branches = append(branches, ev.Branch)
+	}
+	if len(branches) != 2 || branches[0] != "feat/a" || branches[1] != "feat/b" {
+		t.Errorf("pause announced to %v, want [feat/a feat/b] once", branches)
+	}
+}
@@ -2,13 +2,17 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/LISSConsulting/RalphSpec/internal/claude"
 	"github.com/LISSConsulting/RalphSpec/internal/config"
 	"github.com/LISSConsulting/RalphSpec/internal/git"
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
 	"github.com/LISSConsulting/RalphSpec/internal/regent"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
 	"github.com/LISSConsulting/RalphSpec/internal/worktree"
 )
 
@@ -19,6 +23,15 @@ type Orchestrator struct {
 	mu          sync.Mutex
 	agents      map[string]*WorktreeAgent // keyed by branch name
 	fanInWg     sync.WaitGroup
+	agentWg     sync.WaitGroup // Launch goroutines, through auto-merge
+	resolveWg   sync.WaitGroup // conflict-resolution goroutines
+	saveMu      sync.Mutex     // serialises writes to StatePath
+	queue       []*queueEntry  // launches waiting for a slot; see Enqueue
+	queueSeq    uint64
+	queueMu     sync.Mutex // serialises startQueued
+	gov         *governor  // global budget, invocation cap and rate-limit pause
+	ctx         context.Context
+	cancel      context.CancelFunc // cancels ctx; conflict resolvers derive from it
 	MaxParallel int
 	AutoMerge   bool
 	MergeTarget string
@@ -32,11 +45,25 @@ type Orchestrator struct {
 	// NotificationHook, if set, is called for synthesised merge-result log
 	// entries so the external notification system can fire webhooks.
 	NotificationHook func(loop.LogEntry)
+
+	// SessionLog, if set, records merge-time events (e.g. the original SHAs
+	// of a squash-spec merge) in the dashboard's session log.
+	SessionLog store.Writer
+
+	// StatePath, if set, is where agent records are persisted after every
+	// state change (normally .ralph/orchestrator.json) so they survive a
+	// restart. See Restore.
+	StatePath string
+
+	// Agent runs Claude for worktree loops and merge-conflict resolution.
+	// Nil uses the claude CLI.
+	Agent claude.Agent
 }
 
 // New creates an Orchestrator with the given settings.
 func New(cfg *config.Config, ops worktree.WorktreeOps) *Orchestrator {
-	return &Orchestrator{
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Orchestrator{
 		agents:       make(map[string]*WorktreeAgent),
 		MaxParallel:  cfg.Worktree.MaxParallel,
 		AutoMerge:    cfg.Worktree.AutoMerge,
@@ -44,7 +71,13 @@ func New(cfg *config.Config, ops worktree.WorktreeOps) *Orchestrator {
 		WorktreeOps:  ops,
 		cfg:          cfg,
 		MergedEvents: make(chan TaggedLogEntry, mergedEventsBuf),
+		gov:          newGovernor(cfg),
+		ctx:          ctx,
+		cancel:       cancel,
 	}
+	o.gov.onPause = o.announcePause
+	o.gov.onExhausted = o.budgetExhausted
+	return o
 }
 
 // ActiveAgents returns a snapshot of agents that have not been removed.
@@ -82,6 +115,27 @@ func (o *Orchestrator) AgentByBranch(branch string) *WorktreeAgent {
 	return o.agents[branch]
 }
 
+// AgentSnapshot returns a copy of the agent for branch taken under the
+// orchestrator lock, so its fields can be read while the agent is running.
+func (o *Orchestrator) AgentSnapshot(branch string) (WorktreeAgent, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	a, ok := o.agents[branch]
+	if !ok {
+		return WorktreeAgent{}, false
+	}
+	return *a, true
+}
+
+// Wait blocks until every launched agent has exited, including agents started
+// from the queue meanwhile and any auto-merge or conflict resolution that
+// follows, and their events have been forwarded to MergedEvents.
+func (o *Orchestrator) Wait() {
+	o.agentWg.Wait()
+	o.resolveWg.Wait()
+	o.fanInWg.Wait()
+}
+
 // AgentState returns the current state of the agent for the given branch
 // under the orchestrator lock. Returns StateCreating and false if not found.
 func (o *Orchestrator) AgentState(branch string) (AgentState, bool) {
@@ -99,43 +153,62 @@ func (o *Orchestrator) AgentState(branch string) (AgentState, bool) {
 // goroutine.
 //
 // Returns an error if:
-//   - max_parallel is already reached
+//   - max_parallel is already reached (use Enqueue to wait for a slot)
 //   - an agent for branch already exists and is not in a terminal state
 //   - WorktreeOps.Switch() fails
 func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir string, mode loop.Mode, maxOverride int) error {
 	o.mu.Lock()
+	agent, err := o.reserve(branch, specName, specDir, mode)
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := o.start(ctx, agent, mode, maxOverride); err != nil {
+		o.startQueued() // the reserved slot is free again
+		return err
+	}
+	return nil
+}
 
+// reserve registers a StateCreating agent for branch, taking one of the
+// MaxParallel slots before the worktree exists. Callers must hold o.mu.
+func (o *Orchestrator) reserve(branch, specName, specDir string, mode loop.Mode) (*WorktreeAgent, error) {
 	// Reject duplicate branches (non-terminal state).
 	if existing, ok := o.agents[branch]; ok {
 		switch existing.State {
-		case StateRunning, StateCreating:
-			o.mu.Unlock()
-			return fmt.Errorf("orchestrator: agent already running on branch %s", branch)
+		case StateRunning, StateCreating, StateResolving:
+			return nil, fmt.Errorf("orchestrator: agent already running on branch %s", branch)
 		}
 	}
 
 	if o.runningCount() >= o.MaxParallel {
-		o.mu.Unlock()
-		return fmt.Errorf("orchestrator: max parallel agents (%d) reached", o.MaxParallel)
+		return nil, fmt.Errorf("orchestrator: max parallel agents (%d) reached", o.MaxParallel)
 	}
+	o.cancelQueued(branch) // a direct launch supersedes a queued one
 
 	// events is the outward-facing channel consumed by the fan-in goroutine.
-	// loopEvents is the internal channel the loop writes to; a drain goroutine
-	// bridges the two and calls rgt.UpdateState() to keep the hang timer alive.
 	events := make(chan loop.LogEntry, 128)
-	loopEvents := make(chan loop.LogEntry, 128)
 	stopCh := make(chan struct{})
 
 	agent := &WorktreeAgent{
-		Branch:   branch,
-		SpecName: specName,
-		SpecDir:  specDir,
-		State:    StateCreating,
-		Events:   events,
-		StopCh:   stopCh,
+		Branch:    branch,
+		SpecName:  specName,
+		SpecDir:   specDir,
+		Mode:      mode,
+		State:     StateCreating,
+		Events:    events,
+		StopCh:    stopCh,
+		StartedAt: time.Now(),
 	}
 	o.agents[branch] = agent
-	o.mu.Unlock()
+	return agent, nil
+}
+
+// start creates the worktree for a reserved agent and runs its loop in the
+// background. A worktree failure leaves the agent in StateFailed.
+func (o *Orchestrator) start(ctx context.Context, agent *WorktreeAgent, mode loop.Mode, maxOverride int) error {
+	branch, specName, specDir := agent.Branch, agent.SpecName, agent.SpecDir
+	events, stopCh := agent.Events, agent.StopCh
 
 	// Create/switch worktree outside the lock (subprocess call).
 	// Try reuse first (branch already exists as a feature branch), then create.
@@ -149,6 +222,7 @@ func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir str
 		agent.Error = err
 		o.mu.Unlock()
 		close(events)
+		o.save()
 		return fmt.Errorf("orchestrator: create worktree for %s: %w", branch, err)
 	}
 
@@ -156,6 +230,7 @@ func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir str
 	agent.WorktreePath = wtPath
 	agent.State = StateRunning
 	o.mu.Unlock()
+	o.save()
 
 	// Register with fan-in so events reach MergedEvents.
 	// The onEntry callback updates per-agent stats under the lock.
@@ -165,12 +240,16 @@ func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir str
 			agent.Iterations++
 			agent.TotalCost += e.CostUSD
 			o.mu.Unlock()
+			o.save()
 		}
 	}, &o.fanInWg)
 
-	// Build the loop for this worktree.
+	// Build the loop for this worktree. loopEvents is the internal channel the
+	// loop writes to; a drain goroutine bridges it to events and calls
+	// rgt.UpdateState() to keep the hang timer alive.
+	loopEvents := make(chan loop.LogEntry, 128)
 	lp := &loop.Loop{
-		Agent:     &loop.ClaudeAgent{Executable: "claude"},
+		Agent:     o.governed(branch),
 		Git:       git.NewRunner(wtPath),
 		Config:    o.cfg,
 		Dir:       wtPath,
@@ -180,7 +259,10 @@ func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir str
 		StopAfter: stopCh,
 	}
 
+	o.agentWg.Add(1)
 	go func() {
+		defer o.agentWg.Done()
+
 		// Each agent runs its own Regent instance for independent supervision
 		// (crash detection, hang detection, per-worktree rollback). Creating one
 		// Regent per agent satisfies FR-019/FR-020: failures are isolated — a
@@ -240,11 +322,16 @@ func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir str
 		} else if agent.State == StateRunning {
 			agent.State = StateCompleted
 		}
+		agent.FinishedAt = time.Now()
 		finalState := agent.State
 		o.mu.Unlock()
+		o.save()
 
 		close(events)
 
+		// This agent's slot is free: start the next queued launch.
+		o.startQueued()
+
 		if finalState == StateCompleted {
 			o.autoMergeIfNeeded(agent, branch)
 		}
@@ -254,6 +341,7 @@ func (o *Orchestrator) Launch(ctx context.Context, branch, specName, specDir str
 }
 
 // Stop requests a graceful stop for the agent on branch by closing its StopCh.
+// A queued agent is cancelled instead.
 func (o *Orchestrator) Stop(branch string) error {
 	o.mu.Lock()
 	agent, ok := o.agents[branch]
@@ -261,6 +349,12 @@ func (o *Orchestrator) Stop(branch string) error {
 		o.mu.Unlock()
 		return fmt.Errorf("orchestrator: no agent for branch %s", branch)
 	}
+	if agent.State == StateQueued {
+		o.cancelQueued(branch)
+		o.mu.Unlock()
+		o.save()
+		return nil
+	}
 	if agent.State != StateRunning {
 		o.mu.Unlock()
 		return fmt.Errorf("orchestrator: agent %s is not running (state: %s)", branch, agent.State)
@@ -276,16 +370,26 @@ func (o *Orchestrator) Stop(branch string) error {
 	o.mu.Lock()
 	agent.State = StateStopped
 	o.mu.Unlock()
+	o.save()
 	return nil
 }
 
-// StopAll stops every currently running agent.
+// StopAll stops every currently running agent, cancels any conflict
+// resolution in progress, and empties the launch queue.
 func (o *Orchestrator) StopAll() {
 	o.mu.Lock()
+	for len(o.queue) > 0 {
+		o.cancelQueued(o.queue[0].agent.Branch)
+	}
 	branches := make([]string, 0, len(o.agents))
 	for b, a := range o.agents {
-		if a.State == StateRunning {
+		switch a.State {
+		case StateRunning:
 			branches = append(branches, b)
+		case StateResolving:
+			if a.cancelResolve != nil {
+				a.cancelResolve()
+			}
 		}
 	}
 	o.mu.Unlock()
@@ -296,6 +400,10 @@ func (o *Orchestrator) StopAll() {
 }
 
 // Merge merges a completed/stopped worktree branch into the merge target.
+// When the merge fails on conflicting changes (worktree.ErrMergeConflict) and
+// [worktree] on_conflict = "agent", the branch is handed to a background
+// resolution agent (StateResolving) and the returned error wraps
+// ErrResolvingConflict. Other merge failures are returned as-is.
 func (o *Orchestrator) Merge(branch string) error {
 	o.mu.Lock()
 	agent, ok := o.agents[branch]
@@ -307,24 +415,94 @@ func (o *Orchestrator) Merge(branch string) error {
 		o.mu.Unlock()
 		return fmt.Errorf("orchestrator: cannot merge running agent %s — stop it first", branch)
 	}
+	if agent.State == StateQueued {
+		o.mu.Unlock()
+		return fmt.Errorf("orchestrator: agent %s has not started yet", branch)
+	}
+	if agent.State == StateResolving {
+		o.mu.Unlock()
+		return fmt.Errorf("orchestrator: conflict resolution already in progress for %s", branch)
+	}
 	agent.State = StateMerging
 	o.mu.Unlock()
 
+	if o.cfg.Git.Strategy == "squash-spec" {
+		if err := o.squashForMerge(agent, branch); err != nil {
+			o.mu.Lock()
+			agent.State = StateMergeFailed
+			agent.Error = err
+			o.mu.Unlock()
+			o.save()
+			return fmt.Errorf("orchestrator: squash %s: %w", branch, err)
+		}
+	}
+
 	if err := o.WorktreeOps.Merge(branch, o.MergeTarget); err != nil {
+		if o.cfg.Worktree.OnConflict == "agent" && errors.Is(err, worktree.ErrMergeConflict) {
+			ctx, cancel := context.WithCancel(o.ctx)
+			o.mu.Lock()
+			agent.State = StateResolving
+			agent.Error = err
+			agent.cancelResolve = cancel
+			o.mu.Unlock()
+			o.save()
+			o.resolveWg.Add(1)
+			go o.resolveConflict(ctx, agent, branch, err)
+			return fmt.Errorf("orchestrator: merge %s: %w", branch, ErrResolvingConflict)
+		}
 		o.mu.Lock()
 		agent.State = StateMergeFailed
 		agent.Error = err
 		o.mu.Unlock()
+		o.save()
 		return fmt.Errorf("orchestrator: merge %s: %w", branch, err)
 	}
 
 	o.mu.Lock()
 	agent.State = StateMerged
 	o.mu.Unlock()
+	o.save()
 	return nil
 }
 
-// Clean removes a non-running worktree agent via worktrunk.
+// squashForMerge folds the agent's branch into a single commit before it is
+// merged ([git] strategy = "squash-spec"). The original SHAs are emitted to
+// MergedEvents and recorded in SessionLog so history can still be traced.
+func (o *Orchestrator) squashForMerge(agent *WorktreeAgent, branch string) error {
+	name := agent.SpecName
+	if name == "" {
+		name = branch
+	}
+	gr := git.NewRunner(agent.WorktreePath)
+	original, err := gr.SquashBranch(o.MergeTarget, git.SpecSubject(name))
+	if err != nil {
+		return err
+	}
+	if len(original) < 2 {
+		return nil
+	}
+	shas := make([]string, len(original))
+	for i, c := range original {
+		shas[i] = c.SHA
+	}
+	commit, _ := gr.LastCommit()
+	entry := loop.LogEntry{
+		Kind:     loop.LogInfo,
+		Message:  fmt.Sprintf("worktree %s: squashed %d commits into %s", branch, len(original), commit),
+		Branch:   branch,
+		Commit:   commit,
+		Squashed: shas,
+	}
+	o.emitToMerged(branch, entry)
+	if o.SessionLog != nil {
+		_ = o.SessionLog.Append(entry)
+	}
+	return nil
+}
+
+// Clean removes a non-running worktree agent through the configured worktree
+// backend (WorktreeOps). A queued agent has no worktree yet and is simply
+// cancelled.
 func (o *Orchestrator) Clean(branch string) error {
 	o.mu.Lock()
 	agent, ok := o.agents[branch]
@@ -332,7 +510,13 @@ func (o *Orchestrator) Clean(branch string) error {
 		o.mu.Unlock()
 		return fmt.Errorf("orchestrator: no agent for branch %s", branch)
 	}
-	if agent.State == StateRunning || agent.State == StateCreating {
+	if agent.State == StateQueued {
+		o.cancelQueued(branch)
+		o.mu.Unlock()
+		o.save()
+		return nil
+	}
+	if agent.State == StateRunning || agent.State == StateCreating || agent.State == StateResolving {
 		o.mu.Unlock()
 		return fmt.Errorf("orchestrator: cannot clean running agent %s — stop it first", branch)
 	}
@@ -345,6 +529,7 @@ func (o *Orchestrator) Clean(branch string) error {
 	o.mu.Lock()
 	agent.State = StateRemoved
 	o.mu.Unlock()
+	o.save()
 	return nil
 }
 
@@ -362,11 +547,12 @@ func (o *Orchestrator) WorktreePaths() []string {
 	return paths
 }
 
-// runningCount returns the count of StateRunning agents; callers must hold o.mu.
+// runningCount returns the number of MaxParallel slots in use: agents that
+// are running or still creating their worktree. Callers must hold o.mu.
 func (o *Orchestrator) runningCount() int {
 	count := 0
 	for _, a := range o.agents {
-		if a.State == StateRunning {
+		if a.State == StateRunning || a.State == StateCreating {
 			count++
 		}
 	}
@@ -380,7 +566,8 @@ func (o *Orchestrator) runningCount() int {
 //   - If AutoMerge is false, this is a no-op.
 //   - If regent.test_command is configured, the command is executed inside the
 //     worktree directory.  A failing test run skips the merge and logs a warning.
-//   - If wt merge fails the agent transitions to StateMergeFailed.
+//   - If wt merge fails the agent transitions to StateMergeFailed, or to
+//     StateResolving when on_conflict = "agent".
 //   - Merge result events are emitted to MergedEvents and to NotificationHook.
 func (o *Orchestrator) autoMergeIfNeeded(agent *WorktreeAgent, branch string) {
 	if !o.AutoMerge {
@@ -406,7 +593,9 @@ func (o *Orchestrator) autoMergeIfNeeded(agent *WorktreeAgent, branch string) {
 		}
 	}
 
-	if err := o.Merge(branch); err != nil {
+	if err := o.Merge(branch); errors.Is(err, ErrResolvingConflict) {
+		return // resolveConflict reports the outcome
+	} else if err != nil {
 		o.emitToMerged(branch, loop.LogEntry{
 			Kind:    loop.LogError,
 			Message: fmt.Sprintf("worktree %s: auto-merge failed: %v", branch, err),
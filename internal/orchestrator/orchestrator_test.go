@@ -3,6 +3,9 @@ package orchestrator
 import (
 	"context"
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -173,6 +176,60 @@ func TestMerge_ConflictLeavesMergeFailed(t *testing.T) {
 	}
 }
 
+func TestMerge_SquashSpec(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	run("commit", "--allow-empty", "-m", "init")
+	run("checkout", "-b", "007-worktree-support")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", name)
+		run("commit", "-m", "add "+name)
+	}
+
+	ops := &fakeWorktreeOps{switchPath: dir}
+	o := newTestOrchestrator(ops)
+	o.cfg.Git.Strategy = "squash-spec"
+	o.MergeTarget = "main"
+	o.agents["007-worktree-support"] = &WorktreeAgent{
+		Branch: "007-worktree-support", SpecName: "007-worktree-support",
+		State: StateCompleted, WorktreePath: dir,
+	}
+
+	if err := o.Merge("007-worktree-support"); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if got := run("rev-list", "--count", "main..HEAD"); got != "1" {
+		t.Errorf("expected 1 squashed commit ahead of main, got %s", got)
+	}
+	if got := run("log", "-1", "--format=%s"); got != "feat: worktree support" {
+		t.Errorf("squash subject = %q", got)
+	}
+
+	select {
+	case ev := <-o.MergedEvents:
+		if len(ev.Entry.Squashed) != 2 {
+			t.Errorf("expected 2 original SHAs, got %v", ev.Entry.Squashed)
+		}
+	default:
+		t.Error("expected a squash event on MergedEvents")
+	}
+}
+
 // ─── Clean ────────────────────────────────────────────────────────────────────
 
 func TestClean_CompletedAgent(t *testing.T) {
@@ -681,6 +738,8 @@ func TestAgentState_String(t *testing.T) {
 		{StateMerged, "merged"},
 		{StateMergeFailed, "merge_failed"},
 		{StateRemoved, "removed"},
+		{StateResolving, "resolving"},
+		{StateQueued, "queued"},
 		{AgentState(999), "unknown"},
 	}
 	for _, tt := range tests {
@@ -689,3 +748,36 @@ func TestAgentState_String(t *testing.T) {
 		}
 	}
 }
+
+func TestWait_ReturnsAfterAgentsAndQueueFinish(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Regent.Enabled = false
+	cfg.Build.PromptFile = "BUILD.md" // missing → loops fail fast
+	cfg.Worktree.MaxParallel = 1
+	o := New(cfg, &fakeWorktreeOps{switchPath: t.TempDir()})
+
+	for _, b := range []string{"feat/a", "feat/b"} {
+		if _, err := o.Enqueue(context.Background(), b, "", "", loop.ModeBuild, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		o.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return")
+	}
+	for _, b := range []string{"feat/a", "feat/b"} {
+		a, ok := o.AgentSnapshot(b)
+		if !ok || a.State != StateFailed || a.FinishedAt.IsZero() {
+			t.Errorf("%s snapshot = %+v (found %v), want a finished, failed agent", b, a, ok)
+		}
+	}
+	if _, ok := o.AgentSnapshot("feat/missing"); ok {
+		t.Error("AgentSnapshot of unknown branch should report not found")
+	}
+}
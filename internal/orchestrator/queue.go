@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// queueEntry is a launch waiting for a free slot under MaxParallel.
+type queueEntry struct {
+	agent       *WorktreeAgent
+	ctx         context.Context
+	mode        loop.Mode
+	maxOverride int
+	seq         uint64 // arrival order; breaks priority ties (FIFO)
+}
+
+// Enqueue launches an agent for branch like Launch when a slot is free and
+// nothing is waiting. Otherwise the agent is added to the launch queue in
+// StateQueued and started automatically when a running agent exits. Queued
+// agents start highest priority first, then in arrival order; the initial
+// priority comes from [worktree] priorities for specName.
+//
+// Returns true if the agent was queued rather than started.
+func (o *Orchestrator) Enqueue(ctx context.Context, branch, specName, specDir string, mode loop.Mode, maxOverride int) (bool, error) {
+	o.mu.Lock()
+	if existing, ok := o.agents[branch]; ok {
+		switch existing.State {
+		case StateRunning, StateCreating, StateResolving:
+			o.mu.Unlock()
+			return false, fmt.Errorf("orchestrator: agent already running on branch %s", branch)
+		case StateQueued:
+			o.mu.Unlock()
+			return false, fmt.Errorf("orchestrator: agent already queued on branch %s", branch)
+		}
+	}
+	if len(o.queue) == 0 && o.runningCount() < o.MaxParallel {
+		agent, err := o.reserve(branch, specName, specDir, mode)
+		o.mu.Unlock()
+		if err != nil {
+			return false, err
+		}
+		return false, o.start(ctx, agent, mode, maxOverride)
+	}
+
+	agent := &WorktreeAgent{
+		Branch:   branch,
+		SpecName: specName,
+		SpecDir:  specDir,
+		Mode:     mode,
+		State:    StateQueued,
+		Priority: o.cfg.Worktree.Priorities[specName],
+		QueuedAt: time.Now(),
+	}
+	o.agents[branch] = agent
+	o.queueSeq++
+	o.queue = append(o.queue, &queueEntry{agent: agent, ctx: ctx, mode: mode, maxOverride: maxOverride, seq: o.queueSeq})
+	o.sortQueue()
+	o.mu.Unlock()
+	o.save()
+
+	// A slot may have freed up while the lock was released.
+	o.startQueued()
+	state, _ := o.AgentState(branch)
+	return state == StateQueued, nil
+}
+
+// Queued returns the queued agents in the order they will start.
+func (o *Orchestrator) Queued() []*WorktreeAgent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	agents := make([]*WorktreeAgent, len(o.queue))
+	for i, e := range o.queue {
+		agents[i] = e.agent
+	}
+	return agents
+}
+
+// AdjustPriority changes the priority of a queued agent by delta and
+// reorders the queue. Higher priorities start first.
+func (o *Orchestrator) AdjustPriority(branch string, delta int) error {
+	o.mu.Lock()
+	e := o.queued(branch)
+	if e == nil {
+		o.mu.Unlock()
+		return fmt.Errorf("orchestrator: %s is not queued", branch)
+	}
+	e.agent.Priority += delta
+	o.sortQueue()
+	o.mu.Unlock()
+	o.save()
+	return nil
+}
+
+// cancelQueued removes a queued agent from the queue; the agent is marked
+// StateRemoved since it never had a worktree. Callers must hold o.mu.
+func (o *Orchestrator) cancelQueued(branch string) bool {
+	for i, e := range o.queue {
+		if e.agent.Branch == branch {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			e.agent.State = StateRemoved
+			return true
+		}
+	}
+	return false
+}
+
+// startQueued launches queued agents while slots are free. The slot is
+// reserved before o.mu is released, so a concurrent Enqueue or Launch cannot
+// take it. A queued agent that fails to start is left in StateFailed, the
+// failure is reported on MergedEvents and the next entry is tried.
+func (o *Orchestrator) startQueued() {
+	o.queueMu.Lock()
+	defer o.queueMu.Unlock()
+	for {
+		o.mu.Lock()
+		if len(o.queue) == 0 || o.runningCount() >= o.MaxParallel {
+			o.mu.Unlock()
+			return
+		}
+		e := o.queue[0]
+		o.queue = o.queue[1:]
+		a := e.agent
+		if e.ctx.Err() != nil {
+			a.State = StateRemoved
+			o.mu.Unlock()
+			o.save()
+			continue
+		}
+		agent, err := o.reserve(a.Branch, a.SpecName, a.SpecDir, e.mode)
+		if err != nil {
+			a.State = StateFailed
+			a.Error = err
+		}
+		o.mu.Unlock()
+
+		if err == nil {
+			err = o.start(e.ctx, agent, e.mode, e.maxOverride)
+		}
+		if err != nil {
+			o.save()
+			o.emitToMerged(a.Branch, loop.LogEntry{
+				Kind:    loop.LogError,
+				Message: fmt.Sprintf("worktree %s: queued launch failed: %v", a.Branch, err),
+				Branch:  a.Branch,
+			})
+			continue
+		}
+		o.emitToMerged(a.Branch, loop.LogEntry{
+			Kind:    loop.LogInfo,
+			Message: fmt.Sprintf("worktree %s: started from queue (waited %s)", a.Branch, time.Since(a.QueuedAt).Round(time.Second)),
+			Branch:  a.Branch,
+		})
+	}
+}
+
+// queued returns the queue entry for branch, or nil. Callers must hold o.mu.
+func (o *Orchestrator) queued(branch string) *queueEntry {
+	for _, e := range o.queue {
+		if e.agent.Branch == branch {
+			return e
+		}
+	}
+	return nil
+}
+
+// sortQueue orders the queue by priority (highest first), then arrival.
+// Callers must hold o.mu.
+func (o *Orchestrator) sortQueue() {
+	sort.SliceStable(o.queue, func(i, j int) bool {
+		a, b := o.queue[i], o.queue[j]
+		if a.agent.Priority != b.agent.Priority {
+			return a.agent.Priority > b.agent.Priority
+		}
+		return a.seq < b.seq
+	})
+}
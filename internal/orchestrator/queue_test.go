@@ -0,0 +1,217 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// newQueueOrchestrator returns an orchestrator whose launched loops fail fast
+// (no prompt file, no Regent), with both parallel slots already taken.
+func newQueueOrchestrator(t *testing.T) *Orchestrator {
+	t.Helper()
+	cfg := defaultCfg()
+	cfg.Regent.Enabled = false
+	cfg.Build.PromptFile = "BUILD.md"
+	o := New(cfg, &fakeWorktreeOps{switchPath: t.TempDir()})
+	o.agents["busy/a"] = &WorktreeAgent{Branch: "busy/a", State: StateRunning}
+	o.agents["busy/b"] = &WorktreeAgent{Branch: "busy/b", State: StateRunning}
+	return o
+}
+
+func queuedBranches(o *Orchestrator) []string {
+	var branches []string
+	for _, a := range o.Queued() {
+		branches = append(branches, a.Branch)
+	}
+	return branches
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEnqueue_StartsImmediatelyWhenSlotFree(t *testing.T) {
+	o := newQueueOrchestrator(t)
+	delete(o.agents, "busy/b")
+
+	queued, err := o.Enqueue(context.Background(), "feat/now", "", "", loop.ModeBuild, 1)
+	if err != nil || queued {
+		t.Fatalf("Enqueue = %v, %v; want started immediately", queued, err)
+	}
+	waitAgentTerminal(t, o, "feat/now", 5*time.Second)
+}
+
+func TestEnqueue_QueuesPastMaxParallel(t *testing.T) {
+	o := newQueueOrchestrator(t)
+
+	queued, err := o.Enqueue(context.Background(), "feat/later", "", "", loop.ModeBuild, 1)
+	if err != nil || !queued {
+		t.Fatalf("Enqueue = %v, %v; want queued", queued, err)
+	}
+	if s, _ := o.AgentState("feat/later"); s != StateQueued {
+		t.Fatalf("state = %v, want queued", s)
+	}
+	if _, err := o.Enqueue(context.Background(), "feat/later", "", "", loop.ModeBuild, 1); err == nil {
+		t.Error("expected duplicate enqueue to be rejected")
+	}
+	if err := o.Merge("feat/later"); err == nil {
+		t.Error("expected merge of a queued agent to be rejected")
+	}
+
+	// Free a slot: the queued agent starts.
+	o.mu.Lock()
+	o.agents["busy/a"].State = StateCompleted
+	o.mu.Unlock()
+	o.startQueued()
+
+	if len(o.Queued()) != 0 {
+		t.Errorf("queue should be empty, got %v", queuedBranches(o))
+	}
+	agent := waitAgentTerminal(t, o, "feat/later", 5*time.Second)
+	if agent.State == StateQueued {
+		t.Error("queued agent should have been launched")
+	}
+}
+
+func TestEnqueue_PriorityOrder(t *testing.T) {
+	o := newQueueOrchestrator(t)
+	o.cfg.Worktree.Priorities = map[string]int{"002-urgent": 5}
+	ctx := context.Background()
+	for _, name := range []string{"001-first", "002-urgent", "003-last"} {
+		if _, err := o.Enqueue(ctx, name, name, "specs/"+name, loop.ModeBuild, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := queuedBranches(o), []string{"002-urgent", "001-first", "003-last"}; !equalStrings(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+
+	if err := o.AdjustPriority("003-last", 10); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := queuedBranches(o), []string{"003-last", "002-urgent", "001-first"}; !equalStrings(got, want) {
+		t.Errorf("after raise, queue = %v, want %v", got, want)
+	}
+	if err := o.AdjustPriority("busy/a", 1); err == nil {
+		t.Error("expected error adjusting a running agent")
+	}
+}
+
+func TestQueue_Cancel(t *testing.T) {
+	o := newQueueOrchestrator(t)
+	ctx := context.Background()
+	for _, b := range []string{"q/1", "q/2", "q/3"} {
+		if _, err := o.Enqueue(ctx, b, "", "", loop.ModeBuild, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := o.Stop("q/1"); err != nil {
+		t.Fatalf("Stop queued: %v", err)
+	}
+	if err := o.Clean("q/2"); err != nil {
+		t.Fatalf("Clean queued: %v", err)
+	}
+	if got := queuedBranches(o); !equalStrings(got, []string{"q/3"}) {
+		t.Errorf("queue = %v, want [q/3]", got)
+	}
+	for _, b := range []string{"q/1", "q/2"} {
+		if s, _ := o.AgentState(b); s != StateRemoved {
+			t.Errorf("%s state = %v, want removed", b, s)
+		}
+	}
+
+	o.StopAll()
+	if len(o.Queued()) != 0 {
+		t.Errorf("StopAll should empty the queue, got %v", queuedBranches(o))
+	}
+}
+
+func TestQueue_StartsWhenAgentExits(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Regent.Enabled = false
+	cfg.Build.PromptFile = "BUILD.md"
+	cfg.Worktree.MaxParallel = 1
+	o := New(cfg, &fakeWorktreeOps{switchPath: t.TempDir()})
+	ctx := context.Background()
+
+	for _, b := range []string{"feat/a", "feat/b", "feat/c"} {
+		if _, err := o.Enqueue(ctx, b, "", "", loop.ModeBuild, 1); err != nil {
+			t.Fatalf("Enqueue %s: %v", b, err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(o.Queued()) > 0 || o.RunningCount() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue did not drain: %v", queuedBranches(o))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, b := range []string{"feat/a", "feat/b", "feat/c"} {
+		if s, _ := o.AgentState(b); s != StateFailed {
+			t.Errorf("%s state = %v, want failed (loop ran without a prompt file)", b, s)
+		}
+	}
+}
+
+func TestQueue_LaunchFailureMarksAgentFailed(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Regent.Enabled = false
+	cfg.Worktree.MaxParallel = 1
+	o := New(cfg, &fakeWorktreeOps{switchErr: errors.New("wt: boom")})
+	o.agents["busy/a"] = &WorktreeAgent{Branch: "busy/a", State: StateRunning}
+	ctx := context.Background()
+
+	for _, b := range []string{"feat/a", "feat/b"} {
+		if queued, err := o.Enqueue(ctx, b, "", "", loop.ModeBuild, 1); err != nil || !queued {
+			t.Fatalf("Enqueue %s = %v, %v; want queued", b, queued, err)
+		}
+	}
+
+	o.mu.Lock()
+	o.agents["busy/a"].State = StateCompleted
+	o.mu.Unlock()
+	o.startQueued()
+
+	if q := queuedBranches(o); len(q) != 0 {
+		t.Errorf("queue = %v, want empty after failed launches", q)
+	}
+	for _, b := range []string{"feat/a", "feat/b"} {
+		if s, _ := o.AgentState(b); s != StateFailed {
+			t.Errorf("%s state = %v, want failed", b, s)
+		}
+	}
+	var failures int
+	for len(o.MergedEvents) > 0 {
+		ev := <-o.MergedEvents
+		if ev.Entry.Kind == loop.LogError && strings.Contains(ev.Entry.Message, "queued launch failed") {
+			failures++
+		}
+	}
+	if failures != 2 {
+		t.Errorf("got %d queued-launch failure events, want 2", failures)
+	}
+}
+
+func TestEnqueue_CreatingAgentHoldsSlot(t *testing.T) {
+	o := newQueueOrchestrator(t)
+	o.agents["busy/b"].State = StateCreating
+
+	queued, err := o.Enqueue(context.Background(), "feat/later", "", "", loop.ModeBuild, 1)
+	if err != nil || !queued {
+		t.Fatalf("Enqueue = %v, %v; want queued while a worktree is being created", queued, err)
+	}
+}
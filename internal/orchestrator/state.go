@@ -0,0 +1,300 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// stateFileName is the orchestrator state file within the .ralph directory.
+const stateFileName = "orchestrator.json"
+
+// StatePath returns the path of the orchestrator state file for the project
+// rooted at dir: .ralph/orchestrator.json.
+func StatePath(dir string) string {
+	return filepath.Join(dir, ".ralph", stateFileName)
+}
+
+// AgentRecord is the persisted form of a WorktreeAgent.
+type AgentRecord struct {
+	Branch       string    `json:"branch"`
+	SpecName     string    `json:"spec_name,omitempty"`
+	SpecDir      string    `json:"spec_dir,omitempty"`
+	WorktreePath string    `json:"worktree_path"`
+	Mode         string    `json:"mode,omitempty"`
+	State        string    `json:"state"`
+	Iterations   int       `json:"iterations"`
+	TotalCost    float64   `json:"total_cost_usd"`
+	Error        string    `json:"error,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Launch queue fields, set for queued agents only.
+	Priority    int       `json:"priority,omitempty"`
+	QueuedAt    time.Time `json:"queued_at,omitempty"`
+	MaxOverride int       `json:"max_override,omitempty"`
+}
+
+// stateFile is the on-disk layout of .ralph/orchestrator.json.
+type stateFile struct {
+	Agents []AgentRecord `json:"agents"`
+}
+
+// LoadState reads agent records from path. Returns no records (not an error)
+// if the file does not exist.
+func LoadState(path string) ([]AgentRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("orchestrator: read state: %w", err)
+	}
+	var f stateFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("orchestrator: parse state: %w", err)
+	}
+	return f.Agents, nil
+}
+
+// SaveState writes agent records to path, creating its directory if needed.
+// Uses a write-then-rename pattern so readers never observe a partial file.
+func SaveState(path string, records []AgentRecord) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("orchestrator: create state dir: %w", err)
+	}
+	data, err := json.MarshalIndent(stateFile{Agents: records}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("orchestrator: marshal state: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".orchestrator-*.tmp")
+	if err != nil {
+		return fmt.Errorf("orchestrator: create temp state: %w", err)
+	}
+	if _, writeErr := tmp.Write(data); writeErr != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("orchestrator: write state: %w", writeErr)
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("orchestrator: close state: %w", closeErr)
+	}
+	if renameErr := os.Rename(tmp.Name(), path); renameErr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("orchestrator: finalize state: %w", renameErr)
+	}
+	return nil
+}
+
+// save persists every non-removed agent to StatePath. It is a no-op when
+// StatePath is empty. Failures are ignored: the state file is a convenience
+// for restarts and must never interrupt an agent. Callers must not hold o.mu.
+func (o *Orchestrator) save() {
+	if o.StatePath == "" {
+		return
+	}
+	o.saveMu.Lock()
+	defer o.saveMu.Unlock()
+
+	now := time.Now()
+	o.mu.Lock()
+	records := make([]AgentRecord, 0, len(o.agents))
+	for _, a := range o.agents {
+		if a.State == StateRemoved {
+			continue
+		}
+		r := AgentRecord{
+			Branch:       a.Branch,
+			SpecName:     a.SpecName,
+			SpecDir:      a.SpecDir,
+			WorktreePath: a.WorktreePath,
+			Mode:         string(a.Mode),
+			State:        a.State.String(),
+			Iterations:   a.Iterations,
+			TotalCost:    a.TotalCost,
+			StartedAt:    a.StartedAt,
+			FinishedAt:   a.FinishedAt,
+			UpdatedAt:    now,
+		}
+		if a.Error != nil {
+			r.Error = a.Error.Error()
+		}
+		if e := o.queued(a.Branch); e != nil {
+			r.Priority, r.QueuedAt, r.MaxOverride = a.Priority, a.QueuedAt, e.maxOverride
+		}
+		records = append(records, r)
+	}
+	o.mu.Unlock()
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Branch < records[j].Branch })
+	_ = SaveState(o.StatePath, records)
+}
+
+// Restore rebuilds agents from StatePath after a restart. Records are checked
+// against WorktreeOps.List: agents whose worktree no longer exists, and
+// agents that were already merged, are dropped. Agents that were running
+// when ralph exited come back as stopped, and interrupted merges as
+// merge_failed. Queued agents, which have no worktree yet, go back on the
+// launch queue with their priority and start as slots free up. Branches
+// already tracked in memory are left untouched.
+//
+// Returns the restored agents that can be resumed (stopped or failed), sorted
+// by branch.
+func (o *Orchestrator) Restore() ([]*WorktreeAgent, error) {
+	if o.StatePath == "" {
+		return nil, nil
+	}
+	records, err := LoadState(o.StatePath)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	infos, err := o.WorktreeOps.List()
+	if err != nil {
+		return nil, fmt.Errorf("orchestrator: restore: %w", err)
+	}
+	paths := make(map[string]string, len(infos))
+	for _, info := range infos {
+		paths[info.Branch] = info.Path
+	}
+
+	var resumable []*WorktreeAgent
+	var queued []AgentRecord
+	o.mu.Lock()
+	for _, r := range records {
+		if _, tracked := o.agents[r.Branch]; tracked {
+			continue
+		}
+		state := parseAgentState(r.State)
+		if state == StateQueued {
+			queued = append(queued, r)
+			continue
+		}
+		path, ok := paths[r.Branch]
+		if !ok {
+			continue // worktree was removed outside ralph
+		}
+		var agentErr error
+		if r.Error != "" {
+			agentErr = fmt.Errorf("%s", r.Error)
+		}
+		switch state {
+		case StateMerged, StateRemoved:
+			continue
+		case StateCreating, StateRunning:
+			state = StateStopped
+			agentErr = fmt.Errorf("interrupted: ralph exited while the agent was running")
+		case StateMerging, StateResolving:
+			state = StateMergeFailed
+			agentErr = fmt.Errorf("interrupted: ralph exited during the merge")
+		}
+		a := &WorktreeAgent{
+			Branch:       r.Branch,
+			WorktreePath: path,
+			SpecName:     r.SpecName,
+			SpecDir:      r.SpecDir,
+			Mode:         loop.Mode(r.Mode),
+			State:        state,
+			Iterations:   r.Iterations,
+			TotalCost:    r.TotalCost,
+			Error:        agentErr,
+			StartedAt:    r.StartedAt,
+			FinishedAt:   r.FinishedAt,
+		}
+		o.agents[r.Branch] = a
+		if state == StateStopped || state == StateFailed {
+			resumable = append(resumable, a)
+		}
+	}
+	// Records are saved in branch order; requeue in arrival order so equal
+	// priorities keep their FIFO order.
+	sort.SliceStable(queued, func(i, j int) bool { return queued[i].QueuedAt.Before(queued[j].QueuedAt) })
+	for _, r := range queued {
+		o.requeue(r)
+	}
+	o.sortQueue()
+	o.mu.Unlock()
+	o.save()
+	o.startQueued()
+
+	sort.Slice(resumable, func(i, j int) bool { return resumable[i].Branch < resumable[j].Branch })
+	return resumable, nil
+}
+
+// requeue puts a persisted queued agent back on the launch queue. Restored
+// entries run under the orchestrator's own context. Callers must hold o.mu.
+func (o *Orchestrator) requeue(r AgentRecord) {
+	mode := loop.Mode(r.Mode)
+	if mode == "" {
+		mode = loop.ModeBuild
+	}
+	agent := &WorktreeAgent{
+		Branch:   r.Branch,
+		SpecName: r.SpecName,
+		SpecDir:  r.SpecDir,
+		Mode:     mode,
+		State:    StateQueued,
+		Priority: r.Priority,
+		QueuedAt: r.QueuedAt,
+	}
+	o.agents[r.Branch] = agent
+	o.queueSeq++
+	o.queue = append(o.queue, &queueEntry{agent: agent, ctx: o.ctx, mode: mode, maxOverride: r.MaxOverride, seq: o.queueSeq})
+}
+
+// Resume relaunches a stopped or failed agent in its existing worktree with
+// its original spec and mode. Iteration and cost totals carry over.
+func (o *Orchestrator) Resume(ctx context.Context, branch string) error {
+	o.mu.Lock()
+	agent, ok := o.agents[branch]
+	if !ok {
+		o.mu.Unlock()
+		return fmt.Errorf("orchestrator: no agent for branch %s", branch)
+	}
+	if agent.State != StateStopped && agent.State != StateFailed {
+		o.mu.Unlock()
+		return fmt.Errorf("orchestrator: agent %s cannot be resumed (state: %s)", branch, agent.State)
+	}
+	prev := *agent
+	o.mu.Unlock()
+
+	mode := prev.Mode
+	if mode == "" {
+		mode = loop.ModeBuild
+	}
+	if err := o.Launch(ctx, branch, prev.SpecName, prev.SpecDir, mode, 0); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	if a, ok := o.agents[branch]; ok {
+		a.Iterations += prev.Iterations
+		a.TotalCost += prev.TotalCost
+		if !prev.StartedAt.IsZero() {
+			a.StartedAt = prev.StartedAt
+		}
+	}
+	o.mu.Unlock()
+	o.save()
+	return nil
+}
+
+// parseAgentState is the inverse of AgentState.String. Unknown names map to
+// StateFailed so a corrupt record is surfaced rather than silently resumed as
+// running.
+func parseAgentState(s string) AgentState {
+	for st := StateCreating; st <= StateQueued; st++ {
+		if st.String() == s {
+			return st
+		}
+	}
+	return StateFailed
+}
@@ -0,0 +1,206 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/worktree"
+)
+
+func TestLoadState_MissingFile(t *testing.T) {
+	records, err := LoadState(filepath.Join(t.TempDir(), "orchestrator.json"))
+	if err != nil || records != nil {
+		t.Errorf("LoadState(missing) = %v, %v; want nil, nil", records, err)
+	}
+}
+
+func TestLoadState_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orchestrator.json")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadState(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestSaveLoadState_RoundTrip(t *testing.T) {
+	path := StatePath(t.TempDir())
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := []AgentRecord{{
+		Branch: "feat/a", SpecName: "001-a", SpecDir: "specs/001-a", WorktreePath: "/wt/a",
+		Mode: "build", State: "stopped", Iterations: 4, TotalCost: 1.25, Error: "boom",
+		StartedAt: started,
+	}}
+	if err := SaveState(path, want); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	got, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if len(got) != 1 || got[0].Branch != "feat/a" || got[0].Iterations != 4 ||
+		got[0].TotalCost != 1.25 || got[0].Error != "boom" || !got[0].StartedAt.Equal(started) {
+		t.Errorf("round trip = %+v", got)
+	}
+}
+
+func TestOrchestrator_PersistsStateChanges(t *testing.T) {
+	o := newTestOrchestrator(&fakeWorktreeOps{})
+	o.StatePath = StatePath(t.TempDir())
+	o.agents["feat/run"] = &WorktreeAgent{Branch: "feat/run", State: StateRunning, StopCh: make(chan struct{}), Iterations: 2}
+	o.agents["feat/gone"] = &WorktreeAgent{Branch: "feat/gone", State: StateCompleted}
+
+	if err := o.Stop("feat/run"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Clean("feat/gone"); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := LoadState(o.StatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("want 1 record (removed agents are dropped), got %+v", records)
+	}
+	if r := records[0]; r.Branch != "feat/run" || r.State != "stopped" || r.Iterations != 2 || r.UpdatedAt.IsZero() {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ops := &fakeWorktreeOps{listResult: []worktree.WorktreeInfo{
+		{Branch: "feat/running", Path: "/wt/running-moved"},
+		{Branch: "feat/failed", Path: "/wt/failed"},
+		{Branch: "feat/merging", Path: "/wt/merging"},
+		{Branch: "feat/merged", Path: "/wt/merged"},
+		{Branch: "feat/completed", Path: "/wt/completed"},
+	}}
+	o := newTestOrchestrator(ops)
+	o.MaxParallel = 0 // keep the restored queue from starting
+	o.StatePath = StatePath(t.TempDir())
+	now := time.Now()
+	if err := SaveState(o.StatePath, []AgentRecord{
+		{Branch: "feat/running", WorktreePath: "/wt/running", State: "running", Mode: "plan", Iterations: 3, TotalCost: 0.5},
+		{Branch: "feat/failed", WorktreePath: "/wt/failed", State: "failed", Error: "claude crashed"},
+		{Branch: "feat/merging", WorktreePath: "/wt/merging", State: "merging"},
+		{Branch: "feat/merged", WorktreePath: "/wt/merged", State: "merged"},
+		{Branch: "feat/completed", WorktreePath: "/wt/completed", State: "completed"},
+		{Branch: "feat/deleted", WorktreePath: "/wt/deleted", State: "stopped"},
+		{Branch: "feat/queued", State: "queued", Mode: "plan", Priority: 1, QueuedAt: now, MaxOverride: 4},
+		{Branch: "feat/queued-first", State: "queued", QueuedAt: now.Add(-time.Minute)},
+		{Branch: "feat/queued-urgent", State: "queued", Priority: 5, QueuedAt: now},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resumable, err := o.Restore()
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(resumable) != 2 || resumable[0].Branch != "feat/failed" || resumable[1].Branch != "feat/running" {
+		t.Fatalf("resumable = %+v", resumable)
+	}
+
+	tests := []struct {
+		branch string
+		want   AgentState
+	}{
+		{"feat/running", StateStopped},
+		{"feat/failed", StateFailed},
+		{"feat/merging", StateMergeFailed},
+		{"feat/completed", StateCompleted},
+		{"feat/queued", StateQueued},
+	}
+	for _, tt := range tests {
+		if s, ok := o.AgentState(tt.branch); !ok || s != tt.want {
+			t.Errorf("%s: state = %v (found %v), want %v", tt.branch, s, ok, tt.want)
+		}
+	}
+	for _, b := range []string{"feat/merged", "feat/deleted"} {
+		if o.AgentByBranch(b) != nil {
+			t.Errorf("%s should not be restored", b)
+		}
+	}
+	r := o.AgentByBranch("feat/running")
+	if r.WorktreePath != "/wt/running-moved" || r.Mode != loop.ModePlan || r.Iterations != 3 || r.TotalCost != 0.5 {
+		t.Errorf("restored agent = %+v", r)
+	}
+	if r.Error == nil || !strings.Contains(r.Error.Error(), "interrupted") {
+		t.Errorf("running agent should be marked interrupted, got %v", r.Error)
+	}
+	if f := o.AgentByBranch("feat/failed"); f.Error == nil || f.Error.Error() != "claude crashed" {
+		t.Errorf("failed agent error = %v", f.Error)
+	}
+
+	var order []string
+	for _, a := range o.Queued() {
+		order = append(order, a.Branch)
+	}
+	if got := strings.Join(order, ","); got != "feat/queued-urgent,feat/queued,feat/queued-first" {
+		t.Errorf("queue order = %s", got)
+	}
+	o.mu.Lock()
+	e := o.queued("feat/queued")
+	o.mu.Unlock()
+	if e.mode != loop.ModePlan || e.maxOverride != 4 || e.agent.Priority != 1 {
+		t.Errorf("restored queue entry = mode %s, max %d, priority %d", e.mode, e.maxOverride, e.agent.Priority)
+	}
+
+	// The queue survives a second restart.
+	records, err := LoadState(o.StatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		if r.Branch == "feat/queued" && (r.State != "queued" || r.Priority != 1 || r.MaxOverride != 4) {
+			t.Errorf("saved queued record = %+v", r)
+		}
+	}
+}
+
+func TestRestore_NoStatePath(t *testing.T) {
+	o := newTestOrchestrator(&fakeWorktreeOps{})
+	if agents, err := o.Restore(); agents != nil || err != nil {
+		t.Errorf("Restore without StatePath = %v, %v", agents, err)
+	}
+}
+
+func TestResume(t *testing.T) {
+	wtDir := t.TempDir()
+	cfg := defaultCfg()
+	cfg.Regent.Enabled = false
+	cfg.Build.PromptFile = "BUILD.md" // missing → the resumed loop fails fast
+	o := New(cfg, &fakeWorktreeOps{switchPath: wtDir})
+	o.StatePath = StatePath(t.TempDir())
+	o.agents["feat/r"] = &WorktreeAgent{
+		Branch: "feat/r", SpecName: "001-r", State: StateStopped, Mode: loop.ModeBuild,
+		Iterations: 3, TotalCost: 1.5, WorktreePath: wtDir,
+	}
+	o.agents["feat/done"] = &WorktreeAgent{Branch: "feat/done", State: StateCompleted}
+
+	if err := o.Resume(context.Background(), "feat/done"); err == nil {
+		t.Error("expected completed agent to be rejected")
+	}
+	if err := o.Resume(context.Background(), "feat/missing"); err == nil {
+		t.Error("expected unknown branch to be rejected")
+	}
+	if err := o.Resume(context.Background(), "feat/r"); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	agent := waitAgentTerminal(t, o, "feat/r", 5*time.Second)
+	o.Wait() // the final state save must finish before TempDir cleanup
+	o.mu.Lock()
+	iterations, cost, spec := agent.Iterations, agent.TotalCost, agent.SpecName
+	o.mu.Unlock()
+	if iterations != 3 || cost != 1.5 || spec != "001-r" {
+		t.Errorf("resumed agent lost its totals: iterations=%d cost=%v spec=%q", iterations, cost, spec)
+	}
+}
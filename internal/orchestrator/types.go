@@ -3,6 +3,9 @@
 package orchestrator
 
 import (
+	"context"
+	"time"
+
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
 )
 
@@ -19,6 +22,8 @@ const (
 	StateMerged                        // worktree was merged and removed
 	StateMergeFailed                   // wt merge returned an error
 	StateRemoved                       // worktree was removed without merging
+	StateResolving                     // conflict-resolution agent is working on a failed merge
+	StateQueued                        // waiting in the launch queue for a free slot
 )
 
 func (s AgentState) String() string {
@@ -41,6 +46,10 @@ func (s AgentState) String() string {
 		return "merge_failed"
 	case StateRemoved:
 		return "removed"
+	case StateResolving:
+		return "resolving"
+	case StateQueued:
+		return "queued"
 	default:
 		return "unknown"
 	}
@@ -52,12 +61,19 @@ type WorktreeAgent struct {
 	WorktreePath string
 	SpecName     string
 	SpecDir      string
+	Mode         loop.Mode
 	State        AgentState
 	Iterations   int
 	TotalCost    float64
 	Events       chan loop.LogEntry // receives loop events; closed when loop exits
 	StopCh       chan struct{}      // close to request graceful stop
 	Error        error              // non-nil when State == StateFailed
+	StartedAt    time.Time          // when the loop was launched
+	FinishedAt   time.Time          // when the loop last exited
+	Priority     int                // launch-queue priority; higher starts first
+	QueuedAt     time.Time          // when the agent entered the launch queue
+
+	cancelResolve context.CancelFunc // cancels conflict resolution; set while StateResolving
 }
 
 // TaggedLogEntry wraps a loop.LogEntry with the source branch name so the
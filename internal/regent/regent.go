@@ -2,6 +2,7 @@ package regent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -86,6 +87,20 @@ func (r *Regent) Supervise(ctx context.Context, run RunFunc) error {
 			return ctx.Err()
 		}
 
+		if errors.Is(err, loop.ErrProtectedBranch) || errors.Is(err, loop.ErrPolicyViolation) {
+			r.mu.Lock()
+			r.state.FinishedAt = time.Now()
+			r.state.Passed = false
+			r.mu.Unlock()
+			r.saveState()
+			if errors.Is(err, loop.ErrPolicyViolation) {
+				r.emit(fmt.Sprintf("Ralph stopped by tool-use policy: %v — not retrying", err))
+			} else {
+				r.emit(fmt.Sprintf("Ralph refused to start: %v — not retrying", err))
+			}
+			return err
+		}
+
 		consecutiveErrors++
 		r.mu.Lock()
 		r.state.ConsecutiveErrs = consecutiveErrors
@@ -167,8 +182,6 @@ func (r *Regent) NotifyOutput() {
 // hang detection timer, and persists state to disk so `ralph status` shows
 // current progress during a running loop.
 func (r *Regent) UpdateState(entry loop.LogEntry) {
-	r.touchOutput()
-
 	r.mu.Lock()
 	changed := false
 	if entry.Iteration > 0 {
@@ -191,7 +204,15 @@ func (r *Regent) UpdateState(entry loop.LogEntry) {
 		r.state.Mode = entry.Mode
 		changed = true
 	}
+	if entry.Kind == loop.LogRateLimit {
+		r.state.RateLimitedUntil = entry.ResetAt
+		changed = true
+	} else if !r.state.RateLimitedUntil.IsZero() && !time.Now().Before(r.state.RateLimitedUntil) {
+		r.state.RateLimitedUntil = time.Time{}
+		changed = true
+	}
 	r.mu.Unlock()
+	r.touchOutput()
 
 	if changed {
 		r.saveState()
@@ -240,10 +261,16 @@ func (r *Regent) finishGraceful() {
 	r.saveState()
 }
 
+// touchOutput resets the hang timer. While the loop sleeps on a usage limit
+// the timer starts from the reset time, so the silent wait is not a hang.
 func (r *Regent) touchOutput() {
 	r.mu.Lock()
-	r.lastOutputAt = time.Now()
-	r.state.LastOutputAt = r.lastOutputAt
+	now := time.Now()
+	r.lastOutputAt = now
+	if r.state.RateLimitedUntil.After(now) {
+		r.lastOutputAt = r.state.RateLimitedUntil
+	}
+	r.state.LastOutputAt = now
 	r.mu.Unlock()
 }
 
@@ -3,6 +3,7 @@ package regent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -83,6 +84,59 @@ func TestSupervise(t *testing.T) {
 		}
 	})
 
+	t.Run("protected branch refusal is not retried", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := defaultTestRegentConfig()
+		cfg.MaxRetries = 2
+		events := make(chan loop.LogEntry, 128)
+		rgt := New(cfg, dir, &mockGit{branch: "main"}, events)
+
+		calls := 0
+		run := func(_ context.Context) error {
+			calls++
+			return fmt.Errorf("loop: main: %w", loop.ErrProtectedBranch)
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- rgt.Supervise(context.Background(), run)
+			close(events)
+		}()
+
+		err := <-errCh
+		if !errors.Is(err, loop.ErrProtectedBranch) {
+			t.Fatalf("expected ErrProtectedBranch, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("tool-use policy stop is not retried", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := defaultTestRegentConfig()
+		cfg.MaxRetries = 2
+		events := make(chan loop.LogEntry, 128)
+		rgt := New(cfg, dir, &mockGit{branch: "main"}, events)
+
+		calls := 0
+		run := func(_ context.Context) error {
+			calls++
+			return fmt.Errorf("loop: iteration 1: %w: rule #1 matched Bash", loop.ErrPolicyViolation)
+		}
+
+		err := rgt.Supervise(context.Background(), run)
+		if !errors.Is(err, loop.ErrPolicyViolation) {
+			t.Fatalf("expected ErrPolicyViolation, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+		if state, _ := LoadState(dir); state.Passed {
+			t.Error("a policy stop should not be recorded as passed")
+		}
+	})
+
 	t.Run("gives up after max retries", func(t *testing.T) {
 		dir := t.TempDir()
 		cfg := defaultTestRegentConfig()
@@ -514,6 +568,46 @@ func TestUpdateStatePersistsToFile(t *testing.T) {
 	}
 }
 
+func TestUpdateStateRateLimit(t *testing.T) {
+	dir := t.TempDir()
+	rgt := New(defaultTestRegentConfig(), dir, &mockGit{}, make(chan loop.LogEntry, 128))
+
+	until := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	rgt.UpdateState(loop.LogEntry{Kind: loop.LogRateLimit, ResetAt: until})
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if !state.RateLimitedUntil.Equal(until) {
+		t.Errorf("RateLimitedUntil = %v, want %v", state.RateLimitedUntil, until)
+	}
+	rgt.mu.Lock()
+	lastOutput := rgt.lastOutputAt
+	rgt.mu.Unlock()
+	if !lastOutput.Equal(until) {
+		t.Errorf("hang timer starts at %v, want the reset time %v", lastOutput, until)
+	}
+
+	// Output before the reset keeps the pause; output after it clears it.
+	rateLimitedUntil := func() time.Time {
+		rgt.mu.Lock()
+		defer rgt.mu.Unlock()
+		return rgt.state.RateLimitedUntil
+	}
+	rgt.UpdateState(loop.LogEntry{Kind: loop.LogInfo, Iteration: 2})
+	if rateLimitedUntil().IsZero() {
+		t.Error("RateLimitedUntil cleared before the reset time")
+	}
+	rgt.mu.Lock()
+	rgt.state.RateLimitedUntil = time.Now().Add(-time.Second)
+	rgt.mu.Unlock()
+	rgt.UpdateState(loop.LogEntry{Kind: loop.LogInfo, Message: "Usage limit lifted — resuming"})
+	if !rateLimitedUntil().IsZero() {
+		t.Error("RateLimitedUntil should clear once the limit has lifted")
+	}
+}
+
 func TestUpdateStateSkipsSaveOnNoChange(t *testing.T) {
 	dir := t.TempDir()
 	cfg := defaultTestRegentConfig()
@@ -23,6 +23,10 @@ type State struct {
 	StartedAt       time.Time `json:"started_at"`
 	FinishedAt      time.Time `json:"finished_at"`
 	Passed          bool      `json:"passed"`
+
+	// RateLimitedUntil is when the Claude usage limit the loop is sleeping
+	// on lifts; zero when the loop is not rate-limited.
+	RateLimitedUntil time.Time `json:"rate_limited_until,omitempty"`
 }
 
 // stateFileName is the path within the .ralph directory.
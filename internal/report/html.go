@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// htmlStyle is inlined so the report is a single self-contained file.
+const htmlStyle = `body{font-family:-apple-system,"Segoe UI",Helvetica,Arial,sans-serif;max-width:60em;margin:2em auto;padding:0 1em;color:#222;line-height:1.5}
+h1{border-bottom:2px solid #7D56F4;padding-bottom:.3em}
+table{border-collapse:collapse;margin:1em 0}
+th,td{border:1px solid #ddd;padding:.3em .7em;text-align:left}
+th{background:#f4f1fe}
+code{background:#f4f4f4;padding:0 .3em;border-radius:3px}
+blockquote{border-left:4px solid #ddd;margin:0;padding:0 1em;color:#555;white-space:pre-wrap}
+.rollback,.hang,.error,.failed{color:#c0392b}
+.retry{color:#d68910}
+.passed{color:#1e8449}
+footer{color:#888;margin-top:2em;font-size:.9em}`
+
+// HTML renders the report as a self-contained HTML document.
+func (r Report) HTML() string {
+	var b strings.Builder
+	esc := html.EscapeString
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", esc(r.Title()), htmlStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", esc(r.Title()))
+
+	b.WriteString("<h2>Summary</h2>\n<ul>\n")
+	if r.Spec != "" {
+		fmt.Fprintf(&b, "<li>Spec: <code>%s</code></li>\n", esc(r.Spec))
+	}
+	if r.Branch != "" {
+		fmt.Fprintf(&b, "<li>Branch: <code>%s</code></li>\n", esc(r.Branch))
+	}
+	fmt.Fprintf(&b, "<li>Started: %s</li>\n", r.StartedAt.Local().Format(timeLayout))
+	if !r.EndedAt.IsZero() {
+		fmt.Fprintf(&b, "<li>Last event: %s</li>\n", r.EndedAt.Local().Format(timeLayout))
+	}
+	fmt.Fprintf(&b, "<li>Iterations: %d</li>\n", len(r.Iterations))
+	fmt.Fprintf(&b, "<li>Total cost: $%.2f</li>\n", r.TotalCost)
+	if passed, failed := r.TestCounts(); passed+failed > 0 {
+		fmt.Fprintf(&b, "<li>Tests: %d passed, %d failed</li>\n", passed, failed)
+	}
+	if r.Outcome != "" {
+		fmt.Fprintf(&b, "<li>Outcome: %s</li>\n", esc(r.Outcome))
+	}
+	b.WriteString("</ul>\n")
+
+	if len(r.Iterations) > 0 {
+		b.WriteString("<h2>Timeline</h2>\n<table>\n<tr><th>#</th><th>Mode</th><th>Result</th><th>Cost</th><th>Duration</th><th>Finished</th><th>Files</th></tr>\n")
+		for _, it := range r.Iterations {
+			fmt.Fprintf(&b, "<tr><td>%d</td><td>%s</td><td>%s</td><td>$%.2f</td><td>%s</td><td>%s</td><td>%d</td></tr>\n",
+				it.Number, esc(it.Mode), esc(it.Subtype), it.CostUSD, formatDuration(it.Duration),
+				it.EndAt.Local().Format("15:04:05"), it.Files)
+		}
+		b.WriteString("</table>\n")
+	}
+
+	if commits := r.commitLines(); len(commits) > 0 {
+		b.WriteString("<h2>Commits</h2>\n<ul>\n")
+		for _, c := range commits {
+			fmt.Fprintf(&b, "<li><code>%s</code> %s <em>(iteration %d)</em></li>\n", esc(c.sha), esc(c.subject), c.iteration)
+		}
+		b.WriteString("</ul>\n")
+	}
+
+	writeHTMLEvents(&b, "Regent actions", r.Regent)
+	writeHTMLEvents(&b, "Test runs", r.Tests)
+
+	if len(r.Tools) > 0 {
+		b.WriteString("<h2>Tool usage</h2>\n<table>\n<tr><th>Tool</th><th>Calls</th></tr>\n")
+		for _, t := range r.Tools {
+			fmt.Fprintf(&b, "<tr><td>%s</td><td>%d</td></tr>\n", esc(t.Name), t.Calls)
+		}
+		b.WriteString("</table>\n")
+	}
+
+	var final bool
+	for _, it := range r.Iterations {
+		if it.FinalMessage == "" {
+			continue
+		}
+		if !final {
+			b.WriteString("<h2>Final messages</h2>\n")
+			final = true
+		}
+		fmt.Fprintf(&b, "<h3>Iteration %d</h3>\n<blockquote>%s</blockquote>\n", it.Number, esc(it.FinalMessage))
+	}
+
+	b.WriteString("<footer>Generated by RalphSpec.</footer>\n</body>\n</html>\n")
+	return b.String()
+}
+
+func writeHTMLEvents(b *strings.Builder, heading string, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "<h2>%s</h2>\n<ul>\n", heading)
+	for _, e := range events {
+		fmt.Fprintf(b, "<li>%s <strong class=\"%s\">%s</strong> %s</li>\n",
+			e.At.Local().Format("15:04:05"), e.Kind, e.Kind, html.EscapeString(e.Message))
+	}
+	b.WriteString("</ul>\n")
+}
@@ -0,0 +1,30 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	r := Build(fixture())
+	r.Iterations[0].FinalMessage = "Use <b>bold</b> & ship"
+	doc := r.HTML()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<style>",
+		"<title>Ralph session 100-1 — 001-auth</title>",
+		"<li>Total cost: $0.75</li>",
+		"<td>1</td><td>build</td><td>success</td><td>$0.50</td><td>1m30s</td>",
+		"<li><code>bbbbbbb</code> add login <em>(iteration 1)</em></li>",
+		`<strong class="hang">hang</strong>`,
+		"<tr><td>Read</td><td>2</td></tr>",
+		"Use &lt;b&gt;bold&lt;/b&gt; &amp; ship",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("html missing %q:\n%s", want, doc)
+		}
+	}
+	if strings.Contains(doc, "<b>bold</b>") {
+		t.Error("messages must be escaped")
+	}
+}
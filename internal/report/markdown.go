@@ -0,0 +1,138 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// timeLayout formats timestamps in reports.
+const timeLayout = "2006-01-02 15:04"
+
+// Markdown renders the report as a self-contained Markdown document.
+func (r Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", r.Title())
+
+	b.WriteString("## Summary\n\n")
+	if r.Spec != "" {
+		fmt.Fprintf(&b, "- Spec: `%s`\n", r.Spec)
+	}
+	if r.Branch != "" {
+		fmt.Fprintf(&b, "- Branch: `%s`\n", r.Branch)
+	}
+	fmt.Fprintf(&b, "- Started: %s\n", r.StartedAt.Local().Format(timeLayout))
+	if !r.EndedAt.IsZero() {
+		fmt.Fprintf(&b, "- Last event: %s\n", r.EndedAt.Local().Format(timeLayout))
+	}
+	fmt.Fprintf(&b, "- Iterations: %d\n", len(r.Iterations))
+	fmt.Fprintf(&b, "- Total cost: $%.2f\n", r.TotalCost)
+	if passed, failed := r.TestCounts(); passed+failed > 0 {
+		fmt.Fprintf(&b, "- Tests: %d passed, %d failed\n", passed, failed)
+	}
+	if r.Outcome != "" {
+		fmt.Fprintf(&b, "- Outcome: %s\n", r.Outcome)
+	}
+	b.WriteString("\n")
+
+	if len(r.Iterations) > 0 {
+		b.WriteString("## Timeline\n\n")
+		b.WriteString("| # | Mode | Result | Cost | Duration | Finished | Files |\n")
+		b.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, it := range r.Iterations {
+			fmt.Fprintf(&b, "| %d | %s | %s | $%.2f | %s | %s | %d |\n",
+				it.Number, it.Mode, it.Subtype, it.CostUSD, formatDuration(it.Duration),
+				it.EndAt.Local().Format("15:04:05"), it.Files)
+		}
+		b.WriteString("\n")
+	}
+
+	if commits := r.commitLines(); len(commits) > 0 {
+		b.WriteString("## Commits\n\n")
+		for _, c := range commits {
+			fmt.Fprintf(&b, "- `%s` %s _(iteration %d)_\n", c.sha, mdInline(c.subject), c.iteration)
+		}
+		b.WriteString("\n")
+	}
+
+	writeEvents(&b, "Regent actions", r.Regent)
+	writeEvents(&b, "Test runs", r.Tests)
+
+	if len(r.Tools) > 0 {
+		b.WriteString("## Tool usage\n\n")
+		b.WriteString("| Tool | Calls |\n|---|---|\n")
+		for _, t := range r.Tools {
+			fmt.Fprintf(&b, "| %s | %d |\n", mdCell(t.Name), t.Calls)
+		}
+		b.WriteString("\n")
+	}
+
+	var final bool
+	for _, it := range r.Iterations {
+		if it.FinalMessage == "" {
+			continue
+		}
+		if !final {
+			b.WriteString("## Final messages\n\n")
+			final = true
+		}
+		fmt.Fprintf(&b, "### Iteration %d\n\n", it.Number)
+		for _, line := range strings.Split(it.FinalMessage, "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("---\n_Generated by RalphSpec._\n")
+	return b.String()
+}
+
+func writeEvents(b *strings.Builder, heading string, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n", heading)
+	for _, e := range events {
+		fmt.Fprintf(b, "- %s **%s** %s\n", e.At.Local().Format("15:04:05"), e.Kind, mdInline(e.Message))
+	}
+	b.WriteString("\n")
+}
+
+// commitLine is one commit in the Commits section.
+type commitLine struct {
+	sha       string
+	subject   string
+	iteration int
+}
+
+// commitLines lists the commits each iteration produced, falling back to
+// the iteration's head commit when its range could not be listed.
+func (r Report) commitLines() []commitLine {
+	var lines []commitLine
+	for _, it := range r.Iterations {
+		if len(it.Commits) > 0 {
+			for _, c := range it.Commits {
+				lines = append(lines, commitLine{shortSHA(c.SHA), c.Subject(), it.Number})
+			}
+			continue
+		}
+		if it.HeadCommit == "" {
+			continue // the iteration committed nothing
+		}
+		line := commitLine{sha: shortSHA(it.HeadCommit), iteration: it.Number}
+		if sha, subject, _ := strings.Cut(it.Commit, " "); sha != "" && strings.HasPrefix(it.HeadCommit, sha) {
+			line.subject = subject
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// mdCell keeps pipe characters from breaking a table row.
+func mdCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// mdInline keeps a message on one Markdown line.
+func mdInline(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+func TestMarkdown(t *testing.T) {
+	r := Build(fixture())
+	r.AddCommits(func(string, string) ([]git.Commit, error) {
+		return []git.Commit{{SHA: "cccccccccc", Message: "feat: login | logout"}}, nil
+	})
+	md := r.Markdown()
+	for _, want := range []string{
+		"# Ralph session 100-1 — 001-auth",
+		"- Spec: `001-auth`",
+		"- Total cost: $0.75",
+		"- Tests: 1 passed, 0 failed",
+		"- Outcome: Loop complete",
+		"| 1 | build | success | $0.50 | 1m30s |",
+		"| 2 | build | error_max_turns | $0.25 | 30s |",
+		"- `ccccccc` feat: login | logout _(iteration 1)_",
+		"**hang** Hang detected",
+		"**retry** Retrying in 2s",
+		"**passed** Tests passed",
+		"| Read | 2 |",
+		"### Iteration 1\n\n> Login works; tests added.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Running tests") || strings.Contains(md, "Starting Ralph") {
+		t.Errorf("routine Regent messages should be left out:\n%s", md)
+	}
+}
+
+func TestMarkdown_Empty(t *testing.T) {
+	md := Report{SessionID: "1-1"}.Markdown()
+	for _, absent := range []string{"## Timeline", "## Commits", "## Tool usage", "## Final messages", "Tests:"} {
+		if strings.Contains(md, absent) {
+			t.Errorf("empty report should omit %q:\n%s", absent, md)
+		}
+	}
+}
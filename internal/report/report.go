@@ -0,0 +1,192 @@
+// Package report renders a self-contained Markdown or HTML report of one
+// ralph session from its JSONL log: the iteration timeline, commits, Regent
+// actions, test results, tool usage and each iteration's final message.
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// Report is everything a session report shows.
+type Report struct {
+	SessionID  string
+	Spec       string // "" when the session ran without an active spec
+	Branch     string
+	StartedAt  time.Time
+	EndedAt    time.Time
+	TotalCost  float64
+	Outcome    string // message of the entry that ended the loop, if any
+	Iterations []Iteration
+	Regent     []Event     // rollbacks, retries, hangs and crashes
+	Tests      []Event     // Regent test runs
+	Tools      []ToolCount // most-used first
+}
+
+// Iteration is one completed iteration with what it produced.
+type Iteration struct {
+	store.IterationSummary
+	Commits      []git.Commit // filled by AddCommits; nil when git is unavailable
+	Files        int          // files changed by the iteration's commits
+	FinalMessage string       // the agent's last text output in the iteration
+}
+
+// Event is a timestamped Regent message.
+type Event struct {
+	At      time.Time
+	Kind    string // "rollback", "retry", "hang", "error", "passed", "failed"
+	Message string
+}
+
+// ToolCount is how often a tool was called in the session.
+type ToolCount struct {
+	Name  string
+	Calls int
+}
+
+// regentKinds classifies Regent messages by prefix. Messages that match
+// none (start-up, shutdown, "Running tests") are routine and left out.
+var regentKinds = []struct {
+	prefix string
+	kind   string
+	test   bool
+}{
+	{"Tests passed", "passed", true},
+	{"Tests failed", "failed", true},
+	{"Failed to start tests", "failed", true},
+	{"Reverted commit", "rollback", false},
+	{"Failed to revert", "rollback", false},
+	{"Retrying", "retry", false},
+	{"Max retries", "retry", false},
+	{"Hang detected", "hang", false},
+	{"Ralph exited with error", "error", false},
+	{"Ralph stopped by tool-use policy", "error", false},
+	{"Ralph refused to start", "error", false},
+}
+
+// Build assembles the report for the session whose summary, completed
+// iterations and full event log are given.
+func Build(sum store.SessionSummary, iters []store.IterationSummary, entries []loop.LogEntry) Report {
+	r := Report{
+		SessionID: sum.SessionID,
+		Branch:    sum.Branch,
+		StartedAt: sum.StartedAt,
+		TotalCost: sum.TotalCost,
+	}
+	byNumber := make(map[int]int, len(iters))
+	for i, it := range iters {
+		r.Iterations = append(r.Iterations, Iteration{IterationSummary: it})
+		byNumber[it.Number] = i
+		if r.Spec == "" {
+			r.Spec = it.Spec
+		}
+	}
+
+	tools := map[string]int{}
+	current := 0 // iteration the entries belong to; 0 between iterations
+	for _, e := range entries {
+		if !e.Timestamp.IsZero() {
+			r.EndedAt = e.Timestamp
+		}
+		switch e.Kind {
+		case loop.LogIterStart:
+			current = e.Iteration
+		case loop.LogIterComplete:
+			current = 0
+		case loop.LogToolUse:
+			tools[e.ToolName]++
+		case loop.LogText:
+			if i, ok := byNumber[current]; ok && strings.TrimSpace(e.Message) != "" {
+				r.Iterations[i].FinalMessage = strings.TrimSpace(e.Message)
+			}
+		case loop.LogFiles:
+			if i, ok := byNumber[e.Iteration]; ok {
+				r.Iterations[i].Files = len(e.Files)
+			}
+		case loop.LogRegent:
+			r.addRegent(e)
+		case loop.LogDone, loop.LogStopped, loop.LogSpecComplete, loop.LogSweepComplete, loop.LogPaused:
+			r.Outcome = e.Message
+		}
+	}
+
+	for name, n := range tools {
+		r.Tools = append(r.Tools, ToolCount{Name: name, Calls: n})
+	}
+	sort.Slice(r.Tools, func(i, j int) bool {
+		if r.Tools[i].Calls != r.Tools[j].Calls {
+			return r.Tools[i].Calls > r.Tools[j].Calls
+		}
+		return r.Tools[i].Name < r.Tools[j].Name
+	})
+	return r
+}
+
+// addRegent records a Regent message under Tests or Regent when it is one
+// of the classified kinds.
+func (r *Report) addRegent(e loop.LogEntry) {
+	for _, k := range regentKinds {
+		if !strings.HasPrefix(e.Message, k.prefix) {
+			continue
+		}
+		ev := Event{At: e.Timestamp, Kind: k.kind, Message: e.Message}
+		if k.test {
+			r.Tests = append(r.Tests, ev)
+		} else {
+			r.Regent = append(r.Regent, ev)
+		}
+		return
+	}
+}
+
+// AddCommits fills each iteration's commits from its recorded commit range
+// using list (typically git.Runner.CommitsBetween). Ranges that cannot be
+// listed, e.g. because the commits were rebased away, are left empty.
+func (r *Report) AddCommits(list func(from, to string) ([]git.Commit, error)) {
+	for i, it := range r.Iterations {
+		if it.BaseCommit == "" || it.HeadCommit == "" {
+			continue
+		}
+		if commits, err := list(it.BaseCommit, it.HeadCommit); err == nil {
+			r.Iterations[i].Commits = commits
+		}
+	}
+}
+
+// TestCounts returns how many Regent test runs passed and failed.
+func (r Report) TestCounts() (passed, failed int) {
+	for _, t := range r.Tests {
+		if t.Kind == "passed" {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// Title is the report's heading.
+func (r Report) Title() string {
+	if r.Spec != "" {
+		return "Ralph session " + r.SessionID + " — " + r.Spec
+	}
+	return "Ralph session " + r.SessionID
+}
+
+// formatDuration renders seconds as a compact duration (e.g. "1m30s").
+func formatDuration(secs float64) string {
+	return time.Duration(secs * float64(time.Second)).Round(time.Second).String()
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
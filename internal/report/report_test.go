@@ -0,0 +1,112 @@
+package report
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+var t0 = time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+
+// fixture returns a two-iteration session: the first commits and passes
+// its tests, the second hangs and is retried.
+func fixture() (store.SessionSummary, []store.IterationSummary, []loop.LogEntry) {
+	sum := store.SessionSummary{SessionID: "100-1", Branch: "001-auth", StartedAt: t0, TotalCost: 0.75}
+	iters := []store.IterationSummary{
+		{Number: 1, Mode: "build", Subtype: "success", CostUSD: 0.5, Duration: 90, Spec: "001-auth",
+			Commit: "bbbbbbb add login", BaseCommit: "aaaaaaaaaa", HeadCommit: "bbbbbbbbbb", EndAt: t0.Add(2 * time.Minute)},
+		{Number: 2, Mode: "build", Subtype: "error_max_turns", CostUSD: 0.25, Duration: 30, Spec: "001-auth", EndAt: t0.Add(5 * time.Minute)},
+	}
+	entries := []loop.LogEntry{
+		{Kind: loop.LogRegent, Message: "Starting Ralph (attempt 1/4)", Timestamp: t0},
+		{Kind: loop.LogIterStart, Iteration: 1, Timestamp: t0},
+		{Kind: loop.LogToolUse, Iteration: 1, ToolName: "Read", Timestamp: t0},
+		{Kind: loop.LogToolUse, Iteration: 1, ToolName: "Edit", Timestamp: t0},
+		{Kind: loop.LogToolUse, Iteration: 1, ToolName: "Read", Timestamp: t0},
+		{Kind: loop.LogText, Message: "Reading the handler", Timestamp: t0},
+		{Kind: loop.LogText, Message: "Login works; tests added.", Timestamp: t0},
+		{Kind: loop.LogIterComplete, Iteration: 1, Timestamp: t0},
+		{Kind: loop.LogFiles, Iteration: 1, Files: []loop.ChangedFile{{Path: "a.go"}, {Path: "b.go"}}, Timestamp: t0},
+		{Kind: loop.LogRegent, Message: "Running tests: go test ./...", Timestamp: t0},
+		{Kind: loop.LogRegent, Message: "Tests passed ✅ — commit bbbbbbb kept", Timestamp: t0},
+		{Kind: loop.LogText, Message: "between iterations", Timestamp: t0},
+		{Kind: loop.LogIterStart, Iteration: 2, Timestamp: t0},
+		{Kind: loop.LogToolUse, Iteration: 2, ToolName: "Bash", Timestamp: t0},
+		{Kind: loop.LogIterComplete, Iteration: 2, Timestamp: t0},
+		{Kind: loop.LogRegent, Message: "Hang detected — no output for 5m0s — killing loop", Timestamp: t0.Add(6 * time.Minute)},
+		{Kind: loop.LogRegent, Message: "Retrying in 2s (attempt 2/4)", Timestamp: t0.Add(6 * time.Minute)},
+		{Kind: loop.LogDone, Message: "Loop complete", Timestamp: t0.Add(7 * time.Minute)},
+	}
+	return sum, iters, entries
+}
+
+func TestBuild(t *testing.T) {
+	r := Build(fixture())
+	if r.SessionID != "100-1" || r.Spec != "001-auth" || r.Branch != "001-auth" || r.TotalCost != 0.75 {
+		t.Errorf("header = %+v", r)
+	}
+	if !r.EndedAt.Equal(t0.Add(7*time.Minute)) || r.Outcome != "Loop complete" {
+		t.Errorf("EndedAt = %v, Outcome = %q", r.EndedAt, r.Outcome)
+	}
+	if len(r.Iterations) != 2 {
+		t.Fatalf("Iterations = %d, want 2", len(r.Iterations))
+	}
+	if got := r.Iterations[0]; got.Files != 2 || got.FinalMessage != "Login works; tests added." {
+		t.Errorf("iteration 1 = files %d, final %q", got.Files, got.FinalMessage)
+	}
+	if got := r.Iterations[1].FinalMessage; got != "" {
+		t.Errorf("iteration 2 final message = %q; text between iterations must not leak in", got)
+	}
+	if len(r.Tools) != 3 || r.Tools[0] != (ToolCount{"Read", 2}) || r.Tools[1].Name != "Bash" {
+		t.Errorf("Tools = %+v, want Read first then by name", r.Tools)
+	}
+	if len(r.Tests) != 1 || r.Tests[0].Kind != "passed" {
+		t.Errorf("Tests = %+v", r.Tests)
+	}
+	if len(r.Regent) != 2 || r.Regent[0].Kind != "hang" || r.Regent[1].Kind != "retry" {
+		t.Errorf("Regent = %+v, want hang then retry (routine messages skipped)", r.Regent)
+	}
+	if passed, failed := r.TestCounts(); passed != 1 || failed != 0 {
+		t.Errorf("TestCounts = %d, %d", passed, failed)
+	}
+}
+
+func TestAddCommits(t *testing.T) {
+	r := Build(fixture())
+	var calls int
+	r.AddCommits(func(from, to string) ([]git.Commit, error) {
+		calls++
+		if from != "aaaaaaaaaa" || to != "bbbbbbbbbb" {
+			t.Errorf("range = %s..%s", from, to)
+		}
+		return []git.Commit{{SHA: "cccccccccc", Message: "feat: login\n\nbody"}}, nil
+	})
+	if calls != 1 {
+		t.Errorf("list called %d times, want once (iteration 2 committed nothing)", calls)
+	}
+	if len(r.Iterations[0].Commits) != 1 {
+		t.Errorf("commits = %+v", r.Iterations[0].Commits)
+	}
+
+	r = Build(fixture())
+	r.AddCommits(func(string, string) ([]git.Commit, error) { return nil, errors.New("unknown revision") })
+	if r.Iterations[0].Commits != nil {
+		t.Error("an unlistable range should leave Commits empty")
+	}
+	if lines := r.commitLines(); len(lines) != 1 || lines[0].sha != "bbbbbbb" || lines[0].subject != "add login" {
+		t.Errorf("fallback commit lines = %+v", lines)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	if got := (Report{SessionID: "1-2", Spec: "001-auth"}).Title(); got != "Ralph session 1-2 — 001-auth" {
+		t.Errorf("Title = %q", got)
+	}
+	if got := (Report{SessionID: "1-2"}).Title(); got != "Ralph session 1-2" {
+		t.Errorf("Title without spec = %q", got)
+	}
+}
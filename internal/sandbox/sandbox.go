@@ -0,0 +1,296 @@
+// Package sandbox wraps the Claude CLI command line so the agent runs inside
+// a bubblewrap jail or a rootless podman/docker container. Only the working
+// directory (project or worktree) is writable, except the repository's git
+// hooks and config; toolchains and an allowlist of home-directory paths are
+// mounted as configured in [sandbox].
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+)
+
+// ErrRuntimeNotFound is returned when the configured sandbox runtime is not
+// installed or not on PATH.
+var ErrRuntimeNotFound = errors.New("sandbox: runtime not found")
+
+// systemDirs are mounted read-only into a bwrap jail so the agent can run
+// system binaries, shared libraries, and read /etc (DNS, CA certificates).
+var systemDirs = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc", "/opt"}
+
+// Sandbox builds sandboxed command lines from a [sandbox] config.
+type Sandbox struct {
+	cfg      config.SandboxConfig
+	home     string
+	uid, gid int
+	lookPath func(string) (string, error)
+}
+
+// New returns a Sandbox for cfg, or nil when sandboxing is disabled.
+func New(cfg config.SandboxConfig) *Sandbox {
+	if !cfg.Enabled {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	return &Sandbox{
+		cfg:      cfg,
+		home:     home,
+		uid:      os.Getuid(),
+		gid:      os.Getgid(),
+		lookPath: exec.LookPath,
+	}
+}
+
+// Check reports whether the sandbox runtime is installed.
+func (s *Sandbox) Check() error {
+	if _, err := s.lookPath(s.cfg.Runtime); err != nil {
+		return fmt.Errorf("%w: %s is not installed or not on PATH (install it or set [sandbox] enabled = false)",
+			ErrRuntimeNotFound, s.cfg.Runtime)
+	}
+	return nil
+}
+
+// Container reports whether the runtime is a container engine. Stopping a
+// container needs a signal forwarded by the engine's client rather than a
+// kill, so callers should interrupt the client and give it time to exit.
+func (s *Sandbox) Container() bool {
+	return s.cfg.Runtime == "podman" || s.cfg.Runtime == "docker"
+}
+
+// Wrap returns the command line that runs argv inside the sandbox with dir
+// as its writable working directory. An empty dir means the current one.
+func (s *Sandbox) Wrap(dir string, argv []string) ([]string, error) {
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("sandbox: empty command")
+	}
+	if err := s.Check(); err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("sandbox: get working directory: %w", err)
+		}
+		dir = wd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: resolve %s: %w", dir, err)
+	}
+
+	// A linked worktree keeps its objects and refs in the main repository's
+	// .git directory, which must be writable for the agent to commit.
+	writable := []string{dir}
+	if common := gitCommonDir(dir); common != "" && !within(common, dir) {
+		writable = append(writable, common)
+	}
+	readOnly := gitProtectedPaths(dir)
+
+	if s.Container() {
+		return s.containerArgs(dir, writable, readOnly, argv), nil
+	}
+	return s.bwrapArgs(dir, writable, readOnly, argv)
+}
+
+// bwrapArgs builds a bubblewrap command line: system directories and extra
+// mounts read-only, allowlisted home paths, and the writable directories with
+// readOnly paths inside them remounted read-only.
+func (s *Sandbox) bwrapArgs(dir string, writable, readOnly, argv []string) ([]string, error) {
+	exe, err := s.lookPath(argv[0])
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: %s not found on PATH: %w", argv[0], err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if exe, err = filepath.Abs(exe); err != nil {
+		return nil, fmt.Errorf("sandbox: resolve %s: %w", argv[0], err)
+	}
+
+	args := []string{s.cfg.Runtime, "--die-with-parent", "--new-session", "--unshare-all"}
+	if s.cfg.Network != "none" {
+		args = append(args, "--share-net")
+	}
+	for _, d := range systemDirs {
+		args = append(args, "--ro-bind-try", d, d)
+	}
+	args = append(args, "--proc", "/proc", "--dev", "/dev", "--tmpfs", "/tmp")
+	for _, m := range s.cfg.ReadOnlyMounts {
+		args = append(args, "--ro-bind-try", m, m)
+	}
+	if s.home != "" {
+		args = append(args, "--setenv", "HOME", s.home)
+		for _, h := range s.homeMounts() {
+			flag := "--ro-bind-try"
+			if h.rw {
+				flag = "--bind-try"
+			}
+			args = append(args, flag, h.path, h.path)
+		}
+	}
+	args = append(args, "--ro-bind", exe, exe)
+	for _, w := range writable {
+		args = append(args, "--bind", w, w)
+	}
+	for _, r := range readOnly {
+		args = append(args, "--ro-bind", r, r)
+	}
+	args = append(args, "--chdir", dir, "--")
+	args = append(args, exe)
+	return append(args, argv[1:]...), nil
+}
+
+// containerArgs builds a `podman run` or `docker run` command line. The
+// container runs as the calling user with no capabilities, and paths keep
+// their host locations so logs and errors read the same inside and out.
+func (s *Sandbox) containerArgs(dir string, writable, readOnly, argv []string) []string {
+	args := []string{s.cfg.Runtime, "run", "--rm", "-i", "--init",
+		"--cap-drop", "ALL", "--security-opt", "no-new-privileges"}
+	switch s.cfg.Network {
+	case "full":
+	case "none":
+		args = append(args, "--network", "none")
+	default:
+		args = append(args, "--network", s.cfg.Network)
+	}
+	if s.cfg.Runtime == "podman" {
+		args = append(args, "--userns", "keep-id")
+	} else if s.uid >= 0 {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", s.uid, s.gid))
+	}
+	for _, name := range s.cfg.Env {
+		args = append(args, "-e", name)
+	}
+	for _, m := range s.cfg.ReadOnlyMounts {
+		args = append(args, "-v", m+":"+m+":ro")
+	}
+	if s.home != "" {
+		args = append(args, "-e", "HOME="+s.home)
+		for _, h := range s.homeMounts() {
+			// The engine would create a missing source as a root-owned
+			// directory, so only existing paths are mounted.
+			if _, err := os.Stat(h.path); err != nil {
+				continue
+			}
+			mount := h.path + ":" + h.path
+			if !h.rw {
+				mount += ":ro"
+			}
+			args = append(args, "-v", mount)
+		}
+	}
+	for _, w := range writable {
+		args = append(args, "-v", w+":"+w)
+	}
+	for _, r := range readOnly {
+		args = append(args, "-v", r+":"+r+":ro")
+	}
+	args = append(args, "-w", dir, s.cfg.Image, filepath.Base(argv[0]))
+	return append(args, argv[1:]...)
+}
+
+type homeMount struct {
+	path string
+	rw   bool
+}
+
+// homeMounts resolves [sandbox] home_allowlist entries against the home
+// directory. A ":rw" suffix makes the mount writable.
+func (s *Sandbox) homeMounts() []homeMount {
+	mounts := make([]homeMount, 0, len(s.cfg.HomeAllowlist))
+	for _, entry := range s.cfg.HomeAllowlist {
+		rel, rw := strings.CutSuffix(entry, ":rw")
+		mounts = append(mounts, homeMount{path: filepath.Join(s.home, rel), rw: rw})
+	}
+	return mounts
+}
+
+// gitProtectedPaths returns the hooks directory and config files of the git
+// directory serving dir and, for a linked worktree, the config files of its
+// own gitdir (<common>/worktrees/<name>, read with extensions.worktreeConfig).
+// The host runs git on this repository after the agent exits, so a hook,
+// core.hooksPath or core.fsmonitor planted there would run outside the
+// sandbox; these paths are mounted read-only. Missing ones are created first
+// so the agent cannot create them instead.
+func gitProtectedPaths(dir string) []string {
+	gitDir := gitCommonDir(dir)
+	if gitDir == "" {
+		gitDir = filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+			return nil
+		}
+	}
+	var paths []string
+	if hooks := filepath.Join(gitDir, "hooks"); os.MkdirAll(hooks, 0o755) == nil {
+		paths = append(paths, hooks)
+	}
+	configDirs := []string{gitDir}
+	if own := linkedGitDir(dir); own != "" && own != gitDir {
+		configDirs = append(configDirs, own)
+	}
+	for _, d := range configDirs {
+		for _, name := range []string{"config", "config.worktree"} {
+			if p := filepath.Join(d, name); ensureFile(p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// ensureFile creates path as an empty file if it does not exist and reports
+// whether it exists afterwards.
+func ensureFile(path string) bool {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return false
+	}
+	return f.Close() == nil
+}
+
+// linkedGitDir returns the gitdir named by dir's .git file when dir is a
+// linked worktree, or "" otherwise.
+func linkedGitDir(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return "" // no .git, or a directory: the repository is inside dir
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitdir = strings.TrimSpace(gitdir)
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(dir, gitdir)
+	}
+	return filepath.Clean(gitdir)
+}
+
+// gitCommonDir returns the main repository's .git directory when dir is a
+// linked worktree (its .git is a "gitdir: ..." file), or "" otherwise.
+func gitCommonDir(dir string) string {
+	gitdir := linkedGitDir(dir)
+	if gitdir == "" {
+		return ""
+	}
+	common := gitdir
+	if data, err := os.ReadFile(filepath.Join(gitdir, "commondir")); err == nil {
+		common = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitdir, common)
+		}
+	}
+	return filepath.Clean(common)
+}
+
+// within reports whether path is dir or inside it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,300 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+)
+
+// testSandbox returns a Sandbox whose runtime and claude binary resolve to
+// fixed paths, with home in a temp directory.
+func testSandbox(t *testing.T, cfg config.SandboxConfig) *Sandbox {
+	t.Helper()
+	cfg.Enabled = true
+	s := New(cfg)
+	s.home = t.TempDir()
+	s.uid, s.gid = 1000, 1000
+	s.lookPath = func(name string) (string, error) {
+		switch name {
+		case cfg.Runtime:
+			return "/usr/bin/" + name, nil
+		case "claude":
+			return "/usr/bin/claude", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	return s
+}
+
+// hasSeq reports whether args contains want as a contiguous run.
+func hasSeq(args []string, want ...string) bool {
+	for i := 0; i+len(want) <= len(args); i++ {
+		if slices.Equal(args[i:i+len(want)], want) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNew_Disabled(t *testing.T) {
+	if s := New(config.SandboxConfig{Runtime: "bwrap"}); s != nil {
+		t.Errorf("New() with sandbox disabled = %+v, want nil", s)
+	}
+}
+
+func TestCheck_RuntimeMissing(t *testing.T) {
+	s := testSandbox(t, config.SandboxConfig{Runtime: "bwrap", Network: "full"})
+	s.lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	err := s.Check()
+	if !errors.Is(err, ErrRuntimeNotFound) {
+		t.Fatalf("Check() = %v, want ErrRuntimeNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "bwrap") || !strings.Contains(err.Error(), "enabled = false") {
+		t.Errorf("error should name the runtime and the way out: %v", err)
+	}
+	if _, err := s.Wrap(t.TempDir(), []string{"claude", "-p", "x"}); !errors.Is(err, ErrRuntimeNotFound) {
+		t.Errorf("Wrap() = %v, want ErrRuntimeNotFound", err)
+	}
+}
+
+func TestWrap_Bwrap(t *testing.T) {
+	s := testSandbox(t, config.SandboxConfig{
+		Runtime:        "bwrap",
+		Network:        "full",
+		ReadOnlyMounts: []string{"/usr/local/go"},
+		HomeAllowlist:  []string{".claude:rw", ".gitconfig"},
+	})
+	dir := t.TempDir()
+
+	args, err := s.Wrap(dir, []string{"claude", "-p", "prompt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := [][]string{
+		{"bwrap", "--die-with-parent"},
+		{"--unshare-all"},
+		{"--share-net"},
+		{"--ro-bind-try", "/usr", "/usr"},
+		{"--ro-bind-try", "/usr/local/go", "/usr/local/go"},
+		{"--bind-try", filepath.Join(s.home, ".claude"), filepath.Join(s.home, ".claude")},
+		{"--ro-bind-try", filepath.Join(s.home, ".gitconfig"), filepath.Join(s.home, ".gitconfig")},
+		{"--bind", dir, dir},
+		{"--chdir", dir, "--", "/usr/bin/claude", "-p", "prompt"},
+	}
+	for _, want := range checks {
+		if !hasSeq(args, want...) {
+			t.Errorf("args missing %q:\n%q", want, args)
+		}
+	}
+}
+
+func TestWrap_BwrapNoNetwork(t *testing.T) {
+	s := testSandbox(t, config.SandboxConfig{Runtime: "bwrap", Network: "none"})
+	args, err := s.Wrap(t.TempDir(), []string{"claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(args, "--share-net") {
+		t.Errorf("network = none should not share the network: %q", args)
+	}
+}
+
+func TestWrap_Containers(t *testing.T) {
+	for _, tt := range []struct {
+		runtime string
+		user    []string
+	}{
+		{"podman", []string{"--userns", "keep-id"}},
+		{"docker", []string{"--user", "1000:1000"}},
+	} {
+		t.Run(tt.runtime, func(t *testing.T) {
+			s := testSandbox(t, config.SandboxConfig{
+				Runtime:       tt.runtime,
+				Image:         "ralph/claude:latest",
+				Network:       "anthropic-only",
+				HomeAllowlist: []string{".claude:rw", ".gitconfig", ".missing"},
+				Env:           []string{"ANTHROPIC_API_KEY"},
+			})
+			if err := os.Mkdir(filepath.Join(s.home, ".claude"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(s.home, ".gitconfig"), nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+
+			args, err := s.Wrap(dir, []string{"/home/me/.local/bin/claude", "-p", "prompt"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			claudeDir := filepath.Join(s.home, ".claude")
+			gitconfig := filepath.Join(s.home, ".gitconfig")
+			checks := [][]string{
+				{tt.runtime, "run", "--rm", "-i"},
+				{"--cap-drop", "ALL"},
+				{"--network", "anthropic-only"},
+				tt.user,
+				{"-e", "ANTHROPIC_API_KEY"},
+				{"-v", claudeDir + ":" + claudeDir},
+				{"-v", gitconfig + ":" + gitconfig + ":ro"},
+				{"-v", dir + ":" + dir},
+				{"-w", dir, "ralph/claude:latest", "claude", "-p", "prompt"},
+			}
+			for _, want := range checks {
+				if !hasSeq(args, want...) {
+					t.Errorf("args missing %q:\n%q", want, args)
+				}
+			}
+			if strings.Contains(strings.Join(args, " "), ".missing") {
+				t.Errorf("missing home paths must not be mounted: %q", args)
+			}
+		})
+	}
+}
+
+func TestWrap_WorktreeMountsCommonGitDir(t *testing.T) {
+	repo := t.TempDir()
+	wtGitDir := filepath.Join(repo, ".git", "worktrees", "feat")
+	if err := os.MkdirAll(wtGitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wtGitDir, "commondir"), []byte("../..\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wt := t.TempDir()
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+wtGitDir+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := testSandbox(t, config.SandboxConfig{Runtime: "bwrap", Network: "full"})
+	args, err := s.Wrap(wt, []string{"claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	common := filepath.Join(repo, ".git")
+	if !hasSeq(args, "--bind", common, common) {
+		t.Errorf("worktree sandbox should mount the main .git read-write: %q", args)
+	}
+	hooks := filepath.Join(common, "hooks")
+	if !hasSeq(args, "--ro-bind", hooks, hooks) {
+		t.Errorf("worktree sandbox should mount the main .git/hooks read-only: %q", args)
+	}
+}
+
+func TestWrap_GitHooksAndConfigReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.Mkdir(gitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte("[core]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hooks := filepath.Join(gitDir, "hooks")
+	gitConfig := filepath.Join(gitDir, "config")
+
+	bw := testSandbox(t, config.SandboxConfig{Runtime: "bwrap", Network: "full"})
+	args, err := bw.Wrap(dir, []string{"claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{hooks, gitConfig} {
+		if !hasSeq(args, "--ro-bind", p, p) {
+			t.Errorf("bwrap should mount %s read-only: %q", p, args)
+		}
+	}
+	// bwrap applies mounts in order, so the read-only ones must come after
+	// the writable bind of dir that contains them.
+	if slices.Index(args, hooks) < slices.Index(args, dir) {
+		t.Errorf("read-only git mounts must follow the writable bind: %q", args)
+	}
+	if info, err := os.Stat(hooks); err != nil || !info.IsDir() {
+		t.Errorf("missing hooks directory should be created before mounting: %v", err)
+	}
+
+	docker := testSandbox(t, config.SandboxConfig{Runtime: "docker", Image: "img", Network: "full"})
+	args, err = docker.Wrap(dir, []string{"claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{hooks, gitConfig} {
+		if !hasSeq(args, "-v", p+":"+p+":ro") {
+			t.Errorf("container should mount %s read-only: %q", p, args)
+		}
+	}
+}
+
+func TestWrap_LinkedWorktreeConfigReadOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	wt := filepath.Join(t.TempDir(), "feat")
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"-c", "user.email=t@t", "-c", "user.name=t", "commit", "--allow-empty", "-m", "init"},
+		{"config", "extensions.worktreeConfig", "true"},
+		{"worktree", "add", "-b", "feat", wt},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	common, err := filepath.EvalSymlinks(filepath.Join(repo, ".git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	own := linkedGitDir(wt)
+	if resolved, err := filepath.EvalSymlinks(own); err != nil || filepath.Dir(resolved) != filepath.Join(common, "worktrees") {
+		t.Fatalf("linkedGitDir = %q, want a directory under %s/worktrees", own, common)
+	}
+	protected := []string{
+		filepath.Join(gitCommonDir(wt), "config.worktree"),
+		filepath.Join(own, "config"),
+		filepath.Join(own, "config.worktree"),
+	}
+
+	bw := testSandbox(t, config.SandboxConfig{Runtime: "bwrap", Network: "full"})
+	args, err := bw.Wrap(wt, []string{"claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range protected {
+		if !hasSeq(args, "--ro-bind", p, p) {
+			t.Errorf("bwrap should mount %s read-only: %q", p, args)
+		}
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("missing %s should be created before mounting: %v", p, err)
+		}
+	}
+
+	docker := testSandbox(t, config.SandboxConfig{Runtime: "docker", Image: "img", Network: "full"})
+	args, err = docker.Wrap(wt, []string{"claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range protected {
+		if !hasSeq(args, "-v", p+":"+p+":ro") {
+			t.Errorf("container should mount %s read-only: %q", p, args)
+		}
+	}
+}
+
+func TestGitCommonDir_PlainRepo(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitCommonDir(dir); got != "" {
+		t.Errorf("gitCommonDir() = %q, want empty for a repository inside dir", got)
+	}
+}
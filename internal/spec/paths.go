@@ -0,0 +1,151 @@
+package spec
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// boundaryFiles are the spec artifacts whose front-matter may declare the
+// paths a spec is allowed to touch.
+var boundaryFiles = []string{"spec.md", "plan.md"}
+
+// ReadPaths returns the globs listed under "paths:" in the YAML front-matter
+// of <specDir>/spec.md and <specDir>/plan.md, in order and without
+// duplicates. Both block and inline lists are accepted:
+//
+//	---
+//	paths:
+//	  - internal/loop/**
+//	  - cmd/ralph/*.go
+//	---
+//
+//	---
+//	paths: [internal/loop/**, cmd/ralph/*.go]
+//	---
+//
+// Returns nil (not an error) when neither file declares paths.
+func ReadPaths(specDir string) ([]string, error) {
+	var globs []string
+	seen := make(map[string]bool)
+	for _, name := range boundaryFiles {
+		found, err := frontMatterPaths(filepath.Join(specDir, name))
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range found {
+			if !seen[g] {
+				seen[g] = true
+				globs = append(globs, g)
+			}
+		}
+	}
+	return globs, nil
+}
+
+// frontMatterPaths parses the "paths:" key of one file's front-matter.
+func frontMatterPaths(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read paths: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return nil, scanner.Err()
+	}
+	var globs []string
+	inPaths := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" {
+			return globs, nil
+		}
+		if inPaths {
+			if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+				globs = appendGlob(globs, item)
+				continue
+			}
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			inPaths = false
+		}
+		if line != trimmed {
+			continue // nested under another key
+		}
+		value, ok := strings.CutPrefix(trimmed, "paths:")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if list, ok := strings.CutPrefix(value, "["); ok {
+			for _, item := range strings.Split(strings.TrimSuffix(list, "]"), ",") {
+				globs = appendGlob(globs, item)
+			}
+			continue
+		}
+		inPaths = value == ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read paths: %w", err)
+	}
+	return nil, nil // unterminated front-matter: not front-matter at all
+}
+
+// appendGlob unquotes a YAML scalar, drops trailing comments, and appends it
+// when non-empty.
+func appendGlob(globs []string, item string) []string {
+	item = strings.TrimSpace(item)
+	if i := strings.Index(item, " #"); i >= 0 {
+		item = strings.TrimSpace(item[:i])
+	}
+	item = strings.Trim(item, `"'`)
+	if item == "" {
+		return globs
+	}
+	return append(globs, item)
+}
+
+// MatchPath reports whether the slash-separated, repository-relative file
+// name matches glob. Segments use path.Match syntax, "**" matches any number
+// of directories, and a glob that matches a directory covers everything
+// beneath it (so "internal/loop" and "internal/loop/" both allow
+// "internal/loop/loop.go").
+func MatchPath(glob, name string) bool {
+	glob = strings.TrimPrefix(path.Clean("/"+strings.TrimSuffix(glob, "/")), "/")
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if glob == "" {
+		return true
+	}
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(glob, name []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(glob[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(glob[0], name[0]); err != nil || !ok {
+			return false
+		}
+		glob, name = glob[1:], name[1:]
+	}
+	return true // glob consumed: an exact match or a directory prefix
+}
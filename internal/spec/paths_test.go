@@ -0,0 +1,93 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadPaths(t *testing.T) {
+	dir := t.TempDir()
+	specMD := `---
+title: Core loop
+paths:
+  - internal/loop/**
+  - "cmd/ralph/*.go"   # wiring
+  - internal/loop/**
+owners:
+  - alice
+---
+
+# Spec
+
+paths:
+  - not/front/matter
+`
+	planMD := `---
+paths: [internal/config/, 'README.md']
+---
+# Plan
+`
+	if err := os.WriteFile(filepath.Join(dir, "spec.md"), []byte(specMD), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte(planMD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadPaths(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"internal/loop/**", "cmd/ralph/*.go", "internal/config/", "README.md"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ReadPaths() = %q, want %q", got, want)
+	}
+}
+
+func TestReadPaths_None(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "spec.md"), []byte("# Spec\n\n---\npaths: [x]\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte("---\npaths: [unterminated]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadPaths(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("ReadPaths() = %q, want nil without front-matter", got)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		glob, name string
+		want       bool
+	}{
+		{"internal/loop/**", "internal/loop/loop.go", true},
+		{"internal/loop/**", "internal/loop/sub/x.go", true},
+		{"internal/loop/**", "internal/loopy/x.go", false},
+		{"internal/loop", "internal/loop/loop.go", true},
+		{"internal/loop/", "internal/loop/loop.go", true},
+		{"cmd/ralph/*.go", "cmd/ralph/main.go", true},
+		{"cmd/ralph/*.go", "cmd/ralph/sub/main.go", false},
+		{"**/*_test.go", "internal/loop/loop_test.go", true},
+		{"**/*_test.go", "loop_test.go", true},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"docs/**/*.md", "docs/c.md", true},
+		{"README.md", "README.md", true},
+		{"README.md", "docs/README.md", false},
+		{"./README.md", "README.md", true},
+		{".github/**", ".github/workflows/ci.yml", true},
+		{"[", "x", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.glob, tt.name); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.glob, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package spec
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TaskProgress counts checklist items in a spec directory's tasks.md.
+type TaskProgress struct {
+	Done  int
+	Total int
+}
+
+// Remaining returns the number of unchecked tasks.
+func (p TaskProgress) Remaining() int {
+	return p.Total - p.Done
+}
+
+// Percent returns the completed share of tasks in the range 0–100.
+// Returns 0 when there are no tasks.
+func (p TaskProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Done * 100 / p.Total
+}
+
+// ReadTaskProgress parses <specDir>/tasks.md and counts "- [ ]" and "- [x]"
+// checklist items. Returns a zero TaskProgress (not an error) if tasks.md does
+// not exist.
+func ReadTaskProgress(specDir string) (TaskProgress, error) {
+	f, err := os.Open(filepath.Join(specDir, "tasks.md"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return TaskProgress{}, nil
+		}
+		return TaskProgress{}, fmt.Errorf("read tasks: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var p TaskProgress
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimLeft(line, "-*+ ")
+		switch {
+		case strings.HasPrefix(line, "[ ]"):
+			p.Total++
+		case strings.HasPrefix(line, "[x]"), strings.HasPrefix(line, "[X]"):
+			p.Total++
+			p.Done++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return TaskProgress{}, fmt.Errorf("read tasks: %w", err)
+	}
+	return p, nil
+}
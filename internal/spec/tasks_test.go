@@ -0,0 +1,49 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTaskProgress(t *testing.T) {
+	dir := t.TempDir()
+	content := `# Tasks
+
+## Phase 1
+- [x] T001 Create config
+- [X] T002 Add tests
+- [ ] T003 Wire TUI
+  - [ ] T003a nested item
+* [x] T004 star bullet
+
+Not a task: [x] inline
+`
+	if err := os.WriteFile(filepath.Join(dir, "tasks.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ReadTaskProgress(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 5 || p.Done != 3 {
+		t.Errorf("got %d/%d, want 3/5", p.Done, p.Total)
+	}
+	if p.Remaining() != 2 {
+		t.Errorf("Remaining() = %d, want 2", p.Remaining())
+	}
+	if p.Percent() != 60 {
+		t.Errorf("Percent() = %d, want 60", p.Percent())
+	}
+}
+
+func TestReadTaskProgress_Missing(t *testing.T) {
+	p, err := ReadTaskProgress(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Total != 0 || p.Percent() != 0 {
+		t.Errorf("expected zero progress, got %+v", p)
+	}
+}
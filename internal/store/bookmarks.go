@@ -0,0 +1,80 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Bookmark is a log entry the user bookmarked in the TUI.
+type Bookmark struct {
+	Iteration int
+	At        time.Time // timestamp of the bookmarked entry
+	Text      string    // the line as displayed, for listing bookmarks
+}
+
+// Bookmarker persists TUI bookmarks alongside a session log. *JSONL
+// satisfies it.
+type Bookmarker interface {
+	Bookmarks() ([]Bookmark, error)
+	SetBookmark(b Bookmark, on bool) error
+}
+
+// bookmarksPath returns the sidecar file holding the bookmarks of the
+// session log at path: "<session>.bookmarks.json".
+func bookmarksPath(path string) string {
+	return strings.TrimSuffix(path, ".jsonl") + ".bookmarks.json"
+}
+
+// Bookmarks returns the session's bookmarks in the order they were added.
+func (j *JSONL) Bookmarks() ([]Bookmark, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.readBookmarks()
+}
+
+// SetBookmark adds b (on) or removes the bookmark on the same entry (off).
+// The session log itself stays append-only; bookmarks live in a sidecar file
+// removed together with it by EnforceRetention.
+func (j *JSONL) SetBookmark(b Bookmark, on bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	marks, err := j.readBookmarks()
+	if err != nil {
+		return err
+	}
+	kept := marks[:0]
+	for _, m := range marks {
+		if m.Iteration != b.Iteration || !m.At.Equal(b.At) {
+			kept = append(kept, m)
+		}
+	}
+	if on {
+		kept = append(kept, b)
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("store: marshal bookmarks: %w", err)
+	}
+	if err := os.WriteFile(bookmarksPath(j.file.Name()), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("store: write bookmarks: %w", err)
+	}
+	return nil
+}
+
+func (j *JSONL) readBookmarks() ([]Bookmark, error) {
+	data, err := os.ReadFile(bookmarksPath(j.file.Name()))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: read bookmarks: %w", err)
+	}
+	var marks []Bookmark
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, fmt.Errorf("store: parse bookmarks: %w", err)
+	}
+	return marks, nil
+}
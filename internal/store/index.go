@@ -1,10 +1,15 @@
 package store
 
-import "github.com/LISSConsulting/RalphSpec/internal/loop"
+import (
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
 
 // iterRange is the [start, end) byte range of one iteration in the JSONL file.
 // start is the offset of the LogIterStart line; end is the offset of the first
-// byte after the LogIterComplete line (i.e. start of the next line).
+// byte after the LogIterComplete line (i.e. start of the next line), or after
+// the iteration's LogFiles line when one follows.
 type iterRange struct {
 	start int64
 	end   int64
@@ -16,6 +21,7 @@ type iterRange struct {
 type fileIndex struct {
 	summaries []IterationSummary // ordered by completion time
 	ranges    map[int]iterRange  // iteration Number → byte range
+	tools     []IterationTools   // tool usage per completed iteration, like summaries
 	pending   *pendingIter       // open iteration being built (nil if none)
 }
 
@@ -23,6 +29,7 @@ type fileIndex struct {
 type pendingIter struct {
 	startOffset int64
 	summary     IterationSummary
+	tools       ToolStats
 }
 
 func newFileIndex() *fileIndex {
@@ -42,6 +49,8 @@ func (idx *fileIndex) onAppend(entry loop.LogEntry, lineOffset, lineLen int64) {
 				Mode:    entry.Mode,
 				StartAt: entry.Timestamp,
 				Commit:  entry.Commit,
+				Spec:    entry.Spec,
+				Model:   entry.Model,
 			},
 		}
 	case loop.LogIterComplete:
@@ -61,6 +70,30 @@ func (idx *fileIndex) onAppend(entry loop.LogEntry, lineOffset, lineLen int64) {
 			end:   lineOffset + lineLen,
 		}
 		idx.summaries = append(idx.summaries, s)
+		idx.tools = append(idx.tools, IterationTools{Number: s.Number, Stats: idx.pending.tools})
 		idx.pending = nil
+	case loop.LogToolUse:
+		if idx.pending != nil {
+			idx.pending.tools.Add(entry)
+		}
+	case loop.LogFiles:
+		// The changed-file list is emitted after LogIterComplete, once the
+		// iteration's commits are known; stretch that iteration's range over it.
+		r, ok := idx.ranges[entry.Iteration]
+		if !ok || idx.pending != nil || lineOffset < r.end {
+			return
+		}
+		r.end = lineOffset + lineLen
+		idx.ranges[entry.Iteration] = r
+		for i := len(idx.summaries) - 1; i >= 0; i-- {
+			if s := &idx.summaries[i]; s.Number == entry.Iteration {
+				s.BaseCommit = entry.BaseCommit
+				s.HeadCommit, _, _ = strings.Cut(entry.Commit, " ")
+				if entry.Commit != "" {
+					s.Commit = entry.Commit
+				}
+				break
+			}
+		}
 	}
 }
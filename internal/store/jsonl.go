@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -129,6 +130,28 @@ func (j *JSONL) IterationLog(n int) ([]loop.LogEntry, error) {
 	if _, err := j.file.ReadAt(buf, r.start); err != nil {
 		return nil, fmt.Errorf("store: read iteration %d: %w", n, err)
 	}
+	return decodeLines(buf, fmt.Sprintf("iteration %d", n)), nil
+}
+
+// Entries returns every event in the session log, including those logged
+// between iterations (Regent actions, git pushes, the final outcome).
+func (j *JSONL) Entries() ([]loop.LogEntry, error) {
+	j.mu.Lock()
+	size := j.pos
+	j.mu.Unlock()
+	if size == 0 {
+		return nil, nil
+	}
+	buf := make([]byte, size)
+	if _, err := j.file.ReadAt(buf, 0); err != nil {
+		return nil, fmt.Errorf("store: read session %s: %w", j.sessionID, err)
+	}
+	return decodeLines(buf, "session "+j.sessionID), nil
+}
+
+// decodeLines parses buf as JSONL, logging and skipping malformed lines;
+// where names the span being read for the log message.
+func decodeLines(buf []byte, where string) []loop.LogEntry {
 	var entries []loop.LogEntry
 	for _, line := range bytes.Split(buf, []byte("\n")) {
 		if len(line) == 0 {
@@ -136,17 +159,17 @@ func (j *JSONL) IterationLog(n int) ([]loop.LogEntry, error) {
 		}
 		var e loop.LogEntry
 		if err := json.Unmarshal(line, &e); err != nil {
-			log.Printf("store: skipping malformed line in iteration %d: %v", n, err)
+			log.Printf("store: skipping malformed line in %s: %v", where, err)
 			continue
 		}
 		entries = append(entries, e)
 	}
-	return entries, nil
+	return entries
 }
 
-// EnforceRetention removes the oldest session log files in dir, keeping at most
-// maxKeep files. If maxKeep is 0, no files are removed. Returns nil if dir does
-// not exist or is empty.
+// EnforceRetention removes the oldest session log files in dir, keeping at
+// most maxKeep files, together with their bookmark files. If maxKeep is 0, no
+// files are removed. Returns nil if dir does not exist or is empty.
 func EnforceRetention(dir string, maxKeep int) error {
 	if maxKeep <= 0 {
 		return nil
@@ -171,8 +194,10 @@ func EnforceRetention(dir string, maxKeep int) error {
 	toDelete := len(files) - maxKeep
 	for i := 0; i < toDelete; i++ {
 		path := filepath.Join(dir, files[i])
-		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("store: remove %q: %w", path, err)
+		for _, p := range []string{path, bookmarksPath(path)} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("store: remove %q: %w", p, err)
+			}
 		}
 	}
 	return nil
@@ -196,3 +221,68 @@ func (j *JSONL) SessionSummary() (SessionSummary, error) {
 		Branch:     j.branch,
 	}, nil
 }
+
+// Open reopens an existing session log read-only and rebuilds its iteration
+// index by scanning every line. It is used to inspect past sessions (e.g. for
+// pull request bodies and reports) without appending to them.
+func Open(path string) (*JSONL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %q: %w", path, err)
+	}
+	j := &JSONL{
+		file:      f,
+		idx:       newFileIndex(),
+		sessionID: strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			lineLen := int64(len(line))
+			var e loop.LogEntry
+			if jsonErr := json.Unmarshal(bytes.TrimSpace(line), &e); jsonErr == nil {
+				if j.startedAt.IsZero() {
+					j.startedAt = e.Timestamp
+				}
+				j.idx.onAppend(e, j.pos, lineLen)
+				if e.Branch != "" {
+					j.branch = e.Branch
+				}
+				if e.Commit != "" {
+					j.lastCommit = e.Commit
+				}
+			}
+			j.pos += lineLen
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("store: read %q: %w", path, readErr)
+		}
+	}
+	return j, nil
+}
+
+// Sessions returns the paths of all session logs in dir, oldest first.
+// Returns nil (not an error) if dir does not exist.
+func Sessions(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: read dir %q: %w", dir, err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(paths) // timestamp-prefixed names sort chronologically
+	return paths, nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -132,6 +133,21 @@ func TestIterations(t *testing.T) {
 	}
 }
 
+func TestIterations_SpecAndModel(t *testing.T) {
+	s, err := store.NewJSONL(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	_ = s.Append(loop.LogEntry{Kind: loop.LogIterStart, Iteration: 1, Spec: "001-auth", Model: "sonnet"})
+	_ = s.Append(loop.LogEntry{Kind: loop.LogIterComplete, Iteration: 1, CostUSD: 0.1})
+	iters, _ := s.Iterations()
+	if len(iters) != 1 || iters[0].Spec != "001-auth" || iters[0].Model != "sonnet" {
+		t.Errorf("Iterations = %+v, want spec 001-auth on model sonnet", iters)
+	}
+}
+
 func TestIterationsReturnsCopy(t *testing.T) {
 	dir := t.TempDir()
 	s, err := store.NewJSONL(dir)
@@ -307,6 +323,84 @@ func TestIterationLog_EntriesBetweenIterations(t *testing.T) {
 	}
 }
 
+func TestEntries(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewJSONL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if entries, err := s.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("empty session Entries() = %v, %v; want none", entries, err)
+	}
+	now := time.Now()
+	all := []loop.LogEntry{
+		{Kind: loop.LogIterStart, Iteration: 1, Mode: "build", Timestamp: now},
+		{Kind: loop.LogIterComplete, Iteration: 1, CostUSD: 0.10, Timestamp: now},
+		{Kind: loop.LogRegent, Message: "Tests passed", Timestamp: now},
+		{Kind: loop.LogDone, Message: "done", Timestamp: now},
+	}
+	for _, e := range all {
+		if err := s.Append(e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	entries, err := s.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != len(all) {
+		t.Fatalf("Entries() returned %d entries, want %d", len(entries), len(all))
+	}
+	if entries[2].Kind != loop.LogRegent || entries[3].Message != "done" {
+		t.Errorf("entries between and after iterations missing: %+v", entries[2:])
+	}
+}
+
+func TestIterationLog_IncludesChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewJSONL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	now := time.Now()
+	files := []loop.ChangedFile{{Status: "M", Path: "a.go"}, {Status: "A", Path: ".github/ci.yml", OutOfBounds: true}}
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Iteration: 1, Timestamp: now},
+		{Kind: loop.LogIterComplete, Iteration: 1, Subtype: "success", Timestamp: now},
+		{Kind: loop.LogFiles, Iteration: 1, Message: "2 file(s) changed", Files: files, BaseCommit: "aaa111", Commit: "bbb222 feat: x", Timestamp: now},
+		{Kind: loop.LogInfo, Message: "after", Timestamp: now},
+		{Kind: loop.LogFiles, Iteration: 7, Message: "unknown iteration", Timestamp: now},
+	} {
+		if err := s.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	log1, err := s.IterationLog(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log1) != 3 {
+		t.Fatalf("expected start, complete and files entries, got %d", len(log1))
+	}
+	got := log1[2]
+	if got.Kind != loop.LogFiles || len(got.Files) != 2 || got.Files[1] != files[1] {
+		t.Errorf("files entry = %+v", got)
+	}
+
+	summaries, err := s.Iterations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := summaries[0]; sum.BaseCommit != "aaa111" || sum.HeadCommit != "bbb222" || sum.Commit != "bbb222 feat: x" {
+		t.Errorf("summary commit range = %q..%q (commit %q)", sum.BaseCommit, sum.HeadCommit, sum.Commit)
+	}
+}
+
 func TestIterations_Empty(t *testing.T) {
 	dir := t.TempDir()
 	s, err := store.NewJSONL(dir)
@@ -723,3 +817,191 @@ func TestAppend_RoundTripsAllFields(t *testing.T) {
 		t.Errorf("Mode: want %q, got %q", orig.Mode, e.Mode)
 	}
 }
+
+func TestOpen_RebuildsIndex(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewJSONL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 1, Mode: "build", Branch: "feat"},
+		{Kind: loop.LogToolUse, Timestamp: now, ToolName: "Read"},
+		{Kind: loop.LogIterComplete, Timestamp: now, Iteration: 1, CostUSD: 0.25, Subtype: "success", Commit: "abc123 add x"},
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 2, Mode: "build"},
+		{Kind: loop.LogIterComplete, Timestamp: now, Iteration: 2, CostUSD: 0.5, Subtype: "success"},
+	} {
+		if err := s.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = s.Close()
+
+	paths, err := store.Sessions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("Sessions: want 1 path, got %d", len(paths))
+	}
+
+	r, err := store.Open(paths[0])
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	iters, _ := r.Iterations()
+	if len(iters) != 2 {
+		t.Fatalf("want 2 iterations, got %d", len(iters))
+	}
+	if iters[0].Commit != "abc123 add x" {
+		t.Errorf("iteration 1 commit: got %q", iters[0].Commit)
+	}
+	log1, err := r.IterationLog(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log1) != 3 {
+		t.Errorf("iteration 1 log: want 3 entries, got %d", len(log1))
+	}
+	sum, _ := r.SessionSummary()
+	if sum.Branch != "feat" {
+		t.Errorf("branch: got %q", sum.Branch)
+	}
+	if sum.TotalCost != 0.75 {
+		t.Errorf("total cost: got %v", sum.TotalCost)
+	}
+	if sum.SessionID != strings.TrimSuffix(filepath.Base(paths[0]), ".jsonl") {
+		t.Errorf("session id: got %q", sum.SessionID)
+	}
+}
+
+func TestOpen_Missing(t *testing.T) {
+	if _, err := store.Open(filepath.Join(t.TempDir(), "nope.jsonl")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestSessions_MissingDir(t *testing.T) {
+	paths, err := store.Sessions(filepath.Join(t.TempDir(), "nope"))
+	if err != nil || paths != nil {
+		t.Errorf("want nil, nil; got %v, %v", paths, err)
+	}
+}
+
+// Compile-time check: *JSONL implements Bookmarker.
+var _ store.Bookmarker = (*store.JSONL)(nil)
+
+func TestSearch(t *testing.T) {
+	s, err := store.NewJSONL(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	now := time.Now()
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 1},
+		{Kind: loop.LogToolUse, Timestamp: now, Iteration: 1, ToolName: "Read", ToolInput: "internal/Auth.go", Message: "Read internal/Auth.go"},
+		{Kind: loop.LogIterComplete, Timestamp: now, Iteration: 1},
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 2},
+		{Kind: loop.LogText, Timestamp: now, Iteration: 2, Message: "nothing relevant"},
+		{Kind: loop.LogToolResult, Timestamp: now, Iteration: 2, Message: "result: Bash  error  (1 line)", Content: "panic: nil map"},
+		{Kind: loop.LogIterComplete, Timestamp: now, Iteration: 2},
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 3},
+		{Kind: loop.LogToolUse, Timestamp: now, Iteration: 3, ToolName: "Edit", ToolInput: "auth.go"},
+		{Kind: loop.LogIterComplete, Timestamp: now, Iteration: 3},
+		{Kind: loop.LogIterStart, Timestamp: now, Iteration: 4},
+		{Kind: loop.LogText, Timestamp: now, Iteration: 4, Message: "auth.go in the running iteration"},
+	} {
+		if err := s.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hits, err := store.Search(s, "AUTH.GO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 2 || hits[0].Iteration != 1 || hits[1].Iteration != 3 {
+		t.Fatalf("hits = %+v, want iterations 1 and 3 (completed only)", hits)
+	}
+	if hits[1].Entry.ToolName != "Edit" {
+		t.Errorf("hit entry = %+v", hits[1].Entry)
+	}
+	if hits, _ := store.Search(s, "nil map"); len(hits) != 1 || hits[0].Iteration != 2 {
+		t.Errorf("content search hits = %+v, want the iteration 2 tool result", hits)
+	}
+	if hits, _ := store.Search(s, ""); hits != nil {
+		t.Errorf("empty query should find nothing, got %+v", hits)
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewJSONL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if marks, err := s.Bookmarks(); err != nil || marks != nil {
+		t.Fatalf("Bookmarks() = %v, %v; want none", marks, err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	a := store.Bookmark{Iteration: 1, At: at, Text: "first"}
+	b := store.Bookmark{Iteration: 2, At: at.Add(time.Second), Text: "second"}
+	for _, m := range []store.Bookmark{a, b, a} {
+		if err := s.SetBookmark(m, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetBookmark(store.Bookmark{Iteration: 2, At: b.At.In(time.FixedZone("X", 3600))}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening the session read-only sees the same bookmarks.
+	sum, _ := s.SessionSummary()
+	path := filepath.Join(dir, sum.SessionID+".jsonl")
+	reopened, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = reopened.Close() }()
+	marks, err := reopened.Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marks) != 1 || marks[0].Text != "first" || !marks[0].At.Equal(at) {
+		t.Errorf("Bookmarks() = %+v, want only the re-added first bookmark", marks)
+	}
+
+	if err := store.EnforceRetention(dir, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(path, ".jsonl") + ".bookmarks.json"); err != nil {
+		t.Errorf("retention must keep the newest session's bookmarks: %v", err)
+	}
+}
+
+func TestEnforceRetention_RemovesBookmarks(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"0001-1.jsonl", "0001-1.bookmarks.json", "0002-2.jsonl", "0002-2.bookmarks.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.EnforceRetention(dir, 1); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if strings.Join(names, " ") != "0002-2.bookmarks.json 0002-2.jsonl" {
+		t.Errorf("files left = %v", names)
+	}
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// SearchHit is one log entry matching a Search query.
+type SearchHit struct {
+	Iteration int
+	Entry     loop.LogEntry
+}
+
+// Search returns the entries of r's completed iterations whose message,
+// tool name, tool input, or tool result/thinking content contains query,
+// case-insensitively, in iteration order. Each iteration is read through the
+// index with IterationLog, so the search covers the whole session, not just
+// what a view has rendered.
+func Search(r Reader, query string) ([]SearchHit, error) {
+	if query == "" {
+		return nil, nil
+	}
+	summaries, err := r.Iterations()
+	if err != nil {
+		return nil, fmt.Errorf("store: search: %w", err)
+	}
+	q := strings.ToLower(query)
+	var hits []SearchHit
+	for _, s := range summaries {
+		entries, err := r.IterationLog(s.Number)
+		if err != nil {
+			return nil, fmt.Errorf("store: search: %w", err)
+		}
+		for _, e := range entries {
+			if strings.Contains(strings.ToLower(e.Message), q) ||
+				strings.Contains(strings.ToLower(e.ToolName), q) ||
+				strings.Contains(strings.ToLower(e.ToolInput), q) ||
+				strings.Contains(strings.ToLower(e.Content), q) {
+				hits = append(hits, SearchHit{Iteration: s.Number, Entry: e})
+			}
+		}
+	}
+	return hits, nil
+}
@@ -41,6 +41,13 @@ type IterationSummary struct {
 	Commit   string
 	StartAt  time.Time
 	EndAt    time.Time
+	Spec     string // active spec; "" in roam mode or logs from before it was recorded
+	Model    string // configured Claude model; "" = CLI default
+
+	// BaseCommit..HeadCommit is the commit range the iteration produced, as
+	// SHAs. Both are empty when the iteration changed no files.
+	BaseCommit string
+	HeadCommit string
 }
 
 // SessionSummary summarises the current session.
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// ThrashCalls is how many calls on one file mark it as thrashing: the agent
+// re-reading or re-editing the same file instead of making progress.
+const ThrashCalls = 10
+
+// fileTools are the tools whose summarized input is a file path.
+var fileTools = map[string]bool{
+	"Read": true, "Edit": true, "MultiEdit": true, "Write": true,
+	"NotebookRead": true, "NotebookEdit": true,
+}
+
+// editTools are the file tools that change the file.
+var editTools = map[string]bool{"Edit": true, "MultiEdit": true, "Write": true, "NotebookEdit": true}
+
+// testCommands are substrings of Bash commands that run a test suite.
+var testCommands = []string{
+	"go test", "gotestsum", "npm test", "npm run test", "yarn test", "pnpm test", "bun test", "deno test",
+	"pytest", "python -m unittest", "cargo test", "cargo nextest", "make test", "make check",
+	"jest", "vitest", "mvn test", "gradle test", "gradlew test", "rspec", "phpunit",
+	"dotnet test", "mix test", "ctest",
+}
+
+// ToolStats counts the tool calls of one iteration or session.
+type ToolStats struct {
+	Calls    map[string]int // tool name → calls
+	Files    map[string]int // file path → calls of Read/Edit/Write and similar tools
+	Commands map[string]int // Bash command → runs
+	Edits    int            // calls that changed a file
+	TestRuns int            // Bash commands that ran a test suite
+}
+
+// IterationTools is the tool usage of one completed iteration.
+type IterationTools struct {
+	Number int
+	Stats  ToolStats
+}
+
+// ToolReader returns per-iteration tool usage indexed from a session log.
+// *JSONL satisfies it.
+type ToolReader interface {
+	IterationTools() ([]IterationTools, error)
+}
+
+// Count is one row of a ranked count.
+type Count struct {
+	Key string
+	N   int
+}
+
+// Add counts a LogToolUse entry; other kinds are ignored.
+func (s *ToolStats) Add(e loop.LogEntry) {
+	if e.Kind != loop.LogToolUse || e.ToolName == "" {
+		return
+	}
+	if s.Calls == nil {
+		s.Calls = map[string]int{}
+		s.Files = map[string]int{}
+		s.Commands = map[string]int{}
+	}
+	s.Calls[e.ToolName]++
+	input := strings.TrimSpace(e.ToolInput)
+	switch {
+	case fileTools[e.ToolName] && input != "":
+		s.Files[input]++
+		if editTools[e.ToolName] {
+			s.Edits++
+		}
+	case e.ToolName == "Bash" && input != "":
+		cmd := strings.Join(strings.Fields(input), " ")
+		s.Commands[cmd]++
+		if IsTestCommand(cmd) {
+			s.TestRuns++
+		}
+	}
+}
+
+// Merge adds o's counts to s.
+func (s *ToolStats) Merge(o ToolStats) {
+	if s.Calls == nil {
+		s.Calls = map[string]int{}
+		s.Files = map[string]int{}
+		s.Commands = map[string]int{}
+	}
+	for k, n := range o.Calls {
+		s.Calls[k] += n
+	}
+	for k, n := range o.Files {
+		s.Files[k] += n
+	}
+	for k, n := range o.Commands {
+		s.Commands[k] += n
+	}
+	s.Edits += o.Edits
+	s.TestRuns += o.TestRuns
+}
+
+// Total returns the number of tool calls.
+func (s ToolStats) Total() int {
+	n := 0
+	for _, c := range s.Calls {
+		n += c
+	}
+	return n
+}
+
+// Untested reports whether files were changed without a test run.
+func (s ToolStats) Untested() bool {
+	return s.Edits > 0 && s.TestRuns == 0
+}
+
+// Thrashing returns the files called on at least ThrashCalls times, most
+// first.
+func (s ToolStats) Thrashing() []Count {
+	var out []Count
+	for _, c := range Top(s.Files, 0) {
+		if c.N >= ThrashCalls {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// clone returns a deep copy of s.
+func (s ToolStats) clone() ToolStats {
+	var c ToolStats
+	c.Merge(s)
+	return c
+}
+
+// Top returns the n highest counts in m, most first and then by key; n <= 0
+// returns them all.
+func Top(m map[string]int, n int) []Count {
+	out := make([]Count, 0, len(m))
+	for k, v := range m {
+		out = append(out, Count{Key: k, N: v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].N != out[j].N {
+			return out[i].N > out[j].N
+		}
+		return out[i].Key < out[j].Key
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// IsTestCommand reports whether a Bash command runs a test suite.
+func IsTestCommand(cmd string) bool {
+	for _, t := range testCommands {
+		if strings.Contains(cmd, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// IterationTools returns the tool usage of every completed iteration, in
+// completion order. The returned stats are copies and safe to mutate.
+func (j *JSONL) IterationTools() ([]IterationTools, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := make([]IterationTools, len(j.idx.tools))
+	for i, t := range j.idx.tools {
+		out[i] = IterationTools{Number: t.Number, Stats: t.Stats.clone()}
+	}
+	return out, nil
+}
@@ -0,0 +1,133 @@
+package store_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+func toolUse(name, input string) loop.LogEntry {
+	return loop.LogEntry{Kind: loop.LogToolUse, ToolName: name, ToolInput: input, Timestamp: time.Now()}
+}
+
+func TestToolStats_Add(t *testing.T) {
+	var s store.ToolStats
+	for _, e := range []loop.LogEntry{
+		toolUse("Read", "a.go"),
+		toolUse("Read", "a.go"),
+		toolUse("Edit", "a.go"),
+		toolUse("Write", "b.go"),
+		toolUse("Grep", "TODO"),
+		toolUse("Bash", "go  test ./..."),
+		toolUse("Bash", "go test ./..."),
+		toolUse("Bash", "git status"),
+		{Kind: loop.LogText, Message: "not a tool"},
+	} {
+		s.Add(e)
+	}
+	if s.Total() != 8 || s.Calls["Read"] != 2 || s.Calls["Bash"] != 3 {
+		t.Errorf("Calls = %v", s.Calls)
+	}
+	if s.Files["a.go"] != 3 || s.Files["b.go"] != 1 || len(s.Files) != 2 {
+		t.Errorf("Files = %v; Grep patterns are not files", s.Files)
+	}
+	if s.Commands["go test ./..."] != 2 {
+		t.Errorf("Commands = %v; whitespace should be collapsed", s.Commands)
+	}
+	if s.Edits != 2 || s.TestRuns != 2 || s.Untested() {
+		t.Errorf("Edits = %d, TestRuns = %d", s.Edits, s.TestRuns)
+	}
+}
+
+func TestToolStats_MergeTopThrashing(t *testing.T) {
+	var a, b store.ToolStats
+	for i := 0; i < store.ThrashCalls-1; i++ {
+		a.Add(toolUse("Read", "hot.go"))
+	}
+	a.Add(toolUse("Read", "cold.go"))
+	b.Add(toolUse("Edit", "hot.go"))
+
+	if len(a.Thrashing()) != 0 {
+		t.Errorf("Thrashing below the threshold = %v", a.Thrashing())
+	}
+	if !b.Untested() {
+		t.Error("an edit without a test run should be untested")
+	}
+	a.Merge(b)
+	if got := a.Thrashing(); len(got) != 1 || got[0] != (store.Count{Key: "hot.go", N: store.ThrashCalls}) {
+		t.Errorf("Thrashing = %v", got)
+	}
+	if top := store.Top(a.Files, 1); len(top) != 1 || top[0].Key != "hot.go" {
+		t.Errorf("Top(1) = %v", top)
+	}
+	if all := store.Top(map[string]int{"b": 1, "a": 1, "c": 2}, 0); fmt.Sprint(all) != "[{c 2} {a 1} {b 1}]" {
+		t.Errorf("Top(0) = %v, want all, ties by key", all)
+	}
+}
+
+func TestIsTestCommand(t *testing.T) {
+	for cmd, want := range map[string]bool{
+		"go test ./...":             true,
+		"cd web && npm test":        true,
+		"python -m pytest -q":       true,
+		"cargo test --all":          true,
+		"go build ./...":            false,
+		"cat internal/test/main.go": false,
+		"git commit -m 'fix tests'": false,
+	} {
+		if got := store.IsTestCommand(cmd); got != want {
+			t.Errorf("IsTestCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
+
+func TestIterationTools(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewJSONL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+	for _, e := range []loop.LogEntry{
+		toolUse("Read", "stray.go"), // outside any iteration
+		{Kind: loop.LogIterStart, Iteration: 1},
+		toolUse("Read", "a.go"),
+		toolUse("Bash", "go test ./..."),
+		{Kind: loop.LogIterComplete, Iteration: 1},
+		{Kind: loop.LogIterStart, Iteration: 2},
+		toolUse("Edit", "a.go"),
+		{Kind: loop.LogIterComplete, Iteration: 2},
+		{Kind: loop.LogIterStart, Iteration: 3},
+		toolUse("Read", "pending.go"), // iteration still running
+	} {
+		if err := s.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tools, err := s.IterationTools()
+	if err != nil {
+		t.Fatalf("IterationTools: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Number != 1 || tools[1].Number != 2 {
+		t.Fatalf("IterationTools = %+v, want iterations 1 and 2", tools)
+	}
+	if tools[0].Stats.Total() != 2 || tools[0].Stats.TestRuns != 1 || tools[0].Stats.Files["stray.go"] != 0 {
+		t.Errorf("iteration 1 = %+v", tools[0].Stats)
+	}
+	if !tools[1].Stats.Untested() {
+		t.Errorf("iteration 2 edited without tests: %+v", tools[1].Stats)
+	}
+
+	// The result is a copy.
+	tools[0].Stats.Calls["Read"] = 99
+	again, _ := s.IterationTools()
+	if again[0].Stats.Calls["Read"] != 1 {
+		t.Error("IterationTools should return copies")
+	}
+
+	var _ store.ToolReader = s
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/LISSConsulting/RalphSpec/internal/git"
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
 	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
 	"github.com/LISSConsulting/RalphSpec/internal/spec"
 	"github.com/LISSConsulting/RalphSpec/internal/store"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
 	"github.com/LISSConsulting/RalphSpec/internal/tui/panels"
 )
 
@@ -45,12 +47,14 @@ type Model struct {
 	maxIter    int
 	mode       string
 	branch     string
+	pushGuard  string // push-safety status from the loop, shown in the header
 	totalCost  float64
 	lastCommit string
 
 	// Time
-	startedAt time.Time
-	now       time.Time
+	startedAt      time.Time
+	now            time.Time
+	rateLimitUntil time.Time // loop sleeping on a Claude usage limit until then
 
 	// Identity
 	projectName string
@@ -66,6 +70,19 @@ type Model struct {
 	// Loop control (nil when launched from ralph build/plan/run)
 	controller LoopController
 
+	// Spec-kit phase started from the Specs panel (zero when none)
+	phase phaseRun
+
+	// Tool usage of completed iterations and of the running one, shown in
+	// the Secondary panel's Tools tab
+	iterTools []store.IterationTools
+	curTools  store.ToolStats
+
+	// Steering-note queue (nil disables : / i) and what the Main panel's
+	// input prompt is open for
+	steer *loop.SteerQueue
+	input inputPurpose
+
 	// Worktree mode (nil when [worktree] is disabled)
 	orch                 *orchestrator.Orchestrator
 	worktreeLogsByBranch map[string][]string // branch → accumulated rendered log lines
@@ -135,6 +152,7 @@ func (m Model) Init() tea.Cmd {
 	if m.orch != nil {
 		cmds = append(cmds, waitForTaggedEvent(m.orch.MergedEvents))
 	}
+	cmds = append(cmds, loadSteerNotesCmd(m.steer))
 	return tea.Batch(cmds...)
 }
 
@@ -163,7 +181,15 @@ func initIterationsCmd(sr store.Reader) tea.Cmd {
 			return iterationsLoadedMsg{}
 		}
 		summaries, _ := sr.Iterations()
-		return iterationsLoadedMsg{Summaries: summaries}
+		var marks []store.Bookmark
+		if b, ok := sr.(store.Bookmarker); ok {
+			marks, _ = b.Bookmarks()
+		}
+		var tools []store.IterationTools
+		if tr, ok := sr.(store.ToolReader); ok {
+			tools, _ = tr.IterationTools()
+		}
+		return iterationsLoadedMsg{Summaries: summaries, Bookmarks: marks, Tools: tools}
 	}
 }
 
@@ -210,7 +236,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleTaggedEvent(msg)
 	case tickMsg:
 		m.now = time.Time(msg)
-		return m, tickCmd()
+		return m, tea.Batch(tickCmd(), loadSteerNotesCmd(m.steer))
 	case loopDoneMsg:
 		// Channel closed — loop finished. Transition to idle but keep TUI open.
 		// In dashboard mode the channel is never closed; for ralph build/plan/run
@@ -227,8 +253,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleSpecSelected(msg)
 	case panels.EditSpecRequestMsg:
 		return m.handleEditSpecRequest(msg)
+	case panels.OpenFileRequestMsg:
+		return m.handleOpenFileRequest(msg)
 	case panels.CreateSpecRequestMsg:
 		return m.handleCreateSpecRequest(msg)
+	case panels.PhaseRequestMsg:
+		return m.handlePhaseRequest(msg)
+	case panels.InputSubmittedMsg:
+		return m.handleInputSubmitted(msg)
+	case panels.SteerRemoveMsg:
+		return m.handleSteerRemove(msg)
+	case steerNotesMsg:
+		return m.handleSteerNotes(msg)
+	case spendMsg:
+		return m.handleSpend(msg)
 	case specsRefreshedMsg:
 		return m.handleSpecsRefreshed(msg)
 	case gitInfoMsg:
@@ -238,11 +276,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.LastCommit != "" {
 			m.lastCommit = msg.LastCommit
 		}
-		return m, nil
+		return m, loadSpendCmd(m.workDir, m.branch)
 	case iterationsLoadedMsg:
 		for _, s := range msg.Summaries {
 			m.iterationsPanel = m.iterationsPanel.AddIteration(s)
 		}
+		marks := make([]components.Bookmark, len(msg.Bookmarks))
+		for i, b := range msg.Bookmarks {
+			marks[i] = components.Bookmark{Iteration: b.Iteration, At: b.At.UnixNano()}
+		}
+		m.mainView = m.mainView.SetBookmarks(marks)
+		m.iterTools = append(msg.Tools, m.iterTools...)
+		return m.refreshTools(), nil
+	case panels.SearchRequestMsg:
+		return m.handleSearchRequest(msg)
+	case searchResultsMsg:
+		if msg.Err == nil {
+			m.mainView = m.mainView.SetSearchIterations(msg.Query, msg.Iterations)
+			m.iterationsPanel = m.iterationsPanel.SetMarked(msg.Iterations)
+		}
+		return m, nil
+	case panels.BookmarkToggledMsg:
+		return m.handleBookmarkToggled(msg)
+	case bookmarkSavedMsg:
+		if msg.Err != nil {
+			m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
+				Kind:    loop.LogError,
+				Message: fmt.Sprintf("bookmark not saved: %v", msg.Err),
+			}, m.layout.Main.Width))
+		}
 		return m, nil
 	case panels.IterationSelectedMsg:
 		return m.handleIterationSelected(msg)
@@ -286,6 +348,10 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.helpVisible = false
 		return m, nil
 	}
+	// An open search/filter prompt takes every key, including shortcuts.
+	if m.focus == FocusMain && m.mainView.InputActive() && msg.String() != "ctrl+c" {
+		return m.delegateToFocused(msg)
+	}
 	switch msg.String() {
 	case "?":
 		m.helpVisible = true
@@ -327,6 +393,12 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.controller.StopLoop()
 		}
 		return m, nil
+	case " ":
+		return m.togglePause()
+	case ":", "i":
+		if m.steer != nil {
+			return m.askSteerNote()
+		}
 	case "W":
 		// Launch a worktree agent for the currently selected spec.
 		// Use the spec name as the branch — it matches the existing feature branch
@@ -343,15 +415,21 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if sel := m.specsPanel.SelectedSpec(); sel != nil {
-			if err := m.orch.Launch(context.Background(), sel.Name, sel.Name, sel.Dir, loop.ModeBuild, 0); err != nil {
+			// Past max_parallel the agent is queued and starts when a slot frees.
+			queued, err := m.orch.Enqueue(context.Background(), sel.Name, sel.Name, sel.Dir, loop.ModeBuild, 0)
+			if err != nil {
 				m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
 					Kind:    loop.LogError,
 					Message: fmt.Sprintf("worktree launch failed: %v", err),
 				}, m.layout.Main.Width))
 			} else {
+				verb := "launched"
+				if queued {
+					verb = "queued"
+				}
 				m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
 					Kind:    loop.LogInfo,
-					Message: fmt.Sprintf("worktree agent launched for %s", sel.Name),
+					Message: fmt.Sprintf("worktree agent %s for %s", verb, sel.Name),
 				}, m.layout.Main.Width))
 				m.secondary = m.secondary.SetWorktreeEntries(agentsToEntries(m.orch.ActiveAgents()))
 			}
@@ -404,6 +482,9 @@ func (m Model) handleLogEntry(msg logEntryMsg) (tea.Model, tea.Cmd) {
 	if entry.Branch != "" {
 		m.branch = entry.Branch
 	}
+	if entry.PushGuard != "" {
+		m.pushGuard = entry.PushGuard
+	}
 	if entry.Mode != "" {
 		m.mode = entry.Mode
 	}
@@ -422,7 +503,10 @@ func (m Model) handleLogEntry(msg logEntryMsg) (tea.Model, tea.Cmd) {
 
 	// Derive LoopState transitions from log kind
 	switch entry.Kind {
+	case loop.LogRateLimit:
+		m.rateLimitUntil = entry.ResetAt
 	case loop.LogIterStart:
+		m.rateLimitUntil = time.Time{}
 		next := StateBuilding
 		if entry.Mode == "plan" {
 			next = StatePlanning
@@ -448,7 +532,13 @@ func (m Model) handleLogEntry(msg logEntryMsg) (tea.Model, tea.Cmd) {
 		m.iterationsPanel = m.iterationsPanel.AddIteration(summary).SetCurrent(0)
 		m.secondary = m.secondary.AddIteration(summary)
 
+	case loop.LogPaused:
+		if m.loopState.CanTransitionTo(StatePaused) {
+			m.loopState = StatePaused
+		}
+
 	case loop.LogDone, loop.LogStopped, loop.LogSpecComplete, loop.LogSweepComplete:
+		m.rateLimitUntil = time.Time{}
 		if m.loopState.CanTransitionTo(StateIdle) {
 			m.loopState = StateIdle
 		}
@@ -466,20 +556,35 @@ func (m Model) handleLogEntry(msg logEntryMsg) (tea.Model, tea.Cmd) {
 
 	// Render once at current width; route by kind
 	rendered := m.theme.RenderLogLine(entry, m.layout.Main.Width)
+	meta := logLineMeta(entry, m.iteration)
 	switch entry.Kind {
 	case loop.LogRegent:
 		m.secondary = m.secondary.AppendLine(rendered, panels.TabRegent)
 		if strings.Contains(rendered, "Tests") || strings.Contains(rendered, "Reverted") {
 			m.secondary = m.secondary.AppendLine(rendered, panels.TabTests)
 		}
+	case loop.LogPolicy:
+		m.secondary = m.secondary.AppendLine(rendered, panels.TabRegent)
+		m.mainView = m.mainView.AppendLineMeta(rendered, meta)
+	case loop.LogFiles, loop.LogToolResult, loop.LogThinking:
+		lines := m.theme.RenderLogLines(entry, m.layout.Main.Width)
+		for i, lm := range logLinesMeta(entry, m.iteration, len(lines)) {
+			m.mainView = m.mainView.AppendLineMeta(lines[i], lm)
+		}
 	case loop.LogGitPull, loop.LogGitPush:
 		m.secondary = m.secondary.AppendLine(rendered, panels.TabGit)
-		m.mainView = m.mainView.AppendLine(rendered)
+		m.mainView = m.mainView.AppendLineMeta(rendered, meta)
 	default:
-		m.mainView = m.mainView.AppendLine(rendered)
+		m.mainView = m.mainView.AppendLineMeta(rendered, meta)
 	}
 
-	return m, waitForEvent(m.events)
+	m = m.trackTools(entry)
+	m, phaseCmd := m.trackPhase(entry)
+	var spendCmd tea.Cmd
+	if entry.Kind == loop.LogIterComplete {
+		spendCmd = loadSpendCmd(m.workDir, m.branch) // the store already has the iteration
+	}
+	return m, tea.Batch(waitForEvent(m.events), phaseCmd, spendCmd)
 }
 
 // handleTaggedEvent processes a log entry from a worktree agent.
@@ -487,21 +592,28 @@ func (m Model) handleLogEntry(msg logEntryMsg) (tea.Model, tea.Cmd) {
 // If the event's branch is the currently active worktree, the line is also
 // appended to the Main panel in real time.
 func (m Model) handleTaggedEvent(msg taggedEventMsg) (tea.Model, tea.Cmd) {
-	rendered := m.theme.RenderLogLine(msg.Entry, m.layout.Main.Width)
+	lines := m.theme.RenderLogLines(msg.Entry, m.layout.Main.Width)
+	rendered := lines[0]
+	if hasDetail(msg.Entry.Kind) {
+		lines = lines[:1] // worktree logs have no fold state; keep the summary
+	}
 
 	// Accumulate per-branch log.
 	if m.worktreeLogsByBranch == nil {
 		m.worktreeLogsByBranch = make(map[string][]string)
 	}
-	m.worktreeLogsByBranch[msg.Branch] = append(m.worktreeLogsByBranch[msg.Branch], rendered)
+	m.worktreeLogsByBranch[msg.Branch] = append(m.worktreeLogsByBranch[msg.Branch], lines...)
 
 	// Live-append to Main panel when viewing this branch's log.
 	if m.activeWorktreeBranch == msg.Branch {
-		m.mainView = m.mainView.AppendLine(rendered)
+		for _, line := range lines {
+			m.mainView = m.mainView.AppendLine(line)
+		}
 	}
 
-	// Route Regent events to the Secondary panel so the Regent tab is populated.
-	if msg.Entry.Kind == loop.LogRegent {
+	// Route Regent events and policy violations to the Secondary panel so the
+	// Regent tab is populated.
+	if msg.Entry.Kind == loop.LogRegent || msg.Entry.Kind == loop.LogPolicy {
 		m.secondary = m.secondary.AppendLine(rendered, panels.TabRegent)
 	}
 
@@ -513,7 +625,8 @@ func (m Model) handleTaggedEvent(msg taggedEventMsg) (tea.Model, tea.Cmd) {
 	return m, waitForTaggedEvent(m.orch.MergedEvents)
 }
 
-// handleWorktreeAction dispatches stop/merge/clean on the orchestrator.
+// handleWorktreeAction dispatches stop/merge/clean/resume and queue priority
+// changes on the orchestrator.
 func (m Model) handleWorktreeAction(msg panels.WorktreeActionMsg) (tea.Model, tea.Cmd) {
 	if m.orch == nil {
 		return m, nil
@@ -525,6 +638,17 @@ func (m Model) handleWorktreeAction(msg panels.WorktreeActionMsg) (tea.Model, te
 		_ = m.orch.Merge(msg.Branch)
 	case "clean":
 		_ = m.orch.Clean(msg.Branch)
+	case "resume":
+		if err := m.orch.Resume(context.Background(), msg.Branch); err != nil {
+			m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
+				Kind:    loop.LogError,
+				Message: fmt.Sprintf("worktree resume failed: %v", err),
+			}, m.layout.Main.Width))
+		}
+	case "raise":
+		_ = m.orch.AdjustPriority(msg.Branch, 1)
+	case "lower":
+		_ = m.orch.AdjustPriority(msg.Branch, -1)
 	}
 	// Refresh worktrees tab after state change.
 	m.secondary = m.secondary.SetWorktreeEntries(agentsToEntries(m.orch.ActiveAgents()))
@@ -564,11 +688,32 @@ func renderMarkdown(content string, width int) string {
 }
 
 func (m Model) handleEditSpecRequest(msg panels.EditSpecRequestMsg) (tea.Model, tea.Cmd) {
+	cmd := m.editorCommand(msg.Path)
+	if cmd == nil {
+		return m, nil
+	}
+	workDir := m.workDir
+	return m, tea.ExecProcess(cmd, func(_ error) tea.Msg {
+		specs, _ := spec.List(workDir)
+		return specsRefreshedMsg{Specs: specs}
+	})
+}
+
+func (m Model) handleOpenFileRequest(msg panels.OpenFileRequestMsg) (tea.Model, tea.Cmd) {
+	cmd := m.editorCommand(msg.Path)
+	if cmd == nil {
+		return m, nil
+	}
+	return m, tea.ExecProcess(cmd, func(_ error) tea.Msg { return nil })
+}
+
+// editorCommand builds the $EDITOR command for path, resolved against the
+// working directory. Returns nil when $EDITOR is unset.
+func (m Model) editorCommand(path string) *exec.Cmd {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
-		return m, nil
+		return nil
 	}
-	path := msg.Path
 	if !filepath.IsAbs(path) && m.workDir != "" {
 		path = filepath.Join(m.workDir, path)
 	}
@@ -576,12 +721,7 @@ func (m Model) handleEditSpecRequest(msg panels.EditSpecRequestMsg) (tea.Model,
 	cmdArgs := make([]string, len(parts)-1, len(parts))
 	copy(cmdArgs, parts[1:])
 	cmdArgs = append(cmdArgs, path)
-	cmd := exec.Command(parts[0], cmdArgs...) //nolint:gosec
-	workDir := m.workDir
-	return m, tea.ExecProcess(cmd, func(_ error) tea.Msg {
-		specs, _ := spec.List(workDir)
-		return specsRefreshedMsg{Specs: specs}
-	})
+	return exec.Command(parts[0], cmdArgs...) //nolint:gosec
 }
 
 func (m Model) handleCreateSpecRequest(msg panels.CreateSpecRequestMsg) (tea.Model, tea.Cmd) {
@@ -597,7 +737,7 @@ func (m Model) handleCreateSpecRequest(msg panels.CreateSpecRequestMsg) (tea.Mod
 
 func (m Model) handleSpecsRefreshed(msg specsRefreshedMsg) (tea.Model, tea.Cmd) {
 	specsW, specsH := innerDims(m.layout.Specs)
-	m.specsPanel = panels.NewSpecsPanel(msg.Specs, m.workDir, specsW, specsH)
+	m.specsPanel = m.specsPanel.SetSpecs(msg.Specs).SetSize(specsW, specsH)
 	return m, nil
 }
 
@@ -618,6 +758,7 @@ func (m Model) handleIterationSelected(msg panels.IterationSelectedMsg) (tea.Mod
 		return m, nil
 	}
 	n := msg.Number
+	workDir := m.workDir
 	return m, func() tea.Msg {
 		entries, err := m.storeReader.IterationLog(n)
 		var summary store.IterationSummary
@@ -629,7 +770,12 @@ func (m Model) handleIterationSelected(msg panels.IterationSelectedMsg) (tea.Mod
 				}
 			}
 		}
-		return iterationLogLoadedMsg{Number: n, Entries: entries, Summary: summary, Err: err}
+		loaded := iterationLogLoadedMsg{Number: n, Entries: entries, Summary: summary, Err: err}
+		if summary.BaseCommit != "" && summary.HeadCommit != "" && workDir != "" {
+			diff, dErr := git.NewRunner(workDir).Diff(summary.BaseCommit, summary.HeadCommit)
+			loaded.Diff, loaded.DiffErr = git.ParseDiff(diff), dErr
+		}
+		return loaded
 	}
 }
 
@@ -637,17 +783,73 @@ func (m Model) handleIterationLogLoaded(msg iterationLogLoadedMsg) (tea.Model, t
 	if msg.Err != nil {
 		return m, nil
 	}
-	rendered := make([]string, len(msg.Entries))
-	for i, e := range msg.Entries {
-		rendered[i] = m.theme.RenderLogLine(e, m.layout.Main.Width)
+	rendered := make([]string, 0, len(msg.Entries))
+	var meta []components.LineMeta
+	for _, e := range msg.Entries {
+		lines := m.theme.RenderLogLines(e, m.layout.Main.Width)
+		rendered = append(rendered, lines...)
+		meta = append(meta, logLinesMeta(e, msg.Number, len(lines))...)
 	}
-	m.mainView = m.mainView.ShowIterationLog(rendered)
+	m.mainView = m.mainView.ShowIterationLines(rendered, meta)
 	detail := renderIterationSummary(msg.Summary)
 	m.mainView = m.mainView.SetIterationSummary(detail)
 	m.secondary = m.secondary.ShowDetail(detail)
+
+	var diffLines []string
+	var starts []int
+	var paths []string
+	switch {
+	case msg.DiffErr != nil:
+		diffLines = []string{fmt.Sprintf("(cannot load diff: %v)", msg.DiffErr)}
+	case len(msg.Diff) == 0:
+		diffLines = []string{"(no changes recorded for this iteration)"}
+	default:
+		diffLines, starts = renderDiff(msg.Diff, m.layout.Main.Width)
+		for _, f := range msg.Diff {
+			paths = append(paths, f.Path)
+		}
+	}
+	m.mainView = m.mainView.SetDiff(diffLines, starts, paths)
 	return m, nil
 }
 
+// handleSearchRequest searches every completed iteration in the session
+// store for the query, so matches beyond the rendered lines are found.
+func (m Model) handleSearchRequest(msg panels.SearchRequestMsg) (tea.Model, tea.Cmd) {
+	if msg.Query == "" || m.storeReader == nil {
+		m.iterationsPanel = m.iterationsPanel.SetMarked(nil)
+		return m, nil
+	}
+	sr := m.storeReader
+	return m, func() tea.Msg {
+		hits, err := store.Search(sr, msg.Query)
+		var iters []int
+		for _, h := range hits {
+			if len(iters) == 0 || iters[len(iters)-1] != h.Iteration {
+				iters = append(iters, h.Iteration)
+			}
+		}
+		return searchResultsMsg{Query: msg.Query, Iterations: iters, Err: err}
+	}
+}
+
+// handleBookmarkToggled persists a bookmark change when the session store
+// supports bookmarks.
+func (m Model) handleBookmarkToggled(msg panels.BookmarkToggledMsg) (tea.Model, tea.Cmd) {
+	b, ok := m.storeReader.(store.Bookmarker)
+	if !ok {
+		return m, nil
+	}
+	mark := store.Bookmark{
+		Iteration: msg.Bookmark.Iteration,
+		At:        time.Unix(0, msg.Bookmark.At),
+		Text:      msg.Text,
+	}
+	return m, func() tea.Msg {
+		return bookmarkSavedMsg{Err: b.SetBookmark(mark, msg.On)}
+	}
+}
+
 // renderIterationSummary formats an IterationSummary as key-value lines for the Summary tab.
 func renderIterationSummary(s store.IterationSummary) []string {
 	lines := []string{
@@ -662,6 +864,9 @@ func renderIterationSummary(s store.IterationSummary) []string {
 	if s.Commit != "" {
 		lines = append(lines, fmt.Sprintf("%-12s %s", "Commit:", s.Commit))
 	}
+	if s.BaseCommit != "" {
+		lines = append(lines, fmt.Sprintf("%-12s %s..%s", "Range:", s.BaseCommit, s.HeadCommit))
+	}
 	return lines
 }
 
@@ -683,13 +888,17 @@ func (m Model) renderHelp() string {
 		"    p           Start plan loop",
 		"    R           Smart run (auto plan+build)",
 		"    x           Stop loop immediately",
+		"    space       Pause after this iteration / resume from checkpoint",
+		"    : / i       Steer the loop (note for the next iteration)",
 		"",
 		"  SPECS PANEL",
 		"    j / k       Navigate specs",
 		"    enter       View spec",
 		"    e           Edit spec in $EDITOR",
 		"    n           Create new spec",
-		"    W           Launch worktree agent for selected spec",
+		"    S / C       Run speckit specify / clarify (dashboard mode)",
+		"    P / T       Run speckit plan / tasks (dashboard mode)",
+		"    W           Launch (or queue) worktree agent for selected spec",
 		"",
 		"  ITERATIONS PANEL",
 		"    j / k       Navigate iterations",
@@ -697,15 +906,25 @@ func (m Model) renderHelp() string {
 		"",
 		"  MAIN PANEL",
 		"    f           Toggle follow (auto-scroll)",
-		"    [ / ]       Cycle tabs",
+		"    [ / ]       Cycle tabs (Output/Spec/Iteration/Summary/Diff/Spend)",
 		"    ctrl+u/d    Page up / down",
 		"    j / k       Scroll line up / down",
+		"    n / N       Next / previous file (Diff tab, no search)",
+		"    e           Open current file in $EDITOR (Diff tab)",
+		"    /           Search all tabs and iterations (n / N next / prev)",
+		"    F           Filter: tool text error git regent info tool:NAME",
+		"    esc         Clear search, then filter",
+		"    m / '       Bookmark line / jump to next bookmark",
+		"    enter       Expand / collapse tool result and thinking",
 		"",
 		"  SECONDARY PANEL",
-		"    [ / ]       Cycle tabs (Regent/Git/Tests/Cost/Worktrees)",
+		"    [ / ]       Cycle tabs (Regent/Git/Tests/Cost/Tools/Worktrees/Steer)",
 		"    j / k       Scroll / navigate worktree agents",
 		"    enter       View worktree agent log (Worktrees tab)",
 		"    x / M / D   Stop / merge / clean agent (Worktrees tab)",
+		"    r           Resume stopped/failed agent (Worktrees tab)",
+		"    + / -       Raise / lower queued agent priority (Worktrees tab)",
+		"    d           Remove queued steering note (Steer tab)",
 		"",
 		"  Press any key to close",
 	}
@@ -731,6 +950,7 @@ func (m Model) View() string {
 		ProjectName: m.projectName,
 		WorkDir:     m.workDir,
 		Branch:      m.branch,
+		PushGuard:   m.pushGuard,
 		Mode:        m.mode,
 		Iteration:   m.iteration,
 		MaxIter:     m.maxIter,
@@ -739,6 +959,8 @@ func (m Model) View() string {
 		StateLabel:  m.loopState.Label(),
 		Elapsed:     m.now.Sub(m.startedAt),
 		Clock:       m.now,
+
+		RateLimitUntil: m.rateLimitUntil,
 	}, m.layout.Header.Width, m.theme.AccentHeaderStyle())
 
 	footer := panels.RenderFooter(panels.FooterProps{
@@ -793,6 +1015,7 @@ func agentsToEntries(agents []*orchestrator.WorktreeAgent) []panels.WorktreeEntr
 			Iterations: a.Iterations,
 			TotalCost:  a.TotalCost,
 			SpecName:   a.SpecName,
+			Priority:   a.Priority,
 		}
 	}
 	return entries
@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/LISSConsulting/RalphSpec/internal/config"
+	"github.com/LISSConsulting/RalphSpec/internal/git"
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
 	"github.com/LISSConsulting/RalphSpec/internal/orchestrator"
 	"github.com/LISSConsulting/RalphSpec/internal/spec"
@@ -851,6 +852,31 @@ func TestHandleLogEntry_CommitSet(t *testing.T) {
 	}
 }
 
+func TestHandleLogEntry_PushGuardSet(t *testing.T) {
+	m := newTestModel()
+	entry := loop.LogEntry{Kind: loop.LogError, Branch: "main", PushGuard: loop.PushGuardRefused, Message: "refused"}
+	updated, _ := m.Update(logEntryMsg(entry))
+	m2 := updated.(Model)
+	if m2.pushGuard != loop.PushGuardRefused {
+		t.Errorf("pushGuard = %q, want %q", m2.pushGuard, loop.PushGuardRefused)
+	}
+}
+
+func TestHandleLogEntry_RateLimit(t *testing.T) {
+	m := newTestModel()
+	until := time.Now().Add(time.Hour)
+	updated, _ := m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogRateLimit, ResetAt: until, Message: "Usage limit reached"}))
+	m2 := updated.(Model)
+	if !m2.rateLimitUntil.Equal(until) {
+		t.Errorf("rateLimitUntil = %v, want %v", m2.rateLimitUntil, until)
+	}
+
+	updated, _ = m2.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogIterStart, Iteration: 2}))
+	if m3 := updated.(Model); !m3.rateLimitUntil.IsZero() {
+		t.Errorf("rateLimitUntil should clear when the next iteration starts, got %v", m3.rateLimitUntil)
+	}
+}
+
 // TestWaitForEvent_EntryReceived covers the logEntryMsg return path.
 func TestWaitForEvent_EntryReceived(t *testing.T) {
 	ch := make(chan loop.LogEntry, 1)
@@ -1107,7 +1133,7 @@ func TestHandleWorktreeAction_DoesNotPanic(t *testing.T) {
 	m := New(ch, nil, "", "Proj", "", nil, nil, nil)
 	m = m.WithOrchestrator(newTestOrch())
 
-	for _, action := range []string{"stop", "merge", "clean"} {
+	for _, action := range []string{"stop", "merge", "clean", "resume", "raise", "lower"} {
 		msg := panels.WorktreeActionMsg{Branch: "wt/nonexistent", Action: action}
 		updated, cmd := m.Update(msg)
 		_ = updated.(Model) // must not panic
@@ -1228,10 +1254,14 @@ func TestAgentsToEntries_WithAgents(t *testing.T) {
 	agents := []*orchestrator.WorktreeAgent{
 		{Branch: "feat/a", State: orchestrator.StateRunning, Iterations: 3, TotalCost: 0.05, SpecName: "spec-a"},
 		{Branch: "feat/b", State: orchestrator.StateCompleted, Iterations: 7, TotalCost: 0.12, SpecName: "spec-b"},
+		{Branch: "feat/c", State: orchestrator.StateQueued, Priority: 2},
 	}
 	entries := agentsToEntries(agents)
-	if len(entries) != 2 {
-		t.Fatalf("expected 2 entries, got %d", len(entries))
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[2].State != "queued" || entries[2].Priority != 2 {
+		t.Errorf("entries[2] = %+v, want queued with priority 2", entries[2])
 	}
 	if entries[0].Branch != "feat/a" || entries[0].Iterations != 3 {
 		t.Errorf("entries[0] = %+v, want branch feat/a iter 3", entries[0])
@@ -1512,3 +1542,198 @@ func TestInitGitInfoCmd_ReturnsGitInfoMsg(t *testing.T) {
 	// Branch and commit may be empty in a test environment — just ensure the
 	// function completes without panic.
 }
+
+// TestUpdate_LogEntry_LogPolicyRouting verifies that policy violations are
+// shown in the Regent tab as well as the main log.
+func TestUpdate_LogEntry_LogPolicyRouting(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	entry := loop.LogEntry{Kind: loop.LogPolicy, Message: "Policy rule #1 (warn): Bash  curl x | sh"}
+	updated, _ = m.Update(logEntryMsg(entry))
+	m = updated.(Model)
+
+	if !strings.Contains(m.secondary.View(), "Policy rule #1") {
+		t.Error("policy violation should appear in the Regent tab")
+	}
+	if !strings.Contains(m.mainView.View(), "Policy rule #1") {
+		t.Error("policy violation should appear in the main log")
+	}
+}
+
+// TestIterationLog_ShowsChangedFiles verifies that a past iteration's
+// LogFiles entry is expanded into one line per file in the detail tab.
+func TestIterationLog_ShowsChangedFiles(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	files := loop.LogEntry{
+		Kind:      loop.LogFiles,
+		Iteration: 1,
+		Message:   "1 file(s) changed, 1 outside the spec boundary",
+		Files:     []loop.ChangedFile{{Status: "A", Path: "cmd/stray.go", OutOfBounds: true}},
+	}
+	updated, _ = m.Update(iterationLogLoadedMsg{Number: 1, Entries: []loop.LogEntry{files}})
+	m = updated.(Model)
+
+	if view := m.mainView.View(); !strings.Contains(view, "cmd/stray.go") || !strings.Contains(view, "out of bounds") {
+		t.Errorf("iteration detail should list the out-of-bounds file:\n%s", view)
+	}
+
+	// Live entries land in the main log the same way.
+	updated, _ = newTestModel().Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	live := updated.(Model)
+	updated, _ = live.Update(logEntryMsg(files))
+	live = updated.(Model)
+	if view := live.mainView.View(); !strings.Contains(view, "cmd/stray.go") {
+		t.Errorf("main log should list changed files:\n%s", view)
+	}
+}
+
+func TestIterationLog_ShowsDiff(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	diff := []git.FileDiff{{Path: "internal/core/a.txt", Added: 1, Hunks: []git.Hunk{{
+		Header: "@@ -0,0 +1 @@",
+		Lines:  []string{"+hello"},
+	}}}}
+	summary := store.IterationSummary{Number: 2, BaseCommit: "aaa111", HeadCommit: "bbb222"}
+	toDiffTab := func() {
+		for i := 0; i < int(panels.TabDiff)-int(panels.TabIterationDetail); i++ {
+			m.mainView, _ = m.mainView.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+		}
+	}
+	updated, _ = m.Update(iterationLogLoadedMsg{Number: 2, Summary: summary, Diff: diff})
+	m = updated.(Model)
+	toDiffTab()
+	view := m.mainView.View()
+	for _, want := range []string{"1 file changed", "internal/core/a.txt", "+hello"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Diff tab missing %q:\n%s", want, view)
+		}
+	}
+	if detail := strings.Join(renderIterationSummary(summary), "\n"); !strings.Contains(detail, "aaa111..bbb222") {
+		t.Errorf("summary should show the commit range:\n%s", detail)
+	}
+
+	updated, _ = m.Update(iterationLogLoadedMsg{Number: 3})
+	m = updated.(Model)
+	toDiffTab()
+	if view := m.mainView.View(); !strings.Contains(view, "no changes recorded") {
+		t.Errorf("Diff tab without a range:\n%s", view)
+	}
+}
+
+func TestSearchPrompt_SwallowsShortcuts(t *testing.T) {
+	ctrl := &mockLoopController{}
+	m := New(make(chan loop.LogEntry, 1), nil, "", "Proj", "/tmp", nil, nil, ctrl)
+	m.focus = FocusMain
+	for _, k := range []string{"/", "b", "q", "?"} {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = updated.(Model)
+		if k == "q" && cmd != nil {
+			if _, quit := cmd().(tea.QuitMsg); quit {
+				t.Fatal("q typed into the search prompt must not quit")
+			}
+		}
+	}
+	if ctrl.startCalled != "" || m.helpVisible {
+		t.Errorf("shortcuts fired while typing: start %q, help %v", ctrl.startCalled, m.helpVisible)
+	}
+	if !m.mainView.InputActive() {
+		t.Error("prompt should still be open")
+	}
+}
+
+func TestSearchRequest_MarksIterations(t *testing.T) {
+	reader := &mockStoreReader{
+		iterations: []store.IterationSummary{{Number: 1}, {Number: 2}},
+		entries:    []loop.LogEntry{{Kind: loop.LogToolUse, ToolName: "Edit", ToolInput: "auth.go"}},
+	}
+	m := New(make(chan loop.LogEntry, 1), reader, "", "Proj", "/tmp", nil, nil, nil)
+	updated, _ := m.Update(iterationsLoadedMsg{Summaries: reader.iterations})
+	m = updated.(Model)
+
+	_, cmd := m.Update(panels.SearchRequestMsg{Query: "auth"})
+	if cmd == nil {
+		t.Fatal("search should return a command")
+	}
+	res, ok := cmd().(searchResultsMsg)
+	if !ok || res.Err != nil || len(res.Iterations) != 2 {
+		t.Fatalf("search result = %+v", res)
+	}
+	updated, _ = m.Update(res)
+	m = updated.(Model)
+	if view := m.iterationsPanel.View(); strings.Count(view, "🔍") != 2 {
+		t.Errorf("both iterations should be marked:\n%s", view)
+	}
+
+	updated, _ = m.Update(panels.SearchRequestMsg{})
+	m = updated.(Model)
+	if strings.Contains(m.iterationsPanel.View(), "🔍") {
+		t.Error("clearing the search should clear the marks")
+	}
+}
+
+func TestBookmarks_PersistInStore(t *testing.T) {
+	s, err := store.NewJSONL(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+	m := New(make(chan loop.LogEntry, 1), s, "", "Proj", "/tmp", nil, nil, nil)
+	at := time.Date(2026, 3, 4, 5, 6, 7, 8, time.UTC)
+	updated, _ := m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogText, Timestamp: at, Iteration: 4, Message: "found it"}))
+	m = updated.(Model)
+
+	m.focus = FocusMain
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if cmd == nil {
+		t.Fatal("m should toggle a bookmark")
+	}
+	updated, cmd = m.Update(cmd())
+	m = updated.(Model)
+	if saved, ok := cmd().(bookmarkSavedMsg); !ok || saved.Err != nil {
+		t.Fatalf("save result = %+v", saved)
+	}
+
+	marks, _ := s.Bookmarks()
+	if len(marks) != 1 || marks[0].Iteration != 4 || !marks[0].At.Equal(at) || !strings.Contains(marks[0].Text, "found it") {
+		t.Fatalf("stored bookmarks = %+v", marks)
+	}
+
+	// A new TUI on the same session shows the bookmark again.
+	fresh := New(make(chan loop.LogEntry, 1), s, "", "Proj", "/tmp", nil, nil, nil)
+	updated, _ = fresh.Update(initIterationsCmd(s)())
+	fresh = updated.(Model)
+	updated, _ = fresh.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogText, Timestamp: at, Iteration: 4, Message: "found it"}))
+	fresh = updated.(Model)
+	if !strings.Contains(fresh.mainView.View(), "★") {
+		t.Errorf("reloaded bookmark not shown:\n%s", fresh.mainView.View())
+	}
+}
+
+func TestToolResult_ExpandsUnderCall(t *testing.T) {
+	m := newTestModel()
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogToolUse, Timestamp: at, Iteration: 1, ToolName: "Bash", ToolInput: "go test ./...", ToolUseID: "toolu_1"},
+		{Kind: loop.LogToolResult, Timestamp: at.Add(time.Second), Iteration: 1, ToolName: "Bash", ToolUseID: "toolu_1",
+			Content: "--- FAIL: TestAuth\nexit status 1", IsError: true},
+	} {
+		updated, _ := m.Update(logEntryMsg(e))
+		m = updated.(Model)
+	}
+	view := m.mainView.View()
+	if !strings.Contains(view, "↳ ✗ Bash") || strings.Contains(view, "exit status 1") {
+		t.Fatalf("result should show collapsed:\n%s", view)
+	}
+
+	m.focus = FocusMain
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter}) // cursor is the top line, the tool call
+	m = updated.(Model)
+	if view := m.mainView.View(); !strings.Contains(view, "exit status 1") {
+		t.Errorf("enter on the call should expand its result:\n%s", view)
+	}
+}
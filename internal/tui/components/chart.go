@@ -0,0 +1,50 @@
+package components
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sparkRunes are the eighth-block glyphs a Sparkline draws with, lowest first.
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// chartStyle colors sparklines and bars with the tab bar's accent color.
+var chartStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+
+// Sparkline renders values as one row of block glyphs scaled to the largest
+// value; zero values draw as the lowest glyph.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = math.Max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if peak > 0 && v > 0 {
+			i = int(math.Round(v / peak * float64(len(sparkRunes)-1)))
+		}
+		b.WriteRune(sparkRunes[i])
+	}
+	return chartStyle.Render(b.String())
+}
+
+// Bar renders value as a horizontal bar of up to width cells scaled to peak,
+// padded with spaces to width so bars in a column line up. Any non-zero value
+// gets at least one cell.
+func Bar(value, peak float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := 0
+	if peak > 0 && value > 0 {
+		n = max(int(math.Round(value/peak*float64(width))), 1)
+		n = min(n, width)
+	}
+	return chartStyle.Render(strings.Repeat("█", n)) + strings.Repeat(" ", width-n)
+}
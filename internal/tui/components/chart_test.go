@@ -0,0 +1,42 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline(nil); got != "" {
+		t.Errorf("Sparkline(nil) = %q, want empty", got)
+	}
+	if got := ansi.Strip(Sparkline([]float64{0, 1, 2, 4, 8})); got != "▁▂▃▅█" {
+		t.Errorf("Sparkline = %q, want ▁▂▃▅█", got)
+	}
+	if got := ansi.Strip(Sparkline([]float64{0, 0})); got != "▁▁" {
+		t.Errorf("all-zero Sparkline = %q, want ▁▁", got)
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		value, peak float64
+		width       int
+		want        string
+	}{
+		{10, 10, 5, "█████"},
+		{5, 10, 4, "██  "},
+		{0.01, 10, 4, "█   "}, // never invisible when non-zero
+		{0, 10, 3, "   "},
+		{20, 10, 3, "███"},
+		{1, 1, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(Bar(tt.value, tt.peak, tt.width)); got != tt.want {
+			t.Errorf("Bar(%v, %v, %d) = %q, want %q", tt.value, tt.peak, tt.width, got, tt.want)
+		}
+		if got := ansi.Strip(Bar(tt.value, tt.peak, tt.width)); len([]rune(got)) != tt.width {
+			t.Errorf("Bar width = %d, want %d", len([]rune(got)), tt.width)
+		}
+	}
+}
@@ -1,21 +1,138 @@
 package components
 
 import (
+	"fmt"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
+// Line categories used by LineMeta.Kind and Filter.Kinds.
+const (
+	KindTool   = "tool"   // Claude tool calls
+	KindText   = "text"   // Claude text/reasoning
+	KindError  = "error"  // loop errors and policy violations
+	KindGit    = "git"    // pulls, pushes, and changed files
+	KindRegent = "regent" // Regent supervisor messages
+	KindInfo   = "info"   // everything else
+)
+
+// Kinds lists the line categories in filter-bar order.
+var Kinds = []string{KindTool, KindText, KindError, KindGit, KindRegent, KindInfo}
+
+// Search and bookmark styles.
+var (
+	matchStyle        = lipgloss.NewStyle().Reverse(true)
+	currentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("#FFD93D")).Foreground(lipgloss.Color("#000000"))
+	bookmarkStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD93D"))
+)
+
+// LineMeta describes the log entry a line was rendered from, for filtering
+// and bookmarks. Lines added without meta (Kind "") are never filtered out
+// and cannot be bookmarked.
+type LineMeta struct {
+	Kind      string // one of the Kind* categories
+	Tool      string // tool name for KindTool lines
+	Iteration int
+	At        time.Time // entry timestamp; identifies the entry for bookmarks
+
+	// Fold groups the lines of a tool call with its result and thinking.
+	// Detail lines stay hidden until their fold is expanded with ToggleFold.
+	Fold   string
+	Detail bool
+}
+
+// Bookmark identifies a bookmarked log entry. Every line rendered from the
+// entry shows the mark.
+type Bookmark struct {
+	Iteration int
+	At        int64 // entry timestamp, Unix nanoseconds
+}
+
+// Bookmark returns the bookmark identity of the line's entry; ok is false
+// for lines without meta.
+func (m LineMeta) Bookmark() (b Bookmark, ok bool) {
+	if m.Kind == "" || m.At.IsZero() {
+		return Bookmark{}, false
+	}
+	return Bookmark{Iteration: m.Iteration, At: m.At.UnixNano()}, true
+}
+
+// Filter limits a LogView to lines of the given categories and, for tool
+// lines, to one tool. The zero Filter shows everything.
+type Filter struct {
+	Kinds []string // empty means all categories
+	Tool  string   // case-insensitive tool name; "" means all tools
+}
+
+// ParseFilter parses filter-bar text: space-separated categories (see
+// Kinds; a trailing "s" is allowed, as in "errors") and "tool:NAME" to show
+// only one tool's calls. A tool: term on its own implies the tool category.
+func ParseFilter(s string) (Filter, error) {
+	var f Filter
+	for _, term := range strings.Fields(strings.ToLower(s)) {
+		if name, ok := strings.CutPrefix(term, "tool:"); ok {
+			f.Tool = name
+			continue
+		}
+		kind := term
+		if !slices.Contains(Kinds, kind) {
+			kind = strings.TrimSuffix(term, "s")
+		}
+		if !slices.Contains(Kinds, kind) {
+			return Filter{}, fmt.Errorf("unknown filter %q (want %s or tool:NAME)", term, strings.Join(Kinds, ", "))
+		}
+		if !slices.Contains(f.Kinds, kind) {
+			f.Kinds = append(f.Kinds, kind)
+		}
+	}
+	if f.Tool != "" && len(f.Kinds) == 0 {
+		f.Kinds = []string{KindTool}
+	}
+	return f, nil
+}
+
+func (f Filter) match(m LineMeta) bool {
+	if m.Kind == "" {
+		return true
+	}
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, m.Kind) {
+		return false
+	}
+	return f.Tool == "" || m.Kind != KindTool || strings.EqualFold(f.Tool, m.Tool)
+}
+
 // LogView is a scrollable log panel that wraps bubbles/viewport.
 // In follow mode (default), new lines cause the view to auto-scroll to the bottom.
 // Pressing 'f' toggles follow mode on/off.
+//
+// Lines may carry a LineMeta, which lets the view hide lines with a Filter
+// and mark bookmarked entries. A search query highlights matching lines
+// (case-insensitive, on the unstyled text); NextMatch and PrevMatch jump
+// between them.
 type LogView struct {
 	vp     viewport.Model
-	lines  []string // rendered (pre-styled) lines
+	lines  []string   // rendered (pre-styled) lines
+	plain  []string   // lines without styling, for search
+	meta   []LineMeta // parallel to lines
 	follow bool
 	width  int
 	height int
+
+	filter    Filter
+	visible   []int // indices into lines that pass the filter
+	query     string
+	matches   []int // indices into visible of lines containing query
+	match     int   // current index into matches; -1 before the first jump
+	cursor    int   // visible line bookmarks act on; -1 means the top line
+	bookmarks map[Bookmark]bool
+	expanded  map[string]bool // folds whose detail lines are shown
 }
 
 // NewLogView creates a LogView with the given dimensions, initially in follow mode.
@@ -26,14 +143,24 @@ func NewLogView(w, h int) LogView {
 		follow: true,
 		width:  w,
 		height: h,
+		match:  -1,
+		cursor: -1,
 	}
 }
 
 // AppendLine appends a pre-rendered (styled) line to the log.
 // If follow mode is enabled, the viewport scrolls to the bottom.
 func (v LogView) AppendLine(rendered string) LogView {
+	return v.AppendLineMeta(rendered, LineMeta{})
+}
+
+// AppendLineMeta appends a pre-rendered line rendered from the entry meta
+// describes.
+func (v LogView) AppendLineMeta(rendered string, meta LineMeta) LogView {
 	v.lines = append(v.lines, rendered)
-	v.vp.SetContent(strings.Join(v.lines, "\n"))
+	v.plain = append(v.plain, ansi.Strip(rendered))
+	v.meta = append(v.meta, meta)
+	v.refresh()
 	if v.follow {
 		v.vp.GotoBottom()
 	}
@@ -43,15 +170,181 @@ func (v LogView) AppendLine(rendered string) LogView {
 // SetContent replaces all log lines with the given slice.
 // Scrolls to the bottom if follow mode is enabled.
 func (v LogView) SetContent(lines []string) LogView {
+	return v.SetContentMeta(lines, nil)
+}
+
+// SetContentMeta replaces all log lines; meta, when non-nil, is parallel to
+// lines. With a search active the view jumps to the first match; otherwise
+// it scrolls to the bottom if follow mode is enabled.
+func (v LogView) SetContentMeta(lines []string, meta []LineMeta) LogView {
 	v.lines = make([]string, len(lines))
 	copy(v.lines, lines)
-	v.vp.SetContent(strings.Join(v.lines, "\n"))
+	v.plain = make([]string, len(lines))
+	v.meta = make([]LineMeta, len(lines))
+	for i, l := range lines {
+		v.plain[i] = ansi.Strip(l)
+		if i < len(meta) {
+			v.meta[i] = meta[i]
+		}
+	}
+	v.match, v.cursor = -1, -1
+	v.refresh()
+	if len(v.matches) > 0 {
+		return v.NextMatch()
+	}
 	if v.follow {
 		v.vp.GotoBottom()
 	}
 	return v
 }
 
+// SetFilter shows only the lines f matches.
+func (v LogView) SetFilter(f Filter) LogView {
+	v.filter = f
+	v.match, v.cursor = -1, -1
+	v.refresh()
+	if v.follow {
+		v.vp.GotoBottom()
+	}
+	return v
+}
+
+// SetSearch highlights lines containing query ("" clears the search). It
+// does not scroll; call NextMatch to jump to the first match.
+func (v LogView) SetSearch(query string) LogView {
+	v.query = query
+	v.match = -1
+	v.refresh()
+	return v
+}
+
+// MatchCount returns the number of visible lines matching the search.
+func (v LogView) MatchCount() int {
+	return len(v.matches)
+}
+
+// MatchIndex returns the 1-based position of the current match, or 0 before
+// the first jump.
+func (v LogView) MatchIndex() int {
+	return v.match + 1
+}
+
+// NextMatch jumps to the next matching line, wrapping at the end. The first
+// jump goes to the first match at or below the top of the view.
+func (v LogView) NextMatch() LogView {
+	if len(v.matches) == 0 {
+		return v
+	}
+	if v.match < 0 {
+		v.match = 0
+		from := v.cursorLine()
+		for i, m := range v.matches {
+			if m >= from {
+				v.match = i
+				break
+			}
+		}
+	} else {
+		v.match = (v.match + 1) % len(v.matches)
+	}
+	return v.jumpTo(v.matches[v.match])
+}
+
+// PrevMatch jumps to the previous matching line, wrapping at the start.
+func (v LogView) PrevMatch() LogView {
+	if len(v.matches) == 0 {
+		return v
+	}
+	if v.match < 0 {
+		v.match = len(v.matches) - 1
+		from := v.cursorLine()
+		for i := len(v.matches) - 1; i >= 0; i-- {
+			if v.matches[i] <= from {
+				v.match = i
+				break
+			}
+		}
+	} else {
+		v.match = (v.match + len(v.matches) - 1) % len(v.matches)
+	}
+	return v.jumpTo(v.matches[v.match])
+}
+
+// SetBookmarks sets the bookmarked entries to mark. The map is shared, not
+// copied: call SetBookmarks again after changing it to re-render.
+func (v LogView) SetBookmarks(set map[Bookmark]bool) LogView {
+	v.bookmarks = set
+	v.refresh()
+	return v
+}
+
+// NextBookmark jumps to the next bookmarked line below the cursor, wrapping
+// at the end. ok is false when no visible line is bookmarked.
+func (v LogView) NextBookmark() (_ LogView, ok bool) {
+	n := len(v.visible)
+	from := v.cursorLine()
+	for step := 1; step <= n; step++ {
+		vi := (from + step) % n
+		if v.bookmarked(v.visible[vi]) {
+			return v.jumpTo(vi), true
+		}
+	}
+	return v, false
+}
+
+// CursorLine returns the unstyled text and meta of the line bookmarks act
+// on: the last line jumped to, or the top line of the view after scrolling.
+func (v LogView) CursorLine() (text string, meta LineMeta, ok bool) {
+	vi := v.cursorLine()
+	if vi < 0 || vi >= len(v.visible) {
+		return "", LineMeta{}, false
+	}
+	i := v.visible[vi]
+	return v.plain[i], v.meta[i], true
+}
+
+// ToggleFold expands or collapses the detail lines of the fold the cursor
+// line belongs to (see CursorLine). The cursor stays on the same line, or
+// moves up to the nearest visible one when its detail line is collapsed.
+func (v LogView) ToggleFold() LogView {
+	vi := v.cursorLine()
+	if vi < 0 || vi >= len(v.visible) {
+		return v
+	}
+	i := v.visible[vi]
+	fold := v.meta[i].Fold
+	if fold == "" {
+		return v
+	}
+	expanded := make(map[string]bool, len(v.expanded)+1)
+	for k := range v.expanded {
+		expanded[k] = true
+	}
+	if expanded[fold] {
+		delete(expanded, fold)
+	} else {
+		expanded[fold] = true
+	}
+	v.expanded = expanded
+	offset := v.vp.YOffset
+	v.refresh()
+	v.cursor = 0
+	for nvi, j := range v.visible {
+		if j > i {
+			break
+		}
+		v.cursor = nvi
+	}
+	v.follow = false
+	v.vp.SetYOffset(min(offset, v.cursor))
+	return v
+}
+
+// Expanded reports whether fold's detail lines are shown.
+func (v LogView) Expanded(fold string) bool {
+	return v.expanded[fold]
+}
+
 // ToggleFollow switches follow mode on or off.
 // When turned on, scrolls immediately to the bottom.
 func (v LogView) ToggleFollow() LogView {
@@ -79,10 +372,28 @@ func (v LogView) Following() bool {
 	return v.follow
 }
 
+// GotoLine scrolls so line n (0-based) is at the top of the view and turns
+// follow mode off.
+func (v LogView) GotoLine(n int) LogView {
+	v.follow = false
+	v.cursor = n
+	v.vp.SetYOffset(n)
+	return v
+}
+
+// Offset returns the index of the line at the top of the view.
+func (v LogView) Offset() int {
+	return v.vp.YOffset
+}
+
 // Update handles bubbletea messages (scroll keys, mouse events).
 func (v LogView) Update(msg tea.Msg) (LogView, tea.Cmd) {
 	var cmd tea.Cmd
+	offset := v.vp.YOffset
 	v.vp, cmd = v.vp.Update(msg)
+	if v.vp.YOffset != offset {
+		v.cursor = -1
+	}
 	// If user scrolled away from bottom, exit follow mode.
 	if v.follow && !v.vp.AtBottom() {
 		// Only disable follow on explicit scroll messages, not on resize.
@@ -98,3 +409,91 @@ func (v LogView) Update(msg tea.Msg) (LogView, tea.Cmd) {
 func (v LogView) View() string {
 	return v.vp.View()
 }
+
+// jumpTo moves the cursor to visible line vi and scrolls it into the upper
+// third of the view, leaving context above it.
+func (v LogView) jumpTo(vi int) LogView {
+	v.follow = false
+	v.cursor = vi
+	v.refresh()
+	v.vp.SetYOffset(max(vi-v.height/3, 0))
+	return v
+}
+
+func (v LogView) cursorLine() int {
+	if v.cursor >= 0 && v.cursor < len(v.visible) {
+		return v.cursor
+	}
+	return v.vp.YOffset
+}
+
+func (v LogView) bookmarked(i int) bool {
+	b, ok := v.meta[i].Bookmark()
+	return ok && v.bookmarks[b]
+}
+
+// refresh recomputes the visible lines and search matches and re-renders
+// the viewport content.
+func (v *LogView) refresh() {
+	v.visible = make([]int, 0, len(v.lines))
+	v.matches = nil
+	for i := range v.lines {
+		if !v.filter.match(v.meta[i]) {
+			continue
+		}
+		if v.meta[i].Detail && !v.expanded[v.meta[i].Fold] {
+			continue
+		}
+		if v.query != "" && indexFold(v.plain[i], v.query) >= 0 {
+			v.matches = append(v.matches, len(v.visible))
+		}
+		v.visible = append(v.visible, i)
+	}
+	if v.match >= len(v.matches) {
+		v.match = -1
+	}
+
+	rendered := make([]string, len(v.visible))
+	for vi, i := range v.visible {
+		line := v.lines[i]
+		if v.query != "" && indexFold(v.plain[i], v.query) >= 0 {
+			style := matchStyle
+			if v.match >= 0 && v.matches[v.match] == vi {
+				style = currentMatchStyle
+			}
+			line = highlight(v.plain[i], v.query, style)
+		}
+		if v.bookmarked(i) {
+			line = bookmarkStyle.Render("★") + " " + line
+		}
+		rendered[vi] = line
+	}
+	v.vp.SetContent(strings.Join(rendered, "\n"))
+}
+
+// highlight renders plain with every case-insensitive occurrence of query
+// in style.
+func highlight(plain, query string, style lipgloss.Style) string {
+	var b strings.Builder
+	for {
+		i := indexFold(plain, query)
+		if i < 0 {
+			b.WriteString(plain)
+			return b.String()
+		}
+		b.WriteString(plain[:i])
+		b.WriteString(style.Render(plain[i : i+len(query)]))
+		plain = plain[i+len(query):]
+	}
+}
+
+// indexFold is strings.Index under Unicode case folding.
+func indexFold(s, substr string) int {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		if utf8.RuneStart(s[i]) && strings.EqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -171,3 +172,207 @@ func TestLogView_Update_AtBottom(t *testing.T) {
 		t.Error("expected follow mode to remain on when viewport is at bottom")
 	}
 }
+
+func TestLogView_GotoLine(t *testing.T) {
+	lv := NewLogView(40, 3)
+	for i := range 10 {
+		lv = lv.AppendLine(fmt.Sprintf("line %02d", i))
+	}
+	lv = lv.GotoLine(4)
+	if lv.Following() {
+		t.Error("GotoLine should turn follow mode off")
+	}
+	if lv.Offset() != 4 {
+		t.Errorf("Offset() = %d, want 4", lv.Offset())
+	}
+	if !strings.HasPrefix(lv.View(), "line 04") {
+		t.Errorf("View() should start at line 04:\n%s", lv.View())
+	}
+}
+
+// metaLines builds a LogView with one line per kind, tagged with meta.
+func metaLines(t *testing.T) LogView {
+	t.Helper()
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	lv := NewLogView(80, 4)
+	for i, l := range []struct{ text, kind, tool string }{
+		{"Read auth.go", KindTool, "Read"},
+		{"thinking about auth", KindText, ""},
+		{"Bash go test", KindTool, "Bash"},
+		{"error: build failed", KindError, ""},
+		{"pushed abc123", KindGit, ""},
+		{"regent: tests pass", KindRegent, ""},
+	} {
+		lv = lv.AppendLineMeta(l.text, LineMeta{Kind: l.kind, Tool: l.tool, Iteration: 1, At: at.Add(time.Duration(i) * time.Second)})
+	}
+	return lv.AppendLine("plain line without meta")
+}
+
+func TestLogView_Filter(t *testing.T) {
+	lv := metaLines(t)
+	f, err := ParseFilter("errors git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lv = lv.SetFilter(f).SetSize(80, 10)
+	view := lv.View()
+	for _, want := range []string{"error: build failed", "pushed abc123", "plain line without meta"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("filtered view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Read auth.go") || strings.Contains(view, "regent") {
+		t.Errorf("filtered view shows hidden kinds:\n%s", view)
+	}
+
+	f, _ = ParseFilter("tool:bash")
+	view = lv.SetFilter(f).View()
+	if !strings.Contains(view, "Bash go test") || strings.Contains(view, "Read auth.go") || strings.Contains(view, "thinking") {
+		t.Errorf("tool filter view:\n%s", view)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Filter
+		err  bool
+	}{
+		{"", Filter{}, false},
+		{"error Errors git", Filter{Kinds: []string{KindError, KindGit}}, false},
+		{"tool:Edit", Filter{Kinds: []string{KindTool}, Tool: "edit"}, false},
+		{"text tool:Bash", Filter{Kinds: []string{KindText}, Tool: "bash"}, false},
+		{"warnings", Filter{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFilter(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseFilter(%q) err = %v", tt.in, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogView_Search(t *testing.T) {
+	lv := metaLines(t).SetSearch("AUTH")
+	if lv.MatchCount() != 2 || lv.MatchIndex() != 0 {
+		t.Fatalf("matches = %d (current %d), want 2 before any jump", lv.MatchCount(), lv.MatchIndex())
+	}
+	lv = lv.NextMatch()
+	if text, _, _ := lv.CursorLine(); text != "Read auth.go" || lv.Following() {
+		t.Errorf("first match = %q (follow %v)", text, lv.Following())
+	}
+	lv = lv.NextMatch()
+	if text, _, _ := lv.CursorLine(); text != "thinking about auth" || lv.MatchIndex() != 2 {
+		t.Errorf("second match = %q (%d)", text, lv.MatchIndex())
+	}
+	lv = lv.NextMatch() // wraps
+	if lv.MatchIndex() != 1 {
+		t.Errorf("NextMatch should wrap, at %d", lv.MatchIndex())
+	}
+	lv = lv.PrevMatch()
+	if lv.MatchIndex() != 2 {
+		t.Errorf("PrevMatch should wrap, at %d", lv.MatchIndex())
+	}
+
+	// Matches are counted among visible lines only.
+	f, _ := ParseFilter("text")
+	if n := lv.SetFilter(f).MatchCount(); n != 1 {
+		t.Errorf("filtered matches = %d, want 1", n)
+	}
+	if n := lv.SetSearch("").MatchCount(); n != 0 {
+		t.Errorf("cleared search matches = %d", n)
+	}
+}
+
+func TestLogView_SetContentJumpsToMatch(t *testing.T) {
+	lv := NewLogView(80, 3).SetSearch("needle")
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	lines[12] = "the needle"
+	lv = lv.SetContent(lines)
+	if text, _, _ := lv.CursorLine(); text != "the needle" {
+		t.Errorf("cursor = %q, want the match", text)
+	}
+	if !strings.Contains(lv.View(), "needle") {
+		t.Errorf("match not in view:\n%s", lv.View())
+	}
+}
+
+func TestLogView_Bookmarks(t *testing.T) {
+	lv := metaLines(t)
+	lv = lv.GotoLine(3)
+	_, meta, ok := lv.CursorLine()
+	b, hasB := meta.Bookmark()
+	if !ok || !hasB {
+		t.Fatal("line 3 should be bookmarkable")
+	}
+	set := map[Bookmark]bool{b: true}
+	lv, ok = lv.GotoLine(0).SetBookmarks(set).NextBookmark()
+	if text, _, _ := lv.CursorLine(); !ok || text != "error: build failed" {
+		t.Errorf("NextBookmark = %q, %v", text, ok)
+	}
+	if !strings.Contains(lv.View(), "★ error: build failed") {
+		t.Errorf("bookmarked line not marked:\n%s", lv.View())
+	}
+
+	_, meta, _ = lv.GotoLine(6).CursorLine() // the plain line
+	if _, ok := meta.Bookmark(); ok {
+		t.Error("lines without meta cannot be bookmarked")
+	}
+	if _, ok := NewLogView(80, 4).AppendLine("x").NextBookmark(); ok {
+		t.Error("NextBookmark with no bookmarks should report false")
+	}
+}
+
+func TestLogView_ScrollResetsCursor(t *testing.T) {
+	lv := metaLines(t).GotoLine(0)
+	lv, _ = lv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if text, _, _ := lv.CursorLine(); text != "thinking about auth" {
+		t.Errorf("cursor after scrolling = %q, want the new top line", text)
+	}
+}
+
+func TestLogView_ToggleFold(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	call := LineMeta{Kind: KindTool, Tool: "Bash", Iteration: 1, At: at, Fold: "toolu_1"}
+	result := LineMeta{Kind: KindTool, Tool: "Bash", Iteration: 1, At: at.Add(time.Second), Fold: "toolu_1"}
+	detail := result
+	detail.Detail = true
+	lv := NewLogView(80, 10).SetContentMeta(
+		[]string{"Bash go test", "↳ ✗ FAIL (2 lines)", "--- FAIL: TestX", "FAIL", "done"},
+		[]LineMeta{call, result, detail, detail, {}},
+	)
+	if v := lv.View(); strings.Contains(v, "TestX") || !strings.Contains(v, "done") {
+		t.Fatalf("detail lines should start hidden:\n%s", v)
+	}
+
+	lv = lv.GotoLine(0).ToggleFold() // from the call line
+	if !lv.Expanded("toolu_1") || !strings.Contains(lv.View(), "--- FAIL: TestX") {
+		t.Fatalf("ToggleFold should expand the call's result:\n%s", lv.View())
+	}
+	if text, _, _ := lv.CursorLine(); text != "Bash go test" {
+		t.Errorf("cursor = %q, want it to stay on the call", text)
+	}
+	if lv.Following() {
+		t.Error("ToggleFold should turn follow mode off")
+	}
+
+	lv = lv.GotoLine(3).ToggleFold() // from a detail line
+	if lv.Expanded("toolu_1") || strings.Contains(lv.View(), "TestX") {
+		t.Errorf("ToggleFold on a detail line should collapse it:\n%s", lv.View())
+	}
+	if text, _, _ := lv.CursorLine(); text != "↳ ✗ FAIL (2 lines)" {
+		t.Errorf("cursor = %q, want the result header", text)
+	}
+
+	lv = lv.GotoLine(2).ToggleFold() // "done" has no fold
+	if text, _, _ := lv.CursorLine(); text != "done" || lv.Expanded("") {
+		t.Errorf("ToggleFold on an unfolded line changed state: cursor %q", text)
+	}
+}
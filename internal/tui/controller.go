@@ -1,5 +1,7 @@
 package tui
 
+import "github.com/LISSConsulting/RalphSpec/internal/loop"
+
 // LoopController allows the TUI to start and stop loop runs without restarting
 // the binary. It is passed to New() and used by the b/p/R/x key handlers.
 // Pass nil to disable loop-control keys — the existing ralph build/plan/run
@@ -15,3 +17,25 @@ type LoopController interface {
 	// IsRunning reports whether a loop is currently active.
 	IsRunning() bool
 }
+
+// PhaseController is implemented by LoopControllers that can also run
+// spec-kit phases, enabling the Specs panel's S/C/P/T keys. The phase shares
+// the loop's running state: StopLoop cancels it and IsRunning reports it.
+type PhaseController interface {
+	// RunPhase runs the requested phase in the background, streaming its
+	// events like a loop's. A no-op if a loop or phase is already running.
+	RunPhase(req loop.PhaseRequest)
+}
+
+// PauseController is implemented by LoopControllers that can pause a loop
+// at an iteration boundary and resume it from the saved checkpoint, enabling
+// the space key.
+type PauseController interface {
+	// PauseLoop asks the running loop to pause after its current iteration,
+	// saving a checkpoint. No-op if idle.
+	PauseLoop()
+
+	// ResumeLoop continues the loop recorded in the saved checkpoint, keeping
+	// its iteration numbering and running cost. No-op if a loop is running.
+	ResumeLoop()
+}
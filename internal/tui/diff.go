@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+// Diff tab styles.
+var (
+	diffFileStyle = lipgloss.NewStyle().Bold(true)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(colorBlue)
+	diffAddStyle  = lipgloss.NewStyle().Foreground(colorGreen)
+	diffDelStyle  = lipgloss.NewStyle().Foreground(colorRed)
+)
+
+// renderDiff renders an iteration's parsed diff for the Diff tab: a file
+// list with +/- counts, then each file's hunks with syntax-highlighted code.
+// starts holds the index in lines of each file's header, in files order, so
+// the tab can jump between files.
+func renderDiff(files []git.FileDiff, width int) (lines []string, starts []int) {
+	var added, deleted int
+	for _, f := range files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	lines = append(lines, fmt.Sprintf("%s changed  %s %s",
+		pluralFiles(len(files)),
+		diffAddStyle.Render(fmt.Sprintf("+%d", added)),
+		diffDelStyle.Render(fmt.Sprintf("-%d", deleted))))
+	for _, f := range files {
+		lines = append(lines, "  "+diffCounts(f)+"  "+f.Path)
+	}
+
+	for _, f := range files {
+		lines = append(lines, "")
+		starts = append(starts, len(lines))
+		lines = append(lines, diffFileStyle.Render("━━ "+f.Path)+"  "+diffCounts(f))
+		if f.Binary {
+			lines = append(lines, timestampStyle.Render("   (binary file)"))
+			continue
+		}
+		highlight := highlighter(f.Path)
+		for _, h := range f.Hunks {
+			lines = append(lines, diffHunkStyle.Render(truncateRunes(h.Header, width)))
+			for _, l := range h.Lines {
+				lines = append(lines, renderDiffLine(l, width, highlight))
+			}
+		}
+	}
+	return lines, starts
+}
+
+// renderDiffLine colors the +/- marker and highlights the code after it.
+func renderDiffLine(line string, width int, highlight func(string) string) string {
+	marker := line[:1]
+	code := truncateRunes(strings.ReplaceAll(line[1:], "\t", "    "), width-1)
+	switch marker {
+	case "\\":
+		return timestampStyle.Render(line)
+	case "+":
+		marker = diffAddStyle.Render(marker)
+	case "-":
+		marker = diffDelStyle.Render(marker)
+	}
+	if highlight != nil {
+		code = highlight(code)
+	}
+	return marker + code
+}
+
+// diffCounts formats a file's "+A -D" counts, or "bin" for binary files.
+func diffCounts(f git.FileDiff) string {
+	if f.Binary {
+		return timestampStyle.Render(fmt.Sprintf("%-9s", "bin"))
+	}
+	return diffAddStyle.Render(fmt.Sprintf("+%-4d", f.Added)) + diffDelStyle.Render(fmt.Sprintf("-%-4d", f.Deleted))
+}
+
+func pluralFiles(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// highlighter returns a function that syntax-highlights one line of code in
+// the language chroma detects from path, or nil for plain text.
+func highlighter(path string) func(string) string {
+	lexer := lexers.Match(path)
+	if lexer == nil || lexer.Config().Name == "plaintext" {
+		return nil
+	}
+	lexer = chroma.Coalesce(lexer)
+	style := styles.Get("monokai")
+	formatter := formatters.Get("terminal256")
+	return func(code string) string {
+		it, err := lexer.Tokenise(nil, code)
+		if err != nil {
+			return code
+		}
+		var b strings.Builder
+		if err := formatter.Format(&b, style, it); err != nil {
+			return code
+		}
+		// Lexers append a newline token; the line must stay a single row.
+		return strings.ReplaceAll(b.String(), "\n", "")
+	}
+}
+
+// truncateRunes cuts s to at most n runes so long lines do not wrap.
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
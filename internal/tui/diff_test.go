@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/LISSConsulting/RalphSpec/internal/git"
+)
+
+func TestRenderDiff(t *testing.T) {
+	files := []git.FileDiff{
+		{Path: "notes.txt", Added: 1, Deleted: 1, Hunks: []git.Hunk{{
+			Header: "@@ -1,2 +1,2 @@",
+			Lines:  []string{" keep", "-old line", "+new line", `\ No newline at end of file`},
+		}}},
+		{Path: "logo.png", Binary: true},
+	}
+	lines, starts := renderDiff(files, 80)
+	plain := make([]string, len(lines))
+	for i, l := range lines {
+		plain[i] = ansi.Strip(l)
+	}
+
+	if !strings.Contains(plain[0], "2 files changed") || !strings.Contains(plain[0], "+1 -1") {
+		t.Errorf("summary line = %q", plain[0])
+	}
+	if len(starts) != 2 {
+		t.Fatalf("starts = %v, want one per file", starts)
+	}
+	if !strings.Contains(plain[starts[0]], "notes.txt") || !strings.Contains(plain[starts[1]], "logo.png") {
+		t.Errorf("file headers = %q, %q", plain[starts[0]], plain[starts[1]])
+	}
+	body := strings.Join(plain, "\n")
+	for _, want := range []string{"@@ -1,2 +1,2 @@", " keep", "-old line", "+new line", "No newline", "(binary file)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("diff missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderDiff_HighlightsKnownLanguages(t *testing.T) {
+	files := []git.FileDiff{{Path: "main.go", Added: 1, Hunks: []git.Hunk{{
+		Header: "@@ -0,0 +1 @@",
+		Lines:  []string{"+func main() {}"},
+	}}}}
+	lines, _ := renderDiff(files, 80)
+	last := lines[len(lines)-1]
+	if ansi.Strip(last) != "+func main() {}" {
+		t.Errorf("code line = %q", ansi.Strip(last))
+	}
+	if !strings.Contains(last, "\x1b[") {
+		t.Errorf("Go code should be highlighted: %q", last)
+	}
+	if strings.Contains(last, "\n") {
+		t.Error("highlighted line must stay one row")
+	}
+}
+
+func TestRenderDiffLine_Truncates(t *testing.T) {
+	got := ansi.Strip(renderDiffLine("+"+strings.Repeat("x", 50), 10, nil))
+	if got != "+"+strings.Repeat("x", 9) {
+		t.Errorf("renderDiffLine = %q", got)
+	}
+}
@@ -45,15 +45,17 @@ const (
 	StateBuilding                       // Build loop running
 	StateFailed                         // Last run ended in failure
 	StateRegentRestart                  // Regent is restarting the loop
+	StatePaused                         // Loop paused after an iteration; resumable from its checkpoint
 )
 
 // validTransitions defines the allowed LoopState transitions.
 var validTransitions = map[LoopState][]LoopState{
 	StateIdle:          {StatePlanning, StateBuilding},
-	StatePlanning:      {StateBuilding, StateFailed, StateIdle, StateRegentRestart},
-	StateBuilding:      {StateFailed, StateIdle, StateRegentRestart},
+	StatePlanning:      {StateBuilding, StateFailed, StateIdle, StateRegentRestart, StatePaused},
+	StateBuilding:      {StateFailed, StateIdle, StateRegentRestart, StatePaused},
 	StateFailed:        {StateIdle, StateRegentRestart, StateBuilding, StatePlanning},
 	StateRegentRestart: {StateBuilding, StatePlanning, StateFailed, StateIdle},
+	StatePaused:        {StateBuilding, StatePlanning, StateFailed, StateIdle},
 }
 
 // CanTransitionTo reports whether transitioning from s to next is valid.
@@ -79,6 +81,8 @@ func (s LoopState) Label() string {
 		return "FAILED"
 	case StateRegentRestart:
 		return "REGENT RESTART"
+	case StatePaused:
+		return "PAUSED"
 	default:
 		return "UNKNOWN"
 	}
@@ -97,6 +101,8 @@ func (s LoopState) Symbol() string {
 		return "✗"
 	case StateRegentRestart:
 		return "⟳"
+	case StatePaused:
+		return "⏸"
 	default:
 		return "?"
 	}
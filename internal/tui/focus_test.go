@@ -107,6 +107,13 @@ func TestLoopState_CanTransitionTo_ValidTransitions(t *testing.T) {
 		{StateRegentRestart, StatePlanning, true},
 		{StateRegentRestart, StateFailed, true},
 		{StateRegentRestart, StateIdle, true},
+		// StatePaused
+		{StateBuilding, StatePaused, true},
+		{StatePlanning, StatePaused, true},
+		{StateIdle, StatePaused, false},
+		{StatePaused, StateBuilding, true},
+		{StatePaused, StatePlanning, true},
+		{StatePaused, StateIdle, true},
 		// Invalid same-state transitions
 		{StateIdle, StateIdle, false},
 		{StateBuilding, StateBuilding, false},
@@ -133,6 +140,7 @@ func TestLoopState_Label(t *testing.T) {
 		{StateBuilding, "BUILDING"},
 		{StateFailed, "FAILED"},
 		{StateRegentRestart, "REGENT RESTART"},
+		{StatePaused, "PAUSED"},
 		{LoopState(99), "UNKNOWN"},
 	}
 	for _, tt := range tests {
@@ -155,6 +163,7 @@ func TestLoopState_Symbol(t *testing.T) {
 		{StateBuilding, "●"},
 		{StateFailed, "✗"},
 		{StateRegentRestart, "⟳"},
+		{StatePaused, "⏸"},
 		{LoopState(99), "?"},
 	}
 	for _, tt := range tests {
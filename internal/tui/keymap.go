@@ -2,14 +2,14 @@ package tui
 
 // GlobalKeyBindings lists the keys that are always handled by the root model
 // before dispatching to focused panels.
-var GlobalKeyBindings = []string{"tab", "shift+tab", "1", "2", "3", "4", "q", "ctrl+c", "s", "?", "b", "p", "R", "x"}
+var GlobalKeyBindings = []string{"tab", "shift+tab", "1", "2", "3", "4", "q", "ctrl+c", "s", "?", "b", "p", "R", "x", " ", ":", "i"}
 
 // panelKeys maps each FocusTarget to the keys that panel handles internally.
 var panelKeys = map[FocusTarget][]string{
-	FocusSpecs:      {"j", "k", "enter", "e", "n"},
+	FocusSpecs:      {"j", "k", "enter", "e", "n", "S", "C", "P", "T"},
 	FocusIterations: {"j", "k", "enter"},
-	FocusMain:       {"f", "[", "]", "ctrl+u", "ctrl+d", "j", "k"},
-	FocusSecondary:  {"[", "]", "j", "k"},
+	FocusMain:       {"f", "[", "]", "ctrl+u", "ctrl+d", "j", "k", "n", "N", "e", "/", "F", "esc", "m", "'", "enter"},
+	FocusSecondary:  {"[", "]", "j", "k", "d"},
 }
 
 // IsGlobalKey reports whether key is a global keybinding (handled before panel dispatch).
@@ -17,6 +17,9 @@ func TestIsGlobalKey(t *testing.T) {
 		{"ctrl+c", true},
 		{"s", true},
 		{"?", true},
+		{" ", true},
+		{":", true},
+		{"i", true},
 		// Not global
 		{"j", false},
 		{"k", false},
@@ -45,9 +48,9 @@ func TestPanelKeys(t *testing.T) {
 		focus FocusTarget
 		must  []string // keys that must be present
 	}{
-		{FocusSpecs, []string{"j", "k", "enter", "e", "n"}},
+		{FocusSpecs, []string{"j", "k", "enter", "e", "n", "S", "C", "P", "T"}},
 		{FocusIterations, []string{"j", "k", "enter"}},
-		{FocusMain, []string{"f", "[", "]", "ctrl+u", "ctrl+d", "j", "k"}},
+		{FocusMain, []string{"f", "[", "]", "ctrl+u", "ctrl+d", "j", "k", "n", "N", "e", "/", "F", "esc", "m", "'"}},
 		{FocusSecondary, []string{"[", "]", "j", "k"}},
 	}
 
@@ -3,6 +3,7 @@ package tui
 import (
 	"time"
 
+	"github.com/LISSConsulting/RalphSpec/internal/git"
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
 	"github.com/LISSConsulting/RalphSpec/internal/spec"
 	"github.com/LISSConsulting/RalphSpec/internal/store"
@@ -20,11 +21,14 @@ type loopErrMsg struct{ err error }
 // tickMsg is sent every second for the clock.
 type tickMsg time.Time
 
-// iterationLogLoadedMsg carries loaded iteration log data.
+// iterationLogLoadedMsg carries loaded iteration log data. Diff is the
+// iteration's commit-range diff; it is nil when no range was recorded.
 type iterationLogLoadedMsg struct {
 	Number  int
 	Entries []loop.LogEntry
 	Summary store.IterationSummary
+	Diff    []git.FileDiff
+	DiffErr error
 	Err     error
 }
 
@@ -37,11 +41,32 @@ type gitInfoMsg struct {
 	LastCommit string
 }
 
-// iterationsLoadedMsg carries iteration summaries pre-loaded from the store on startup.
+// iterationsLoadedMsg carries iteration summaries, bookmarks and tool usage
+// pre-loaded from the store on startup.
 type iterationsLoadedMsg struct {
 	Summaries []store.IterationSummary
+	Bookmarks []store.Bookmark
+	Tools     []store.IterationTools
 }
 
+// searchResultsMsg carries the completed iterations whose logs match Query.
+type searchResultsMsg struct {
+	Query      string
+	Iterations []int
+	Err        error
+}
+
+// steerNotesMsg carries the queued steering notes after a load or change.
+// Added is the text of a note just queued.
+type steerNotesMsg struct {
+	Notes []loop.SteerNote
+	Added string
+	Err   error
+}
+
+// bookmarkSavedMsg reports the result of persisting a bookmark change.
+type bookmarkSavedMsg struct{ Err error }
+
 // taggedEventMsg wraps a log entry from the orchestrator fan-in channel together
 // with the source worktree branch name.  Defined here without importing
 // orchestrator so that msg.go stays import-free of business-logic packages.
@@ -16,6 +16,7 @@ type HeaderProps struct {
 	ProjectName string
 	WorkDir     string
 	Branch      string
+	PushGuard   string // push-safety status, e.g. "dry-run" or "refused (protected)"
 	Mode        string
 	Iteration   int
 	MaxIter     int
@@ -24,6 +25,10 @@ type HeaderProps struct {
 	StateLabel  string // e.g. "BUILDING", "IDLE", "FAILED"
 	Elapsed     time.Duration
 	Clock       time.Time
+
+	// RateLimitUntil is when the Claude usage limit the loop is sleeping on
+	// lifts; a countdown is shown while it is after Clock.
+	RateLimitUntil time.Time
 }
 
 // AbbreviatePath returns a display-friendly path, replacing the home directory
@@ -80,6 +85,9 @@ func RenderHeader(props HeaderProps, width int, accentStyle lipgloss.Style) stri
 	if branch == "" {
 		branch = "—"
 	}
+	if props.PushGuard != "" {
+		branch += " ⛔ push: " + props.PushGuard
+	}
 
 	mode := props.Mode
 	if mode == "" {
@@ -110,6 +118,9 @@ func RenderHeader(props HeaderProps, width int, accentStyle lipgloss.Style) stri
 	if stateLabel != "" {
 		parts = append(parts, stateLabel)
 	}
+	if props.RateLimitUntil.After(props.Clock) {
+		parts = append(parts, fmt.Sprintf("⏸ usage limit — resumes in %s", FormatElapsed(props.RateLimitUntil.Sub(props.Clock))))
+	}
 	if props.Elapsed > 0 {
 		parts = append(parts, fmt.Sprintf("elapsed: %s", FormatElapsed(props.Elapsed)))
 	}
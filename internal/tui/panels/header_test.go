@@ -111,6 +111,22 @@ func TestRenderHeader_Elapsed(t *testing.T) {
 	}
 }
 
+func TestRenderHeader_RateLimitCountdown(t *testing.T) {
+	accent := lipgloss.NewStyle()
+	now := time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC)
+	props := HeaderProps{Clock: now, RateLimitUntil: now.Add(90 * time.Minute)}
+
+	rendered := RenderHeader(props, 300, accent)
+	if !strings.Contains(rendered, "resumes in 1h30m") {
+		t.Errorf("RenderHeader() should show the usage-limit countdown; got %q", rendered)
+	}
+
+	props.Clock = now.Add(2 * time.Hour)
+	if rendered := RenderHeader(props, 300, accent); strings.Contains(rendered, "usage limit") {
+		t.Errorf("countdown should disappear once the limit lifts; got %q", rendered)
+	}
+}
+
 func TestAbbreviatePath(t *testing.T) {
 	// Empty string returns empty.
 	if got := AbbreviatePath(""); got != "" {
@@ -188,3 +204,16 @@ func TestFormatElapsed(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderHeader_PushGuard(t *testing.T) {
+	props := HeaderProps{Branch: "main", PushGuard: "refused (protected)"}
+	rendered := RenderHeader(props, 200, lipgloss.NewStyle())
+	if !strings.Contains(rendered, "branch: main ⛔ push: refused (protected)") {
+		t.Errorf("RenderHeader() missing push guard; got %q", rendered)
+	}
+
+	rendered = RenderHeader(HeaderProps{Branch: "main"}, 200, lipgloss.NewStyle())
+	if strings.Contains(rendered, "push:") {
+		t.Errorf("RenderHeader() without guard should not mention push; got %q", rendered)
+	}
+}
@@ -19,6 +19,7 @@ type IterationSelectedMsg struct{ Number int }
 type iterItem struct {
 	summary store.IterationSummary
 	running bool // true if this is the currently-running iteration
+	marked  bool // true if the iteration's log matches the active search
 }
 
 func (i iterItem) Title() string {
@@ -28,7 +29,11 @@ func (i iterItem) Title() string {
 	} else if i.summary.Subtype == "error_max_turns" || i.summary.Subtype == "error" {
 		status = "✗"
 	}
-	return fmt.Sprintf("#%d %s %s", i.summary.Number, i.summary.Mode, status)
+	title := fmt.Sprintf("#%d %s %s", i.summary.Number, i.summary.Mode, status)
+	if i.marked {
+		title += " 🔍"
+	}
+	return title
 }
 
 func (i iterItem) Description() string {
@@ -47,6 +52,7 @@ type IterationsPanel struct {
 	list       list.Model
 	iterations []store.IterationSummary
 	currentNum *int // currently running iteration number (nil if idle)
+	marked     map[int]bool
 	width      int
 	height     int
 }
@@ -105,12 +111,23 @@ func (p IterationsPanel) SetCurrent(n int) IterationsPanel {
 	return p
 }
 
+// SetMarked marks the iterations whose logs match the active search; nil
+// clears the marks.
+func (p IterationsPanel) SetMarked(nums []int) IterationsPanel {
+	p.marked = make(map[int]bool, len(nums))
+	for _, n := range nums {
+		p.marked[n] = true
+	}
+	p.list.SetItems(p.buildItems())
+	return p
+}
+
 // buildItems rebuilds the list.Item slice from stored summaries.
 func (p IterationsPanel) buildItems() []list.Item {
 	items := make([]list.Item, len(p.iterations))
 	for i, s := range p.iterations {
 		running := p.currentNum != nil && *p.currentNum == s.Number
-		items[i] = iterItem{summary: s, running: running}
+		items[i] = iterItem{summary: s, running: running, marked: p.marked[s.Number]}
 	}
 	return items
 }
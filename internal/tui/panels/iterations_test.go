@@ -233,3 +233,17 @@ func TestIterItem_Title(t *testing.T) {
 		})
 	}
 }
+
+func TestIterationsPanel_SetMarked(t *testing.T) {
+	p := NewIterationsPanel(40, 10).
+		AddIteration(makeSummary(1, "build", "success", 0.1, 1)).
+		AddIteration(makeSummary(2, "build", "success", 0.1, 1))
+	p = p.SetMarked([]int{2})
+	view := p.View()
+	if !strings.Contains(view, "#2 build ✓ 🔍") || strings.Contains(view, "#1 build ✓ 🔍") {
+		t.Errorf("only #2 should be marked:\n%s", view)
+	}
+	if view := p.SetMarked(nil).View(); strings.Contains(view, "🔍") {
+		t.Errorf("SetMarked(nil) should clear marks:\n%s", view)
+	}
+}
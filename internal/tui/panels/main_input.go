@@ -0,0 +1,41 @@
+package panels
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InputSubmittedMsg is emitted when the user answers an AskInput prompt with
+// enter, or dismisses it with esc (Cancelled).
+type InputSubmittedMsg struct {
+	Text      string
+	Cancelled bool
+}
+
+// AskInput opens a free-text prompt labelled label in the search bar row,
+// e.g. to answer a question asked by a spec-kit phase. The answer arrives as
+// an InputSubmittedMsg.
+func (v MainView) AskInput(label string) (MainView, tea.Cmd) {
+	return v.openPrompt(promptInput, label, "")
+}
+
+// updateInput handles keys while the AskInput prompt is open. Enter with no
+// text is ignored so an answer is not sent by accident.
+func (v MainView) updateInput(msg tea.KeyMsg) (MainView, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v = v.closePrompt().resize()
+		return v, func() tea.Msg { return InputSubmittedMsg{Cancelled: true} }
+	case "enter":
+		text := strings.TrimSpace(v.prompt.Value())
+		if text == "" {
+			return v, nil
+		}
+		v = v.closePrompt().resize()
+		return v, func() tea.Msg { return InputSubmittedMsg{Text: text} }
+	}
+	var cmd tea.Cmd
+	v.prompt, cmd = v.prompt.Update(msg)
+	return v, cmd
+}
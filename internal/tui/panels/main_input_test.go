@@ -0,0 +1,44 @@
+package panels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMainView_AskInput(t *testing.T) {
+	v, _ := searchView().AskInput("answer: ")
+	if !v.InputActive() || !strings.Contains(v.barView(), "answer: ") {
+		t.Fatalf("AskInput should open a labelled prompt; bar = %q", v.barView())
+	}
+
+	// Shortcut keys such as n and / are typed into the prompt.
+	v, msgs := keys(v, "n", "/", "enter")
+	if len(msgs) != 1 {
+		t.Fatalf("msgs = %#v, want one InputSubmittedMsg", msgs)
+	}
+	if got := msgs[0].(InputSubmittedMsg); got.Text != "n/" || got.Cancelled {
+		t.Errorf("submitted = %+v, want Text %q", got, "n/")
+	}
+	if v.InputActive() || v.barVisible() {
+		t.Error("enter should close the prompt and hide the bar")
+	}
+}
+
+func TestMainView_AskInput_EmptyEnterIgnored(t *testing.T) {
+	v, _ := searchView().AskInput("answer: ")
+	v, msgs := keys(v, "enter")
+	if !v.InputActive() || len(msgs) != 0 {
+		t.Errorf("enter with no text: active %v, msgs %#v; want the prompt to stay open", v.InputActive(), msgs)
+	}
+}
+
+func TestMainView_AskInput_Esc(t *testing.T) {
+	v, _ := searchView().AskInput("answer: ")
+	v, msgs := keys(v, "x", "esc")
+	if v.InputActive() {
+		t.Error("esc should close the prompt")
+	}
+	if len(msgs) != 1 || !msgs[0].(InputSubmittedMsg).Cancelled {
+		t.Errorf("msgs = %#v, want a cancelled InputSubmittedMsg", msgs)
+	}
+}
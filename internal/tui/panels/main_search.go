@@ -0,0 +1,234 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
+)
+
+// promptKind is the input prompt open in the main view's search/filter bar.
+type promptKind int
+
+const (
+	promptNone   promptKind = iota
+	promptSearch            // "/" search query
+	promptFilter            // "F" filter terms
+	promptInput             // free text requested with AskInput
+)
+
+// SearchRequestMsg is emitted when the user submits (or clears, with an
+// empty Query) a search, so the app can search every iteration in the
+// session store.
+type SearchRequestMsg struct{ Query string }
+
+// BookmarkToggledMsg is emitted when the user bookmarks (On) or removes the
+// bookmark from a log line with 'm'.
+type BookmarkToggledMsg struct {
+	Bookmark components.Bookmark
+	Text     string // the line's unstyled text
+	On       bool
+}
+
+var searchBarStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+// InputActive reports whether the search, filter, or AskInput prompt is
+// open, so the app can route every key to it instead of treating keys as
+// shortcuts.
+func (v MainView) InputActive() bool {
+	return v.promptKind != promptNone
+}
+
+// SetSearchIterations records which completed iterations match query, for
+// the search bar. Results for a query that is no longer active are dropped.
+func (v MainView) SetSearchIterations(query string, iters []int) MainView {
+	if query == v.query {
+		v.searchIters = iters
+	}
+	return v
+}
+
+// SetBookmarks replaces the bookmarked entries shown in every tab.
+func (v MainView) SetBookmarks(bookmarks []components.Bookmark) MainView {
+	v.bookmarks = make(map[components.Bookmark]bool, len(bookmarks))
+	for _, b := range bookmarks {
+		v.bookmarks[b] = true
+	}
+	return v.applyBookmarks()
+}
+
+// updateSearch handles the search, filter, and bookmark keys outside the
+// prompt.
+func (v MainView) updateSearch(key string) (MainView, tea.Cmd) {
+	lv := v.activeLogView()
+	switch key {
+	case "/":
+		v.prevQuery = v.query
+		return v.openPrompt(promptSearch, "/", v.query)
+	case "F":
+		return v.openPrompt(promptFilter, "filter: ", v.filterText)
+	case "n":
+		*lv = lv.NextMatch()
+	case "N":
+		*lv = lv.PrevMatch()
+	case "esc":
+		if v.query != "" {
+			v = v.applySearch("").resize()
+			return v, searchCmd("")
+		}
+		if v.filterText != "" || v.filterErr != "" {
+			v.filterText, v.filterErr = "", ""
+			v = v.applyFilter(components.Filter{}).resize()
+		}
+	case "m":
+		text, meta, ok := lv.CursorLine()
+		b, hasB := meta.Bookmark()
+		if !ok || !hasB {
+			return v, nil
+		}
+		on := !v.bookmarks[b]
+		if on {
+			v.bookmarks[b] = true
+		} else {
+			delete(v.bookmarks, b)
+		}
+		v = v.applyBookmarks()
+		return v, func() tea.Msg { return BookmarkToggledMsg{Bookmark: b, Text: text, On: on} }
+	case "'":
+		*lv, _ = lv.NextBookmark()
+	}
+	return v, nil
+}
+
+// updatePrompt handles keys while the search or filter prompt is open.
+// Searches update as the user types; enter keeps the result and esc
+// restores the previous query.
+func (v MainView) updatePrompt(msg tea.KeyMsg) (MainView, tea.Cmd) {
+	kind := v.promptKind
+	if kind == promptInput {
+		return v.updateInput(msg)
+	}
+	switch msg.String() {
+	case "esc":
+		v = v.closePrompt()
+		if kind == promptSearch {
+			v = v.applySearch(v.prevQuery)
+		}
+		return v.resize(), nil
+	case "enter":
+		value := strings.TrimSpace(v.prompt.Value())
+		v = v.closePrompt()
+		if kind == promptFilter {
+			f, err := components.ParseFilter(value)
+			v.filterText, v.filterErr = value, ""
+			if err != nil {
+				v.filterText, v.filterErr = "", err.Error()
+			}
+			return v.applyFilter(f).resize(), nil
+		}
+		v = v.applySearch(value).resize()
+		return v, searchCmd(value)
+	}
+	var cmd tea.Cmd
+	v.prompt, cmd = v.prompt.Update(msg)
+	if kind == promptSearch {
+		v = v.applySearch(v.prompt.Value())
+	}
+	return v, cmd
+}
+
+func searchCmd(query string) tea.Cmd {
+	return func() tea.Msg { return SearchRequestMsg{Query: query} }
+}
+
+func (v MainView) openPrompt(kind promptKind, label, value string) (MainView, tea.Cmd) {
+	v.promptKind = kind
+	v.prompt.Prompt = label
+	v.prompt.SetValue(value)
+	v.prompt.CursorEnd()
+	cmd := v.prompt.Focus()
+	return v.resize(), cmd
+}
+
+func (v MainView) closePrompt() MainView {
+	v.promptKind = promptNone
+	v.prompt.Blur()
+	return v
+}
+
+// applySearch sets query on every tab and jumps to the first match in the
+// active one.
+func (v MainView) applySearch(query string) MainView {
+	if query != v.query {
+		v.searchIters = nil
+	}
+	v.query = query
+	for _, lv := range v.logViews() {
+		*lv = lv.SetSearch(query)
+	}
+	if query != "" {
+		lv := v.activeLogView()
+		*lv = lv.NextMatch()
+	}
+	return v
+}
+
+func (v MainView) applyFilter(f components.Filter) MainView {
+	for _, lv := range v.logViews() {
+		*lv = lv.SetFilter(f)
+	}
+	return v
+}
+
+func (v MainView) applyBookmarks() MainView {
+	for _, lv := range v.logViews() {
+		*lv = lv.SetBookmarks(v.bookmarks)
+	}
+	return v
+}
+
+// logViews returns pointers to every tab's LogView.
+func (v *MainView) logViews() []*components.LogView {
+	return []*components.LogView{&v.outputLog, &v.specLog, &v.iterationLog, &v.summaryLog, &v.diffLog}
+}
+
+// barVisible reports whether the search/filter bar row is shown.
+func (v MainView) barVisible() bool {
+	return v.promptKind != promptNone || v.query != "" || v.filterText != "" || v.filterErr != ""
+}
+
+// barView renders the search/filter bar: the open prompt, or the active
+// search with its match position and the iterations it was found in, and
+// the active filter.
+func (v MainView) barView() string {
+	if v.promptKind != promptNone {
+		return v.prompt.View()
+	}
+	var parts []string
+	if v.query != "" {
+		lv := v.activeLogView()
+		part := fmt.Sprintf("/%s  %d/%d", v.query, lv.MatchIndex(), lv.MatchCount())
+		if len(v.searchIters) > 0 {
+			nums := make([]string, len(v.searchIters))
+			for i, n := range v.searchIters {
+				nums[i] = fmt.Sprintf("#%d", n)
+			}
+			part += "  in iterations " + strings.Join(nums, " ")
+		}
+		parts = append(parts, part)
+	}
+	if v.filterText != "" {
+		parts = append(parts, "filter: "+v.filterText)
+	}
+	if v.filterErr != "" {
+		parts = append(parts, "filter: "+v.filterErr)
+	}
+	bar := strings.Join(parts, "  ·  ")
+	if r := []rune(bar); v.width > 0 && len(r) > v.width {
+		bar = string(r[:v.width])
+	}
+	return searchBarStyle.Render(bar)
+}
@@ -0,0 +1,174 @@
+package panels
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
+)
+
+// keys sends each key to v and returns the messages of the commands issued
+// outside the prompt (commands from an open prompt only blink the cursor).
+func keys(v MainView, ks ...string) (MainView, []tea.Msg) {
+	var msgs []tea.Msg
+	for _, k := range ks {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		var cmd tea.Cmd
+		v, cmd = v.Update(msg)
+		if cmd != nil && !v.InputActive() {
+			if m := cmd(); m != nil {
+				msgs = append(msgs, m)
+			}
+		}
+	}
+	return v, msgs
+}
+
+func searchView() MainView {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	v := NewMainView(80, 10)
+	for i, l := range []struct{ text, kind, tool string }{
+		{"Read auth.go", components.KindTool, "Read"},
+		{"thinking", components.KindText, ""},
+		{"error: auth failed", components.KindError, ""},
+	} {
+		v = v.AppendLineMeta(l.text, components.LineMeta{Kind: l.kind, Tool: l.tool, Iteration: 3, At: at.Add(time.Duration(i) * time.Second)})
+	}
+	return v
+}
+
+func TestMainView_Search(t *testing.T) {
+	v, _ := keys(searchView(), "/")
+	if !v.InputActive() {
+		t.Fatal("/ should open the search prompt")
+	}
+	v, msgs := keys(v, "a", "u", "t", "h")
+	if v.query != "auth" || v.outputLog.MatchCount() != 2 {
+		t.Errorf("incremental search: query %q, %d matches", v.query, v.outputLog.MatchCount())
+	}
+	if len(msgs) != 0 {
+		t.Errorf("typing should not search the store yet: %v", msgs)
+	}
+	v, msgs = keys(v, "enter")
+	if v.InputActive() {
+		t.Error("enter should close the prompt")
+	}
+	if len(msgs) != 1 || msgs[0] != (SearchRequestMsg{Query: "auth"}) {
+		t.Errorf("enter msgs = %v, want SearchRequestMsg", msgs)
+	}
+
+	v = v.SetSearchIterations("auth", []int{3, 7})
+	v = v.SetSearchIterations("stale", []int{9})
+	if bar := v.barView(); !strings.Contains(bar, "/auth  1/2") || !strings.Contains(bar, "#3 #7") || strings.Contains(bar, "#9") {
+		t.Errorf("bar = %q", bar)
+	}
+	v, _ = keys(v, "n")
+	if v.outputLog.MatchIndex() != 2 {
+		t.Errorf("n should go to match 2, at %d", v.outputLog.MatchIndex())
+	}
+
+	// The query applies to the other tabs too.
+	v = v.ShowIterationLog([]string{"x", "auth here"})
+	if v.iterationLog.MatchCount() != 1 {
+		t.Errorf("iteration tab matches = %d", v.iterationLog.MatchCount())
+	}
+
+	// A cancelled prompt restores the previous query.
+	v, _ = keys(v, "/", "x", "esc")
+	if v.query != "auth" || v.InputActive() {
+		t.Errorf("after esc: query %q, prompt %v", v.query, v.InputActive())
+	}
+	v, msgs = keys(v, "esc")
+	if v.query != "" || len(msgs) != 1 || msgs[0] != (SearchRequestMsg{}) {
+		t.Errorf("esc should clear the search: query %q, msgs %v", v.query, msgs)
+	}
+	if v.barVisible() {
+		t.Error("bar should hide with no search or filter")
+	}
+}
+
+func TestMainView_Filter(t *testing.T) {
+	v, _ := keys(searchView(), "F", "e", "r", "r", "o", "r", "enter")
+	view := v.View()
+	if !strings.Contains(view, "filter: error") || !strings.Contains(view, "error: auth failed") || strings.Contains(view, "Read auth.go") {
+		t.Errorf("filtered view:\n%s", view)
+	}
+
+	v, _ = keys(v, "F")
+	if v.prompt.Value() != "error" {
+		t.Errorf("filter prompt should start with the active filter, got %q", v.prompt.Value())
+	}
+	v, _ = keys(v, "s", "enter") // "errors" is accepted too
+	if v.filterErr != "" || v.filterText != "errors" {
+		t.Errorf("filterText %q, err %q", v.filterText, v.filterErr)
+	}
+
+	v, _ = keys(v, "F", " ", "x", "enter")
+	if v.filterErr == "" || !strings.Contains(v.View(), "unknown filter") {
+		t.Errorf("bad filter should be reported:\n%s", v.View())
+	}
+	v, _ = keys(v, "esc")
+	if v.barVisible() || !strings.Contains(v.View(), "Read auth.go") {
+		t.Errorf("esc should clear the filter:\n%s", v.View())
+	}
+}
+
+func TestMainView_Bookmarks(t *testing.T) {
+	v, msgs := keys(searchView(), "m")
+	if len(msgs) != 1 {
+		t.Fatalf("m msgs = %v", msgs)
+	}
+	toggled, ok := msgs[0].(BookmarkToggledMsg)
+	if !ok || !toggled.On || toggled.Text != "Read auth.go" || toggled.Bookmark.Iteration != 3 {
+		t.Errorf("BookmarkToggledMsg = %+v", msgs[0])
+	}
+	if !strings.Contains(v.View(), "★ Read auth.go") {
+		t.Errorf("bookmark not shown:\n%s", v.View())
+	}
+	_, msgs = keys(v, "m")
+	if toggled := msgs[0].(BookmarkToggledMsg); toggled.On {
+		t.Error("second m should remove the bookmark")
+	}
+
+	// Bookmarks loaded from the store mark matching lines in every tab.
+	_, meta, _ := searchView().outputLog.GotoLine(0).CursorLine()
+	v = searchView().SetBookmarks([]components.Bookmark{toggled.Bookmark})
+	v, _ = keys(v.ShowIterationLines([]string{"other", "Read auth.go"}, []components.LineMeta{{}, meta}), "'")
+	if text, _, _ := v.iterationLog.CursorLine(); text != "Read auth.go" {
+		t.Errorf("' should jump to the bookmark, at %q", text)
+	}
+}
+
+func TestMainView_SearchOverridesDiffKeys(t *testing.T) {
+	v := NewMainView(80, 5).SetDiff([]string{"list", "", "== a.go", "+auth", "", "== b.go", "+auth"}, []int{2, 5}, []string{"a.go", "b.go"})
+	v.activeTab = TabDiff
+	v, _ = keys(v, "n")
+	if v.diffFile != 0 {
+		t.Errorf("n without a search should jump files, diffFile = %d", v.diffFile)
+	}
+	v, _ = keys(v, "/", "a", "u", "t", "h", "enter", "n")
+	if v.diffLog.MatchIndex() != 2 || v.diffFile != 0 {
+		t.Errorf("n with a search should jump matches: match %d, diffFile %d", v.diffLog.MatchIndex(), v.diffFile)
+	}
+}
+
+func TestMainView_PromptTakesKeys(t *testing.T) {
+	v, _ := keys(searchView(), "/", "]", "f")
+	if v.activeTab != TabOutput || !v.outputLog.Following() {
+		t.Error("keys typed into the prompt must not act as shortcuts")
+	}
+	if v.prompt.Value() != "]f" {
+		t.Errorf("prompt value = %q", v.prompt.Value())
+	}
+}
@@ -1,6 +1,7 @@
 package panels
 
 import (
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -15,8 +16,14 @@ const (
 	TabSpecContent                     // Spec file content viewer (US2)
 	TabIterationDetail                 // Past iteration log drill-down (US3)
 	TabIterationSummary                // Iteration metadata summary (US3)
+	TabDiff                            // Selected iteration's commit-range diff
+	TabSpend                           // Cost analytics across all sessions
 )
 
+// OpenFileRequestMsg is emitted when the user presses 'e' on a file in the
+// Diff tab. Path is relative to the repository root.
+type OpenFileRequestMsg struct{ Path string }
+
 // MainView is the main (right-top) panel showing loop output and spec/iteration content.
 // Each tab owns its own LogView so content is never displaced by output from another tab.
 type MainView struct {
@@ -25,12 +32,27 @@ type MainView struct {
 	specLog      components.LogView // Tab 1: spec file content
 	iterationLog components.LogView // Tab 2: past iteration log
 	summaryLog   components.LogView // Tab 3: iteration metadata summary
+	diffLog      components.LogView // Tab 4: iteration diff
+	costLog      components.LogView // Tab 5: cost analytics (Spend)
+	diffPaths    []string           // file paths in the diff, in display order
+	diffStarts   []int              // diffLog line index of each file's header
+	diffFile     int                // current file for n/N/e; -1 on the file list
 	width        int
 	height       int
 	activeTab    MainTab
+
+	// Search, filter, and bookmarks apply to every tab (see main_search.go).
+	prompt      textinput.Model
+	promptKind  promptKind
+	prevQuery   string // query to restore when a search prompt is cancelled
+	query       string
+	filterText  string // active filter as typed, e.g. "error tool:Bash"
+	filterErr   string
+	searchIters []int // completed iterations whose logs match query
+	bookmarks   map[components.Bookmark]bool
 }
 
-var mainTabLabels = []string{"Output", "Spec", "Iteration", "Summary"}
+var mainTabLabels = []string{"Output", "Spec", "Iteration", "Summary", "Diff", "Spend"}
 
 // NewMainView creates a MainView with the output tab active.
 func NewMainView(w, h int) MainView {
@@ -38,14 +60,21 @@ func NewMainView(w, h int) MainView {
 	if contentH < 1 {
 		contentH = 1
 	}
+	ti := textinput.New()
+	ti.CharLimit = 200
 	return MainView{
 		tabbar:       components.NewTabBar(mainTabLabels).SetWidth(w),
 		outputLog:    components.NewLogView(w, contentH),
 		specLog:      components.NewLogView(w, contentH),
 		iterationLog: components.NewLogView(w, contentH),
 		summaryLog:   components.NewLogView(w, contentH),
+		diffLog:      components.NewLogView(w, contentH),
+		costLog:      components.NewLogView(w, contentH).ToggleFollow(), // report reads top-down
+		diffFile:     -1,
 		width:        w,
 		height:       h,
+		prompt:       ti,
+		bookmarks:    make(map[components.Bookmark]bool),
 	}
 }
 
@@ -57,6 +86,13 @@ func (v MainView) AppendLine(rendered string) MainView {
 	return v
 }
 
+// AppendLineMeta appends a line rendered from a loop entry to the output
+// log; meta makes it filterable and bookmarkable.
+func (v MainView) AppendLineMeta(rendered string, meta components.LineMeta) MainView {
+	v.outputLog = v.outputLog.AppendLineMeta(rendered, meta)
+	return v
+}
+
 // ShowSpec loads spec content into the spec viewer and switches to TabSpecContent.
 func (v MainView) ShowSpec(content string) MainView {
 	v.specLog = v.specLog.SetContent(splitLines(content))
@@ -71,7 +107,13 @@ func (v MainView) ShowSpec(content string) MainView {
 // ShowIterationLog loads a past iteration's log entries and switches to TabIterationDetail.
 // entries are pre-rendered strings (app.go renders via theme.RenderLogLine before passing).
 func (v MainView) ShowIterationLog(rendered []string) MainView {
-	v.iterationLog = v.iterationLog.SetContent(rendered)
+	return v.ShowIterationLines(rendered, nil)
+}
+
+// ShowIterationLines is ShowIterationLog with per-line meta (parallel to
+// rendered) so the lines can be filtered and bookmarked.
+func (v MainView) ShowIterationLines(rendered []string, meta []components.LineMeta) MainView {
+	v.iterationLog = v.iterationLog.SetContentMeta(rendered, meta)
 	v.activeTab = TabIterationDetail
 	v.tabbar = components.NewTabBar(mainTabLabels).SetWidth(v.width)
 	for i := 0; i < int(TabIterationDetail); i++ {
@@ -87,6 +129,68 @@ func (v MainView) SetIterationSummary(lines []string) MainView {
 	return v
 }
 
+// SetDiff loads a rendered iteration diff into the Diff tab. starts holds
+// the line index of each file's header and paths the matching file paths,
+// for the n/N/e keys. The tab is not switched; the user navigates to Diff
+// with ].
+func (v MainView) SetDiff(lines []string, starts []int, paths []string) MainView {
+	v.diffLog = v.diffLog.SetContent(lines).GotoLine(0)
+	v.diffStarts = starts
+	v.diffPaths = paths
+	v.diffFile = -1
+	return v
+}
+
+// SetSpend loads the rendered cost report into the Spend tab. The tab never
+// follows, so refreshes keep the scroll position. The tab is not
+// switched; the user navigates to Spend with ].
+func (v MainView) SetSpend(lines []string) MainView {
+	v.costLog = v.costLog.SetContent(lines)
+	return v
+}
+
+// diffFileAt returns the index of the last file whose header is at or
+// above the Diff tab's scroll offset, or -1 while the file list is in view.
+func (v MainView) diffFileAt() int {
+	cur := -1
+	for i, start := range v.diffStarts {
+		if start <= v.diffLog.Offset() {
+			cur = i
+		}
+	}
+	return cur
+}
+
+// updateDiff handles the Diff tab's file keys: n and N jump to the next and
+// previous file, e opens the current file in $EDITOR. Other keys scroll and
+// re-derive the current file from the scroll offset.
+func (v MainView) updateDiff(msg tea.KeyMsg) (MainView, tea.Cmd) {
+	switch msg.String() {
+	case "n":
+		if v.diffFile+1 < len(v.diffStarts) {
+			v.diffFile++
+			v.diffLog = v.diffLog.GotoLine(v.diffStarts[v.diffFile])
+		}
+	case "N":
+		if v.diffFile > 0 {
+			v.diffFile--
+			v.diffLog = v.diffLog.GotoLine(v.diffStarts[v.diffFile])
+		}
+	case "e":
+		if len(v.diffPaths) == 0 {
+			return v, nil
+		}
+		path := v.diffPaths[max(v.diffFile, 0)]
+		return v, func() tea.Msg { return OpenFileRequestMsg{Path: path} }
+	default:
+		var cmd tea.Cmd
+		v.diffLog, cmd = v.diffLog.Update(msg)
+		v.diffFile = v.diffFileAt()
+		return v, cmd
+	}
+	return v, nil
+}
+
 // SwitchToOutput returns to the live output tab.
 func (v MainView) SwitchToOutput() MainView {
 	v.activeTab = TabOutput
@@ -108,15 +212,28 @@ func (v MainView) ShowWorktreeLog(lines []string) MainView {
 func (v MainView) SetSize(w, h int) MainView {
 	v.width = w
 	v.height = h
-	contentH := h - 1
+	v.tabbar = v.tabbar.SetWidth(w)
+	return v.resize()
+}
+
+// resize sizes every tab's LogView to the space under the tab bar and, when
+// shown, the search/filter bar.
+func (v MainView) resize() MainView {
+	w := v.width
+	contentH := v.height - 1
+	if v.barVisible() {
+		contentH--
+	}
 	if contentH < 1 {
 		contentH = 1
 	}
-	v.tabbar = v.tabbar.SetWidth(w)
+	v.prompt.Width = max(w-12, 1)
 	v.outputLog = v.outputLog.SetSize(w, contentH)
 	v.specLog = v.specLog.SetSize(w, contentH)
 	v.iterationLog = v.iterationLog.SetSize(w, contentH)
 	v.summaryLog = v.summaryLog.SetSize(w, contentH)
+	v.diffLog = v.diffLog.SetSize(w, contentH)
+	v.costLog = v.costLog.SetSize(w, contentH)
 	return v
 }
 
@@ -130,6 +247,10 @@ func (v *MainView) activeLogView() *components.LogView {
 		return &v.iterationLog
 	case TabIterationSummary:
 		return &v.summaryLog
+	case TabDiff:
+		return &v.diffLog
+	case TabSpend:
+		return &v.costLog
 	default:
 		return &v.outputLog
 	}
@@ -140,23 +261,45 @@ func (v MainView) Update(msg tea.Msg) (MainView, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if v.promptKind != promptNone {
+			return v.updatePrompt(msg)
+		}
 		switch msg.String() {
+		case "/", "F", "esc", "m", "'":
+			return v.updateSearch(msg.String())
+		case "n", "N":
+			if v.query != "" {
+				return v.updateSearch(msg.String())
+			}
+			if v.activeTab == TabDiff {
+				return v.updateDiff(msg)
+			}
 		case "]":
 			v.tabbar = v.tabbar.Next()
 			v.activeTab = MainTab(v.tabbar.Active())
 		case "[":
 			v.tabbar = v.tabbar.Prev()
 			v.activeTab = MainTab(v.tabbar.Active())
+		case "enter":
+			lv := v.activeLogView()
+			*lv = lv.ToggleFold()
 		case "f":
-			if v.activeTab != TabIterationSummary {
+			if v.activeTab != TabIterationSummary && v.activeTab != TabDiff && v.activeTab != TabSpend {
 				lv := v.activeLogView()
 				*lv = lv.ToggleFollow()
 			}
 		default:
+			if v.activeTab == TabDiff {
+				return v.updateDiff(msg)
+			}
 			lv := v.activeLogView()
 			*lv, cmd = lv.Update(msg)
 		}
 	default:
+		if v.promptKind != promptNone {
+			v.prompt, cmd = v.prompt.Update(msg) // cursor blink
+			return v, cmd
+		}
 		lv := v.activeLogView()
 		*lv, cmd = lv.Update(msg)
 	}
@@ -167,6 +310,9 @@ func (v MainView) Update(msg tea.Msg) (MainView, tea.Cmd) {
 func (v MainView) View() string {
 	tabRow := v.tabbar.View()
 	lv := v.activeLogView()
+	if v.barVisible() {
+		return lipgloss.JoinVertical(lipgloss.Left, tabRow, v.barView(), lv.View())
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, tabRow, lv.View())
 }
 
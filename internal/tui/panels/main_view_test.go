@@ -1,6 +1,7 @@
 package panels
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -270,3 +271,118 @@ func TestMainView_AppendLine_DoesNotAffectSpecLog(t *testing.T) {
 		t.Errorf("Output tab must not contain spec content; got: %q", outView)
 	}
 }
+
+func TestMainView_DiffTab(t *testing.T) {
+	mv := NewMainView(80, 5)
+	var lines []string
+	for i := 0; i < 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	mv = mv.SetDiff(lines, []int{3, 12, 28}, []string{"a.go", "b.go", "c.go"})
+	if mv.activeTab != TabOutput {
+		t.Fatalf("SetDiff should not switch tabs, got %v", mv.activeTab)
+	}
+	for i := 0; i < int(TabDiff); i++ {
+		mv, _ = mv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	}
+	if mv.activeTab != TabDiff {
+		t.Fatalf("activeTab = %v, want TabDiff", mv.activeTab)
+	}
+
+	key := func(k string) tea.Cmd {
+		var cmd tea.Cmd
+		mv, cmd = mv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		return cmd
+	}
+	openedPath := func() string {
+		cmd := key("e")
+		if cmd == nil {
+			t.Fatal("e should return a command")
+		}
+		msg, ok := cmd().(OpenFileRequestMsg)
+		if !ok {
+			t.Fatalf("e cmd returned %T, want OpenFileRequestMsg", cmd())
+		}
+		return msg.Path
+	}
+
+	if got := openedPath(); got != "a.go" {
+		t.Errorf("e on the file list opened %q, want first file", got)
+	}
+	key("n")
+	key("n")
+	if mv.diffLog.Offset() != 12 {
+		t.Errorf("offset after n n = %d, want 12", mv.diffLog.Offset())
+	}
+	if got := openedPath(); got != "b.go" {
+		t.Errorf("e opened %q, want b.go", got)
+	}
+	key("n") // c.go: the offset clamps at the bottom but the file is tracked
+	if got := openedPath(); got != "c.go" {
+		t.Errorf("e opened %q, want c.go", got)
+	}
+	key("n")
+	key("N")
+	key("N")
+	if mv.diffLog.Offset() != 3 {
+		t.Errorf("offset after N N = %d, want 3", mv.diffLog.Offset())
+	}
+	key("j")
+	key("j")
+	if got := openedPath(); got != "a.go" {
+		t.Errorf("e after scrolling opened %q, want a.go", got)
+	}
+}
+
+func TestMainView_DiffTab_Empty(t *testing.T) {
+	mv := NewMainView(80, 10).SetDiff([]string{"(no changes)"}, nil, nil)
+	mv.activeTab = TabDiff
+	for _, k := range []string{"n", "N"} {
+		mv, _ = mv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	if _, cmd := mv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")}); cmd != nil {
+		t.Error("e with no files should do nothing")
+	}
+	if !strings.Contains(mv.View(), "(no changes)") {
+		t.Errorf("View() = %q", mv.View())
+	}
+}
+
+func TestMainView_SpendTab(t *testing.T) {
+	mv := NewMainView(80, 5)
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("cost line %d", i))
+	}
+	mv = mv.SetSpend(lines)
+	if mv.activeTab != TabOutput {
+		t.Fatalf("SetSpend should not switch tabs, got %v", mv.activeTab)
+	}
+	for i := 0; i < int(TabSpend); i++ {
+		mv, _ = mv.Update(keyMsg("]"))
+	}
+	if mv.activeTab != TabSpend {
+		t.Fatalf("activeTab = %v, want TabSpend", mv.activeTab)
+	}
+	if view := mv.View(); !strings.Contains(view, "cost line 0") {
+		t.Errorf("Spend tab should open at the top of the report; got %q", view)
+	}
+
+	// A refresh keeps the scroll position.
+	mv, _ = mv.Update(keyMsg("j"))
+	mv, _ = mv.Update(keyMsg("j"))
+	offset := mv.costLog.Offset()
+	if offset == 0 {
+		t.Fatal("j should scroll the Spend tab")
+	}
+	mv = mv.SetSpend(lines)
+	if got := mv.costLog.Offset(); got != offset {
+		t.Errorf("offset after refresh = %d, want %d", got, offset)
+	}
+
+	// f does not toggle follow on the report.
+	mv, _ = mv.Update(keyMsg("f"))
+	if mv.costLog.Following() {
+		t.Error("f should not enable follow on the Spend tab")
+	}
+}
@@ -20,20 +20,43 @@ const (
 	TabTests                         // Test output (from Regent entries)
 	TabCost                          // Cost breakdown
 	TabWorktrees                     // Worktree agents (when orchestrator active)
+	TabSteer                         // Queued steering notes
+	TabTools                         // Tool usage per iteration
 )
 
-var secondaryTabLabels = []string{"Regent", "Git", "Tests", "Cost"}
-var secondaryTabLabelsWithWorktrees = []string{"Regent", "Git", "Tests", "Cost", "Worktrees"}
+var secondaryTabLabels = map[SecondaryTab]string{
+	TabRegent:    "Regent",
+	TabGit:       "Git",
+	TabTests:     "Tests",
+	TabCost:      "Cost",
+	TabWorktrees: "Worktrees",
+	TabSteer:     "Steer",
+	TabTools:     "Tools",
+}
 
-// SecondaryPanel is the secondary (right-bottom) panel with Regent/git/test/cost tabs.
-// When worktree mode is enabled (via EnableWorktrees), a Worktrees tab is added.
+// tabBar returns the tab bar for tabs, in order.
+func tabBar(tabs []SecondaryTab, w int) components.TabBar {
+	labels := make([]string, len(tabs))
+	for i, t := range tabs {
+		labels[i] = secondaryTabLabels[t]
+	}
+	return components.NewTabBar(labels).SetWidth(w)
+}
+
+// SecondaryPanel is the secondary (right-bottom) panel with Regent, git,
+// test, cost, tool-usage and steering-note tabs. When worktree mode is
+// enabled (via EnableWorktrees), a Worktrees tab is added before the Steer
+// tab.
 type SecondaryPanel struct {
 	tabbar       components.TabBar
+	tabs         []SecondaryTab           // tab shown at each tab bar position
 	regent       components.LogView       // Regent supervisor messages
 	gitLog       components.LogView       // Git operation messages
 	tests        components.LogView       // Test output from Regent entries
 	costData     []store.IterationSummary // Per-iteration cost accumulator
+	tools        components.LogView       // Tool usage, rendered by the app
 	worktrees    WorktreesPanel           // Worktree agents list (only used when hasWorktrees)
+	steer        SteerPanel               // Queued steering notes
 	hasWorktrees bool                     // true when worktree tab is enabled
 	width        int
 	height       int
@@ -46,11 +69,15 @@ func NewSecondaryPanel(w, h int) SecondaryPanel {
 	if contentH < 1 {
 		contentH = 1
 	}
+	tabs := []SecondaryTab{TabRegent, TabGit, TabTests, TabCost, TabTools, TabSteer}
 	return SecondaryPanel{
-		tabbar:    components.NewTabBar(secondaryTabLabels).SetWidth(w),
+		tabbar:    tabBar(tabs, w),
+		tabs:      tabs,
 		regent:    components.NewLogView(w, contentH),
 		gitLog:    components.NewLogView(w, contentH),
 		tests:     components.NewLogView(w, contentH),
+		tools:     components.NewLogView(w, contentH).ToggleFollow(), // reads top-down
+		steer:     NewSteerPanel(w, contentH),
 		width:     w,
 		height:    h,
 		activeTab: TabRegent,
@@ -65,7 +92,35 @@ func (p SecondaryPanel) EnableWorktrees(entries []WorktreeEntry) SecondaryPanel
 		contentH = 1
 	}
 	p.worktrees = NewWorktreesPanel(entries, p.width, contentH)
-	p.tabbar = components.NewTabBar(secondaryTabLabelsWithWorktrees).SetWidth(p.width)
+	p.tabs = []SecondaryTab{TabRegent, TabGit, TabTests, TabCost, TabTools, TabWorktrees, TabSteer}
+	p.tabbar = tabBar(p.tabs, p.width)
+	return p
+}
+
+// SetSteerEntries updates the queued notes in the Steer tab.
+func (p SecondaryPanel) SetSteerEntries(entries []SteerEntry) SecondaryPanel {
+	p.steer = p.steer.SetEntries(entries)
+	return p
+}
+
+// SetTools replaces the Tools tab's lines, keeping its scroll position.
+func (p SecondaryPanel) SetTools(lines []string) SecondaryPanel {
+	p.tools = p.tools.SetContent(lines)
+	return p
+}
+
+// ShowSteer switches to the Steer tab.
+func (p SecondaryPanel) ShowSteer() SecondaryPanel {
+	return p.showTab(TabSteer)
+}
+
+func (p SecondaryPanel) showTab(tab SecondaryTab) SecondaryPanel {
+	for i, t := range p.tabs {
+		if t == tab {
+			p.activeTab = tab
+			p.tabbar = p.tabbar.SetActive(i)
+		}
+	}
 	return p
 }
 
@@ -104,9 +159,7 @@ func (p SecondaryPanel) AddIteration(s store.IterationSummary) SecondaryPanel {
 // so the detail is immediately visible.
 func (p SecondaryPanel) ShowDetail(lines []string) SecondaryPanel {
 	p.regent = p.regent.SetContent(lines)
-	p.activeTab = TabRegent
-	p.tabbar = p.tabbar.SetActive(int(TabRegent))
-	return p
+	return p.showTab(TabRegent)
 }
 
 // SetSize resizes all internal viewports.
@@ -121,9 +174,11 @@ func (p SecondaryPanel) SetSize(w, h int) SecondaryPanel {
 	p.regent = p.regent.SetSize(w, contentH)
 	p.gitLog = p.gitLog.SetSize(w, contentH)
 	p.tests = p.tests.SetSize(w, contentH)
+	p.tools = p.tools.SetSize(w, contentH)
 	if p.hasWorktrees {
 		p.worktrees = p.worktrees.SetSize(w, contentH)
 	}
+	p.steer = p.steer.SetSize(w, contentH)
 	return p
 }
 
@@ -135,10 +190,10 @@ func (p SecondaryPanel) Update(msg tea.Msg) (SecondaryPanel, tea.Cmd) {
 		switch msg.String() {
 		case "]":
 			p.tabbar = p.tabbar.Next()
-			p.activeTab = SecondaryTab(p.tabbar.Active())
+			p.activeTab = p.tabs[p.tabbar.Active()]
 		case "[":
 			p.tabbar = p.tabbar.Prev()
-			p.activeTab = SecondaryTab(p.tabbar.Active())
+			p.activeTab = p.tabs[p.tabbar.Active()]
 		default:
 			// Delegate scroll keys to active tab's logview or worktrees panel.
 			switch p.activeTab {
@@ -148,8 +203,12 @@ func (p SecondaryPanel) Update(msg tea.Msg) (SecondaryPanel, tea.Cmd) {
 				p.gitLog, cmd = p.gitLog.Update(msg)
 			case TabTests:
 				p.tests, cmd = p.tests.Update(msg)
+			case TabTools:
+				p.tools, cmd = p.tools.Update(msg)
 			case TabWorktrees:
 				p.worktrees, cmd = p.worktrees.Update(msg)
+			case TabSteer:
+				p.steer, cmd = p.steer.Update(msg)
 			}
 		}
 	default:
@@ -160,6 +219,8 @@ func (p SecondaryPanel) Update(msg tea.Msg) (SecondaryPanel, tea.Cmd) {
 			p.gitLog, cmd = p.gitLog.Update(msg)
 		case TabTests:
 			p.tests, cmd = p.tests.Update(msg)
+		case TabTools:
+			p.tools, cmd = p.tools.Update(msg)
 		case TabWorktrees:
 			p.worktrees, cmd = p.worktrees.Update(msg)
 		}
@@ -180,8 +241,12 @@ func (p SecondaryPanel) View() string {
 		content = p.tests.View()
 	case TabCost:
 		content = p.renderCostTable()
+	case TabTools:
+		content = p.tools.View()
 	case TabWorktrees:
 		content = p.worktrees.View()
+	case TabSteer:
+		content = p.steer.View()
 	}
 	return lipgloss.JoinVertical(lipgloss.Left, tabRow, content)
 }
@@ -1,6 +1,7 @@
 package panels
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -290,12 +291,12 @@ func TestSecondaryPanel_WorktreesTab_ViewAndUpdate(t *testing.T) {
 	p := NewSecondaryPanel(80, 20)
 	p = p.EnableWorktrees(entries)
 
-	// Navigate to Worktrees tab (index 4: Regent→Git→Tests→Cost→Worktrees).
-	for i := 0; i < 4; i++ {
+	// Navigate to Worktrees tab (index 5: Regent→Git→Tests→Cost→Tools→Worktrees).
+	for i := 0; i < 5; i++ {
 		p, _ = p.Update(keyMsg("]"))
 	}
 	if p.activeTab != TabWorktrees {
-		t.Fatalf("expected TabWorktrees (4), got %v", p.activeTab)
+		t.Fatalf("expected TabWorktrees (5), got %v", p.activeTab)
 	}
 
 	// View() should exercise the TabWorktrees branch.
@@ -378,3 +379,53 @@ func TestSecondaryPanel_AllTabsRenderable(t *testing.T) {
 		}
 	}
 }
+
+// TestSecondaryPanel_SteerTab verifies the Steer tab follows Tools, or
+// Worktrees when enabled, and that ShowSteer switches to it.
+func TestSecondaryPanel_SteerTab(t *testing.T) {
+	p := NewSecondaryPanel(80, 20).SetSteerEntries(steerEntries)
+	for i := 0; i < 5; i++ {
+		p, _ = p.Update(keyMsg("]"))
+	}
+	if p.activeTab != TabSteer {
+		t.Fatalf("activeTab = %v after five ], want TabSteer", p.activeTab)
+	}
+	if view := p.View(); !strings.Contains(view, "skip the docs") {
+		t.Errorf("Steer tab should list the notes:\n%s", view)
+	}
+	if _, cmd := p.Update(keyMsg("d")); cmd == nil {
+		t.Error("d on the Steer tab should remove the selected note")
+	}
+
+	p = NewSecondaryPanel(80, 20).EnableWorktrees(nil).ShowSteer()
+	if p.activeTab != TabSteer || p.tabbar.Active() != 6 {
+		t.Errorf("ShowSteer with worktrees: tab %v at %d, want TabSteer at 6", p.activeTab, p.tabbar.Active())
+	}
+}
+
+// TestSecondaryPanel_ToolsTab verifies the Tools tab follows Cost, shows
+// the lines it is given and keeps its scroll position across refreshes.
+func TestSecondaryPanel_ToolsTab(t *testing.T) {
+	var lines []string
+	for i := 0; i < 40; i++ {
+		lines = append(lines, fmt.Sprintf("tools line %d", i))
+	}
+	p := NewSecondaryPanel(80, 10).SetTools(lines)
+	for i := 0; i < 4; i++ {
+		p, _ = p.Update(keyMsg("]"))
+	}
+	if p.activeTab != TabTools {
+		t.Fatalf("activeTab = %v after four ], want TabTools", p.activeTab)
+	}
+	if view := p.View(); !strings.Contains(view, "Tools") || !strings.Contains(view, "tools line 0") {
+		t.Errorf("Tools tab should open at the top:\n%s", view)
+	}
+	p, _ = p.Update(keyMsg("j"))
+	offset := p.tools.Offset()
+	if offset == 0 {
+		t.Fatal("j should scroll the Tools tab")
+	}
+	if p = p.SetTools(lines); p.tools.Offset() != offset {
+		t.Errorf("offset after refresh = %d, want %d", p.tools.Offset(), offset)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
 	"github.com/LISSConsulting/RalphSpec/internal/spec"
 )
 
@@ -24,6 +25,21 @@ type EditSpecRequestMsg struct{ Path string }
 // CreateSpecRequestMsg is emitted when the user submits a new spec name via the 'n' overlay.
 type CreateSpecRequestMsg struct{ Name string }
 
+// PhaseRequestMsg is emitted when the user presses S, C, P, or T to run the
+// spec-kit specify, clarify, plan, or tasks phase for the selected spec.
+type PhaseRequestMsg struct {
+	Phase string // loop.PhaseSpecify, PhaseClarify, PhasePlan, or PhaseTasks
+	Spec  spec.SpecFile
+}
+
+// phaseKeys maps the Specs panel's phase keys to spec-kit phases.
+var phaseKeys = map[string]string{
+	"S": loop.PhaseSpecify,
+	"C": loop.PhaseClarify,
+	"P": loop.PhasePlan,
+	"T": loop.PhaseTasks,
+}
+
 // specTreeNode holds a directory-level spec with its discovered child files.
 type specTreeNode struct {
 	sf       spec.SpecFile // the directory or single-file spec
@@ -127,6 +143,47 @@ func NewSpecsPanel(specs []spec.SpecFile, workDir string, w, h int) SpecsPanel {
 	}
 }
 
+// SetSpecs replaces the spec list, re-discovering child files, while keeping
+// expanded directories expanded and the cursor on the same row when it still
+// exists.
+func (p SpecsPanel) SetSpecs(specs []spec.SpecFile) SpecsPanel {
+	expanded := make(map[string]bool)
+	for _, n := range p.nodes {
+		if n.expanded {
+			expanded[n.sf.Path] = true
+		}
+	}
+	var curNode, curChild string
+	if p.cursor < len(p.flat) {
+		row := p.flat[p.cursor]
+		curNode = p.nodes[row.nodeIdx].sf.Path
+		if row.isChild {
+			curChild = p.nodes[row.nodeIdx].children[row.childIdx]
+		}
+	}
+
+	p.specs = specs
+	p.nodes = buildTree(specs, p.workDir)
+	for i := range p.nodes {
+		p.nodes[i].expanded = expanded[p.nodes[i].sf.Path] && len(p.nodes[i].children) > 0
+	}
+	p.flat = flattenTree(p.nodes)
+	p.cursor = 0
+	for i, row := range p.flat {
+		n := p.nodes[row.nodeIdx]
+		if n.sf.Path != curNode {
+			continue
+		}
+		if !row.isChild {
+			p.cursor = i // also the fallback when the child row is gone
+		} else if n.children[row.childIdx] == curChild {
+			p.cursor = i
+			break
+		}
+	}
+	return p.moveCursor(0)
+}
+
 // SelectedSpec returns the directory-level spec for the current cursor position.
 // For child-file rows the parent directory spec is returned so callers can use
 // it for operations like launching worktree agents.  Returns nil when empty.
@@ -264,6 +321,15 @@ func (p SpecsPanel) Update(msg tea.Msg) (SpecsPanel, tea.Cmd) {
 		p.input.Reset()
 		p.input.Focus()
 		return p, textinput.Blink
+
+	case "S", "C", "P", "T":
+		// Phases work on spec kit feature directories only.
+		sel := p.SelectedSpec()
+		if sel == nil || !sel.IsDir {
+			return p, nil
+		}
+		msg := PhaseRequestMsg{Phase: phaseKeys[keyMsg.String()], Spec: *sel}
+		return p, func() tea.Msg { return msg }
 	}
 
 	return p, nil
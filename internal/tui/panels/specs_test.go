@@ -609,3 +609,56 @@ func TestSpecsPanel_InputActive_NonKeyMsg_ForwardedToInput(t *testing.T) {
 		t.Error("inputActive should still be true after a non-key message")
 	}
 }
+
+func TestSpecsPanel_PhaseKeys_EmitPhaseRequest(t *testing.T) {
+	dirSpec := spec.SpecFile{Name: "001-feature", Path: "specs/001-feature/spec.md", Dir: "specs/001-feature", IsDir: true}
+	p := NewSpecsPanel([]spec.SpecFile{dirSpec}, "", 80, 20)
+	for key, phase := range map[string]string{"S": "specify", "C": "clarify", "P": "plan", "T": "tasks"} {
+		_, cmd := p.Update(keyMsg(key))
+		if cmd == nil {
+			t.Fatalf("%s: expected a cmd", key)
+		}
+		msg, ok := cmd().(PhaseRequestMsg)
+		if !ok || msg.Phase != phase || msg.Spec.Name != "001-feature" {
+			t.Errorf("%s: got %+v, want PhaseRequestMsg for %s", key, msg, phase)
+		}
+	}
+
+	// Flat-file specs have no feature directory for spec-kit to work in.
+	flat := NewSpecsPanel([]spec.SpecFile{makeSpec("old", "specs/old.md", spec.StatusDone)}, "", 80, 20)
+	if _, cmd := flat.Update(keyMsg("P")); cmd != nil {
+		t.Error("P on a flat-file spec should not emit a cmd")
+	}
+}
+
+func TestSpecsPanel_SetSpecs_KeepsExpansionAndCursor(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "specs", "002-feature")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "spec.md"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	specs := []spec.SpecFile{
+		makeSpec("001-flat", "specs/001-flat.md", spec.StatusDone),
+		{Name: "002-feature", Path: "specs/002-feature/spec.md", Dir: filepath.Join("specs", "002-feature"), IsDir: true},
+	}
+	p := NewSpecsPanel(specs, tmp, 80, 20)
+	p, _ = p.Update(keyMsg("j"))
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter}) // expand 002-feature
+	p, _ = p.Update(keyMsg("j"))                    // cursor on spec.md
+
+	// plan.md appears, e.g. written by the plan phase.
+	if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p = p.SetSpecs(specs)
+	if len(p.flat) != 4 {
+		t.Fatalf("flat rows = %d, want 4 (flat spec, dir, spec.md, plan.md)", len(p.flat))
+	}
+	row := p.flat[p.cursor]
+	if !row.isChild || p.nodes[row.nodeIdx].children[row.childIdx] != filepath.Join("specs", "002-feature", "spec.md") {
+		t.Errorf("cursor row = %+v, want the spec.md child", row)
+	}
+}
@@ -0,0 +1,113 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SteerEntry is a view-model for a queued steering note, populated from
+// loop.SteerNote by the TUI layer.
+type SteerEntry struct {
+	ID   string
+	Text string
+	At   time.Time
+}
+
+// SteerRemoveMsg is emitted when the user presses 'd' on a queued note.
+type SteerRemoveMsg struct{ ID string }
+
+// SteerPanel lists the steering notes waiting for the next iteration.
+type SteerPanel struct {
+	entries []SteerEntry
+	cursor  int
+	width   int
+	height  int
+}
+
+// NewSteerPanel creates an empty steering-note list.
+func NewSteerPanel(w, h int) SteerPanel {
+	return SteerPanel{width: w, height: h}
+}
+
+// SetEntries replaces the queued notes, keeping the cursor in range.
+func (p SteerPanel) SetEntries(entries []SteerEntry) SteerPanel {
+	p.entries = entries
+	if p.cursor >= len(entries) {
+		p.cursor = len(entries) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	return p
+}
+
+// SetSize resizes the panel.
+func (p SteerPanel) SetSize(w, h int) SteerPanel {
+	p.width = w
+	p.height = h
+	return p
+}
+
+// Update handles navigation and removal keys.
+func (p SteerPanel) Update(msg tea.Msg) (SteerPanel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || len(p.entries) == 0 {
+		return p, nil
+	}
+	switch keyMsg.String() {
+	case "j", "down":
+		if p.cursor < len(p.entries)-1 {
+			p.cursor++
+		}
+	case "k", "up":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "d":
+		id := p.entries[p.cursor].ID
+		return p, func() tea.Msg { return SteerRemoveMsg{ID: id} }
+	}
+	return p, nil
+}
+
+// View renders the queued notes, oldest first, with the selected one marked.
+func (p SteerPanel) View() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	if len(p.entries) == 0 {
+		return lipgloss.NewStyle().
+			Width(p.width).Height(p.height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Foreground(lipgloss.Color("#888888")).
+			Render("No steering notes · : to add")
+	}
+
+	accent := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+	rows := p.height - 1 // last row is the hint
+	if rows < 1 {
+		rows = 1
+	}
+	top := 0
+	if p.cursor >= rows {
+		top = p.cursor - rows + 1
+	}
+	var lines []string
+	for i := top; i < len(p.entries) && i < top+rows; i++ {
+		e := p.entries[i]
+		text := strings.Join(strings.Fields(e.Text), " ")
+		line := fmt.Sprintf("%s  %s", e.At.Format("15:04:05"), text)
+		if r := []rune(line); p.width > 3 && len(r) > p.width-2 {
+			line = string(r[:p.width-3]) + "…"
+		}
+		if i == p.cursor {
+			lines = append(lines, accent.Render("▸ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, dim.Render(fmt.Sprintf("%d queued for the next iteration · d remove", len(p.entries))))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,54 @@
+package panels
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var steerEntries = []SteerEntry{
+	{ID: "1", Text: "skip the docs", At: time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)},
+	{ID: "2", Text: "focus on\nthe parser", At: time.Date(2026, 1, 2, 9, 31, 0, 0, time.UTC)},
+}
+
+func TestSteerPanel_EmptyView(t *testing.T) {
+	p := NewSteerPanel(60, 5)
+	if view := p.View(); !strings.Contains(view, "No steering notes") {
+		t.Errorf("empty view = %q", view)
+	}
+	if _, cmd := p.Update(keyMsg("d")); cmd != nil {
+		t.Error("d with no notes should do nothing")
+	}
+}
+
+func TestSteerPanel_ViewAndRemove(t *testing.T) {
+	p := NewSteerPanel(60, 5).SetEntries(steerEntries)
+	view := p.View()
+	for _, want := range []string{"09:30:00", "skip the docs", "focus on the parser", "2 queued"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	p, _ = p.Update(keyMsg("j"))
+	_, cmd := p.Update(keyMsg("d"))
+	if cmd == nil {
+		t.Fatal("d should emit a remove command")
+	}
+	if msg, ok := cmd().(SteerRemoveMsg); !ok || msg.ID != "2" {
+		t.Errorf("d produced %#v, want SteerRemoveMsg{ID: 2}", cmd())
+	}
+}
+
+func TestSteerPanel_SetEntriesClampsCursor(t *testing.T) {
+	p := NewSteerPanel(60, 5).SetEntries(steerEntries)
+	p, _ = p.Update(keyMsg("j"))
+	p = p.SetEntries(steerEntries[:1])
+	if p.cursor != 0 {
+		t.Errorf("cursor = %d after the list shrank, want 0", p.cursor)
+	}
+	p = p.SetEntries(nil)
+	if p.cursor != 0 {
+		t.Errorf("cursor = %d for an empty list, want 0", p.cursor)
+	}
+}
@@ -18,12 +18,14 @@ type WorktreeEntry struct {
 	Iterations int
 	TotalCost  float64
 	SpecName   string
+	Priority   int // launch-queue priority; shown only while queued
 }
 
-// WorktreeActionMsg is emitted when the user requests stop/merge/clean on the selected worktree.
+// WorktreeActionMsg is emitted when the user requests stop/merge/clean/resume
+// on the selected worktree, or raises/lowers a queued agent's priority.
 type WorktreeActionMsg struct {
 	Branch string
-	Action string // "stop", "merge", "clean"
+	Action string // "stop", "merge", "clean", "resume", "raise", "lower"
 }
 
 // WorktreeSelectedMsg is emitted when the user presses enter on a worktree to view its log.
@@ -37,6 +39,9 @@ type worktreeItem struct {
 }
 
 func (w worktreeItem) Title() string {
+	if w.entry.State == "queued" && w.entry.Priority != 0 {
+		return fmt.Sprintf("%s %s (p%+d)", worktreeStateIcon(w.entry.State), w.entry.Branch, w.entry.Priority)
+	}
 	return fmt.Sprintf("%s %s", worktreeStateIcon(w.entry.State), w.entry.Branch)
 }
 
@@ -65,6 +70,10 @@ func worktreeStateIcon(state string) string {
 		return "✅"
 	case "merge_failed":
 		return "❌"
+	case "resolving":
+		return "🩹"
+	case "queued":
+		return "🕒"
 	case "removed":
 		return "🗑"
 	default:
@@ -95,7 +104,8 @@ func (d worktreeDelegate) Render(w io.Writer, m list.Model, index int, item list
 
 // WorktreesPanel displays a navigable list of worktree agents.
 // Keys: j/k navigate, enter selects (shows log in main panel),
-// x stops, M merges, D cleans the selected agent.
+// x stops (or cancels a queued agent), M merges, D cleans, r resumes the
+// selected agent, and +/- raise or lower a queued agent's priority.
 type WorktreesPanel struct {
 	list    list.Model
 	entries []WorktreeEntry
@@ -178,6 +188,24 @@ func (p WorktreesPanel) Update(msg tea.Msg) (WorktreesPanel, tea.Cmd) {
 				b := branch
 				return p, func() tea.Msg { return WorktreeActionMsg{Branch: b, Action: "clean"} }
 			}
+		case "r":
+			branch := p.SelectedBranch()
+			if branch != "" {
+				b := branch
+				return p, func() tea.Msg { return WorktreeActionMsg{Branch: b, Action: "resume"} }
+			}
+		case "+", "=":
+			branch := p.SelectedBranch()
+			if branch != "" {
+				b := branch
+				return p, func() tea.Msg { return WorktreeActionMsg{Branch: b, Action: "raise"} }
+			}
+		case "-":
+			branch := p.SelectedBranch()
+			if branch != "" {
+				b := branch
+				return p, func() tea.Msg { return WorktreeActionMsg{Branch: b, Action: "lower"} }
+			}
 		}
 	}
 	var cmd tea.Cmd
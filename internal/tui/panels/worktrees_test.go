@@ -153,9 +153,58 @@ func TestWorktreesPanel_KeyD_EmitsClean(t *testing.T) {
 	}
 }
 
+func TestWorktreesPanel_KeyR_EmitsResume(t *testing.T) {
+	p := NewWorktreesPanel([]WorktreeEntry{{Branch: "wt/alpha", State: "stopped"}}, 60, 10)
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("r key should return a cmd")
+	}
+	act, ok := cmd().(WorktreeActionMsg)
+	if !ok || act.Action != "resume" || act.Branch != "wt/alpha" {
+		t.Errorf("r key emitted %+v, want resume of wt/alpha", act)
+	}
+}
+
+func TestWorktreesPanel_PriorityKeys(t *testing.T) {
+	p := NewWorktreesPanel([]WorktreeEntry{{Branch: "wt/alpha", State: "queued"}}, 60, 10)
+	for key, want := range map[string]string{"+": "raise", "=": "raise", "-": "lower"} {
+		_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		if cmd == nil {
+			t.Fatalf("%s key should return a cmd", key)
+		}
+		act, ok := cmd().(WorktreeActionMsg)
+		if !ok || act.Action != want || act.Branch != "wt/alpha" {
+			t.Errorf("%s key emitted %+v, want %s of wt/alpha", key, act, want)
+		}
+	}
+}
+
+func TestWorktreeItem_Title_QueuedPriority(t *testing.T) {
+	tests := []struct {
+		entry WorktreeEntry
+		want  string
+	}{
+		{WorktreeEntry{Branch: "wt/a", State: "queued", Priority: 3}, "(p+3)"},
+		{WorktreeEntry{Branch: "wt/a", State: "queued", Priority: -1}, "(p-1)"},
+	}
+	for _, tt := range tests {
+		if got := (worktreeItem{entry: tt.entry}).Title(); !strings.Contains(got, tt.want) {
+			t.Errorf("Title() = %q, want it to contain %q", got, tt.want)
+		}
+	}
+	for _, e := range []WorktreeEntry{
+		{Branch: "wt/a", State: "queued"},
+		{Branch: "wt/a", State: "running", Priority: 3},
+	} {
+		if got := (worktreeItem{entry: e}).Title(); strings.Contains(got, "(p") {
+			t.Errorf("Title() = %q, should not show a priority", got)
+		}
+	}
+}
+
 func TestWorktreesPanel_ActionKeys_EmptyPanel_NoCmd(t *testing.T) {
 	p := NewWorktreesPanel(nil, 40, 10)
-	for _, key := range []string{"x", "M", "D", "enter"} {
+	for _, key := range []string{"x", "M", "D", "r", "+", "-", "enter"} {
 		_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
 		// On an empty panel, SelectedBranch() == "" so no cmd should be returned.
 		// (The list.Update might return a cmd from internal bubbles state.)
@@ -202,7 +251,7 @@ func TestWorktreesPanel_SetEntries_Replaces(t *testing.T) {
 }
 
 func TestWorktreeStateIcon_AllStates(t *testing.T) {
-	states := []string{"creating", "running", "completed", "failed", "stopped", "merging", "merged", "merge_failed", "removed", "unknown-state"}
+	states := []string{"creating", "running", "completed", "failed", "stopped", "merging", "merged", "merge_failed", "removed", "resolving", "queued", "unknown-state"}
 	for _, s := range states {
 		icon := worktreeStateIcon(s)
 		if icon == "" {
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// togglePause handles the space key: a running loop is asked to pause after
+// its current iteration, and otherwise the loop saved in the checkpoint is
+// resumed. The header shows PAUSED once the loop reports LogPaused.
+func (m Model) togglePause() (tea.Model, tea.Cmd) {
+	pc, ok := m.controller.(PauseController)
+	if !ok {
+		return m, nil
+	}
+	if m.controller.IsRunning() {
+		if m.loopState == StatePlanning || m.loopState == StateBuilding {
+			pc.PauseLoop()
+			m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
+				Kind:    loop.LogInfo,
+				Message: "Pausing after the current iteration",
+			}, m.layout.Main.Width))
+		}
+		return m, nil
+	}
+	pc.ResumeLoop()
+	return m, nil
+}
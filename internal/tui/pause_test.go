@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+// mockPauseController is a mockLoopController that also pauses and resumes.
+type mockPauseController struct {
+	mockLoopController
+	pauses, resumes int
+}
+
+func (c *mockPauseController) PauseLoop()  { c.pauses++ }
+func (c *mockPauseController) ResumeLoop() { c.resumes++; c.running = true }
+
+var spaceKey = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+func TestTogglePause_PauseThenResume(t *testing.T) {
+	ctrl := &mockPauseController{}
+	m := newPhaseModel(ctrl)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = updated.(Model)
+
+	updated, _ = m.Update(spaceKey)
+	m = updated.(Model)
+	if ctrl.pauses != 1 {
+		t.Fatalf("space while building: pauses = %d, want 1", ctrl.pauses)
+	}
+	if !strings.Contains(m.mainView.View(), "Pausing after the current iteration") {
+		t.Error("the pause request should be acknowledged")
+	}
+
+	ctrl.running = false
+	updated, _ = m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogPaused, Message: "Paused after iteration 2", Iteration: 2, TotalCost: 0.4}))
+	m = updated.(Model)
+	if m.loopState != StatePaused {
+		t.Fatalf("loopState = %v after LogPaused, want StatePaused", m.loopState.Label())
+	}
+	if !strings.Contains(m.View(), "PAUSED") {
+		t.Error("the header should show PAUSED")
+	}
+
+	updated, _ = m.Update(spaceKey)
+	m = updated.(Model)
+	if ctrl.resumes != 1 {
+		t.Fatalf("space while paused: resumes = %d, want 1", ctrl.resumes)
+	}
+	updated, _ = m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogIterStart, Iteration: 3, Mode: "build"}))
+	if m := updated.(Model); m.loopState != StateBuilding {
+		t.Errorf("loopState = %v after the resumed iteration starts, want BUILDING", m.loopState.Label())
+	}
+}
+
+func TestTogglePause_IdleResumesCheckpoint(t *testing.T) {
+	ctrl := &mockPauseController{}
+	updated, _ := newPhaseModel(ctrl).Update(spaceKey)
+	_ = updated
+	if ctrl.resumes != 1 || ctrl.pauses != 0 {
+		t.Errorf("space while idle should resume a saved checkpoint (resumes %d, pauses %d)", ctrl.resumes, ctrl.pauses)
+	}
+}
+
+func TestTogglePause_Unsupported(t *testing.T) {
+	ctrl := &mockLoopController{running: true}
+	m := newPhaseModel(ctrl)
+	updated, _ := m.Update(spaceKey)
+	if updated.(Model).loopState != m.loopState {
+		t.Error("space without a PauseController should do nothing")
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/panels"
+)
+
+// phaseRun tracks the spec-kit phase started from the Specs panel, from the
+// key press until it finishes or the user stops answering its questions.
+type phaseRun struct {
+	req     loop.PhaseRequest // the request sent, or about to be sent once Input is typed
+	running bool              // the controller is running req; false while waiting for input
+	text    string            // the phase's latest text output, e.g. clarify's question
+}
+
+// active reports whether a phase is running or waiting for input.
+func (p phaseRun) active() bool { return p.req.Phase != "" }
+
+// specWriteTools are the tools whose use during a phase may have created a
+// spec file, so the Specs tree is refreshed after each.
+var specWriteTools = map[string]bool{"Write": true, "Edit": true, "MultiEdit": true}
+
+// handlePhaseRequest starts the phase requested from the Specs panel. specify
+// first asks for the feature description.
+func (m Model) handlePhaseRequest(msg panels.PhaseRequestMsg) (tea.Model, tea.Cmd) {
+	if _, ok := m.controller.(PhaseController); !ok {
+		return m.appendError("spec-kit phases need the dashboard (run ralph without a subcommand)"), nil
+	}
+	if m.phase.active() || m.controller.IsRunning() {
+		return m.appendError("cannot start speckit." + msg.Phase + ": a loop or phase is already running"), nil
+	}
+	m.phase = phaseRun{req: loop.PhaseRequest{Phase: msg.Phase, SpecDir: msg.Spec.Dir}}
+	if msg.Phase == loop.PhaseSpecify {
+		return m.askPhaseInput("describe " + msg.Spec.Name + ": ")
+	}
+	return m.startPhase(), nil
+}
+
+// handleInputSubmitted routes the text typed into the Main panel prompt to
+// what the prompt was opened for.
+func (m Model) handleInputSubmitted(msg panels.InputSubmittedMsg) (tea.Model, tea.Cmd) {
+	purpose := m.input
+	m.input = inputNone
+	switch purpose {
+	case inputSteer:
+		return m.handleSteerInput(msg)
+	case inputPhase:
+		return m.handlePhaseInput(msg)
+	}
+	return m, nil
+}
+
+// handlePhaseInput sends the description or answer to the phase waiting for
+// it. Esc ends the phase.
+func (m Model) handlePhaseInput(msg panels.InputSubmittedMsg) (tea.Model, tea.Cmd) {
+	if !m.phase.active() || m.phase.running {
+		return m, nil
+	}
+	if msg.Cancelled {
+		m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
+			Kind:    loop.LogInfo,
+			Message: fmt.Sprintf("speckit.%s ended", m.phase.req.Phase),
+		}, m.layout.Main.Width))
+		m.phase = phaseRun{}
+		return m, m.refreshSpecs()
+	}
+	m.phase.req.Input = msg.Text
+	return m.startPhase(), nil
+}
+
+func (m Model) startPhase() Model {
+	m.phase.running = true
+	m.phase.text = ""
+	m.mainView = m.mainView.SwitchToOutput()
+	m.controller.(PhaseController).RunPhase(m.phase.req)
+	return m
+}
+
+// askPhaseInput focuses the Main panel and opens its input prompt.
+func (m Model) askPhaseInput(label string) (Model, tea.Cmd) {
+	m.focus = FocusMain
+	m.input = inputPhase
+	var cmd tea.Cmd
+	m.mainView, cmd = m.mainView.AskInput(label)
+	return m, cmd
+}
+
+// trackPhase follows the running phase's log entries: it refreshes the Specs
+// tree as files are written and when the phase ends, and after a successful
+// clarify run shows the question in full and asks for the answer, which
+// resumes the same Claude session.
+func (m Model) trackPhase(entry loop.LogEntry) (Model, tea.Cmd) {
+	if !m.phase.running {
+		return m, nil
+	}
+	switch entry.Kind {
+	case loop.LogText:
+		m.phase.text = entry.Message
+	case loop.LogToolUse:
+		if specWriteTools[entry.ToolName] {
+			return m, m.refreshSpecs()
+		}
+	case loop.LogPhaseDone:
+		if entry.Phase == loop.PhaseClarify && entry.Subtype == "success" && entry.ClaudeSession != "" {
+			m.phase.running = false
+			m.phase.req.Resume = entry.ClaudeSession
+			m = m.showPhaseText()
+			var cmd tea.Cmd
+			m, cmd = m.askPhaseInput("answer (esc to finish): ")
+			return m, tea.Batch(cmd, m.refreshSpecs())
+		}
+		m.phase = phaseRun{}
+		return m, m.refreshSpecs()
+	case loop.LogStopped:
+		m.phase = phaseRun{}
+		return m, m.refreshSpecs()
+	case loop.LogError:
+		// Errors from Claude during the phase carry no Phase; this one means
+		// the phase could not run at all.
+		if entry.Phase != "" {
+			m.phase = phaseRun{}
+		}
+	}
+	return m, nil
+}
+
+// showPhaseText appends the phase's latest text output in full, wrapped to
+// the Main panel, since log lines show only its first line.
+func (m Model) showPhaseText() Model {
+	text := strings.TrimSpace(m.phase.text)
+	if text == "" {
+		return m
+	}
+	w, _ := innerDims(m.layout.Main)
+	wrapped := lipgloss.NewStyle().Width(w - 2).Render(text)
+	meta := components.LineMeta{Kind: components.KindText}
+	for _, line := range strings.Split(wrapped, "\n") {
+		m.mainView = m.mainView.AppendLineMeta(reasoningStyle.Render("  "+line), meta)
+	}
+	return m
+}
+
+// appendError shows message as an error line in the Main panel.
+func (m Model) appendError(message string) Model {
+	m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
+		Kind:    loop.LogError,
+		Message: message,
+	}, m.layout.Main.Width))
+	return m
+}
+
+// refreshSpecs re-reads the spec list for the Specs panel.
+func (m Model) refreshSpecs() tea.Cmd {
+	workDir := m.workDir
+	return func() tea.Msg {
+		specs, _ := spec.List(workDir)
+		return specsRefreshedMsg{Specs: specs}
+	}
+}
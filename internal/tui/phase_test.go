@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/panels"
+)
+
+// mockPhaseController is a mockLoopController that also runs phases.
+type mockPhaseController struct {
+	mockLoopController
+	phases []loop.PhaseRequest
+}
+
+func (c *mockPhaseController) RunPhase(req loop.PhaseRequest) {
+	c.phases = append(c.phases, req)
+}
+
+var phaseSpec = spec.SpecFile{Name: "001-auth", Path: "specs/001-auth/spec.md", Dir: "specs/001-auth", IsDir: true}
+
+// newPhaseModel returns a Model wide enough that log lines are not cut off.
+func newPhaseModel(ctrl LoopController) Model {
+	m := New(make(chan loop.LogEntry, 1), nil, "", "Proj", "/tmp/proj", nil, nil, ctrl)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 50})
+	return updated.(Model)
+}
+
+// typeAnswer types text into the open Main panel prompt, presses enter, and
+// feeds the resulting InputSubmittedMsg back to the model.
+func typeAnswer(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("enter in the prompt returned no command")
+	}
+	msg, ok := cmd().(panels.InputSubmittedMsg)
+	if !ok {
+		t.Fatalf("enter produced %T, want InputSubmittedMsg", msg)
+	}
+	updated, _ = m.Update(msg)
+	return updated.(Model)
+}
+
+// cmdMsgs runs cmd, expanding batches, and returns the messages produced.
+// Commands that block, such as waiting on the event channel, are skipped.
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			var msgs []tea.Msg
+			for _, c := range batch {
+				msgs = append(msgs, cmdMsgs(c)...)
+			}
+			return msgs
+		}
+		return []tea.Msg{msg}
+	case <-time.After(50 * time.Millisecond):
+		return nil
+	}
+}
+
+func TestPhaseRequest_ClarifyAnswerResumesSession(t *testing.T) {
+	ctrl := &mockPhaseController{}
+	m := newPhaseModel(ctrl)
+
+	updated, _ := m.Update(panels.PhaseRequestMsg{Phase: loop.PhaseClarify, Spec: phaseSpec})
+	m = updated.(Model)
+	if len(ctrl.phases) != 1 || ctrl.phases[0].Phase != loop.PhaseClarify || ctrl.phases[0].SpecDir != "specs/001-auth" {
+		t.Fatalf("phases = %+v, want one clarify request for specs/001-auth", ctrl.phases)
+	}
+
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogText, Message: "Question 1: Which login method?\nA) password\nB) SSO"},
+		{Kind: loop.LogPhaseDone, Phase: loop.PhaseClarify, Subtype: "success", ClaudeSession: "sess-1"},
+	} {
+		updated, _ = m.Update(logEntryMsg(e))
+		m = updated.(Model)
+	}
+	if !m.mainView.InputActive() || m.focus != FocusMain {
+		t.Fatalf("clarify should open the answer prompt in the focused Main panel (active %v, focus %v)", m.mainView.InputActive(), m.focus)
+	}
+	if view := m.mainView.View(); !strings.Contains(view, "B) SSO") {
+		t.Errorf("the full question should be shown:\n%s", view)
+	}
+
+	m = typeAnswer(t, m, "B")
+	if len(ctrl.phases) != 2 {
+		t.Fatalf("phases = %+v, want the answer sent", ctrl.phases)
+	}
+	if got := ctrl.phases[1]; got.Resume != "sess-1" || got.Input != "B" || got.Phase != loop.PhaseClarify {
+		t.Errorf("answer request = %+v, want Input B resuming sess-1", got)
+	}
+	if !m.phase.running {
+		t.Error("phase should be running again after the answer")
+	}
+}
+
+func TestPhaseRequest_ClarifyEscEnds(t *testing.T) {
+	ctrl := &mockPhaseController{}
+	m := newPhaseModel(ctrl)
+	updated, _ := m.Update(panels.PhaseRequestMsg{Phase: loop.PhaseClarify, Spec: phaseSpec})
+	updated, _ = updated.(Model).Update(logEntryMsg(loop.LogEntry{Kind: loop.LogPhaseDone, Phase: loop.PhaseClarify, Subtype: "success", ClaudeSession: "s"}))
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	updated, _ = updated.(Model).Update(cmd())
+	m = updated.(Model)
+	if m.phase.active() || len(ctrl.phases) != 1 {
+		t.Errorf("esc should end clarify without another run: phase %+v, runs %d", m.phase, len(ctrl.phases))
+	}
+	if !strings.Contains(m.mainView.View(), "speckit.clarify ended") {
+		t.Error("ending clarify should be logged")
+	}
+}
+
+func TestPhaseRequest_SpecifyAsksForDescription(t *testing.T) {
+	ctrl := &mockPhaseController{}
+	m := newPhaseModel(ctrl)
+	updated, _ := m.Update(panels.PhaseRequestMsg{Phase: loop.PhaseSpecify, Spec: phaseSpec})
+	m = updated.(Model)
+	if len(ctrl.phases) != 0 || !m.mainView.InputActive() {
+		t.Fatalf("specify should ask for a description before running (runs %d)", len(ctrl.phases))
+	}
+	typeAnswer(t, m, "sso login")
+	if len(ctrl.phases) != 1 || ctrl.phases[0].Input != "sso login" || ctrl.phases[0].Resume != "" {
+		t.Errorf("phases = %+v, want specify with the description", ctrl.phases)
+	}
+}
+
+func TestPhaseRequest_Unavailable(t *testing.T) {
+	m := newPhaseModel(&mockLoopController{})
+	updated, _ := m.Update(panels.PhaseRequestMsg{Phase: loop.PhasePlan, Spec: phaseSpec})
+	if m := updated.(Model); m.phase.active() || !strings.Contains(m.mainView.View(), "need the dashboard") {
+		t.Error("a controller without RunPhase should report that phases are unavailable")
+	}
+
+	ctrl := &mockPhaseController{mockLoopController: mockLoopController{running: true}}
+	updated, _ = newPhaseModel(ctrl).Update(panels.PhaseRequestMsg{Phase: loop.PhasePlan, Spec: phaseSpec})
+	if m := updated.(Model); len(ctrl.phases) != 0 || !strings.Contains(m.mainView.View(), "already running") {
+		t.Error("a phase must not start while a loop runs")
+	}
+}
+
+func TestTrackPhase_RefreshesSpecs(t *testing.T) {
+	ctrl := &mockPhaseController{}
+	m := newPhaseModel(ctrl)
+	updated, _ := m.Update(panels.PhaseRequestMsg{Phase: loop.PhasePlan, Spec: phaseSpec})
+	m = updated.(Model)
+
+	hasRefresh := func(cmd tea.Cmd) bool {
+		for _, msg := range cmdMsgs(cmd) {
+			if _, ok := msg.(specsRefreshedMsg); ok {
+				return true
+			}
+		}
+		return false
+	}
+	updated, cmd := m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogToolUse, ToolName: "Write", ToolInput: "specs/001-auth/plan.md"}))
+	m = updated.(Model)
+	if !hasRefresh(cmd) {
+		t.Error("a Write during the phase should refresh the Specs tree")
+	}
+	updated, cmd = m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogToolUse, ToolName: "Read", ToolInput: "spec.md"}))
+	m = updated.(Model)
+	if hasRefresh(cmd) {
+		t.Error("a Read should not refresh the Specs tree")
+	}
+	updated, cmd = m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogPhaseDone, Phase: loop.PhasePlan, Subtype: "success"}))
+	m = updated.(Model)
+	if !hasRefresh(cmd) || m.phase.active() {
+		t.Errorf("plan finishing should refresh specs and end the phase (phase %+v)", m.phase)
+	}
+}
+
+func TestTrackPhase_ErrorEndsPhase(t *testing.T) {
+	ctrl := &mockPhaseController{}
+	m := newPhaseModel(ctrl)
+	updated, _ := m.Update(panels.PhaseRequestMsg{Phase: loop.PhaseTasks, Spec: phaseSpec})
+	m = updated.(Model)
+
+	// Claude errors during the phase carry no Phase and leave it running.
+	updated, _ = m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogError, Message: "Error: tool failed"}))
+	m = updated.(Model)
+	if !m.phase.running {
+		t.Fatal("a Claude error should not end the phase")
+	}
+	updated, _ = m.Update(logEntryMsg(loop.LogEntry{Kind: loop.LogError, Message: "plan.md not found", Phase: loop.PhaseTasks}))
+	if m := updated.(Model); m.phase.active() {
+		t.Error("a phase error should end the phase")
+	}
+}
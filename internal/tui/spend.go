@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/LISSConsulting/RalphSpec/internal/cost"
+	"github.com/LISSConsulting/RalphSpec/internal/spec"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
+)
+
+// spendMsg carries the cost report for the Main panel's Spend tab.
+type spendMsg struct {
+	Report cost.Report
+	Err    error
+}
+
+// sectionStyle renders section headings in the Spend and Tools tabs.
+var sectionStyle = lipgloss.NewStyle().Bold(true)
+
+// spendDays is how many recent days the Spend tab's sparkline covers.
+const spendDays = 30
+
+// loadSpendCmd builds the cost report of every session under workDir,
+// projecting the spec that branch resolves to.
+func loadSpendCmd(workDir, branch string) tea.Cmd {
+	if workDir == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		records, err := cost.Load(workDir)
+		if err != nil {
+			return spendMsg{Err: err}
+		}
+		var active string
+		if as, err := spec.Resolve(workDir, "", branch); err == nil {
+			active = as.Name
+		}
+		return spendMsg{Report: cost.Summarize(workDir, records, active)}
+	}
+}
+
+// handleSpend loads a cost report into the Spend tab.
+func (m Model) handleSpend(msg spendMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.mainView = m.mainView.SetSpend([]string{errorStyle.Render("cost report unavailable: " + msg.Err.Error())})
+		return m, nil
+	}
+	m.mainView = m.mainView.SetSpend(RenderSpend(msg.Report, m.layout.Main.Width))
+	return m, nil
+}
+
+// RenderSpend renders a cost report as the Spend tab's lines: totals, a
+// per-day sparkline, per-spec and per-model bars, and the active spec's
+// projection. `ralph cost` prints the same lines.
+func RenderSpend(rep cost.Report, width int) []string {
+	if rep.Iterations == 0 {
+		return []string{timestampStyle.Render("No completed iterations recorded yet.")}
+	}
+	lines := []string{
+		fmt.Sprintf("Total $%.2f  ·  %d iterations  ·  %d sessions", rep.TotalUSD, rep.Iterations, rep.Sessions),
+	}
+	if rep.PerTask > 0 {
+		lines = append(lines, fmt.Sprintf("Average $%.2f per completed task", rep.PerTask))
+	}
+	if p := rep.Projection; p != nil {
+		basis := p.Spec + " rate"
+		if p.FromAverage {
+			basis = "all-spec average"
+		}
+		lines = append(lines, resultStyle.Render(fmt.Sprintf("Projected $%.2f to finish %s (%d tasks × $%.2f, %s)",
+			p.CostUSD, p.Spec, p.TasksRemaining, p.PerTask, basis)))
+	}
+
+	days := rep.Days
+	if len(days) > spendDays {
+		days = days[len(days)-spendDays:]
+	}
+	values := make([]float64, len(days))
+	peak := 0.0
+	for i, d := range days {
+		values[i] = d.CostUSD
+		peak = max(peak, d.CostUSD)
+	}
+	lines = append(lines, "", sectionStyle.Render(fmt.Sprintf("Per day (last %d)", len(days))),
+		fmt.Sprintf("  %s  peak $%.2f", components.Sparkline(values), peak),
+		timestampStyle.Render(fmt.Sprintf("  %s → %s", days[0].Key, days[len(days)-1].Key)))
+
+	labelW, barW := spendColumns(rep, width)
+	lines = append(lines, "", sectionStyle.Render("Per spec"))
+	for _, s := range rep.Specs {
+		line := spendRow(specLabel(s.Key), s.Bucket, rep.Specs[0].CostUSD, labelW, barW)
+		if s.TasksTotal > 0 {
+			line += timestampStyle.Render(fmt.Sprintf("  %d/%d tasks", s.TasksDone, s.TasksTotal))
+		}
+		if s.PerTask > 0 {
+			line += timestampStyle.Render(fmt.Sprintf("  $%.2f/task", s.PerTask))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", sectionStyle.Render("Per model"))
+	for _, b := range rep.Models {
+		lines = append(lines, spendRow(b.Key, b, rep.Models[0].CostUSD, labelW, barW))
+	}
+	return lines
+}
+
+// spendRow renders one "label  bar  $cost" row of a bar chart.
+func spendRow(label string, b cost.Bucket, peak float64, labelW, barW int) string {
+	return fmt.Sprintf("  %-*s %s %8s", labelW, truncateRunes(label, labelW),
+		components.Bar(b.CostUSD, peak, barW), fmt.Sprintf("$%.2f", b.CostUSD))
+}
+
+// spendColumns sizes the label and bar columns of the bar charts to width.
+func spendColumns(rep cost.Report, width int) (labelW, barW int) {
+	for _, s := range rep.Specs {
+		labelW = max(labelW, len([]rune(specLabel(s.Key))))
+	}
+	for _, b := range rep.Models {
+		labelW = max(labelW, len([]rune(b.Key)))
+	}
+	labelW = min(labelW, 24)
+	barW = min(max(width-labelW-40, 8), 30)
+	return labelW, barW
+}
+
+// specLabel names a spec bucket; iterations without an active spec share
+// the "" key.
+func specLabel(key string) string {
+	if key == "" {
+		return "(no spec)"
+	}
+	return key
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/LISSConsulting/RalphSpec/internal/cost"
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+)
+
+func TestRenderSpend(t *testing.T) {
+	rep := cost.Report{
+		TotalUSD: 3.5, Iterations: 3, Sessions: 2, PerTask: 0.75,
+		Specs: []cost.SpecCost{
+			{Bucket: cost.Bucket{Key: "002-billing", CostUSD: 2, Iterations: 1}, TasksTotal: 2},
+			{Bucket: cost.Bucket{Key: "", CostUSD: 1.5, Iterations: 2}, TasksDone: 2, TasksTotal: 5, PerTask: 0.75},
+		},
+		Days:       []cost.Bucket{{Key: "2026-03-02", CostUSD: 1.5}, {Key: "2026-03-03"}, {Key: "2026-03-04", CostUSD: 2}},
+		Models:     []cost.Bucket{{Key: "opus", CostUSD: 2}, {Key: "default", CostUSD: 1.5}},
+		Projection: &cost.Projection{Spec: "002-billing", TasksRemaining: 2, PerTask: 0.75, CostUSD: 1.5, FromAverage: true},
+	}
+	body := ansi.Strip(strings.Join(RenderSpend(rep, 100), "\n"))
+	for _, want := range []string{
+		"Total $3.50", "3 iterations", "2 sessions",
+		"Average $0.75 per completed task",
+		"Projected $1.50 to finish 002-billing (2 tasks × $0.75, all-spec average)",
+		"Per day (last 3)", "▆▁█", "2026-03-02 → 2026-03-04",
+		"Per spec", "002-billing", "(no spec)", "2/5 tasks", "$0.75/task",
+		"Per model", "opus", "default",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Spend tab missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderSpend_Empty(t *testing.T) {
+	lines := RenderSpend(cost.Report{}, 80)
+	if len(lines) != 1 || !strings.Contains(ansi.Strip(lines[0]), "No completed iterations") {
+		t.Errorf("empty report = %q", lines)
+	}
+}
+
+func TestRenderSpend_LastThirtyDays(t *testing.T) {
+	rep := cost.Report{Iterations: 1, Specs: []cost.SpecCost{{}}, Models: []cost.Bucket{{Key: "default"}}}
+	for i := 0; i < 40; i++ {
+		rep.Days = append(rep.Days, cost.Bucket{Key: time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC).Format("2006-01-02")})
+	}
+	body := ansi.Strip(strings.Join(RenderSpend(rep, 80), "\n"))
+	if !strings.Contains(body, "Per day (last 30)") || !strings.Contains(body, "2026-01-11 → 2026-02-09") {
+		t.Errorf("sparkline should cover the last 30 days:\n%s", body)
+	}
+}
+
+func TestLoadSpendCmd(t *testing.T) {
+	if cmd := loadSpendCmd("", "main"); cmd != nil {
+		t.Error("loadSpendCmd without a work dir should be nil")
+	}
+
+	dir := t.TempDir()
+	logs := filepath.Join(dir, ".ralph", "logs")
+	if err := os.MkdirAll(logs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for _, e := range []loop.LogEntry{
+		{Kind: loop.LogIterStart, Iteration: 1, Mode: "build", Spec: "001-auth"},
+		{Kind: loop.LogIterComplete, Iteration: 1, CostUSD: 0.4, Timestamp: time.Now()},
+	} {
+		data, _ := json.Marshal(e)
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(logs, "100-1.jsonl"), []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, ok := loadSpendCmd(dir, "001-auth")().(spendMsg)
+	if !ok || msg.Err != nil {
+		t.Fatalf("loadSpendCmd() = %#v", msg)
+	}
+	if msg.Report.TotalUSD != 0.4 || len(msg.Report.Specs) != 1 || msg.Report.Specs[0].Key != "001-auth" {
+		t.Errorf("report = %+v", msg.Report)
+	}
+
+	m := newTestModel()
+	m.focus = FocusMain
+	next, _ := m.Update(msg)
+	m = next.(Model)
+	for i := 0; i < 5; i++ {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+		m = next.(Model)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Total $0.40") {
+		t.Errorf("Spend tab should show the loaded report:\n%s", view)
+	}
+}
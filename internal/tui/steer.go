@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/panels"
+)
+
+// inputPurpose is what the Main panel's AskInput prompt was opened for.
+type inputPurpose int
+
+const (
+	inputNone  inputPurpose = iota
+	inputPhase              // description or answer for the spec-kit phase
+	inputSteer              // steering note for the next iteration
+)
+
+// WithSteering enables the : / i keys, which queue steering notes in q for
+// the loop's next iteration, and the Secondary panel's Steer tab.
+func (m Model) WithSteering(q *loop.SteerQueue) Model {
+	m.steer = q
+	return m
+}
+
+// askSteerNote opens the Main panel prompt for a steering note.
+func (m Model) askSteerNote() (Model, tea.Cmd) {
+	m.focus = FocusMain
+	m.input = inputSteer
+	var cmd tea.Cmd
+	m.mainView, cmd = m.mainView.AskInput("steer: ")
+	return m, cmd
+}
+
+// handleSteerInput queues the note typed into the prompt.
+func (m Model) handleSteerInput(msg panels.InputSubmittedMsg) (tea.Model, tea.Cmd) {
+	if msg.Cancelled {
+		return m, nil
+	}
+	q := m.steer
+	return m, func() tea.Msg {
+		note, err := q.Add(msg.Text)
+		notes, _ := q.Pending()
+		return steerNotesMsg{Notes: notes, Added: note.Text, Err: err}
+	}
+}
+
+// handleSteerRemove removes a queued note selected in the Steer tab.
+func (m Model) handleSteerRemove(msg panels.SteerRemoveMsg) (tea.Model, tea.Cmd) {
+	if m.steer == nil {
+		return m, nil
+	}
+	q := m.steer
+	return m, func() tea.Msg {
+		err := q.Remove(msg.ID)
+		notes, _ := q.Pending()
+		return steerNotesMsg{Notes: notes, Err: err}
+	}
+}
+
+// handleSteerNotes shows the queued notes in the Steer tab and reports a
+// note just queued or a failed change.
+func (m Model) handleSteerNotes(msg steerNotesMsg) (tea.Model, tea.Cmd) {
+	entries := make([]panels.SteerEntry, len(msg.Notes))
+	for i, n := range msg.Notes {
+		entries[i] = panels.SteerEntry{ID: n.ID, Text: n.Text, At: n.At}
+	}
+	m.secondary = m.secondary.SetSteerEntries(entries)
+	switch {
+	case msg.Err != nil:
+		m = m.appendError(fmt.Sprintf("steering: %v", msg.Err))
+	case msg.Added != "":
+		m.mainView = m.mainView.AppendLine(m.theme.RenderLogLine(loop.LogEntry{
+			Kind:    loop.LogInfo,
+			Message: "Steering note queued for the next iteration: " + msg.Added,
+		}, m.layout.Main.Width))
+		m.secondary = m.secondary.ShowSteer()
+	}
+	return m, nil
+}
+
+// loadSteerNotesCmd reads the queued notes, which `ralph steer` may change
+// from another process and the loop consumes at each iteration start.
+func loadSteerNotesCmd(q *loop.SteerQueue) tea.Cmd {
+	if q == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		notes, err := q.Pending()
+		return steerNotesMsg{Notes: notes, Err: err}
+	}
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/panels"
+)
+
+// steerMsgs runs cmd and feeds each steerNotesMsg it produces back to m.
+func steerMsgs(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	for _, msg := range cmdMsgs(cmd) {
+		if msg, ok := msg.(steerNotesMsg); ok {
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+	return m
+}
+
+func TestSteer_KeyQueuesNote(t *testing.T) {
+	q := loop.NewSteerQueue(t.TempDir())
+	m := newPhaseModel(&mockLoopController{}).WithSteering(q)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	m = updated.(Model)
+	if !m.mainView.InputActive() || m.focus != FocusMain {
+		t.Fatalf(": should open the steering prompt in the focused Main panel (active %v, focus %v)", m.mainView.InputActive(), m.focus)
+	}
+
+	for _, r := range "skip the docs" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	msg, ok := cmd().(panels.InputSubmittedMsg)
+	if !ok {
+		t.Fatalf("enter produced %T, want InputSubmittedMsg", msg)
+	}
+	updated, cmd = m.Update(msg)
+	m = steerMsgs(t, updated.(Model), cmd)
+
+	notes, err := q.Pending()
+	if err != nil || len(notes) != 1 || notes[0].Text != "skip the docs" {
+		t.Fatalf("Pending = %+v, %v; want the typed note", notes, err)
+	}
+	if view := m.mainView.View(); !strings.Contains(view, "queued for the next iteration") {
+		t.Errorf("queuing should be confirmed in the Main panel:\n%s", view)
+	}
+	if view := m.secondary.View(); !strings.Contains(view, "skip the docs") {
+		t.Errorf("the Steer tab should show the queued note:\n%s", view)
+	}
+
+	updated, cmd = m.Update(panels.SteerRemoveMsg{ID: notes[0].ID})
+	m = steerMsgs(t, updated.(Model), cmd)
+	if notes, _ := q.Pending(); len(notes) != 0 {
+		t.Errorf("note should be removed, still queued: %+v", notes)
+	}
+	if view := m.secondary.View(); strings.Contains(view, "skip the docs") {
+		t.Errorf("the removed note should leave the Steer tab:\n%s", view)
+	}
+}
+
+func TestSteer_DisabledWithoutQueue(t *testing.T) {
+	m := newPhaseModel(&mockLoopController{})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	if updated.(Model).mainView.InputActive() {
+		t.Error("i should not open a prompt when steering is not wired")
+	}
+}
+
+func TestSteer_EscQueuesNothing(t *testing.T) {
+	q := loop.NewSteerQueue(t.TempDir())
+	m := newPhaseModel(&mockLoopController{}).WithSteering(q)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	updated, cmd = updated.(Model).Update(cmd())
+	steerMsgs(t, updated.(Model), cmd)
+	if notes, _ := q.Pending(); len(notes) != 0 {
+		t.Errorf("esc should queue nothing, got %+v", notes)
+	}
+}
+
+func TestSteer_TickReloadsNotes(t *testing.T) {
+	q := loop.NewSteerQueue(t.TempDir())
+	m := newPhaseModel(&mockLoopController{}).WithSteering(q)
+	if _, err := q.Add("from ralph steer"); err != nil {
+		t.Fatal(err)
+	}
+	_, cmd := m.Update(tickMsg{})
+	var found bool
+	for _, msg := range cmdMsgs(cmd) {
+		if msg, ok := msg.(steerNotesMsg); ok && len(msg.Notes) == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("a tick should reload notes queued by another process")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
 )
 
 // Theme holds accent-color-derived styles for the multi-panel TUI.
@@ -108,6 +109,20 @@ func (t Theme) RenderLogLine(entry loop.LogEntry, width int) string {
 		}
 		return fmt.Sprintf("%s  %s", ts, reasoningStyle.Render("💭 "+text))
 
+	case loop.LogToolResult:
+		mark, style := "✓", infoStyle
+		if entry.IsError {
+			mark, style = "✗", errorStyle
+		}
+		label := "↳ " + mark
+		if entry.ToolName != "" {
+			label += " " + entry.ToolName
+		}
+		return fmt.Sprintf("%s  %s", ts, style.Render(label+"  "+preview(entry.Content, width-24)))
+
+	case loop.LogThinking:
+		return fmt.Sprintf("%s  %s", ts, reasoningStyle.Render("🧠 "+preview(entry.Content, width-20)))
+
 	case loop.LogIterStart:
 		return fmt.Sprintf("%s  ── iteration %d ──", ts, entry.Iteration)
 
@@ -133,6 +148,37 @@ func (t Theme) RenderLogLine(entry loop.LogEntry, width int) string {
 	case loop.LogStopped:
 		return fmt.Sprintf("%s  %s", ts, errorStyle.Render("⏹ "+singleLine(entry.Message)))
 
+	case loop.LogRateLimit:
+		return fmt.Sprintf("%s  %s", ts, regentStyle.Render("⏸ "+singleLine(entry.Message)))
+
+	case loop.LogFiles:
+		style := t.gitStyle
+		for _, f := range entry.Files {
+			if f.OutOfBounds {
+				style = errorStyle
+				break
+			}
+		}
+		return fmt.Sprintf("%s  %s", ts, style.Render("📄 "+singleLine(entry.Message)))
+
+	case loop.LogPolicy:
+		return fmt.Sprintf("%s  %s", ts, errorStyle.Render("🚫 "+singleLine(entry.Message)))
+
+	case loop.LogPhaseStart:
+		return fmt.Sprintf("%s  %s", ts, infoStyle.Render("📝 "+singleLine(entry.Message)))
+
+	case loop.LogPhaseDone:
+		if entry.Subtype != "" && entry.Subtype != "success" {
+			return fmt.Sprintf("%s  %s", ts, errorStyle.Render("❌ "+singleLine(entry.Message)))
+		}
+		return fmt.Sprintf("%s  %s", ts, resultStyle.Render("✅ "+singleLine(entry.Message)))
+
+	case loop.LogPaused:
+		return fmt.Sprintf("%s  %s", ts, infoStyle.Render("⏸  "+singleLine(entry.Message)))
+
+	case loop.LogSteer:
+		return fmt.Sprintf("%s  %s", ts, infoStyle.Render("🧭 "+singleLine(entry.Message)))
+
 	case loop.LogRegent:
 		return fmt.Sprintf("%s  %s", ts, regentStyle.Render("🛡️  Regent: "+singleLine(entry.Message)))
 
@@ -141,6 +187,118 @@ func (t Theme) RenderLogLine(entry loop.LogEntry, width int) string {
 	}
 }
 
+// RenderLogLines renders entry as one or more terminal lines: the line from
+// RenderLogLine, followed for a LogFiles entry by one line per changed file
+// with out-of-bounds files marked, and for a tool result or thinking entry
+// by its content (the detail lines logLinesMeta hides until expanded).
+func (t Theme) RenderLogLines(entry loop.LogEntry, width int) []string {
+	lines := []string{t.RenderLogLine(entry, width)}
+	indent := strings.Repeat(" ", 14) // aligns under the message after "[15:04:05]  "
+	if hasDetail(entry.Kind) {
+		style := infoStyle
+		switch {
+		case entry.Kind == loop.LogThinking:
+			style = reasoningStyle
+		case entry.IsError:
+			style = errorStyle
+		}
+		maxLine := max(width-len(indent), 20)
+		for _, l := range strings.Split(strings.TrimRight(entry.Content, "\n"), "\n") {
+			l = strings.ReplaceAll(l, "\t", "    ")
+			if runes := []rune(l); len(runes) > maxLine {
+				l = string(runes[:maxLine-1]) + "…"
+			}
+			lines = append(lines, style.Render(indent+l))
+		}
+		return lines
+	}
+	for _, f := range entry.Files {
+		line := fmt.Sprintf("%s%s %s", indent, f.Status, f.Path)
+		if f.OutOfBounds {
+			lines = append(lines, errorStyle.Render(line+"  ✗ out of bounds"))
+			continue
+		}
+		lines = append(lines, infoStyle.Render(line))
+	}
+	return lines
+}
+
+// hasDetail reports whether entries of kind render detail lines that stay
+// collapsed until expanded.
+func hasDetail(kind loop.LogKind) bool {
+	return kind == loop.LogToolResult || kind == loop.LogThinking
+}
+
+// preview returns the first non-blank line of content, cut to fit width,
+// and how many lines content has.
+func preview(content string, width int) string {
+	content = strings.TrimRight(content, "\n")
+	n := strings.Count(content, "\n") + 1
+	first := ""
+	for _, l := range strings.Split(content, "\n") {
+		if strings.TrimSpace(l) != "" {
+			first = singleLine(l)
+			break
+		}
+	}
+	maxText := width - 12 // room for the line count
+	if maxText < 20 {
+		maxText = 20
+	}
+	if runes := []rune(first); len(runes) > maxText {
+		first = string(runes[:maxText-1]) + "…"
+	}
+	switch {
+	case first == "":
+		return "(empty)"
+	case n == 1:
+		return first
+	default:
+		return fmt.Sprintf("%s  (%d lines)", first, n)
+	}
+}
+
+// logLineMeta returns the filter category and bookmark identity of the lines
+// rendered from entry. iteration is used when the entry carries none. Tool
+// calls, their results, and thinking share a fold keyed by tool-use ID.
+func logLineMeta(entry loop.LogEntry, iteration int) components.LineMeta {
+	meta := components.LineMeta{Kind: components.KindInfo, Iteration: iteration, At: entry.Timestamp}
+	if entry.Iteration > 0 {
+		meta.Iteration = entry.Iteration
+	}
+	if entry.ToolUseID != "" {
+		meta.Fold = entry.ToolUseID
+	} else if hasDetail(entry.Kind) {
+		meta.Fold = fmt.Sprintf("%d@%d", meta.Iteration, entry.Timestamp.UnixNano())
+	}
+	switch entry.Kind {
+	case loop.LogToolUse, loop.LogToolResult:
+		meta.Kind, meta.Tool = components.KindTool, entry.ToolName
+	case loop.LogText, loop.LogThinking:
+		meta.Kind = components.KindText
+	case loop.LogError, loop.LogPolicy:
+		meta.Kind = components.KindError
+	case loop.LogGitPull, loop.LogGitPush, loop.LogFiles:
+		meta.Kind = components.KindGit
+	case loop.LogRegent:
+		meta.Kind = components.KindRegent
+	}
+	return meta
+}
+
+// logLinesMeta returns the meta of each of the n lines RenderLogLines
+// rendered from entry: logLineMeta, with every line after the first marked
+// as a detail line for tool results and thinking.
+func logLinesMeta(entry loop.LogEntry, iteration, n int) []components.LineMeta {
+	meta := logLineMeta(entry, iteration)
+	metas := make([]components.LineMeta, n)
+	for i := range metas {
+		metas[i] = meta
+		metas[i].Detail = i > 0 && hasDetail(entry.Kind)
+	}
+	return metas
+}
+
 // RenderLogLine is also exported as a package-level function for convenience.
 // It delegates to theme.RenderLogLine.
 func RenderLogLine(entry loop.LogEntry, width int, theme Theme) string {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/tui/components"
 )
 
 func TestNewTheme_DefaultAccent(t *testing.T) {
@@ -117,6 +118,36 @@ func TestRenderLogLine_AllKinds(t *testing.T) {
 			entry:    loop.LogEntry{Kind: loop.LogStopped, Timestamp: now, Message: "loop stopped"},
 			contains: []string{"⏹", "loop stopped"},
 		},
+		{
+			name:     "LogPolicy",
+			entry:    loop.LogEntry{Kind: loop.LogPolicy, Timestamp: now, Message: "Policy rule #1 (warn): Bash  rm -rf /"},
+			contains: []string{"🚫", "Policy rule #1", "rm -rf /"},
+		},
+		{
+			name:     "LogPhaseStart",
+			entry:    loop.LogEntry{Kind: loop.LogPhaseStart, Timestamp: now, Message: "Running speckit.plan for specs/001-a"},
+			contains: []string{"📝", "speckit.plan"},
+		},
+		{
+			name:     "LogPhaseDone success",
+			entry:    loop.LogEntry{Kind: loop.LogPhaseDone, Timestamp: now, Message: "speckit.plan complete", Subtype: "success"},
+			contains: []string{"✅", "speckit.plan complete"},
+		},
+		{
+			name:     "LogPhaseDone failure",
+			entry:    loop.LogEntry{Kind: loop.LogPhaseDone, Timestamp: now, Message: "speckit.plan complete — error_max_turns", Subtype: "error_max_turns"},
+			contains: []string{"❌", "error_max_turns"},
+		},
+		{
+			name:     "LogPaused",
+			entry:    loop.LogEntry{Kind: loop.LogPaused, Timestamp: now, Message: "Paused after iteration 3"},
+			contains: []string{"⏸", "Paused after iteration 3"},
+		},
+		{
+			name:     "LogSteer",
+			entry:    loop.LogEntry{Kind: loop.LogSteer, Timestamp: now, Message: "Operator guidance: skip the docs"},
+			contains: []string{"🧭", "Operator guidance", "skip the docs"},
+		},
 		{
 			name:     "LogRegent",
 			entry:    loop.LogEntry{Kind: loop.LogRegent, Timestamp: now, Message: "restarting"},
@@ -190,6 +221,93 @@ func TestRenderLogLine_LogIterComplete_NoSubtype(t *testing.T) {
 	}
 }
 
+func TestRenderLogLines_Files(t *testing.T) {
+	th := NewTheme("")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := loop.LogEntry{
+		Kind:      loop.LogFiles,
+		Timestamp: now,
+		Message:   "2 file(s) changed, 1 outside the spec boundary",
+		Files: []loop.ChangedFile{
+			{Status: "M", Path: "internal/core/a.go"},
+			{Status: "A", Path: ".github/ci.yml", OutOfBounds: true},
+		},
+	}
+	lines := th.RenderLogLines(entry, 120)
+	if len(lines) != 3 {
+		t.Fatalf("RenderLogLines() = %d lines, want header + 2 files", len(lines))
+	}
+	if !strings.Contains(lines[0], "📄") || !strings.Contains(lines[0], "1 outside") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "M internal/core/a.go") || strings.Contains(lines[1], "out of bounds") {
+		t.Errorf("in-bounds line = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "A .github/ci.yml") || !strings.Contains(lines[2], "out of bounds") {
+		t.Errorf("out-of-bounds line = %q", lines[2])
+	}
+
+	if got := th.RenderLogLines(loop.LogEntry{Kind: loop.LogInfo, Timestamp: now, Message: "x"}, 120); len(got) != 1 {
+		t.Errorf("non-files entry rendered as %d lines, want 1", len(got))
+	}
+}
+
+func TestRenderLogLines_ToolResult(t *testing.T) {
+	th := NewTheme("")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	failed := loop.LogEntry{
+		Kind:      loop.LogToolResult,
+		Timestamp: now,
+		ToolName:  "Bash",
+		ToolUseID: "toolu_1",
+		Content:   "\n--- FAIL: TestAuth\nFAIL\tpkg/auth\n",
+		IsError:   true,
+	}
+	lines := th.RenderLogLines(failed, 120)
+	if len(lines) != 4 {
+		t.Fatalf("RenderLogLines() = %q, want summary + 3 content lines", lines)
+	}
+	if !strings.Contains(lines[0], "↳ ✗ Bash  --- FAIL: TestAuth  (3 lines)") {
+		t.Errorf("summary = %q", lines[0])
+	}
+	if !strings.Contains(lines[3], "FAIL    pkg/auth") {
+		t.Errorf("content line = %q, want tabs expanded", lines[3])
+	}
+
+	ok := th.RenderLogLine(loop.LogEntry{Kind: loop.LogToolResult, Timestamp: now, Content: "done"}, 120)
+	if !strings.Contains(ok, "↳ ✓  done") || strings.Contains(ok, "lines") {
+		t.Errorf("one-line result = %q", ok)
+	}
+	if empty := th.RenderLogLine(loop.LogEntry{Kind: loop.LogToolResult, Timestamp: now}, 120); !strings.Contains(empty, "(empty)") {
+		t.Errorf("empty result = %q", empty)
+	}
+	thinking := th.RenderLogLines(loop.LogEntry{Kind: loop.LogThinking, Timestamp: now, Content: "Plan it.\nThen test."}, 120)
+	if len(thinking) != 3 || !strings.Contains(thinking[0], "🧠 Plan it.  (2 lines)") {
+		t.Errorf("thinking = %q", thinking)
+	}
+}
+
+func TestLogLinesMeta(t *testing.T) {
+	at := time.Now()
+	result := loop.LogEntry{Kind: loop.LogToolResult, Timestamp: at, ToolName: "Bash", ToolUseID: "toolu_1"}
+	metas := logLinesMeta(result, 3, 3)
+	if metas[0].Detail || !metas[1].Detail || !metas[2].Detail {
+		t.Errorf("only lines after the summary should be detail lines: %+v", metas)
+	}
+	call := logLineMeta(loop.LogEntry{Kind: loop.LogToolUse, Timestamp: at, ToolUseID: "toolu_1"}, 3)
+	if call.Fold != "toolu_1" || metas[1].Fold != call.Fold {
+		t.Errorf("call fold %q and result fold %q should match", call.Fold, metas[1].Fold)
+	}
+	thinking := logLineMeta(loop.LogEntry{Kind: loop.LogThinking, Timestamp: at}, 3)
+	if thinking.Fold == "" {
+		t.Error("unlinked thinking should get a fold of its own")
+	}
+	files := logLinesMeta(loop.LogEntry{Kind: loop.LogFiles, Timestamp: at}, 3, 2)
+	if files[1].Detail || files[1].Fold != "" {
+		t.Errorf("file lines are not foldable: %+v", files[1])
+	}
+}
+
 func TestRenderPanelBox(t *testing.T) {
 	th := NewTheme("")
 	tests := []struct {
@@ -298,3 +416,30 @@ func TestRenderLogLine_NarrowWidth_LogText(t *testing.T) {
 		t.Error("expected text to be truncated even with narrow width clamp")
 	}
 }
+
+func TestLogLineMeta(t *testing.T) {
+	at := time.Now()
+	tests := []struct {
+		entry loop.LogEntry
+		kind  string
+	}{
+		{loop.LogEntry{Kind: loop.LogToolUse, ToolName: "Bash"}, components.KindTool},
+		{loop.LogEntry{Kind: loop.LogText}, components.KindText},
+		{loop.LogEntry{Kind: loop.LogToolResult, ToolName: "Bash"}, components.KindTool},
+		{loop.LogEntry{Kind: loop.LogThinking}, components.KindText},
+		{loop.LogEntry{Kind: loop.LogPolicy}, components.KindError},
+		{loop.LogEntry{Kind: loop.LogFiles}, components.KindGit},
+		{loop.LogEntry{Kind: loop.LogRegent}, components.KindRegent},
+		{loop.LogEntry{Kind: loop.LogIterStart}, components.KindInfo},
+	}
+	for _, tt := range tests {
+		tt.entry.Timestamp = at
+		meta := logLineMeta(tt.entry, 5)
+		if meta.Kind != tt.kind || meta.Iteration != 5 || !meta.At.Equal(at) {
+			t.Errorf("logLineMeta(%v) = %+v, want kind %q", tt.entry.Kind, meta, tt.kind)
+		}
+	}
+	if meta := logLineMeta(loop.LogEntry{Kind: loop.LogToolUse, ToolName: "Edit", Iteration: 2}, 5); meta.Tool != "Edit" || meta.Iteration != 2 {
+		t.Errorf("entry iteration and tool should win: %+v", meta)
+	}
+}
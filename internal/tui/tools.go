@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+// toolsTop is how many files and commands the Tools tab ranks.
+const toolsTop = 10
+
+// toolsWarnStyle marks iterations that edited without testing and files the
+// agent keeps returning to.
+var toolsWarnStyle = lipgloss.NewStyle().Foreground(colorYellow)
+
+// trackTools counts a loop entry into the running iteration's tool usage
+// and refreshes the Tools tab.
+func (m Model) trackTools(entry loop.LogEntry) Model {
+	switch entry.Kind {
+	case loop.LogIterStart:
+		m.curTools = store.ToolStats{}
+	case loop.LogToolUse:
+		m.curTools.Add(entry)
+	case loop.LogIterComplete:
+		m.iterTools = append(m.iterTools, store.IterationTools{Number: entry.Iteration, Stats: m.curTools})
+		m.curTools = store.ToolStats{}
+	default:
+		return m
+	}
+	return m.refreshTools()
+}
+
+// refreshTools re-renders the Tools tab, including the running iteration.
+func (m Model) refreshTools() Model {
+	var running *store.IterationTools
+	if m.curTools.Total() > 0 {
+		running = &store.IterationTools{Number: m.iteration, Stats: m.curTools}
+	}
+	m.secondary = m.secondary.SetTools(RenderTools(m.iterTools, running, m.layout.Secondary.Width))
+	return m
+}
+
+// RenderTools renders tool usage as the Tools tab's lines: session totals,
+// one row per iteration flagging edits without a test run, and the
+// most-touched files and most-run Bash commands. running, when non-nil, is
+// the iteration in progress. `ralph history --tools` prints the same lines.
+func RenderTools(iters []store.IterationTools, running *store.IterationTools, width int) []string {
+	all := iters
+	if running != nil {
+		all = append(all[:len(all):len(all)], *running)
+	}
+	var session store.ToolStats
+	for _, it := range all {
+		session.Merge(it.Stats)
+	}
+	if session.Total() == 0 {
+		return []string{timestampStyle.Render("No tool calls yet")}
+	}
+
+	lines := []string{
+		fmt.Sprintf("Session  %d calls · %d edits · %d test runs", session.Total(), session.Edits, session.TestRuns),
+		"  " + truncateRunes(toolCounts(session, 0), max(width-2, 1)),
+		"",
+		sectionStyle.Render("Per iteration"),
+	}
+	for i, it := range all {
+		label := fmt.Sprintf("#%d", it.Number)
+		if running != nil && i == len(all)-1 {
+			label += "…"
+		}
+		lines = append(lines, fmt.Sprintf("  %-5s %s%s", label,
+			truncateRunes(toolCounts(it.Stats, 4), max(width-30, 12)), toolFlags(it.Stats)))
+	}
+
+	if files := store.Top(session.Files, toolsTop); len(files) > 0 {
+		lines = append(lines, "", sectionStyle.Render("Most-touched files"))
+		for _, c := range files {
+			line := fmt.Sprintf("  %3d× %s", c.N, truncateRunes(c.Key, max(width-18, 10)))
+			if c.N >= store.ThrashCalls {
+				line += toolsWarnStyle.Render("  ⚠ thrashing")
+			}
+			lines = append(lines, line)
+		}
+	}
+	if cmds := store.Top(session.Commands, toolsTop); len(cmds) > 0 {
+		lines = append(lines, "", sectionStyle.Render("Most-run commands"))
+		for _, c := range cmds {
+			lines = append(lines, fmt.Sprintf("  %3d× %s", c.N, truncateRunes(c.Key, max(width-8, 10))))
+		}
+	}
+	return lines
+}
+
+// toolCounts formats the n most-called tools as "Read 5 · Edit 2"; n <= 0
+// lists them all.
+func toolCounts(s store.ToolStats, n int) string {
+	top := store.Top(s.Calls, 0)
+	parts := make([]string, 0, len(top))
+	for i, c := range top {
+		if n > 0 && i == n {
+			parts = append(parts, fmt.Sprintf("+%d more", len(top)-n))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", c.Key, c.N))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// toolFlags marks an iteration's test runs, untested edits and thrashing.
+func toolFlags(s store.ToolStats) string {
+	var flags string
+	switch {
+	case s.TestRuns > 0:
+		flags = resultStyle.Render("  ✓ tests")
+	case s.Untested():
+		flags = toolsWarnStyle.Render("  ⚠ edits, no tests")
+	}
+	if hot := s.Thrashing(); len(hot) > 0 {
+		flags += toolsWarnStyle.Render(fmt.Sprintf("  ⚠ %d× %s", hot[0].N, hot[0].Key))
+	}
+	return flags
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/LISSConsulting/RalphSpec/internal/loop"
+	"github.com/LISSConsulting/RalphSpec/internal/store"
+)
+
+func toolStats(uses ...string) store.ToolStats {
+	var s store.ToolStats
+	for _, u := range uses {
+		name, input, _ := strings.Cut(u, " ")
+		s.Add(loop.LogEntry{Kind: loop.LogToolUse, ToolName: name, ToolInput: input})
+	}
+	return s
+}
+
+func TestRenderTools(t *testing.T) {
+	hot := make([]string, store.ThrashCalls)
+	for i := range hot {
+		hot[i] = "Read main.go"
+	}
+	iters := []store.IterationTools{
+		{Number: 1, Stats: toolStats("Read a.go", "Edit a.go", "Bash go test ./...")},
+		{Number: 2, Stats: toolStats(append(hot, "Edit main.go")...)},
+	}
+	running := &store.IterationTools{Number: 3, Stats: toolStats("Grep TODO")}
+	body := ansi.Strip(strings.Join(RenderTools(iters, running, 100), "\n"))
+	for _, want := range []string{
+		"Session  15 calls · 2 edits · 1 test runs",
+		"Read 11 · Edit 2 · Bash 1 · Grep 1",
+		"#1    Bash 1 · Edit 1 · Read 1  ✓ tests",
+		"#2    Read 10 · Edit 1  ⚠ edits, no tests  ⚠ 11× main.go",
+		"#3…   Grep 1",
+		"Most-touched files", " 11× main.go  ⚠ thrashing", "  2× a.go",
+		"Most-run commands", "  1× go test ./...",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Tools tab missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderTools_Empty(t *testing.T) {
+	lines := RenderTools(nil, nil, 80)
+	if len(lines) != 1 || !strings.Contains(ansi.Strip(lines[0]), "No tool calls yet") {
+		t.Errorf("empty = %q", lines)
+	}
+}
+
+func TestToolCounts_Limit(t *testing.T) {
+	s := toolStats("Read a", "Read a", "Edit a", "Bash x", "Grep y", "Glob z", "WebFetch u")
+	if got := toolCounts(s, 2); got != "Read 2 · Bash 1 · +4 more" {
+		t.Errorf("toolCounts(2) = %q", got)
+	}
+}
+
+func TestTrackTools(t *testing.T) {
+	m := newTestModel()
+	feed := func(entries ...loop.LogEntry) {
+		for _, e := range entries {
+			next, _ := m.Update(logEntryMsg(e))
+			m = next.(Model)
+		}
+	}
+	feed(
+		loop.LogEntry{Kind: loop.LogIterStart, Iteration: 1, Mode: "build"},
+		loop.LogEntry{Kind: loop.LogToolUse, ToolName: "Edit", ToolInput: "a.go"},
+	)
+	if m.curTools.Total() != 1 || len(m.iterTools) != 0 {
+		t.Fatalf("running iteration: cur %d calls, %d done", m.curTools.Total(), len(m.iterTools))
+	}
+	feed(loop.LogEntry{Kind: loop.LogIterComplete, Iteration: 1})
+	if len(m.iterTools) != 1 || m.iterTools[0].Number != 1 || !m.iterTools[0].Stats.Untested() {
+		t.Errorf("iterTools = %+v", m.iterTools)
+	}
+	if m.curTools.Total() != 0 {
+		t.Error("the running iteration's counts should reset on completion")
+	}
+}
+
+func TestIterationsLoaded_Tools(t *testing.T) {
+	m := newTestModel()
+	m.iterTools = []store.IterationTools{{Number: 3, Stats: toolStats("Read live.go")}}
+	next, _ := m.Update(iterationsLoadedMsg{Tools: []store.IterationTools{{Number: 1, Stats: toolStats("Read old.go")}}})
+	m = next.(Model)
+	if got := fmt.Sprint(m.iterTools[0].Number, m.iterTools[1].Number); got != "1 3" {
+		t.Errorf("iterations = %s, want stored ones before live ones", got)
+	}
+}
@@ -0,0 +1,190 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Merge strategies supported by GitRunner.Merge.
+const (
+	MergeRebase = "rebase" // rebase the branch onto target, then fast-forward (default)
+	MergeFF     = "ff"     // fast-forward only; fails if target has diverged
+	MergeSquash = "squash" // squash the branch into a single commit on target
+)
+
+// GitRunner is a WorktreeOps implementation that uses plain git commands, for
+// teams without worktrunk ([worktree] backend = "git"). Worktrees are created
+// under WorktreeDir using the same layout as Runner's WorktreeDir mode.
+type GitRunner struct {
+	Dir           string // root of the main git working tree
+	WorktreeDir   string // base directory for worktrees
+	MergeStrategy string // MergeRebase, MergeFF, or MergeSquash; empty = MergeRebase
+}
+
+// NewGitRunner returns a GitRunner rooted at dir that stores worktrees under
+// worktreeDir.
+func NewGitRunner(dir, worktreeDir string) *GitRunner {
+	return &GitRunner{Dir: dir, WorktreeDir: worktreeDir}
+}
+
+// Detect checks that git is on PATH and Dir is inside a git working tree.
+func (g *GitRunner) Detect() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("worktree: git not found on PATH")
+	}
+	if _, err := runGit(g.Dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return fmt.Errorf("worktree: %s is not a git repository: %w", g.Dir, err)
+	}
+	return nil
+}
+
+// Switch returns the worktree for branch, creating it under WorktreeDir if
+// none exists. When create is true the branch is created from HEAD.
+func (g *GitRunner) Switch(branch string, create bool) (string, error) {
+	if info, ok := g.find(branch); ok {
+		return info.Path, nil
+	}
+	if g.WorktreeDir == "" {
+		return "", fmt.Errorf("git worktree add %s: no worktree directory configured", branch)
+	}
+	return addGitWorktree(g.Dir, g.WorktreeDir, branch, create)
+}
+
+// List returns all worktrees of the repository, parsed from
+// `git worktree list --porcelain`.
+func (g *GitRunner) List() ([]WorktreeInfo, error) {
+	return listPorcelain(g.Dir)
+}
+
+// Merge integrates branch into target using MergeStrategy. The merge runs in
+// whichever worktree has target checked out, which must be clean. If target
+// is empty, the branch checked out in Dir is used. A failed rebase or squash
+// is aborted so both worktrees are left as they were; when it failed on
+// conflicting changes the error wraps ErrMergeConflict.
+func (g *GitRunner) Merge(branch, target string) error {
+	if target == "" {
+		out, err := runGit(g.Dir, "symbolic-ref", "--short", "HEAD")
+		if err != nil {
+			return fmt.Errorf("git merge %s: resolve target branch: %w", branch, err)
+		}
+		target = out
+	}
+	if target == branch {
+		return fmt.Errorf("git merge %s: cannot merge a branch into itself", branch)
+	}
+	targetWT, ok := g.find(target)
+	if !ok {
+		return fmt.Errorf("git merge %s: target %s is not checked out in any worktree", branch, target)
+	}
+	if status, err := runGit(targetWT.Path, "status", "--porcelain"); err != nil {
+		return fmt.Errorf("git merge %s: %w", branch, err)
+	} else if status != "" {
+		return fmt.Errorf("git merge %s: %s has uncommitted changes in %s", branch, target, targetWT.Path)
+	}
+
+	switch g.MergeStrategy {
+	case MergeFF:
+		if _, err := runGit(targetWT.Path, "merge", "--ff-only", branch); err != nil {
+			return fmt.Errorf("git merge %s: %w", branch, err)
+		}
+	case MergeSquash:
+		if _, err := runGit(targetWT.Path, "merge", "--squash", branch); err != nil {
+			err = conflictError(targetWT.Path, err)
+			_, _ = runGit(targetWT.Path, "reset", "--merge")
+			return fmt.Errorf("git merge %s: %w", branch, err)
+		}
+		if _, err := runGit(targetWT.Path, "diff", "--cached", "--quiet"); err == nil {
+			return nil // branch has no changes relative to target
+		}
+		if _, err := runGit(targetWT.Path, "commit", "--no-edit"); err != nil {
+			_, _ = runGit(targetWT.Path, "reset", "--merge")
+			return fmt.Errorf("git merge %s: %w", branch, err)
+		}
+	default:
+		branchWT, ok := g.find(branch)
+		if !ok {
+			return fmt.Errorf("git merge %s: branch is not checked out in any worktree", branch)
+		}
+		if _, err := runGit(branchWT.Path, "rebase", target); err != nil {
+			err = conflictError(branchWT.Path, err)
+			_, _ = runGit(branchWT.Path, "rebase", "--abort")
+			return fmt.Errorf("git merge %s: rebase onto %s: %w", branch, target, err)
+		}
+		if _, err := runGit(targetWT.Path, "merge", "--ff-only", branch); err != nil {
+			return fmt.Errorf("git merge %s: %w", branch, err)
+		}
+	}
+	return nil
+}
+
+// Remove removes the worktree for branch and deletes the branch if it has
+// been merged. An unmerged branch is kept so no work is lost; git refuses to
+// remove a worktree with uncommitted changes.
+func (g *GitRunner) Remove(branch string) error {
+	info, ok := g.find(branch)
+	if !ok {
+		return fmt.Errorf("git worktree remove %s: no worktree for branch", branch)
+	}
+	if _, err := runGit(g.Dir, "worktree", "remove", info.Path); err != nil {
+		return fmt.Errorf("git worktree remove %s: %w", branch, err)
+	}
+	_, _ = runGit(g.Dir, "branch", "-d", branch)
+	return nil
+}
+
+// conflictError wraps err with ErrMergeConflict and the conflicted paths when
+// the worktree at dir has unmerged files; otherwise it returns err unchanged.
+// It must run before the failed operation is aborted.
+func conflictError(dir string, err error) error {
+	out, diffErr := runGit(dir, "diff", "--name-only", "-z", "--diff-filter=U")
+	if diffErr != nil {
+		return err
+	}
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w in %s: %w", ErrMergeConflict, strings.Join(paths, ", "), err)
+}
+
+// find returns the worktree that has branch checked out.
+func (g *GitRunner) find(branch string) (WorktreeInfo, bool) {
+	infos, err := g.List()
+	if err != nil {
+		return WorktreeInfo{}, false
+	}
+	for _, info := range infos {
+		if info.Branch == branch {
+			return info, true
+		}
+	}
+	return WorktreeInfo{}, false
+}
+
+// runGit runs git in dir and returns its trimmed stdout. On failure the error
+// carries git's stderr.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			return "", err
+		}
+		return "", fmt.Errorf("%s", msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,233 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
+)
+
+// mustGit runs git in dir and returns its trimmed output, failing the test on
+// error.
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := runGit(dir, args...)
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return out
+}
+
+// commitFile writes name in dir and commits it with message.
+func commitFile(t *testing.T, dir, name, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(message), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mustGit(t, dir, "add", name)
+	mustGit(t, dir, "commit", "-m", message)
+}
+
+// newTestGitRunner returns a GitRunner for a fresh repo with one commit, and
+// the name of the branch checked out in the main worktree.
+func newTestGitRunner(t *testing.T) (*GitRunner, string) {
+	t.Helper()
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	return NewGitRunner(dir, t.TempDir()), mustGit(t, dir, "symbolic-ref", "--short", "HEAD")
+}
+
+func TestGitRunner_Detect(t *testing.T) {
+	g, _ := newTestGitRunner(t)
+	if err := g.Detect(); err != nil {
+		t.Errorf("Detect in a git repo: %v", err)
+	}
+	if err := NewGitRunner(t.TempDir(), "").Detect(); err == nil {
+		t.Error("expected Detect to fail outside a git repository")
+	}
+}
+
+func TestGitRunner_SwitchListRemove(t *testing.T) {
+	g, _ := newTestGitRunner(t)
+
+	path, err := g.Switch("feat/a", true)
+	if err != nil {
+		t.Fatalf("Switch create: %v", err)
+	}
+	if want := filepath.Join(g.WorktreeDir, "feat-a"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	again, err := g.Switch("feat/a", false)
+	if err != nil || again != path {
+		t.Errorf("Switch existing = %q, %v; want %q", again, err, path)
+	}
+
+	infos, err := g.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var found bool
+	for _, info := range infos {
+		if info.Branch == "feat/a" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("List() missing feat/a: %+v", infos)
+	}
+
+	if err := g.Remove("feat/a"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Error("worktree directory should be removed")
+	}
+	if out := mustGit(t, g.Dir, "branch", "--list", "feat/a"); out != "" {
+		t.Error("merged branch should be deleted")
+	}
+}
+
+func TestGitRunner_Remove_KeepsUnmergedBranch(t *testing.T) {
+	g, _ := newTestGitRunner(t)
+	path, err := g.Switch("feat/wip", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, path, "wip.txt", "wip")
+
+	if err := g.Remove("feat/wip"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if out := mustGit(t, g.Dir, "branch", "--list", "feat/wip"); out == "" {
+		t.Error("unmerged branch must be kept")
+	}
+	if err := g.Remove("feat/wip"); err == nil {
+		t.Error("expected error removing a branch without a worktree")
+	}
+}
+
+func TestGitRunner_Merge(t *testing.T) {
+	tests := []struct {
+		strategy    string
+		diverge     bool
+		wantErr     bool
+		wantCommits string // commits on target after the merge (including init)
+	}{
+		{strategy: "", diverge: true, wantCommits: "4"},
+		{strategy: MergeRebase, diverge: false, wantCommits: "3"},
+		{strategy: MergeFF, diverge: false, wantCommits: "3"},
+		{strategy: MergeFF, diverge: true, wantErr: true},
+		{strategy: MergeSquash, diverge: true, wantCommits: "3"},
+	}
+	for _, tt := range tests {
+		name := tt.strategy
+		if name == "" {
+			name = "default"
+		}
+		if tt.diverge {
+			name += "/diverged"
+		}
+		t.Run(name, func(t *testing.T) {
+			g, target := newTestGitRunner(t)
+			g.MergeStrategy = tt.strategy
+			path, err := g.Switch("feat/x", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			commitFile(t, path, "a.txt", "feat: a")
+			commitFile(t, path, "b.txt", "feat: b")
+			if tt.diverge {
+				commitFile(t, g.Dir, "main.txt", "chore: main moved")
+			}
+
+			err = g.Merge("feat/x", "")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected merge error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			if got := mustGit(t, g.Dir, "rev-list", "--count", target); got != tt.wantCommits {
+				t.Errorf("%s has %s commits, want %s", target, got, tt.wantCommits)
+			}
+			for _, f := range []string{"a.txt", "b.txt"} {
+				if _, statErr := os.Stat(filepath.Join(g.Dir, f)); statErr != nil {
+					t.Errorf("%s missing from %s after merge", f, target)
+				}
+			}
+			if tt.strategy == MergeSquash && !strings.Contains(mustGit(t, g.Dir, "log", "-1", "--format=%B"), "feat: a") {
+				t.Error("squash commit message should list the squashed commits")
+			}
+		})
+	}
+}
+
+func TestGitRunner_Merge_RebaseConflictAborts(t *testing.T) {
+	g, _ := newTestGitRunner(t)
+	path, err := g.Switch("feat/x", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, path, "same.txt", "branch side")
+	commitFile(t, g.Dir, "same.txt", "main side")
+
+	err = g.Merge("feat/x", "")
+	if !errors.Is(err, ErrMergeConflict) || !strings.Contains(err.Error(), "same.txt") {
+		t.Fatalf("Merge = %v, want ErrMergeConflict naming same.txt", err)
+	}
+	if status := mustGit(t, path, "status", "--porcelain"); status != "" {
+		t.Errorf("branch worktree should be clean after abort, got %q", status)
+	}
+	if branch := mustGit(t, path, "symbolic-ref", "--short", "HEAD"); branch != "feat/x" {
+		t.Errorf("rebase should have been aborted, HEAD is %q", branch)
+	}
+}
+
+func TestGitRunner_Merge_Errors(t *testing.T) {
+	g, target := newTestGitRunner(t)
+	if _, err := g.Switch("feat/x", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Merge("feat/x", "nope"); err == nil || !strings.Contains(err.Error(), "not checked out") {
+		t.Errorf("expected target-not-checked-out error, got %v", err)
+	}
+	if err := g.Merge(target, target); err == nil {
+		t.Error("expected error merging a branch into itself")
+	}
+
+	if err := os.WriteFile(filepath.Join(g.Dir, "dirty.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := g.Merge("feat/x", "")
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Errorf("expected dirty-target error, got %v", err)
+	}
+	if errors.Is(err, ErrMergeConflict) {
+		t.Errorf("a dirty target is not a conflict: %v", err)
+	}
+}
+
+func TestNew_SelectsBackend(t *testing.T) {
+	cfg := config.Defaults().Worktree
+	cfg.WorktreeDir = "/tmp/wts"
+	if r, ok := New(cfg, "/repo").(*Runner); !ok || r.WorktreeDir != "/tmp/wts" {
+		t.Errorf("default backend should be a worktrunk Runner, got %#v", r)
+	}
+
+	cfg.Backend = "git"
+	cfg.MergeStrategy = MergeSquash
+	g, ok := New(cfg, "/repo").(*GitRunner)
+	if !ok {
+		t.Fatal("backend = \"git\" should return a GitRunner")
+	}
+	if g.Dir != "/repo" || g.WorktreeDir != "/tmp/wts" || g.MergeStrategy != MergeSquash {
+		t.Errorf("GitRunner = %+v", g)
+	}
+}
@@ -36,8 +36,14 @@ func (r *Runner) listJSON() ([]WorktreeInfo, error) {
 }
 
 func (r *Runner) listPorcelain() ([]WorktreeInfo, error) {
+	return listPorcelain(r.Dir)
+}
+
+// listPorcelain lists the worktrees of the repository containing dir via
+// `git worktree list --porcelain`.
+func listPorcelain(dir string) ([]WorktreeInfo, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = r.Dir
+	cmd.Dir = dir
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git worktree list: %w", err)
@@ -11,7 +11,8 @@ import (
 // worktrunk to identify which worktree to operate on.
 //
 // If target is empty, worktrunk uses the branch from which the worktree was
-// created (its default behaviour).
+// created (its default behaviour). Failures whose output reports a conflict
+// wrap ErrMergeConflict.
 func (r *Runner) Merge(branch, target string) error {
 	args := []string{"merge"}
 	if target != "" {
@@ -26,6 +27,9 @@ func (r *Runner) Merge(branch, target string) error {
 		if msg == "" {
 			return fmt.Errorf("wt merge %s: %w", branch, err)
 		}
+		if strings.Contains(strings.ToLower(msg), "conflict") {
+			return fmt.Errorf("wt merge %s: %w: %s", branch, ErrMergeConflict, msg)
+		}
 		return fmt.Errorf("wt merge %s: %s", branch, msg)
 	}
 	return nil
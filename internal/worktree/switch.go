@@ -62,9 +62,16 @@ func (r *Runner) Switch(branch string, create bool) (string, error) {
 // switchGit uses `git worktree add` directly with a custom path under WorktreeDir.
 // If the worktree already exists at that path, it returns the path directly.
 func (r *Runner) switchGit(branch string, create bool) (string, error) {
+	return addGitWorktree(r.Dir, r.WorktreeDir, branch, create)
+}
+
+// addGitWorktree creates (or reuses) the worktree for branch under base,
+// running git from repoDir. It is shared by Runner's WorktreeDir mode and the
+// pure-git GitRunner backend.
+func addGitWorktree(repoDir, base, branch string, create bool) (string, error) {
 	// Sanitise branch name for use as a directory name.
 	dirName := strings.ReplaceAll(branch, "/", "-")
-	wtPath := filepath.Join(r.WorktreeDir, dirName)
+	wtPath := filepath.Join(base, dirName)
 
 	// If the worktree directory already exists, reuse it.
 	if info, err := os.Stat(wtPath); err == nil && info.IsDir() {
@@ -72,8 +79,8 @@ func (r *Runner) switchGit(branch string, create bool) (string, error) {
 	}
 
 	// Ensure the parent directory exists.
-	if err := os.MkdirAll(r.WorktreeDir, 0o755); err != nil {
-		return "", fmt.Errorf("create worktree dir %s: %w", r.WorktreeDir, err)
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return "", fmt.Errorf("create worktree dir %s: %w", base, err)
 	}
 
 	var cmd *exec.Cmd
@@ -84,7 +91,7 @@ func (r *Runner) switchGit(branch string, create bool) (string, error) {
 		// Create worktree for existing branch: git worktree add <path> <branch>
 		cmd = exec.Command("git", "worktree", "add", wtPath, branch)
 	}
-	cmd.Dir = r.Dir
+	cmd.Dir = repoDir
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -1,10 +1,14 @@
-// Package worktree provides a thin adapter around the worktrunk CLI (`wt`).
-// Worktrunk is an external binary dependency; Ralph invokes it as a subprocess
-// rather than importing it as a Go library.
+// Package worktree provides the worktree backends used for parallel agents: a
+// thin adapter around the worktrunk CLI (`wt`), invoked as a subprocess rather
+// than imported as a Go library, and a pure-git implementation for projects
+// without worktrunk.
 package worktree
 
 import (
+	"errors"
 	"runtime"
+
+	"github.com/LISSConsulting/RalphSpec/internal/config"
 )
 
 // WorktreeInfo describes a single git worktree managed by worktrunk.
@@ -14,6 +18,10 @@ type WorktreeInfo struct {
 	Bare   bool   `json:"bare"`
 }
 
+// ErrMergeConflict is wrapped by Merge errors caused by conflicting changes,
+// as opposed to setup problems such as a missing or dirty target worktree.
+var ErrMergeConflict = errors.New("merge conflict")
+
 // WorktreeOps is the interface that callers use to interact with worktrees.
 // Runner satisfies this interface.
 type WorktreeOps interface {
@@ -32,6 +40,19 @@ type Runner struct {
 	executable  string // cached after Detect()
 }
 
+// New returns the WorktreeOps backend selected by cfg.Backend for the
+// repository at dir: a GitRunner for "git", otherwise a worktrunk Runner.
+func New(cfg config.WorktreeConfig, dir string) WorktreeOps {
+	if cfg.Backend == "git" {
+		g := NewGitRunner(dir, cfg.ResolvedWorktreeDir())
+		g.MergeStrategy = cfg.MergeStrategy
+		return g
+	}
+	r := NewRunner(dir)
+	r.WorktreeDir = cfg.ResolvedWorktreeDir()
+	return r
+}
+
 // NewRunner returns a new Runner rooted at dir.
 func NewRunner(dir string) *Runner {
 	return &Runner{Dir: dir}
@@ -1,6 +1,7 @@
 package worktree
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -338,6 +339,9 @@ func TestMerge_Conflict(t *testing.T) {
 	if !strings.Contains(err.Error(), "feat/conflict") {
 		t.Errorf("error should mention branch, got: %v", err)
 	}
+	if !errors.Is(err, ErrMergeConflict) {
+		t.Errorf("conflict should wrap ErrMergeConflict, got: %v", err)
+	}
 }
 
 // ─── Remove tests ─────────────────────────────────────────────────────────────
@@ -695,6 +699,9 @@ func TestRemove_ErrorNoOutput(t *testing.T) {
 	if !strings.Contains(err.Error(), "feat/silent-fail") {
 		t.Errorf("error should mention branch name, got: %v", err)
 	}
+	if errors.Is(err, ErrMergeConflict) {
+		t.Errorf("a failure without conflict output should not wrap ErrMergeConflict: %v", err)
+	}
 }
 
 // TestMerge_ErrorNoOutput covers the msg=="" branch in Merge when the
@@ -712,4 +719,7 @@ func TestMerge_ErrorNoOutput(t *testing.T) {
 	if !strings.Contains(err.Error(), "feat/silent-fail") {
 		t.Errorf("error should mention branch name, got: %v", err)
 	}
+	if errors.Is(err, ErrMergeConflict) {
+		t.Errorf("a failure without conflict output should not wrap ErrMergeConflict: %v", err)
+	}
 }